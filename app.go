@@ -16,6 +16,7 @@ import (
 	"red-cloud/mod/gologger"
 	"red-cloud/mod/mcp"
 	"red-cloud/mod/plugin"
+	"red-cloud/mod/secret"
 
 	"github.com/projectdiscovery/gologger/levels"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -34,6 +35,9 @@ type App struct {
 	costCalculator          *cost.CostCalculator
 	taskScheduler           *redc.TaskScheduler
 	spotMonitor             *SpotMonitor
+	driftMonitor            *DriftMonitor
+	pendingChanges          map[string]*redc.ChangePlan // caseID -> 待确认的参数变更
+	pendingAdoptions        map[string]*redc.ChangePlan // caseID -> 纳管后待调和的差异
 	customDeploymentService *redc.CustomDeploymentService
 	templateManager         *redc.TemplateManager
 	configStore             *redc.ConfigStore
@@ -84,6 +88,10 @@ func (a *App) shutdown(ctx context.Context) {
 		a.spotMonitor.Stop()
 		a.spotMonitor = nil
 	}
+	if a.driftMonitor != nil {
+		a.driftMonitor.Stop()
+		a.driftMonitor = nil
+	}
 	if a.timelineStore != nil {
 		a.timelineStore.Close()
 	}
@@ -102,6 +110,10 @@ func (a *App) startup(ctx context.Context) {
 	if redc.U == "" {
 		redc.U = "system" // Use "system" to match CLI default and bypass permission check
 	}
+	// Identify this process in cross-process case locks
+	if redc.LockOwner == "cli" {
+		redc.LockOwner = "gui"
+	}
 
 	// Load GUI settings
 	if settings, err := redc.LoadGUISettings(); err == nil {
@@ -171,27 +183,36 @@ func (a *App) startup(ctx context.Context) {
 	pricingCacheDBPath := filepath.Join(redc.RedcPath, "pricing_cache.db")
 	a.pricingService = cost.NewPricingService(pricingCacheDBPath)
 	a.costCalculator = cost.NewCostCalculator()
+	redc.PolicyPricingService = a.pricingService
 
 	// Set credential provider for cost estimation
 	// This function reads credentials from the active config file
-	credProvider := func(provider string) (accessKey, secretKey, region string, err error) {
+	// provider may be "provider:account" to price with a named account
+	// Providers with a role_arn get cached temporary credentials from AssumeRole
+	credProvider := func(spec string) (accessKey, secretKey, sessionToken, region string, err error) {
 		conf, _, err := redc.ReadConfig(redc.ActiveConfigPath)
 		if err != nil {
-			return "", "", "", fmt.Errorf("failed to read config: %w", err)
+			return "", "", "", "", fmt.Errorf("failed to read config: %w", err)
+		}
+		provider, account := redc.SplitProviderAccount(spec)
+		if conf, err = conf.ForAccount(provider, account); err != nil {
+			return "", "", "", "", err
 		}
 
 		switch provider {
 		case "alicloud":
-			return conf.Providers.Alicloud.AccessKey, conf.Providers.Alicloud.SecretKey, conf.Providers.Alicloud.Region, nil
+			region = conf.Providers.Alicloud.Region
 		case "tencentcloud":
-			return conf.Providers.Tencentcloud.SecretId, conf.Providers.Tencentcloud.SecretKey, conf.Providers.Tencentcloud.Region, nil
+			region = conf.Providers.Tencentcloud.Region
 		case "aws":
-			return conf.Providers.Aws.AccessKey, conf.Providers.Aws.SecretKey, conf.Providers.Aws.Region, nil
+			region = conf.Providers.Aws.Region
 		case "volcengine":
-			return conf.Providers.Volcengine.AccessKey, conf.Providers.Volcengine.SecretKey, conf.Providers.Volcengine.Region, nil
+			return conf.Providers.Volcengine.AccessKey, conf.Providers.Volcengine.SecretKey, "", conf.Providers.Volcengine.Region, nil
 		default:
-			return "", "", "", fmt.Errorf("unsupported provider: %s", provider)
+			return "", "", "", "", fmt.Errorf("unsupported provider: %s", provider)
 		}
+		accessKey, secretKey, sessionToken, err = redc.SessionCredentials(conf, provider)
+		return accessKey, secretKey, sessionToken, region, err
 	}
 
 	a.pricingService.SetCredentialProvider(credProvider)
@@ -217,17 +238,20 @@ func (a *App) startup(ctx context.Context) {
 
 	a.taskScheduler.SetExecuteCallback(func(caseID string, action string) error {
 		if action == "start" {
-			err := a.StartCase(caseID)
+			err := a.startCase(caseID, caller{source: redc.SourceScheduler})
 			if err == nil {
 				a.emitRefresh()
 			}
 			return err
 		} else if action == "stop" {
-			err := a.StopCase(caseID)
+			err := a.stopCase(caseID, caller{source: redc.SourceScheduler})
 			if err == nil {
 				a.emitRefresh()
 			}
 			return err
+		} else if action == "kill" {
+			// 同步执行，TTL 到期销毁的结果需要反馈给任务中心
+			return a.killCase(caseID)
 		}
 		return fmt.Errorf(i18n.Tf("app_unknown_action", action))
 	})
@@ -263,6 +287,10 @@ func (a *App) startup(ctx context.Context) {
 		}
 	})
 
+	if settings, err := redc.LoadGUISettings(); err == nil && settings.CaseTTLWarnMinutes > 0 {
+		a.taskScheduler.SetLeaseWarnBefore(time.Duration(settings.CaseTTLWarnMinutes) * time.Minute)
+	}
+
 	a.taskScheduler.Start()
 
 	fmt.Printf("[INFO] %s\n", i18n.T("app_scheduler_start_success"))
@@ -306,6 +334,16 @@ func (a *App) startup(ctx context.Context) {
 		fmt.Printf("[INFO] %s\n", i18n.T("app_spot_monitor_start_success"))
 	}
 
+	// Start periodic drift detection (if enabled in settings)
+	if settings, err := redc.LoadGUISettings(); err == nil && settings.DriftMonitorEnabled {
+		a.driftMonitor = NewDriftMonitor(a, a.project, driftIntervalFromSettings(settings))
+		a.driftMonitor.Start()
+		fmt.Printf("[INFO] %s\n", i18n.T("app_drift_monitor_started"))
+	}
+
+	// Correct cases left in starting/stopping/removing by an interrupted process
+	go a.reconcileOnStartup()
+
 	// Check for updates in the background
 	go a.CheckForUpdatesOnStartup()
 }
@@ -313,6 +351,7 @@ func (a *App) startup(ctx context.Context) {
 // startupHeadless initializes the app without Wails context
 func (a *App) startupHeadless() {
 	a.wailsMode = false
+	redc.LockOwner = "http"
 	a.startup(context.Background())
 }
 
@@ -328,6 +367,7 @@ func (a *App) emitEvent(name string, data interface{}) {
 
 // emitLog sends a log message to the frontend and writes to file
 func (a *App) emitLog(message string) {
+	message = secret.Redact(message)
 	a.emitEvent("log", message)
 	// Also write to GUI log file
 	if a.logMgr != nil {
@@ -492,6 +532,7 @@ type ResourceSummary struct {
 // BalanceInfo represents account balance result
 type BalanceInfo struct {
 	Provider  string `json:"provider"`
+	Account   string `json:"account,omitempty"`
 	Amount    string `json:"amount"`
 	Currency  string `json:"currency"`
 	UpdatedAt string `json:"updatedAt"`
@@ -520,6 +561,7 @@ type ComposeSummary struct {
 // BillInfo represents billing information for a cloud provider
 type BillInfo struct {
 	Provider    string `json:"provider"`
+	Account     string `json:"account,omitempty"`
 	Month       string `json:"month"`
 	TotalAmount string `json:"totalAmount"`
 	Currency    string `json:"currency"`
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// AdoptResult is returned by AdoptCase: the new case and the diff left after importing.
+type AdoptResult struct {
+	CaseID  string       `json:"caseId"`
+	Name    string       `json:"name"`
+	Preview *PlanPreview `json:"preview"`
+}
+
+// AdoptCase creates a case from a template and imports existing cloud resources
+// (address=id pairs) into its state. The case stays pending until FinishCaseAdoption.
+func (a *App) AdoptCase(templateName string, name string, vars map[string]string, imports []string) (*AdoptResult, error) {
+	return a.adoptCase(templateName, name, vars, imports, caller{})
+}
+
+// adoptCase creates and imports an adopted case on behalf of who.
+func (a *App) adoptCase(templateName string, name string, vars map[string]string, imports []string, who caller) (*AdoptResult, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	targets, err := redc.ParseImportTargets(imports)
+	if err != nil {
+		return nil, err
+	}
+
+	a.activeOps.Add(1)
+	defer a.activeOps.Add(-1)
+	a.emitLog(i18n.Tf("app_adopt_importing", templateName, len(targets)))
+	c, plan, err := project.AdoptCase(templateName, who.user(), name, vars, targets)
+	if err != nil {
+		a.emitLog(i18n.Tf("adopt_failed", err))
+		a.logTimeline("scene", "scene_adopt_failed", "", name, i18n.Tf("adopt_failed", err), templateName, "error")
+		return nil, err
+	}
+	a.emitRefresh()
+
+	a.mu.Lock()
+	if a.pendingAdoptions == nil {
+		a.pendingAdoptions = make(map[string]*redc.ChangePlan)
+	}
+	a.pendingAdoptions[c.Id] = plan
+	a.mu.Unlock()
+
+	a.logTimeline("scene", "scene_adopted", c.Id, c.Name, i18n.Tf("app_adopt_imported", c.Name, len(targets)), plan.Summary(), "info")
+	preview, err := buildPlanPreview(c.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &AdoptResult{CaseID: c.Id, Name: c.Name, Preview: preview}, nil
+}
+
+// FinishCaseAdoption marks an adopted case as running. When the diff shown by
+// AdoptCase is not empty, it is applied first; apply failures never destroy resources.
+func (a *App) FinishCaseAdoption(caseID string) error {
+	return a.finishCaseAdoption(caseID, caller{})
+}
+
+// finishCaseAdoption finishes an adoption on behalf of who.
+func (a *App) finishCaseAdoption(caseID string, who caller) error {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return err
+	}
+	who.bind(c)
+	a.mu.Lock()
+	plan, ok := a.pendingAdoptions[c.Id]
+	delete(a.pendingAdoptions, c.Id)
+	a.mu.Unlock()
+	if !ok {
+		// 进程重启后内存中的 plan 已丢失，重新生成
+		if plan, err = c.AdoptPlan(); err != nil {
+			return err
+		}
+	}
+
+	if a.pluginMgr != nil {
+		a.setupPluginHooks(c)
+	}
+
+	go func() {
+		a.activeOps.Add(1)
+		defer a.activeOps.Add(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				a.emitLog(i18n.Tf("adopt_failed", r))
+			}
+			a.emitRefresh()
+		}()
+
+		if err := c.FinishAdoption(plan, true, who.user()); err != nil {
+			a.emitLog(i18n.Tf("adopt_failed", err))
+			a.logTimeline("scene", "scene_adopt_failed", c.Id, c.Name, i18n.Tf("adopt_failed", err), "", "error")
+			return
+		}
+		a.emitLog(i18n.Tf("adopt_finished", c.Name))
+		a.logTimeline("scene", "scene_adopt_finished", c.Id, c.Name, i18n.Tf("adopt_finished", c.Name), plan.Summary(), "success")
+	}()
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"red-cloud/mod/ai"
 	"red-cloud/mod/gologger"
 	"red-cloud/mod/mcp"
+	"red-cloud/mod/secret"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -328,7 +329,7 @@ func (a *App) ExportChatLog(content string) error {
 	if filePath == "" {
 		return nil
 	}
-	return os.WriteFile(filePath, []byte(content), 0644)
+	return os.WriteFile(filePath, []byte(secret.Redact(content)), 0644)
 }
 
 // ExportConsoleLogs exports console log content to a file via native save dialog
@@ -349,7 +350,7 @@ func (a *App) ExportConsoleLogs(content string) error {
 	if filePath == "" {
 		return nil
 	}
-	return os.WriteFile(filePath, []byte(content), 0644)
+	return os.WriteFile(filePath, []byte(secret.Redact(content)), 0644)
 }
 
 // runAgentLoop is the shared agentic loop used by AgentChatStream and DeployAgentChatStream
@@ -454,6 +455,7 @@ func (a *App) runAgentLoop(conversationId string, messages []AIChatMessage, prom
 
 	// Initialize safety hooks
 	hooks := ai.NewHookChain()
+	hooks.AddPreHook(ai.PolicyHook(a.checkToolPolicy))
 	// Dynamic timeout: base timeout + extra per round (each round may involve API call + tool execution)
 	dynamicTimeout := timeout + time.Duration(maxRounds)*30*time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), dynamicTimeout)
@@ -855,8 +857,9 @@ func canParallelizeToolCalls(calls []ai.ToolCall) bool {
 	for _, tc := range calls {
 		switch tc.Function.Name {
 		case "ask_user", "update_plan",
-			"start_case", "stop_case", "kill_case", "plan_case", "delete_case",
-			"compose_up", "compose_down",
+			"start_case", "stop_case", "kill_case", "plan_case", "delete_case", "set_case_ttl",
+			"replace_resource",
+			"compose_up", "compose_down", "compose_scale", "compose_plan",
 			"exec_command", "exec_userdata",
 			"save_compose_file", "save_template_files",
 			"pull_template", "delete_template",
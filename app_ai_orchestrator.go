@@ -110,6 +110,7 @@ func (a *App) OrchestratorStream(conversationId string, config OrchestratorConfi
 
 	// Safety hooks
 	hooks := ai.NewHookChain()
+	hooks.AddPreHook(ai.PolicyHook(a.checkToolPolicy))
 
 	// Emit orchestrator status
 	emitOrchestratorStatus := func(round int, phase string, detail string) {
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// PlanCaseChange merges new vars into a running case, runs terraform plan and
+// returns the resource-level diff. The plan is kept until ApplyCaseChange confirms it.
+func (a *App) PlanCaseChange(caseID string, vars map[string]string) (*PlanPreview, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	c, err := project.GetCase(caseID)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("app_get_case_failed", err))
+	}
+	if c.State != redc.StateRunning {
+		return nil, fmt.Errorf("%s", i18n.Tf("app_case_change_not_running", c.Name))
+	}
+
+	plan, err := c.PlanChange(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	if a.pendingChanges == nil {
+		a.pendingChanges = make(map[string]*redc.ChangePlan)
+	}
+	a.pendingChanges[c.Id] = plan
+	a.mu.Unlock()
+
+	return buildPlanPreview(c.Path)
+}
+
+// ApplyCaseChange applies the change previously generated by PlanCaseChange.
+func (a *App) ApplyCaseChange(caseID string) error {
+	return a.applyCaseChange(caseID, caller{})
+}
+
+// applyCaseChange applies the pending change of a case on behalf of who.
+func (a *App) applyCaseChange(caseID string, who caller) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.project == nil {
+		return fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	c, err := a.project.GetCase(caseID)
+	if err != nil {
+		return fmt.Errorf("%s", i18n.Tf("app_get_case_failed", err))
+	}
+	plan, ok := a.pendingChanges[c.Id]
+	if !ok {
+		return fmt.Errorf("%s", i18n.T("app_case_change_no_plan"))
+	}
+	delete(a.pendingChanges, c.Id)
+	who.bind(c)
+
+	if a.pluginMgr != nil {
+		a.setupPluginHooks(c)
+	}
+
+	go func() {
+		a.activeOps.Add(1)
+		defer a.activeOps.Add(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				a.emitLog(i18n.Tf("app_case_change_failed", c.Name, r))
+			}
+			a.emitRefresh()
+		}()
+
+		a.emitLog(i18n.Tf("app_case_change_applying", c.Name, plan.Summary()))
+		if err := c.ApplyChange(plan, c.Operator); err != nil {
+			a.emitLog(i18n.Tf("app_case_change_failed", c.Name, err))
+			a.logTimeline("scene", "scene_change_failed", c.Id, c.Name, i18n.Tf("app_case_change_failed", c.Name, err), "", "error")
+			if a.notificationMgr != nil {
+				a.notificationMgr.SendSceneFailed(c.Name, "变更")
+			}
+			return
+		}
+		a.emitLog(i18n.Tf("app_case_change_success", c.Name))
+		a.logTimeline("scene", "scene_changed", c.Id, c.Name, i18n.Tf("app_case_change_success", c.Name), plan.Summary(), "success")
+	}()
+
+	return nil
+}
+
+// GetCaseChangeHistory returns the parameter change history of a case, newest first.
+func (a *App) GetCaseChangeHistory(caseID string) ([]*redc.CaseChangeHistory, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	c, err := project.GetCase(caseID)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("app_get_case_failed", err))
+	}
+	return redc.LoadCaseHistory(project.ProjectName, c.Id)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// GetCaseResourceAddresses returns the resource instance addresses in the case state,
+// used to pick -target / -replace addresses.
+func (a *App) GetCaseResourceAddresses(caseID string) ([]string, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	return c.StateAddresses()
+}
+
+// ApplyCaseTargets applies only the given resources of a running case; addresses in
+// replace are recreated. Addresses are validated against the current state.
+func (a *App) ApplyCaseTargets(caseID string, targets []string, replace []string) error {
+	return a.runCaseTargets(caseID, redc.TargetSpec{Targets: targets, Replace: replace}, caller{})
+}
+
+// DestroyCaseTargets destroys only the given resources of a running case.
+func (a *App) DestroyCaseTargets(caseID string, targets []string) error {
+	return a.runCaseTargets(caseID, redc.TargetSpec{Targets: targets, Destroy: true}, caller{})
+}
+
+func (a *App) runCaseTargets(caseID string, target redc.TargetSpec, who caller) error {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return err
+	}
+	who.bind(c)
+	if c.State != redc.StateRunning {
+		return fmt.Errorf("%s", i18n.Tf("target_case_not_running", c.Name))
+	}
+	// 先同步校验，地址错误时立即返回给前端
+	if err := c.ValidateTargets(target); err != nil {
+		return err
+	}
+	if a.pluginMgr != nil {
+		a.setupPluginHooks(c)
+	}
+
+	a.emitLog(i18n.Tf("app_case_target_start", c.Name, target.String()))
+	go func() {
+		a.activeOps.Add(1)
+		defer a.activeOps.Add(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				a.emitLog(i18n.Tf("app_case_target_failed", c.Name, r))
+			}
+			a.emitRefresh()
+		}()
+
+		err := c.Change(redc.ChangeCommand{AutoApprove: true, Operator: who.user(), Target: target})
+		if err != nil {
+			a.emitLog(i18n.Tf("app_case_target_failed", c.Name, err))
+			a.logTimeline("scene", "scene_target_failed", c.Id, c.Name, i18n.Tf("app_case_target_failed", c.Name, err), target.String(), "error")
+			if a.notificationMgr != nil {
+				a.notificationMgr.SendSceneFailed(c.Name, "变更")
+			}
+			return
+		}
+		a.emitLog(i18n.Tf("app_case_target_success", c.Name))
+		a.logTimeline("scene", "scene_target_changed", c.Id, c.Name, i18n.Tf("app_case_target_success", c.Name), target.String(), "success")
+	}()
+	return nil
+}
@@ -64,6 +64,11 @@ func (a *App) ComposePreview(filePath string, profiles []string) (ComposeSummary
 
 // ComposeUp starts compose deployment asynchronously (GUI button)
 func (a *App) ComposeUp(filePath string, profiles []string) error {
+	return a.composeUp(filePath, profiles, caller{})
+}
+
+// composeUp starts compose deployment asynchronously on behalf of who.
+func (a *App) composeUp(filePath string, profiles []string, who caller) error {
 	a.mu.Lock()
 	project := a.project
 	a.mu.Unlock()
@@ -83,6 +88,9 @@ func (a *App) ComposeUp(filePath string, profiles []string) error {
 		File:     filePath,
 		Profiles: profiles,
 		Project:  project,
+		Timeline: a.timelineStore,
+		Actor:    who.actor,
+		Source:   who.source,
 		LogCallback: func(msg string) {
 			a.emitEvent("compose-log", map[string]string{"message": msg})
 		},
@@ -128,6 +136,7 @@ func (a *App) ComposeUpSync(filePath string, profiles []string) (interface{}, er
 		File:     filePath,
 		Profiles: profiles,
 		Project:  project,
+		Timeline: a.timelineStore,
 		LogCallback: func(msg string) {
 			a.emitEvent("compose-log", map[string]string{"message": msg})
 		},
@@ -153,6 +162,11 @@ func (a *App) ComposeUpSync(filePath string, profiles []string) (interface{}, er
 
 // ComposeDown destroys compose deployment asynchronously (GUI button)
 func (a *App) ComposeDown(filePath string, profiles []string) error {
+	return a.composeDown(filePath, profiles, caller{})
+}
+
+// composeDown destroys compose deployment asynchronously on behalf of who.
+func (a *App) composeDown(filePath string, profiles []string, who caller) error {
 	a.mu.Lock()
 	project := a.project
 	a.mu.Unlock()
@@ -172,6 +186,8 @@ func (a *App) ComposeDown(filePath string, profiles []string) error {
 		File:     filePath,
 		Profiles: profiles,
 		Project:  project,
+		Actor:    who.actor,
+		Source:   who.source,
 		LogCallback: func(msg string) {
 			a.emitEvent("compose-log", map[string]string{"message": msg})
 		},
@@ -238,6 +254,161 @@ func (a *App) ComposeDownSync(filePath string, profiles []string) error {
 	return nil
 }
 
+// ListComposeStacks returns the persisted compose stacks of the current project
+func (a *App) ListComposeStacks() ([]compose.StackInfo, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	return compose.ListStacks(project)
+}
+
+// ComposeDownStack destroys a compose stack from its record asynchronously (GUI button).
+// Works even when the compose file has been moved or edited since deployment.
+func (a *App) ComposeDownStack(stack string) error {
+	return a.composeDownStack(stack, caller{})
+}
+
+// composeDownStack destroys a compose stack asynchronously on behalf of who.
+func (a *App) composeDownStack(stack string, who caller) error {
+	opts, err := a.composeStackOptions(stack)
+	if err != nil {
+		return err
+	}
+	opts.Actor, opts.Source = who.actor, who.source
+	a.emitLog(i18n.Tf("app_compose_down_start", stack))
+	a.emitEvent("compose-status", map[string]string{"action": "down", "phase": "running"})
+	go func() {
+		a.activeOps.Add(1)
+		defer a.activeOps.Add(-1)
+		defer a.emitRefresh()
+		if err := compose.RunComposeDown(opts); err != nil {
+			errMsg := i18n.Tf("app_compose_down_failed", err)
+			a.emitLog(errMsg)
+			a.emitEvent("compose-status", map[string]string{"action": "down", "phase": "error", "error": errMsg})
+			return
+		}
+		a.emitLog(i18n.T("app_compose_down_done"))
+		a.emitEvent("compose-status", map[string]string{"action": "down", "phase": "done"})
+	}()
+	return nil
+}
+
+// ComposeDownStackSync destroys a compose stack from its record synchronously. Used by MCP/Agent tools.
+func (a *App) ComposeDownStackSync(stack string) error {
+	opts, err := a.composeStackOptions(stack)
+	if err != nil {
+		return err
+	}
+	a.emitLog(i18n.Tf("app_compose_down_start", stack))
+	a.emitEvent("compose-status", map[string]string{"action": "down", "phase": "running"})
+	a.activeOps.Add(1)
+	defer a.activeOps.Add(-1)
+	defer a.emitRefresh()
+
+	if err := compose.RunComposeDown(opts); err != nil {
+		errMsg := i18n.Tf("app_compose_down_failed", err)
+		a.emitLog(errMsg)
+		a.emitEvent("compose-status", map[string]string{"action": "down", "phase": "error", "error": errMsg})
+		return err
+	}
+	a.emitLog(i18n.T("app_compose_down_done"))
+	a.emitEvent("compose-status", map[string]string{"action": "down", "phase": "done"})
+	return nil
+}
+
+// ComposeScaleStackSync changes the replica count of services in a deployed stack synchronously.
+// Only the missing instances are deployed and the extra ones destroyed. Used by MCP/Agent tools.
+func (a *App) ComposeScaleStackSync(stack string, replicas map[string]int) (*compose.ComposeScaleResult, error) {
+	return a.composeScaleStack(stack, replicas, caller{})
+}
+
+// composeScaleStack changes the replica count of a stack synchronously on behalf of who.
+func (a *App) composeScaleStack(stack string, replicas map[string]int, who caller) (*compose.ComposeScaleResult, error) {
+	opts, err := a.composeStackOptions(stack)
+	if err != nil {
+		return nil, err
+	}
+	opts.Actor, opts.Source = who.actor, who.source
+	opts.Timeline = a.timelineStore
+	a.emitLog(i18n.Tf("app_compose_scale_start", stack))
+	a.emitEvent("compose-status", map[string]string{"action": "scale", "phase": "running"})
+	a.activeOps.Add(1)
+	defer a.activeOps.Add(-1)
+	defer a.emitRefresh()
+
+	result, err := compose.RunComposeScale(opts, replicas)
+	if err != nil {
+		errMsg := i18n.Tf("app_compose_scale_failed", err)
+		a.emitLog(errMsg)
+		a.emitEvent("compose-status", map[string]string{"action": "scale", "phase": "error", "error": errMsg})
+		return result, err
+	}
+	a.emitLog(i18n.T("app_compose_scale_done"))
+	a.emitEvent("compose-status", map[string]string{"action": "scale", "phase": "done"})
+	return result, nil
+}
+
+// ComposePlan compares a compose file with the live cases of its stack without changing anything:
+// added/removed services, replica changes, re-applies, per-case Terraform plans and the cost delta.
+func (a *App) ComposePlan(filePath string, profiles []string) (*compose.ComposePlanResult, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		if a.initError != "" {
+			return nil, fmt.Errorf("%s", a.initError)
+		}
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	if strings.TrimSpace(filePath) == "" {
+		filePath = "redc-compose.yaml"
+	}
+
+	a.emitLog(i18n.Tf("app_compose_plan_start", filePath))
+	result, err := compose.RunComposePlan(compose.ComposeOptions{
+		File:     filePath,
+		Profiles: profiles,
+		Project:  project,
+		LogCallback: func(msg string) {
+			a.emitEvent("compose-log", map[string]string{"message": msg})
+		},
+	})
+	if err != nil {
+		a.emitLog(i18n.Tf("compose_plan_failed", err))
+		return nil, err
+	}
+	a.emitLog(i18n.Tf("app_compose_plan_done", result.Summary()))
+	return result, nil
+}
+
+func (a *App) composeStackOptions(stack string) (compose.ComposeOptions, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		if a.initError != "" {
+			return compose.ComposeOptions{}, fmt.Errorf("%s", a.initError)
+		}
+		return compose.ComposeOptions{}, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	if strings.TrimSpace(stack) == "" {
+		return compose.ComposeOptions{}, fmt.Errorf("%s", i18n.Tf("compose_stack_not_found", stack))
+	}
+	return compose.ComposeOptions{
+		Stack:   stack,
+		Project: project,
+		LogCallback: func(msg string) {
+			a.emitEvent("compose-log", map[string]string{"message": msg})
+		},
+	}, nil
+}
+
 func formatComposeProvider(provider interface{}) string {
 	if provider == nil {
 		return ""
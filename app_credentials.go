@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"time"
+)
+
+// GetCredentialStatus returns how each credential of the active config is stored
+// (plain / encrypted / env / exec / process) and whether the store is locked.
+func (a *App) GetCredentialStatus() (*redc.CredentialStatus, error) {
+	return redc.GetCredentialStatus(redc.ActiveConfigPath)
+}
+
+// UnlockCredentials unlocks the encrypted credential store for this process and
+// reloads the active config.
+func (a *App) UnlockCredentials(passphrase string) error {
+	if _, err := redc.UnlockCredentials(redc.ActiveConfigPath, []byte(passphrase), 0); err != nil {
+		return err
+	}
+	a.logTimeline("system", "credentials_unlocked", "", "", i18n.T("cred_unlocked_gui"), "", "info")
+	return nil
+}
+
+// LockCredentials forgets the master key; encrypted credentials are unavailable until unlocked again.
+func (a *App) LockCredentials() error {
+	if err := redc.LockCredentials(); err != nil {
+		return err
+	}
+	a.logTimeline("system", "credentials_locked", "", "", i18n.T("cred_locked_done"), "", "info")
+	return nil
+}
+
+// AssumeRole obtains fresh temporary credentials for a provider's role (optionally of a named
+// account) using mfaCode, and returns when they expire. The credentials are cached for
+// terraform runs, balance checks and pricing until shortly before they expire.
+func (a *App) AssumeRole(provider, account, mfaCode string) (string, error) {
+	conf, _, err := redc.ReadConfig(redc.ActiveConfigPath)
+	if err != nil {
+		return "", err
+	}
+	if conf, err = conf.ForAccount(provider, account); err != nil {
+		return "", err
+	}
+	creds, err := redc.RefreshRoleCredentials(conf, provider, mfaCode)
+	if err != nil {
+		return "", err
+	}
+	if creds == nil {
+		return "", fmt.Errorf("%s", i18n.Tf("cred_no_role", provider))
+	}
+	expires := creds.Expiration.Local().Format(time.RFC3339)
+	a.logTimeline("system", "role_assumed", "", "", i18n.Tf("cred_assumed", provider, expires), "", "info")
+	return expires, nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// defaultDriftInterval is used when GUISettings.DriftMonitorInterval is unset.
+const defaultDriftInterval = 60 * time.Minute
+
+// DetectCaseDrift runs a refresh-only plan for a single case and returns drifted resources.
+func (a *App) DetectCaseDrift(caseID string) (*redc.DriftReport, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	c, err := project.GetCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := c.DetectDrift()
+	if err != nil {
+		return nil, err
+	}
+	a.recordDrift(report)
+	return report, nil
+}
+
+// DetectProjectDrift checks every running case in the current project for drift.
+func (a *App) DetectProjectDrift() ([]*redc.DriftReport, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	reports, err := redc.DetectProjectDrift(project.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reports {
+		a.recordDrift(r)
+	}
+	return reports, nil
+}
+
+// recordDrift writes drifted cases to the timeline and notifies the user.
+func (a *App) recordDrift(r *redc.DriftReport) {
+	if r == nil || !r.Drifted {
+		return
+	}
+	detail, _ := json.Marshal(r.Resources)
+	msg := i18n.Tf("drift_detected", r.CaseName, len(r.Resources))
+	a.emitLog(fmt.Sprintf("⚠️ %s", msg))
+	a.logTimeline("drift", "drift_detected", r.CaseID, r.CaseName, msg, string(detail), "warning")
+	a.emitEvent("drift-detected", r)
+	if a.notificationMgr != nil {
+		a.notificationMgr.SendDriftDetected(r.CaseName, len(r.Resources))
+	}
+}
+
+// SetDriftMonitorEnabled toggles the periodic drift check.
+func (a *App) SetDriftMonitorEnabled(enabled bool) error {
+	a.mu.Lock()
+	settings, err := redc.LoadGUISettings()
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	settings.DriftMonitorEnabled = enabled
+	if err := redc.SaveGUISettings(settings); err != nil {
+		a.mu.Unlock()
+		return err
+	}
+
+	if enabled {
+		if a.driftMonitor == nil {
+			a.driftMonitor = NewDriftMonitor(a, a.project, driftIntervalFromSettings(settings))
+			a.driftMonitor.Start()
+			a.emitLog(i18n.T("app_drift_monitor_started"))
+		}
+		a.mu.Unlock()
+		return nil
+	}
+	stopped := a.driftMonitor
+	a.driftMonitor = nil
+	a.mu.Unlock()
+
+	// Stop waits for an in-flight refresh-only plan, which can take minutes; never hold a.mu here.
+	if stopped != nil {
+		stopped.Stop()
+	}
+	a.emitLog(i18n.T("app_drift_monitor_stopped"))
+	return nil
+}
+
+func (a *App) GetDriftMonitorEnabled() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	settings, err := redc.LoadGUISettings()
+	if err != nil {
+		return false
+	}
+	return settings.DriftMonitorEnabled
+}
+
+// SetDriftMonitorInterval sets the check interval in minutes; takes effect on next (re)start.
+func (a *App) SetDriftMonitorInterval(minutes int) error {
+	if minutes < 5 {
+		return fmt.Errorf("%s", i18n.T("app_drift_interval_too_short"))
+	}
+	a.mu.Lock()
+	settings, err := redc.LoadGUISettings()
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	settings.DriftMonitorInterval = minutes
+	if err := redc.SaveGUISettings(settings); err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	old := a.driftMonitor
+	if old != nil {
+		a.driftMonitor = NewDriftMonitor(a, a.project, driftIntervalFromSettings(settings))
+		a.driftMonitor.Start()
+	}
+	a.mu.Unlock()
+
+	// The replaced monitor may be in the middle of a scan; wait for it without holding a.mu.
+	if old != nil {
+		old.Stop()
+	}
+	return nil
+}
+
+func driftIntervalFromSettings(s *redc.GUISettings) time.Duration {
+	if s == nil || s.DriftMonitorInterval <= 0 {
+		return defaultDriftInterval
+	}
+	return time.Duration(s.DriftMonitorInterval) * time.Minute
+}
+
+// DriftMonitor periodically runs drift detection for all running cases.
+// It keeps its own project snapshot so scans never need a.mu, which is held
+// by the setters while they stop the monitor.
+type DriftMonitor struct {
+	app      *App
+	project  atomic.Pointer[redc.RedcProject]
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+	interval time.Duration
+}
+
+// NewDriftMonitor creates a new DriftMonitor for the given project.
+func NewDriftMonitor(app *App, project *redc.RedcProject, interval time.Duration) *DriftMonitor {
+	m := &DriftMonitor{
+		app:      app,
+		stopCh:   make(chan struct{}),
+		interval: interval,
+	}
+	m.project.Store(project)
+	return m
+}
+
+// SetProject switches the project scanned from the next run on.
+func (m *DriftMonitor) SetProject(project *redc.RedcProject) {
+	m.project.Store(project)
+}
+
+// Start begins the background monitoring loop.
+func (m *DriftMonitor) Start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop signals the monitor to stop and waits for it to finish.
+func (m *DriftMonitor) Stop() {
+	close(m.stopCh)
+	m.wg.Wait()
+}
+
+func (m *DriftMonitor) loop() {
+	defer m.wg.Done()
+
+	// Initial delay: let the app finish loading before running terraform
+	select {
+	case <-time.After(5 * time.Minute):
+	case <-m.stopCh:
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	m.scan()
+	for {
+		select {
+		case <-ticker.C:
+			m.scan()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+func (m *DriftMonitor) scan() {
+	app := m.app
+	project := m.project.Load()
+	if project == nil {
+		return
+	}
+
+	cases, err := redc.LoadProjectCases(project.ProjectName)
+	if err != nil {
+		return
+	}
+	for _, c := range cases {
+		select {
+		case <-m.stopCh:
+			return
+		default:
+		}
+		if c.State != redc.StateRunning {
+			continue
+		}
+		report, err := c.DetectDrift()
+		if redc.IsCaseLocked(err) {
+			// The case is being changed; check it again on the next scan.
+			continue
+		}
+		if err != nil {
+			app.logTimeline("drift", "drift_check_failed", c.Id, c.Name, i18n.Tf("drift_case_failed", c.Name, err), "", "error")
+			continue
+		}
+		app.recordDrift(report)
+	}
+}
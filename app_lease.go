@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// SetCaseTTL sets (or with an empty/"0" ttl clears) the TTL of a case, counted from now.
+// ttl accepts Go durations plus days, e.g. "8h", "30m", "2d".
+func (a *App) SetCaseTTL(caseID string, ttl string) (*redc.CaseLease, error) {
+	d, err := redc.ParseTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	lease, err := c.SetLease(d)
+	if err != nil {
+		return nil, err
+	}
+	if lease == nil {
+		a.logTimeline("scene", "ttl_cleared", c.Id, c.Name, i18n.Tf("lease_cleared", c.Name), "", "info")
+	} else {
+		a.logTimeline("scene", "ttl_set", c.Id, c.Name, i18n.Tf("lease_set", c.Name, lease.ExpiresAt.Format("2006-01-02 15:04")), "", "info")
+	}
+	a.emitRefresh()
+	return lease, nil
+}
+
+// ExtendCaseTTL extends the TTL of a case by the given duration.
+func (a *App) ExtendCaseTTL(caseID string, extend string) (*redc.CaseLease, error) {
+	d, err := redc.ParseTTL(extend)
+	if err != nil {
+		return nil, err
+	}
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	lease, err := redc.ExtendCaseLease(c.Id, d)
+	if err != nil {
+		return nil, err
+	}
+	a.logTimeline("scene", "ttl_extended", c.Id, c.Name, i18n.Tf("lease_extended", c.Name, lease.ExpiresAt.Format("2006-01-02 15:04")), "", "info")
+	a.emitRefresh()
+	return lease, nil
+}
+
+// GetCaseTTL returns the TTL of a case, nil when no TTL is set.
+func (a *App) GetCaseTTL(caseID string) (*redc.CaseLease, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	return redc.GetCaseLease(c.Id)
+}
+
+// GetCaseTTLWarnMinutes returns how many minutes before expiry a warning is sent.
+func (a *App) GetCaseTTLWarnMinutes() int {
+	settings, err := redc.LoadGUISettings()
+	if err != nil || settings.CaseTTLWarnMinutes <= 0 {
+		return int(redc.DefaultLeaseWarnBefore / time.Minute)
+	}
+	return settings.CaseTTLWarnMinutes
+}
+
+// SetCaseTTLWarnMinutes sets how many minutes before expiry a warning is sent.
+func (a *App) SetCaseTTLWarnMinutes(minutes int) error {
+	if minutes <= 0 {
+		return fmt.Errorf("%s", i18n.T("lease_warn_minutes_invalid"))
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	settings, err := redc.LoadGUISettings()
+	if err != nil {
+		return err
+	}
+	settings.CaseTTLWarnMinutes = minutes
+	if err := redc.SaveGUISettings(settings); err != nil {
+		return err
+	}
+	if a.taskScheduler != nil {
+		a.taskScheduler.SetLeaseWarnBefore(time.Duration(minutes) * time.Minute)
+	}
+	return nil
+}
+
+// applyCreateTTL sets the TTL chosen in the create dialog on a newly created case.
+// Failure is only logged because the case itself was created.
+func (a *App) applyCreateTTL(c *redc.Case, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	lease, err := c.SetLease(ttl)
+	if err != nil {
+		a.emitLog(i18n.Tf("lease_set_failed", c.Name, err))
+		return
+	}
+	msg := i18n.Tf("lease_set", c.Name, lease.ExpiresAt.Format("2006-01-02 15:04"))
+	a.emitLog(msg)
+	a.logTimeline("scene", "ttl_set", c.Id, c.Name, msg, "", "info")
+}
+
+// getCase resolves a case in the current project.
+func (a *App) getCase(caseID string) (*redc.Case, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	c, err := project.GetCase(caseID)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("app_get_case_failed", err))
+	}
+	return c, nil
+}
+
+// killCase destroys a case synchronously, used by scheduled kill tasks and TTL expiry.
+// The case is looked up in its own project, which may differ from the one open in the GUI.
+func (a *App) killCase(caseID string) error {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if lease, _ := redc.GetCaseLease(caseID); lease != nil && (project == nil || lease.ProjectID != project.ProjectName) {
+		p, err := redc.ProjectParse(lease.ProjectID, redc.U)
+		if err != nil {
+			return err
+		}
+		project = p
+	}
+	if project == nil {
+		return fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	c, err := project.GetCase(caseID)
+	if err != nil {
+		return fmt.Errorf("%s", i18n.Tf("app_get_case_failed", err))
+	}
+	c.SetActor("", redc.SourceScheduler)
+	if a.pluginMgr != nil {
+		a.setupPluginHooks(c)
+	}
+
+	a.activeOps.Add(1)
+	defer a.activeOps.Add(-1)
+	defer a.emitRefresh()
+
+	a.emitLog(i18n.Tf("app_stopping_scene", c.Name))
+	if err := c.Kill(); err != nil {
+		a.emitLog(i18n.Tf("app_scene_stop_failed", err))
+		a.logTimeline("scene", "scene_error", c.Id, c.Name, i18n.Tf("app_scene_stop_failed", err), "", "error")
+		return err
+	}
+	a.emitLog(i18n.Tf("app_scene_stop_success", c.Name))
+	a.logTimeline("scene", "scene_stopped", c.Id, c.Name, i18n.Tf("app_scene_stop_success", c.Name), "", "info")
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// GetCaseLocks returns all current cross-process case locks.
+func (a *App) GetCaseLocks() ([]*redc.CaseLock, error) {
+	return redc.ListCaseLocks()
+}
+
+// UnlockCase force-releases the lock of a case left behind by a crashed process.
+func (a *App) UnlockCase(caseID string) (*redc.CaseLock, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	c, err := project.GetCase(caseID)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("app_get_case_failed", err))
+	}
+	removed, err := redc.ForceUnlockCase(c.Id)
+	if err != nil {
+		return nil, err
+	}
+	if removed != nil {
+		a.logTimeline("case", "unlock", c.Id, c.Name, i18n.Tf("unlock_done", c.Name, removed.Operation, removed.Owner), "", "warning")
+	}
+	return removed, nil
+}
@@ -35,6 +35,26 @@ func (a *App) MCPComposeDownSync(filePath string, profiles []string) error {
 	return a.ComposeDownSync(filePath, profiles)
 }
 
+// MCPListComposeStacks implements AppBridge
+func (a *App) MCPListComposeStacks() (interface{}, error) {
+	return a.ListComposeStacks()
+}
+
+// MCPComposeDownStackSync implements AppBridge — destroys a compose stack from its record
+func (a *App) MCPComposeDownStackSync(stack string) error {
+	return a.ComposeDownStackSync(stack)
+}
+
+// MCPComposeScaleSync implements AppBridge — scales services of a deployed compose stack
+func (a *App) MCPComposeScaleSync(stack string, replicas map[string]int) (interface{}, error) {
+	return a.ComposeScaleStackSync(stack, replicas)
+}
+
+// MCPComposePlan implements AppBridge — diffs a compose file against its deployed stack
+func (a *App) MCPComposePlan(filePath string, profiles []string) (interface{}, error) {
+	return a.ComposePlan(filePath, profiles)
+}
+
 // MCPGetCostEstimate implements AppBridge
 func (a *App) MCPGetCostEstimate(templateName string, variables map[string]string) (interface{}, error) {
 	return a.GetCostEstimate(templateName, variables)
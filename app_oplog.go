@@ -0,0 +1,33 @@
+package main
+
+import (
+	redc "red-cloud/mod"
+)
+
+// ListCaseOps returns the recorded operations (terraform, SSH, plugin hooks) of a case, newest first.
+func (a *App) ListCaseOps(caseID string) ([]*redc.CaseOp, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	return redc.ListCaseOps(c)
+}
+
+// GetCaseOpLog reads an operation log starting at offset. opID <= 0 selects the
+// latest operation; poll with the returned offset to follow a running operation.
+func (a *App) GetCaseOpLog(caseID string, opID int, offset int64) (*redc.CaseOpLogChunk, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	return redc.ReadCaseOpLog(c, opID, offset, 1<<20)
+}
+
+// GetCaseStateHistory returns the state transitions of a case with actor and source, newest first.
+func (a *App) GetCaseStateHistory(caseID string) ([]*redc.StateTransition, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	return redc.ListStateTransitions(c.Id)
+}
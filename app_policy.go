@@ -0,0 +1,65 @@
+package main
+
+import (
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// GetPolicy returns the deployment policy of the active profile (nil when none is set).
+func (a *App) GetPolicy() (*redc.PolicyConfig, error) {
+	return redc.LoadActivePolicy()
+}
+
+// SetPolicy validates and saves the deployment policy of the active profile; nil clears it.
+func (a *App) SetPolicy(policy *redc.PolicyConfig) error {
+	if err := redc.SaveActivePolicy(policy); err != nil {
+		return err
+	}
+	a.logTimeline("system", "policy_updated", "", "", i18n.T("policy_updated"), "", "info")
+	return nil
+}
+
+// CheckCasePolicy evaluates the deployment policy against the current plan of a case
+// without applying it.
+func (a *App) CheckCasePolicy(caseID string) (*redc.PolicyReport, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := c.CheckPolicy()
+	if redc.IsPolicyDenied(err) {
+		return report, nil
+	}
+	return report, err
+}
+
+// checkToolPolicy is the ai.PolicyChecker used by the AI agent: start_case is checked
+// against the case plan before the tool runs. Other deploying tools are gated when they apply.
+func (a *App) checkToolPolicy(toolName string, args map[string]interface{}) (deny []string, warn []string) {
+	if toolName != "start_case" {
+		return nil, nil
+	}
+	caseID, _ := args["case_id"].(string)
+	if caseID == "" {
+		return nil, nil
+	}
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, nil
+	}
+	// 策略无法加载时拒绝；plan 缺失等其他错误留给 apply 时的检查处理
+	if _, err := redc.LoadActivePolicy(); err != nil {
+		return []string{i18n.Tf("policy_load_failed", c.Name, err)}, nil
+	}
+	report, _ := c.CheckPolicy()
+	if report == nil {
+		return nil, nil
+	}
+	for _, v := range report.Denied() {
+		deny = append(deny, v.String())
+	}
+	for _, v := range report.Warnings() {
+		warn = append(warn, v.String())
+	}
+	return deny, warn
+}
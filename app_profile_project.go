@@ -426,6 +426,9 @@ func (a *App) SwitchProject(projectName string) error {
 
 	// Update project reference
 	a.project = p
+	if a.driftMonitor != nil {
+		a.driftMonitor.SetProject(p)
+	}
 
 	// Update log manager to use new project path
 	a.logMgr = gologger.NewLogManager(p.ProjectPath)
@@ -561,24 +564,34 @@ func getProviderDisplayName(provider string) string {
 	}
 }
 
+// GetBalances queries account balances. Entries may be "provider" or "provider:account";
+// a plain "provider" entry also queries every named account of that provider.
 func (a *App) GetBalances(providers []string) ([]BalanceInfo, error) {
-	if len(providers) == 0 {
-		providers = []string{"aliyun", "tencentcloud", "volcengine", "huaweicloud", "ucloud", "vultr", "aws"}
-	}
-
-	conf, _, err := redc.ReadConfig(redc.ActiveConfigPath)
+	allConf, _, err := redc.ReadConfig(redc.ActiveConfigPath)
 	if err != nil {
 		return nil, err
 	}
+	if len(providers) == 0 {
+		providers = []string{"aliyun", "tencentcloud", "volcengine", "huaweicloud", "ucloud", "vultr", "aws"}
+	}
+	providers = withAccounts(allConf, providers)
 
 	results := make([]BalanceInfo, 0, len(providers))
-	for _, p := range providers {
+	for _, spec := range providers {
+		p, account := redc.SplitProviderAccount(spec)
 		result := BalanceInfo{
 			Provider:  p,
+			Account:   account,
 			Amount:    "-",
 			Currency:  "-",
 			UpdatedAt: time.Now().Format("2006-01-02 15:04:05"),
 		}
+		conf, err := allConf.ForAccount(p, account)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
 
 		func() {
 			defer func() {
@@ -592,10 +605,13 @@ func (a *App) GetBalances(providers []string) ([]BalanceInfo, error) {
 
 			switch p {
 		case "aliyun":
-			if conf.Providers.Alicloud.AccessKey == "" || conf.Providers.Alicloud.SecretKey == "" {
+			ak, sk, token, err := redc.SessionCredentials(conf, p)
+			if err != nil {
+				result.Error = err.Error()
+			} else if ak == "" || sk == "" {
 				result.Error = i18n.T("app_cred_aliyun_missing")
 			} else {
-				amount, currency, err := redc.QueryAliyunBalance(conf.Providers.Alicloud.AccessKey, conf.Providers.Alicloud.SecretKey, conf.Providers.Alicloud.Region)
+				amount, currency, err := redc.QueryAliyunBalance(ak, sk, token, conf.Providers.Alicloud.Region)
 				if err != nil {
 					logMsg := fmt.Sprintf("[GetBalances] %s error: %v", p, err)
 					log.Printf(logMsg)
@@ -607,10 +623,13 @@ func (a *App) GetBalances(providers []string) ([]BalanceInfo, error) {
 				}
 			}
 		case "tencentcloud":
-			if conf.Providers.Tencentcloud.SecretId == "" || conf.Providers.Tencentcloud.SecretKey == "" {
+			ak, sk, token, err := redc.SessionCredentials(conf, p)
+			if err != nil {
+				result.Error = err.Error()
+			} else if ak == "" || sk == "" {
 				result.Error = i18n.T("app_cred_tencent_missing")
 			} else {
-				amount, currency, err := redc.QueryTencentBalance(conf.Providers.Tencentcloud.SecretId, conf.Providers.Tencentcloud.SecretKey, conf.Providers.Tencentcloud.Region)
+				amount, currency, err := redc.QueryTencentBalance(ak, sk, token, conf.Providers.Tencentcloud.Region)
 				if err != nil {
 					logMsg := fmt.Sprintf("[GetBalances] %s error: %v", p, err)
 					log.Printf(logMsg)
@@ -682,10 +701,13 @@ func (a *App) GetBalances(providers []string) ([]BalanceInfo, error) {
 				}
 			}
 		case "aws":
-			if conf.Providers.Aws.AccessKey == "" || conf.Providers.Aws.SecretKey == "" {
+			ak, sk, token, err := redc.SessionCredentials(conf, p)
+			if err != nil {
+				result.Error = err.Error()
+			} else if ak == "" || sk == "" {
 				result.Error = i18n.T("app_cred_aws_missing")
 			} else {
-				amount, currency, err := redc.QueryAWSBill(conf.Providers.Aws.AccessKey, conf.Providers.Aws.SecretKey, conf.Providers.Aws.Region)
+				amount, currency, err := redc.QueryAWSBill(ak, sk, token, conf.Providers.Aws.Region)
 				if err != nil {
 					logMsg := fmt.Sprintf("[GetBalances] %s error: %v", p, err)
 					log.Printf(logMsg)
@@ -706,15 +728,16 @@ func (a *App) GetBalances(providers []string) ([]BalanceInfo, error) {
 	return results, nil
 }
 
+// GetBills queries the current month's bills; entries accept the same "provider:account" form as GetBalances.
 func (a *App) GetBills(providers []string) ([]BillInfo, error) {
-	if len(providers) == 0 {
-		providers = []string{"aws", "vultr"}
-	}
-
-	conf, _, err := redc.ReadConfig(redc.ActiveConfigPath)
+	allConf, _, err := redc.ReadConfig(redc.ActiveConfigPath)
 	if err != nil {
 		return nil, err
 	}
+	if len(providers) == 0 {
+		providers = []string{"aws", "vultr"}
+	}
+	providers = withAccounts(allConf, providers)
 
 	now := time.Now()
 	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
@@ -722,20 +745,31 @@ func (a *App) GetBills(providers []string) ([]BillInfo, error) {
 	month := now.Format("2006-01")
 
 	results := make([]BillInfo, 0, len(providers))
-	for _, p := range providers {
+	for _, spec := range providers {
+		p, account := redc.SplitProviderAccount(spec)
 		result := BillInfo{
 			Provider:  p,
+			Account:   account,
 			Month:     month,
 			StartDate: startDate,
 			EndDate:   endDate,
 		}
+		conf, err := allConf.ForAccount(p, account)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
 
 		switch p {
 		case "aws":
-			if conf.Providers.Aws.AccessKey == "" || conf.Providers.Aws.SecretKey == "" {
+			ak, sk, token, err := redc.SessionCredentials(conf, p)
+			if err != nil {
+				result.Error = err.Error()
+			} else if ak == "" || sk == "" {
 				result.Error = i18n.T("app_cred_aws_missing")
 			} else {
-				amount, currency, err := redc.QueryAWSBill(conf.Providers.Aws.AccessKey, conf.Providers.Aws.SecretKey, conf.Providers.Aws.Region)
+				amount, currency, err := redc.QueryAWSBill(ak, sk, token, conf.Providers.Aws.Region)
 				if err != nil {
 					result.Error = err.Error()
 				} else {
@@ -774,3 +808,18 @@ func (a *App) GetBills(providers []string) ([]BillInfo, error) {
 	}
 	return results, nil
 }
+
+// withAccounts appends a "provider:account" entry for every named account of the plain provider entries.
+func withAccounts(conf *redc.Config, providers []string) []string {
+	out := make([]string, 0, len(providers))
+	for _, p := range providers {
+		out = append(out, p)
+		if _, account := redc.SplitProviderAccount(p); account != "" {
+			continue
+		}
+		for _, account := range conf.AccountNames(p) {
+			out = append(out, p+":"+account)
+		}
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// reconcileOnStartup 启动时修正上次进程退出时卡在过渡状态的场景
+func (a *App) reconcileOnStartup() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("[WARN] %s\n", i18n.Tf("reconcile_failed", r))
+		}
+	}()
+	results, err := a.ReconcileCases()
+	if err != nil {
+		fmt.Printf("[WARN] %s\n", i18n.Tf("reconcile_failed", err))
+		return
+	}
+	if len(results) > 0 {
+		a.emitRefresh()
+	}
+}
+
+// ReconcileCases inspects cases stuck in starting / stopping / removing, corrects their
+// state from the actual terraform state and records the interrupted operation.
+func (a *App) ReconcileCases() ([]*redc.ReconcileResult, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+
+	results, err := project.ReconcileCases()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			a.logTimeline("scene", "scene_reconcile_failed", r.CaseID, r.CaseName, i18n.Tf("reconcile_case_failed", r.CaseName, r.Error), r.OldState, "error")
+		case r.Removed:
+			a.logTimeline("scene", "scene_reconciled", r.CaseID, r.CaseName, i18n.Tf("reconcile_removed", r.CaseName), r.OldState, "warning")
+		case r.NewState != "":
+			a.logTimeline("scene", "scene_reconciled", r.CaseID, r.CaseName,
+				i18n.Tf("reconcile_corrected", r.CaseName, r.OldState, r.NewState, r.Interrupt.Resources), r.Interrupt.Operation, "warning")
+		}
+	}
+	return results, nil
+}
+
+// ListInterruptedCases returns the interrupted operations waiting for resume or rollback.
+func (a *App) ListInterruptedCases() ([]*redc.InterruptedOp, error) {
+	a.mu.Lock()
+	project := a.project
+	a.mu.Unlock()
+	if project == nil {
+		return nil, fmt.Errorf("%s", i18n.T("app_project_not_loaded"))
+	}
+	return redc.ListInterruptedOps(project.ProjectName)
+}
+
+// ResumeInterruptedCase continues the interrupted operation of a case in the background.
+func (a *App) ResumeInterruptedCase(caseID string) error {
+	return a.runInterrupted(caseID, "resume", (*redc.Case).ResumeInterrupted, caller{})
+}
+
+// RollbackInterruptedCase undoes the interrupted operation of a case in the background.
+func (a *App) RollbackInterruptedCase(caseID string) error {
+	return a.runInterrupted(caseID, "rollback", (*redc.Case).RollbackInterrupted, caller{})
+}
+
+// DismissInterruptedCase forgets the interrupted operation and keeps the corrected state.
+func (a *App) DismissInterruptedCase(caseID string) error {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return err
+	}
+	if _, err := redc.GetInterruptedOp(c.Id); err != nil {
+		return err
+	}
+	if err := redc.ClearInterruptedOp(c.Id); err != nil {
+		return err
+	}
+	a.logTimeline("scene", "scene_interrupt_dismissed", c.Id, c.Name, i18n.Tf("reconcile_dismissed", c.Name), "", "info")
+	return nil
+}
+
+func (a *App) runInterrupted(caseID, action string, run func(*redc.Case) error, who caller) error {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return err
+	}
+	who.bind(c)
+	op, err := redc.GetInterruptedOp(c.Id)
+	if err != nil {
+		return err
+	}
+	if a.pluginMgr != nil {
+		a.setupPluginHooks(c)
+	}
+
+	a.emitLog(i18n.Tf("reconcile_action_start", action, op.Operation, c.Name))
+	go func() {
+		a.activeOps.Add(1)
+		defer a.activeOps.Add(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				a.emitLog(i18n.Tf("reconcile_action_failed", action, c.Name, r))
+			}
+			a.emitRefresh()
+		}()
+
+		if err := run(c); err != nil {
+			a.emitLog(i18n.Tf("reconcile_action_failed", action, c.Name, err))
+			a.logTimeline("scene", "scene_interrupt_"+action+"_failed", c.Id, c.Name, i18n.Tf("reconcile_action_failed", action, c.Name, err), op.Operation, "error")
+			if a.notificationMgr != nil {
+				a.notificationMgr.SendSceneFailed(c.Name, action)
+			}
+			return
+		}
+		a.emitLog(i18n.Tf("reconcile_action_success", action, op.Operation, c.Name))
+		a.logTimeline("scene", "scene_interrupt_"+action, c.Id, c.Name, i18n.Tf("reconcile_action_success", action, op.Operation, c.Name), op.Operation, "success")
+	}()
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+)
+
+// SaveCasePlan runs terraform plan for a case and keeps the plan file, its hash
+// and the state serial, so that exactly this plan can be applied later.
+func (a *App) SaveCasePlan(caseID string) (*redc.SavedPlan, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if a.pluginMgr != nil {
+		a.setupPluginHooks(c)
+	}
+	return c.SavePlan(redc.U)
+}
+
+// ListCasePlans returns the saved plans of a case, newest first.
+func (a *App) ListCasePlans(caseID string) ([]*redc.SavedPlan, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListSavedPlans()
+}
+
+// GetSavedPlanPreview returns structured preview data for a saved plan.
+func (a *App) GetSavedPlanPreview(caseID string, planID string) (*PlanPreview, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.GetSavedPlan(planID)
+	if err != nil {
+		return nil, err
+	}
+	return buildPlanPreviewFile(c.Path, p.File)
+}
+
+// StartCaseWithPlan applies a saved plan file as-is. It refuses when the plan file
+// hash does not match or the state serial changed since the plan was saved.
+func (a *App) StartCaseWithPlan(caseID string, planID string) error {
+	return a.startCaseWithPlan(caseID, planID, caller{})
+}
+
+// startCaseWithPlan applies a saved plan on behalf of who.
+func (a *App) startCaseWithPlan(caseID string, planID string, who caller) error {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return err
+	}
+	who.bind(c)
+	if a.pluginMgr != nil {
+		a.setupPluginHooks(c)
+	}
+
+	a.emitLog(i18n.Tf("app_plan_apply_start", planID, c.Name))
+	go func() {
+		a.activeOps.Add(1)
+		defer a.activeOps.Add(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				a.emitLog(i18n.Tf("app_plan_apply_failed", planID, c.Name, r))
+			}
+			a.emitRefresh()
+		}()
+
+		if err := c.ApplySavedPlan(planID, who.user()); err != nil {
+			a.emitLog(i18n.Tf("app_plan_apply_failed", planID, c.Name, err))
+			a.logTimeline("scene", "scene_error", c.Id, c.Name, i18n.Tf("app_plan_apply_failed", planID, c.Name, err), "", "error")
+			if a.notificationMgr != nil {
+				a.notificationMgr.SendSceneFailed(c.Name, "启动")
+			}
+			return
+		}
+		a.emitLog(i18n.Tf("app_plan_apply_success", planID, c.Name))
+		a.logTimeline("scene", "scene_started", c.Id, c.Name, i18n.Tf("app_plan_apply_success", planID, c.Name), planID, "success")
+		if a.notificationMgr != nil {
+			a.notificationMgr.SendSceneStarted(c.Name)
+		}
+	}()
+	return nil
+}
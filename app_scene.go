@@ -11,6 +11,7 @@ import (
 	"red-cloud/i18n"
 	redc "red-cloud/mod"
 	"red-cloud/mod/plugin"
+	"red-cloud/mod/secret"
 )
 
 // parseTfvars parses a terraform.tfvars file
@@ -40,8 +41,32 @@ func parseTfvars(filePath string) (map[string]string, error) {
 	return defaults, scanner.Err()
 }
 
+// caller is who triggered an operation: the authenticated user for HTTP requests, the
+// scheduler for timed tasks. Empty fields fall back to the current user and LockOwner.
+type caller struct {
+	actor, source string
+}
+
+// bind records the caller on the state transitions and lock of c.
+func (w caller) bind(c *redc.Case) {
+	c.SetActor(w.actor, w.source)
+}
+
+// user is the operator recorded on created cases and in change history.
+func (w caller) user() string {
+	if w.actor != "" {
+		return w.actor
+	}
+	return redc.U
+}
+
 // StartCase starts a case by ID
 func (a *App) StartCase(caseID string) error {
+	return a.startCase(caseID, caller{})
+}
+
+// startCase starts a case on behalf of who.
+func (a *App) startCase(caseID string, who caller) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -61,6 +86,7 @@ func (a *App) StartCase(caseID string) error {
 	if c.Path == "" {
 		return fmt.Errorf("%s", i18n.T("app_case_path_empty"))
 	}
+	who.bind(c)
 
 	// Wire plugin hooks
 	if a.pluginMgr != nil {
@@ -111,6 +137,11 @@ func (a *App) StartCase(caseID string) error {
 
 // StopCase stops a case by ID
 func (a *App) StopCase(caseID string) error {
+	return a.stopCase(caseID, caller{})
+}
+
+// stopCase stops a case on behalf of who.
+func (a *App) stopCase(caseID string, who caller) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -122,6 +153,7 @@ func (a *App) StopCase(caseID string) error {
 	if err != nil {
 		return err
 	}
+	who.bind(c)
 
 	// Wire plugin hooks
 	if a.pluginMgr != nil {
@@ -160,6 +192,11 @@ func (a *App) StopCase(caseID string) error {
 
 // RemoveCase removes a case by ID
 func (a *App) RemoveCase(caseID string) error {
+	return a.removeCase(caseID, caller{})
+}
+
+// removeCase removes a case on behalf of who.
+func (a *App) removeCase(caseID string, who caller) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -171,6 +208,7 @@ func (a *App) RemoveCase(caseID string) error {
 	if err != nil {
 		return err
 	}
+	who.bind(c)
 
 	go func() {
 		a.activeOps.Add(1)
@@ -196,8 +234,18 @@ func (a *App) RemoveCase(caseID string) error {
 	return nil
 }
 
-// CreateCase creates a new case from a template (async)
-func (a *App) CreateCase(templateName string, name string, vars map[string]string) error {
+// CreateCase creates a new case from a template (async).
+// ttl (e.g. "8h", "2d") schedules automatic teardown; empty means no TTL.
+func (a *App) CreateCase(templateName string, name string, vars map[string]string, ttl string) error {
+	return a.createCase(templateName, name, vars, ttl, caller{})
+}
+
+// createCase creates a case on behalf of who.
+func (a *App) createCase(templateName string, name string, vars map[string]string, ttl string, who caller) error {
+	d, err := redc.ParseTTL(ttl)
+	if err != nil {
+		return err
+	}
 	a.mu.Lock()
 	if a.project == nil {
 		a.mu.Unlock()
@@ -217,19 +265,30 @@ func (a *App) CreateCase(templateName string, name string, vars map[string]strin
 		}()
 
 		a.emitLog(i18n.Tf("app_scene_initing", name, templateName))
-		c, err := project.CaseCreate(templateName, redc.U, name, vars)
+		c, err := project.CaseCreate(templateName, who.user(), name, vars)
 		if err != nil {
 			a.emitLog(i18n.Tf("app_scene_create_failed", err))
 			return
 		}
 		a.emitLog(i18n.Tf("app_scene_create_success", c.Name, c.GetId()))
+		a.applyCreateTTL(c, d)
 	}()
 
 	return nil
 }
 
-// CreateAndRunCase creates a new case and immediately starts it (like CLI "run" command)
-func (a *App) CreateAndRunCase(templateName string, name string, vars map[string]string) error {
+// CreateAndRunCase creates a new case and immediately starts it (like CLI "run" command).
+// ttl (e.g. "8h", "2d") schedules automatic teardown; empty means no TTL.
+func (a *App) CreateAndRunCase(templateName string, name string, vars map[string]string, ttl string) error {
+	return a.createAndRunCase(templateName, name, vars, ttl, caller{})
+}
+
+// createAndRunCase creates and starts a case on behalf of who.
+func (a *App) createAndRunCase(templateName string, name string, vars map[string]string, ttl string, who caller) error {
+	d, err := redc.ParseTTL(ttl)
+	if err != nil {
+		return err
+	}
 	a.mu.Lock()
 	if a.project == nil {
 		a.mu.Unlock()
@@ -251,13 +310,15 @@ func (a *App) CreateAndRunCase(templateName string, name string, vars map[string
 		}()
 
 		a.emitLog(i18n.Tf("app_scene_initing", name, templateName))
-		c, err := project.CaseCreate(templateName, redc.U, name, vars)
+		c, err := project.CaseCreate(templateName, who.user(), name, vars)
 		if err != nil {
 			a.emitLog(i18n.Tf("app_scene_create_failed", err))
 			return
 		}
 		a.emitLog(i18n.Tf("app_scene_create_success", c.Name, c.GetId()))
 		a.logTimeline("scene", "scene_created", c.GetId(), c.Name, i18n.Tf("app_scene_create_success", c.Name, c.GetId()), "", "info")
+		a.applyCreateTTL(c, d)
+		who.bind(c)
 
 		// Wire plugin hooks
 		if a.pluginMgr != nil {
@@ -289,7 +350,7 @@ func (a *App) CreateAndRunCase(templateName string, name string, vars map[string
 
 // DeployCase creates and immediately starts a case (deprecated - use CreateCase then StartCase)
 func (a *App) DeployCase(templateName string, name string, vars map[string]string) error {
-	return a.CreateCase(templateName, name, vars)
+	return a.CreateCase(templateName, name, vars, "")
 }
 
 // PlanResourceChange represents a single resource change in the plan
@@ -371,7 +432,12 @@ func (a *App) GetDeploymentPlanPreview(deploymentID string) (*PlanPreview, error
 
 // buildPlanPreview builds plan preview data from a terraform working directory
 func buildPlanPreview(workDir string) (*PlanPreview, error) {
-	planFile := filepath.Join(workDir, redc.RedcPlanPath)
+	return buildPlanPreviewFile(workDir, redc.RedcPlanPath)
+}
+
+// buildPlanPreviewFile builds plan preview data from a plan file relative to workDir
+func buildPlanPreviewFile(workDir, planPath string) (*PlanPreview, error) {
+	planFile := filepath.Join(workDir, planPath)
 	if _, err := os.Stat(planFile); err != nil {
 		return nil, fmt.Errorf("plan file not found")
 	}
@@ -390,10 +456,11 @@ func buildPlanPreview(workDir string) (*PlanPreview, error) {
 		TypeSummary: []PlanTypeSummary{},
 	}
 
-	resourceChanges, err := te.GetPlanResourceChanges(ctx)
+	plan, err := te.ShowPlanFile(ctx, planPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse plan: %w", err)
 	}
+	resourceChanges := plan.ResourceChanges
 
 	if resourceChanges == nil || len(resourceChanges) == 0 {
 		return preview, nil
@@ -716,7 +783,8 @@ func normalizeDOTNode(name string) string {
 	return name
 }
 
-// GetCaseOutputs returns the terraform outputs for a case
+// GetCaseOutputs returns the terraform outputs for a case. Sensitive outputs are
+// masked; use RevealCaseOutput to read one in clear text.
 func (a *App) GetCaseOutputs(caseID string) (map[string]string, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -751,12 +819,19 @@ func (a *App) GetCaseOutputs(caseID string) (map[string]string, error) {
 				value = absPath
 			}
 		}
+		if redc.IsSensitiveOutput(name, meta.Sensitive) {
+			value = secret.Mask
+		}
 		result[name] = value
 	}
 
 	// Merge plugin hook outputs
 	if pluginOutputs := plugin.LoadPluginOutputs(c.Path); pluginOutputs != nil {
 		for k, v := range pluginOutputs {
+			if secret.IsSensitiveName(k) {
+				secret.Register(v)
+				v = secret.Mask
+			}
 			result[k] = v
 		}
 	}
@@ -764,6 +839,23 @@ func (a *App) GetCaseOutputs(caseID string) (map[string]string, error) {
 	return result, nil
 }
 
+// RevealCaseOutput returns the clear-text value of a single case output, including
+// sensitive ones masked by GetCaseOutputs. Over HTTP it requires the operator role.
+func (a *App) RevealCaseOutput(caseID string, name string) (string, error) {
+	c, err := a.getCase(caseID)
+	if err != nil {
+		return "", err
+	}
+	value, ok := plugin.LoadPluginOutputs(c.Path)[name]
+	if !ok {
+		if value, err = c.RevealOutput(name); err != nil {
+			return "", err
+		}
+	}
+	a.logTimeline("scene", "secret_revealed", c.Id, c.Name, i18n.Tf("secret_revealed", name, c.Name), "", "info")
+	return value, nil
+}
+
 // isRelativeFilePath checks if the value looks like a relative file path
 func isRelativeFilePath(value string) bool {
 	if value == "" {
@@ -777,6 +869,11 @@ func isRelativeFilePath(value string) bool {
 
 // CloneCase clones an existing predefined case by re-creating it from the same template
 func (a *App) CloneCase(caseID string, cloneName string) error {
+	return a.cloneCase(caseID, cloneName, caller{})
+}
+
+// cloneCase clones a case on behalf of who.
+func (a *App) cloneCase(caseID string, cloneName string, who caller) error {
 	a.mu.Lock()
 	if a.project == nil {
 		a.mu.Unlock()
@@ -808,6 +905,9 @@ func (a *App) CloneCase(caseID string, cloneName string) error {
 			vars[p[:idx]] = p[idx+1:]
 		}
 	}
+	if source.Account != "" {
+		vars[redc.AccountVar] = source.Account
+	}
 
 	if cloneName == "" {
 		cloneName = source.Name + "-clone"
@@ -822,7 +922,7 @@ func (a *App) CloneCase(caseID string, cloneName string) error {
 			a.emitRefresh()
 		}()
 
-		c, err := project.CaseCreate(source.Type, redc.U, cloneName, vars)
+		c, err := project.CaseCreate(source.Type, who.user(), cloneName, vars)
 		if err != nil {
 			a.emitLog(i18n.Tf("app_clone_failed", err))
 			return
@@ -250,8 +250,22 @@ func (m *SpotMonitor) isAutoRecoverEnabled() bool {
 	return settings.SpotAutoRecoverEnabled
 }
 
+// recoverFailed reports a recovery that failed before any resources were changed.
+func (m *SpotMonitor) recoverFailed(c *redc.Case, err error) {
+	m.app.emitLog(fmt.Sprintf("❌ %s", i18n.Tf("app_spot_recover_failed", c.Name, err)))
+	m.app.logTimeline("spot", "spot_recover_failed", c.Id, c.Name, i18n.Tf("app_spot_recover_failed", c.Name, err), "", "error")
+	if m.app.notificationMgr != nil {
+		m.app.notificationMgr.SendSpotRecoverFailed(c.Name)
+	}
+	m.app.emitEvent("spot-recover-failed", map[string]interface{}{
+		"caseId":   c.Id,
+		"caseName": c.Name,
+		"error":    err.Error(),
+	})
+}
+
 // attemptRecover runs terraform plan+apply to replenish terminated spot instances.
-// Uses low-level TfPlan/TfApply directly to bypass the state==running check in Case.TfApply().
+// Uses low-level TfPlan/TfApplyPlanFile directly to bypass the state==running check in Case.TfApply().
 func (m *SpotMonitor) attemptRecover(c *redc.Case, downIPs []string) {
 	m.app.emitLog(fmt.Sprintf("🔄 %s", i18n.Tf("app_spot_recovering", c.Name)))
 	m.app.logTimeline("spot", "spot_recovering", c.Id, c.Name, i18n.Tf("app_spot_recovering", c.Name), "", "info")
@@ -262,30 +276,36 @@ func (m *SpotMonitor) attemptRecover(c *redc.Case, downIPs []string) {
 		"caseName": c.Name,
 	})
 
+	// Hold the case lock for the whole recovery so user actions on the case cannot run concurrently
+	release, err := c.AcquireLock("spot-recover")
+	if err != nil {
+		m.recoverFailed(c, err)
+		return
+	}
+	defer release()
+
 	// Run terraform plan + apply directly (bypass Case.TfApply which rejects running state)
 	if err := redc.TfPlan(c.Path, c.Parameter...); err != nil {
-		m.app.emitLog(fmt.Sprintf("❌ %s", i18n.Tf("app_spot_recover_failed", c.Name, err)))
-		m.app.logTimeline("spot", "spot_recover_failed", c.Id, c.Name, i18n.Tf("app_spot_recover_failed", c.Name, err), "", "error")
-		if m.app.notificationMgr != nil {
-			m.app.notificationMgr.SendSpotRecoverFailed(c.Name)
-		}
-		m.app.emitEvent( "spot-recover-failed", map[string]interface{}{
-			"caseId":   c.Id,
-			"caseName": c.Name,
-			"error":    err.Error(),
-		})
+		m.recoverFailed(c, err)
+		return
+	}
+
+	// Recovery creates instances like any other apply, so the deployment policy gates it too
+	if _, err := c.CheckPolicy(); err != nil {
+		m.recoverFailed(c, err)
 		return
 	}
 
-	if err := redc.TfApply(c.Path, c.Parameter...); err != nil {
+	// Apply exactly the plan that passed the policy check
+	if err := redc.TfApplyPlanFile(c.Path, redc.RedcPlanPath); err != nil {
 		m.app.emitLog(fmt.Sprintf("❌ %s", i18n.Tf("app_spot_recover_failed", c.Name, err)))
 		m.app.logTimeline("spot", "spot_recover_failed", c.Id, c.Name, i18n.Tf("app_spot_recover_failed", c.Name, err), "", "error")
 		// Rollback: destroy partially created resources to avoid orphaned instances
 		m.app.emitLog(fmt.Sprintf("🧹 %s", i18n.Tf("app_spot_recover_rollback", c.Name)))
-		if destroyErr := redc.TfDestroy(c.Path, c.Parameter); destroyErr != nil {
+		// The lock is already held above, so use the Locked variant; it moves the case through stopping -> stopped
+		if destroyErr := c.TfDestroyLocked(); destroyErr != nil {
 			m.app.emitLog(fmt.Sprintf("❌ %s", i18n.Tf("app_spot_recover_rollback_failed", c.Name, destroyErr)))
 		} else {
-			c.StatusChange(redc.StateStopped)
 			m.app.emitLog(fmt.Sprintf("✅ %s", i18n.Tf("app_spot_recover_rollback_done", c.Name)))
 		}
 		if m.app.notificationMgr != nil {
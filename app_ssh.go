@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"red-cloud/i18n"
+	redc "red-cloud/mod"
 	"red-cloud/utils/sshutil"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -87,7 +88,9 @@ func (a *App) ExecCommand(caseID string, command string) ExecCommandResult {
 			return result
 		}
 
-		return a.execSSHCommand(sshConfig, command)
+		result = a.execSSHCommand(sshConfig, command)
+		recordSSHOp(c, command, result)
+		return result
 	}
 
 	if service != nil {
@@ -105,6 +108,15 @@ func (a *App) ExecCommand(caseID string, command string) ExecCommandResult {
 	return result
 }
 
+// recordSSHOp 将 SSH 命令输出写入场景操作日志
+func recordSSHOp(c *redc.Case, command string, result ExecCommandResult) {
+	var opErr error
+	if !result.Success {
+		opErr = fmt.Errorf("exit code %d %s", result.ExitCode, result.Error)
+	}
+	redc.RecordCaseOp(c, redc.OpKindSSH, command, result.Stdout+result.Stderr, opErr)
+}
+
 // execSSHCommand 执行 SSH 命令的通用方法
 func (a *App) execSSHCommand(sshConfig *sshutil.SSHConfig, command string) ExecCommandResult {
 	result := ExecCommandResult{}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,12 +9,17 @@ import (
 	redc "red-cloud/mod"
 	"red-cloud/mod/gologger"
 	"red-cloud/mod/plugin"
+	"red-cloud/mod/secret"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
 
-var changeConfig redc.ChangeCommand
+var (
+	changeConfig redc.ChangeCommand
+	startPlanID  string
+)
 
 // helper: 通用的执行器
 func runAction(actionType string, caseID string) {
@@ -34,17 +40,25 @@ func runAction(actionType string, caseID string) {
 	case "stop":
 		actionErr = c.Stop()
 	case "start":
-		actionErr = c.TfApply()
+		if startPlanID != "" {
+			actionErr = c.ApplySavedPlan(startPlanID, redc.U)
+		} else {
+			actionErr = c.TfApply()
+		}
 	case "kill":
 		actionErr = c.Kill()
 	case "change":
+		changeConfig.Operator = redc.U
+		if !IsJSON() {
+			changeConfig.Confirm = confirmChange
+		}
 		actionErr = c.Change(changeConfig)
 	case "status":
 		if IsJSON() {
 			runStatusJSON(c)
 			return
 		}
-		actionErr = c.Status()
+		actionErr = c.Status(revealSecrets)
 	case "rm":
 		actionErr = c.Remove()
 	}
@@ -69,6 +83,18 @@ func runAction(actionType string, caseID string) {
 	}
 }
 
+// confirmChange 展示资源变更并等待用户确认
+func confirmChange(plan *redc.ChangePlan) bool {
+	fmt.Println()
+	for _, rc := range plan.Changes {
+		fmt.Printf("  %-8s %s\n", rc.Action, rc.Address)
+	}
+	fmt.Printf("\n%s\n", i18n.Tf("change_confirm_prompt", plan.Summary()))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 // runStatusJSON outputs case status as JSON
 func runStatusJSON(c *redc.Case) {
 	result := map[string]interface{}{
@@ -84,6 +110,10 @@ func runStatusJSON(c *redc.Case) {
 	if state.Values != nil {
 		outputs := map[string]interface{}{}
 		for k, v := range state.Values.Outputs {
+			if !revealSecrets && redc.IsSensitiveOutput(k, v.Sensitive) {
+				outputs[k] = secret.Mask
+				continue
+			}
 			outputs[k] = v.Value
 		}
 		result["outputs"] = outputs
@@ -201,6 +231,9 @@ var statusCmd = &cobra.Command{
 var changeCmd = &cobra.Command{
 	Use:   "change [id]",
 	Short: i18n.T("change_short"),
+	Example: `redc change ecs-demo -e node=3
+redc change ecs-demo --replace 'alicloud_instance.redirector'
+redc change ecs-demo --target 'alicloud_instance.node[2]' --destroy`,
 	Run: func(cmd *cobra.Command, args []string) {
 		runAction("change", args[0])
 	},
@@ -256,4 +289,11 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(rmCmd)
 	changeCmd.Flags().BoolVar(&changeConfig.IsRemove, "rm", false, i18n.T("flag_change_rm"))
+	changeCmd.Flags().StringToStringVarP(&changeConfig.Pars, "env", "e", nil, i18n.T("flag_plan_env"))
+	changeCmd.Flags().BoolVarP(&changeConfig.AutoApprove, "yes", "y", false, i18n.T("flag_change_yes"))
+	changeCmd.Flags().StringArrayVar(&changeConfig.Target.Targets, "target", nil, i18n.T("flag_change_target"))
+	changeCmd.Flags().StringArrayVar(&changeConfig.Target.Replace, "replace", nil, i18n.T("flag_change_replace"))
+	changeCmd.Flags().BoolVar(&changeConfig.Target.Destroy, "destroy", false, i18n.T("flag_change_destroy"))
+	startCmd.Flags().StringVar(&startPlanID, "plan", "", i18n.T("flag_start_plan"))
+	statusCmd.Flags().BoolVar(&revealSecrets, "reveal", false, i18n.T("flag_status_reveal"))
 }
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	adoptImports     []string
+	adoptFinish      bool
+	adoptAutoApprove bool
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [template_name | case_id]",
+	Short: i18n.T("adopt_short"),
+	Long:  i18n.T("adopt_long"),
+	Example: `redc adopt aliyun/ecs -n legacy-vps --import alicloud_instance.instance=i-bp1abc
+redc adopt --finish legacy-vps -y`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			c    *redc.Case
+			plan *redc.ChangePlan
+			err  error
+		)
+		if adoptFinish {
+			c, err = redcProject.GetCase(args[0])
+			if err != nil {
+				err = fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			} else {
+				plan, err = c.AdoptPlan()
+			}
+		} else {
+			var targets []redc.ImportTarget
+			if targets, err = redc.ParseImportTargets(adoptImports); err == nil {
+				c, plan, err = redcProject.AdoptCase(args[0], userName, projectName, envVars, targets)
+			}
+		}
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("adopt_failed", err))
+			return
+		}
+
+		// 有差异时需要确认 (交互或 -y)；JSON 模式下未指定 -y 只输出差异
+		apply := !plan.HasChanges() || adoptAutoApprove
+		if !apply && !IsJSON() {
+			apply = confirmChange(plan)
+		}
+		if apply {
+			err = c.FinishAdoption(plan, true, redc.U)
+		}
+
+		if IsJSON() {
+			result := map[string]interface{}{
+				"action": "adopt",
+				"id":     c.Id,
+				"name":   c.Name,
+				"state":  c.State,
+				"plan":   plan,
+			}
+			if err != nil {
+				result["error"] = err.Error()
+			}
+			PrintJSON(result)
+			return
+		}
+		switch {
+		case err != nil:
+			gologger.Error().Msgf("%s", i18n.Tf("adopt_failed", err))
+		case !apply:
+			gologger.Info().Msgf("%s", i18n.Tf("adopt_left_pending", c.Name, c.Id))
+		default:
+			gologger.Info().Msgf("%s", i18n.Tf("action_success", "adopt", c.Name, c.Id))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+	adoptCmd.Flags().StringVarP(&userName, "user", "u", "system", i18n.T("flag_plan_user"))
+	adoptCmd.Flags().StringVarP(&projectName, "name", "n", "", i18n.T("flag_plan_name"))
+	adoptCmd.Flags().StringToStringVarP(&envVars, "env", "e", nil, i18n.T("flag_plan_env"))
+	adoptCmd.Flags().StringArrayVar(&adoptImports, "import", nil, i18n.T("flag_adopt_import"))
+	adoptCmd.Flags().BoolVar(&adoptFinish, "finish", false, i18n.T("flag_adopt_finish"))
+	adoptCmd.Flags().BoolVarP(&adoptAutoApprove, "yes", "y", false, i18n.T("flag_adopt_yes"))
+}
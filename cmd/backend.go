@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var backendCmd = &cobra.Command{
+	Use:   "backend",
+	Short: i18n.T("backend_short"),
+}
+
+var backendShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: i18n.T("backend_show_short"),
+	Run: func(cmd *cobra.Command, args []string) {
+		b := redc.GetProjectBackend(redc.Project)
+		if b == nil {
+			if IsJSON() {
+				PrintJSON(map[string]string{"project": redc.Project, "type": "local"})
+				return
+			}
+			gologger.Info().Msgf("%s", i18n.Tf("backend_local", redc.Project))
+			return
+		}
+		// 不输出凭据
+		masked := *b
+		masked.AccessKey, masked.SecretKey, masked.Password = maskSecret(b.AccessKey), maskSecret(b.SecretKey), maskSecret(b.Password)
+		if IsJSON() {
+			PrintJSON(masked)
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "project\t%s\n", redc.Project)
+		fmt.Fprintf(w, "type\t%s\n", masked.Type)
+		switch masked.Type {
+		case redc.BackendS3:
+			fmt.Fprintf(w, "bucket\t%s\n", masked.Bucket)
+			fmt.Fprintf(w, "region\t%s\n", masked.Region)
+			fmt.Fprintf(w, "endpoint\t%s\n", masked.Endpoint)
+			fmt.Fprintf(w, "key_prefix\t%s\n", masked.KeyPrefix)
+			fmt.Fprintf(w, "access_key\t%s\n", masked.AccessKey)
+		case redc.BackendHTTP:
+			fmt.Fprintf(w, "address\t%s\n", masked.Address)
+			fmt.Fprintf(w, "lock_address\t%s\n", masked.LockAddress)
+			fmt.Fprintf(w, "username\t%s\n", masked.Username)
+		}
+		w.Flush()
+	},
+}
+
+var backendMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: i18n.T("backend_migrate_short"),
+	Long:  i18n.T("backend_migrate_long"),
+	Run: func(cmd *cobra.Command, args []string) {
+		results, err := redcProject.MigrateStateToBackend()
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(results)
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "CASE ID\tNAME\tSTATUS\tERROR")
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", shortID(r.CaseID), r.CaseName, r.Status, r.Error)
+		}
+		w.Flush()
+	},
+}
+
+// maskSecret 只保留前 4 位
+func maskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 4 {
+		return "****"
+	}
+	return s[:4] + "****"
+}
+
+func init() {
+	rootCmd.AddCommand(backendCmd)
+	backendCmd.AddCommand(backendShowCmd)
+	backendCmd.AddCommand(backendMigrateCmd)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOutput     string
+	bundlePassphrase string
+	importSkipInit   bool
+	importVerifyOnly bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [id]",
+	Short: i18n.T("export_short"),
+	Long:  i18n.T("export_long"),
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := redcProject.GetCase(args[0])
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			return
+		}
+		dest := exportOutput
+		if dest == "" {
+			dest = fmt.Sprintf("%s-%s%s", c.Name, shortID(c.Id), redc.BundleExt)
+		}
+		manifest, err := c.Export(dest, redc.ExportOptions{Passphrase: resolveBundlePassphrase()})
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]interface{}{"file": dest, "manifest": manifest})
+		}
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: i18n.T("import_short"),
+	Long:  i18n.T("import_long"),
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if importVerifyOnly {
+			manifest, err := redc.ReadBundleManifest(args[0])
+			if err != nil {
+				if IsJSON() {
+					PrintJSONError(err)
+					return
+				}
+				gologger.Error().Msgf("%s", err.Error())
+				return
+			}
+			if IsJSON() {
+				PrintJSON(manifest)
+				return
+			}
+			printBundleManifest(manifest)
+			return
+		}
+		c, err := redcProject.ImportCaseBundle(args[0], redc.ImportOptions{
+			Passphrase: resolveBundlePassphrase(),
+			SkipInit:   importSkipInit,
+		})
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]string{"id": c.Id, "name": c.Name, "type": c.Type, "state": c.State, "project": c.ProjectID})
+		}
+	},
+}
+
+// resolveBundlePassphrase 优先使用命令行参数，其次环境变量
+func resolveBundlePassphrase() string {
+	if bundlePassphrase != "" {
+		return bundlePassphrase
+	}
+	return os.Getenv("REDC_BUNDLE_PASSPHRASE")
+}
+
+func printBundleManifest(m *redc.BundleManifest) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "case\t%s (%s)\n", m.CaseName, m.CaseID)
+	fmt.Fprintf(w, "template\t%s\n", m.Template)
+	fmt.Fprintf(w, "project\t%s\n", m.Project)
+	fmt.Fprintf(w, "state\t%s\n", m.State)
+	fmt.Fprintf(w, "exported\t%s by %s (redc %s)\n", m.ExportedAt.Format("2006-01-02 15:04:05"), m.ExportedBy, m.RedcVersion)
+	fmt.Fprintf(w, "encrypted\t%v\n", m.Encrypted)
+	w.Flush()
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "\nPATH\tSIZE\tSHA256\tENCRYPTED")
+	for _, f := range m.Files {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%v\n", f.Path, f.Size, f.SHA256[:12], f.Encrypted)
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	exportCmd.Flags().StringVarP(&exportOutput, "file", "f", "", i18n.T("export_flag_output"))
+	exportCmd.Flags().StringVar(&bundlePassphrase, "passphrase", "", i18n.T("bundle_flag_passphrase"))
+	importCmd.Flags().StringVar(&bundlePassphrase, "passphrase", "", i18n.T("bundle_flag_passphrase"))
+	importCmd.Flags().BoolVar(&importSkipInit, "skip-init", false, i18n.T("import_flag_skip_init"))
+	importCmd.Flags().BoolVar(&importVerifyOnly, "verify", false, i18n.T("import_flag_verify"))
+}
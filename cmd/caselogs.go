@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	caseLogsOp     int
+	caseLogsFollow bool
+	caseLogsList   bool
+)
+
+var caseLogsCmd = &cobra.Command{
+	Use:   "logs [id]",
+	Short: i18n.T("case_logs_short"),
+	Long:  i18n.T("case_logs_long"),
+	Example: `redc logs ecs-demo --list
+redc logs ecs-demo --op 3
+redc logs ecs-demo -f`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := redcProject.GetCase(args[0])
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			return
+		}
+		if caseLogsList {
+			listCaseOps(c)
+			return
+		}
+
+		chunk, err := redc.ReadCaseOpLog(c, caseLogsOp, 0, 0)
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(chunk)
+			return
+		}
+		op := chunk.Op
+		fmt.Printf("# op %d  %s/%s  %s  %s\n", op.ID, op.Kind, op.Operation, op.Owner, op.StartedAt.Format("2006-01-02 15:04:05"))
+		fmt.Print(chunk.Data)
+		// 跟随进行中的操作，直到结束
+		for caseLogsFollow && !chunk.Op.Done() {
+			time.Sleep(500 * time.Millisecond)
+			next, err := redc.ReadCaseOpLog(c, op.ID, chunk.Offset, 0)
+			if err != nil {
+				gologger.Error().Msgf("%s", err.Error())
+				return
+			}
+			fmt.Print(next.Data)
+			chunk = next
+		}
+		if chunk.Op.Done() {
+			fmt.Printf("# %s", chunk.Op.Status)
+			if chunk.Op.Error != "" {
+				fmt.Printf(": %s", chunk.Op.Error)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// listCaseOps 列出场景的操作记录
+func listCaseOps(c *redc.Case) {
+	ops, err := redc.ListCaseOps(c)
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return
+		}
+		gologger.Error().Msgf("%s", err.Error())
+		return
+	}
+	if IsJSON() {
+		PrintJSON(ops)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "OP\tKIND\tOPERATION\tOWNER\tSTARTED\tDURATION\tSTATUS")
+	for _, op := range ops {
+		duration := "-"
+		if op.Done() {
+			duration = op.EndedAt.Sub(op.StartedAt).Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%.40s\t%s\t%s\t%s\t%s\n", op.ID, op.Kind, op.Operation, op.Owner,
+			op.StartedAt.Format("2006-01-02 15:04:05"), duration, op.Status)
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(caseLogsCmd)
+	caseLogsCmd.Flags().IntVar(&caseLogsOp, "op", 0, i18n.T("flag_case_logs_op"))
+	caseLogsCmd.Flags().BoolVarP(&caseLogsFollow, "follow", "f", false, i18n.T("flag_logs_follow"))
+	caseLogsCmd.Flags().BoolVar(&caseLogsList, "list", false, i18n.T("flag_case_logs_list"))
+}
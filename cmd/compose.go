@@ -1,16 +1,32 @@
 package cmd
 
 import (
+	"bufio"
+	"fmt"
+	"os"
 	"red-cloud/i18n"
+	redc "red-cloud/mod"
 	"red-cloud/mod/compose"
 	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	composeFile string
-	profiles    []string
+	composeFile     string
+	composeStack    string
+	profiles        []string
+	parallelism     int
+	providerLimits  map[string]int
+	continueOnError bool
+	onFailure       string
+	resolvedConfig  bool
 )
 
 var composeCmd = &cobra.Command{
@@ -19,13 +35,28 @@ var composeCmd = &cobra.Command{
 }
 
 var upCmd = &cobra.Command{
-	Use:   "up",
+	Use:   "up [service...]",
 	Short: i18n.T("compose_up_short"),
 	Run: func(cmd *cobra.Command, args []string) {
 		opts := compose.ComposeOptions{
-			File:     composeFile,
-			Profiles: profiles,
-			Project:  redcProject,
+			File:            composeFile,
+			Stack:           composeStack,
+			Services:        args,
+			Profiles:        profiles,
+			Project:         redcProject,
+			Parallelism:     parallelism,
+			ProviderLimits:  providerLimits,
+			ContinueOnError: continueOnError,
+			OnFailure:       onFailure,
+		}
+		if !IsJSON() && term.IsTerminal(int(os.Stdin.Fd())) {
+			opts.OnPause = confirmRollback
+		}
+		if ts, err := redc.NewTimelineStore(); err != nil {
+			gologger.Warning().Msgf("timeline store init failed: %v", err)
+		} else {
+			defer ts.Close()
+			opts.Timeline = ts
 		}
 
 		if err := compose.RunComposeUp(opts); err != nil {
@@ -44,14 +75,34 @@ var upCmd = &cobra.Command{
 	},
 }
 
+// confirmRollback on_failure=pause 时询问是否回滚本次创建的场景
+func confirmRollback(cause error) bool {
+	fmt.Printf("\n%s\n", i18n.T("compose_pause_prompt"))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 var downCmd = &cobra.Command{
-	Use:   "down",
+	Use:   "down [stack | service...]",
 	Short: i18n.T("compose_down_short"),
 	Run: func(cmd *cobra.Command, args []string) {
+		// 单个参数与已有编排记录同名时按编排销毁，否则视为要销毁的服务
+		stack, services := composeStack, args
+		if stack == "" && len(args) == 1 {
+			if _, err := redc.GetComposeStack(redcProject.ProjectName, args[0]); err == nil {
+				stack, services = args[0], nil
+			}
+		}
 		opts := compose.ComposeOptions{
-			File:     composeFile,
-			Profiles: profiles,
-			Project:  redcProject,
+			File:            composeFile,
+			Stack:           stack,
+			Services:        services,
+			Profiles:        profiles,
+			Project:         redcProject,
+			Parallelism:     parallelism,
+			ProviderLimits:  providerLimits,
+			ContinueOnError: continueOnError,
 		}
 
 		if err := compose.RunComposeDown(opts); err != nil {
@@ -70,13 +121,223 @@ var downCmd = &cobra.Command{
 	},
 }
 
+var scaleCmd = &cobra.Command{
+	Use:   "scale service=replicas...",
+	Short: i18n.T("compose_scale_short"),
+	Long:  i18n.T("compose_scale_long"),
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		replicas := make(map[string]int, len(args))
+		for _, arg := range args {
+			name, val, ok := strings.Cut(arg, "=")
+			n, err := strconv.Atoi(val)
+			if !ok || name == "" || err != nil {
+				err := fmt.Errorf("%s", i18n.Tf("compose_scale_arg_invalid", arg))
+				if IsJSON() {
+					PrintJSONError(err)
+					return
+				}
+				gologger.Fatal().Msgf("%s", err.Error())
+			}
+			replicas[name] = n
+		}
+		opts := compose.ComposeOptions{
+			File:            composeFile,
+			Stack:           composeStack,
+			Profiles:        profiles,
+			Project:         redcProject,
+			Parallelism:     parallelism,
+			ProviderLimits:  providerLimits,
+			ContinueOnError: continueOnError,
+			OnFailure:       onFailure,
+		}
+		if !IsJSON() && term.IsTerminal(int(os.Stdin.Fd())) {
+			opts.OnPause = confirmRollback
+		}
+		if ts, err := redc.NewTimelineStore(); err != nil {
+			gologger.Warning().Msgf("timeline store init failed: %v", err)
+		} else {
+			defer ts.Close()
+			opts.Timeline = ts
+		}
+
+		result, err := compose.RunComposeScale(opts, replicas)
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Fatal().Msgf("%s", i18n.Tf("compose_scale_failed", err))
+		}
+		if IsJSON() {
+			PrintJSON(result)
+		}
+	},
+}
+
+var composePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: i18n.T("compose_plan_short"),
+	Long:  i18n.T("compose_plan_long"),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := compose.ComposeOptions{
+			File:     composeFile,
+			Stack:    composeStack,
+			Profiles: profiles,
+			Project:  redcProject,
+		}
+		result, err := compose.RunComposePlan(opts)
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Fatal().Msgf("%s", i18n.Tf("compose_plan_failed", err))
+		}
+		if IsJSON() {
+			PrintJSON(result)
+			return
+		}
+		printComposePlan(result)
+	},
+}
+
+// printComposePlan 按实例输出 compose plan 的结果
+func printComposePlan(r *compose.ComposePlanResult) {
+	if !r.Deployed {
+		gologger.Info().Msgf("%s", i18n.Tf("compose_plan_not_deployed", r.Stack))
+	}
+	if !r.HasChanges() {
+		gologger.Info().Msgf("%s", i18n.Tf("compose_plan_no_changes", r.Stack))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tIMAGE\tCASE ID\tACTION\tRESOURCES\tCOST/MONTH")
+	for _, s := range r.Services {
+		caseID, resources, costDelta := s.CaseID, "-", "-"
+		if caseID == "" {
+			caseID = "-"
+		}
+		if s.Resources != nil {
+			resources = fmt.Sprintf("+%d ~%d ±%d -%d", s.Resources.ToCreate, s.Resources.ToUpdate, s.Resources.ToReplace, s.Resources.ToDestroy)
+		}
+		if s.Currency != "" {
+			costDelta = fmt.Sprintf("%+.2f %s", s.CostDelta, s.Currency)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.Image, caseID, s.Action, resources, costDelta)
+	}
+	w.Flush()
+
+	for _, s := range r.Services {
+		if s.Action == compose.PlanActionUnchanged || (len(s.Params) == 0 && s.Resources == nil && s.Error == "") {
+			continue
+		}
+		fmt.Printf("\n[%s] %s\n", s.Name, s.Action)
+		for _, p := range s.Params {
+			fmt.Printf("  ~ %s: %q -> %q\n", p.Key, p.Old, p.New)
+		}
+		if s.Resources != nil {
+			for _, rc := range s.Resources.Changes {
+				fmt.Printf("  %-8s %s\n", rc.Action, rc.Address)
+			}
+		}
+		if s.Error != "" {
+			fmt.Printf("  ! %s\n", s.Error)
+		}
+	}
+
+	fmt.Println()
+	if len(r.Added) > 0 {
+		fmt.Println(i18n.Tf("compose_plan_added", strings.Join(r.Added, ", ")))
+	}
+	if len(r.Removed) > 0 {
+		fmt.Println(i18n.Tf("compose_plan_removed", strings.Join(r.Removed, ", ")))
+	}
+	for _, rc := range r.Replicas {
+		fmt.Println(i18n.Tf("compose_plan_replicas", rc.Service, rc.From, rc.To))
+	}
+	fmt.Println(r.Summary())
+	if r.Currency != "" {
+		fmt.Println(i18n.Tf("compose_plan_cost_delta", r.CostDelta, r.Currency))
+	}
+}
+
+var psCmd = &cobra.Command{
+	Use:   "ps [stack]",
+	Short: i18n.T("compose_ps_short"),
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var stacks []compose.StackInfo
+		if len(args) == 1 {
+			info, err := compose.GetStack(redcProject, args[0])
+			if err != nil {
+				if IsJSON() {
+					PrintJSONError(err)
+					return
+				}
+				gologger.Fatal().Msgf("%s", err.Error())
+			}
+			stacks = append(stacks, *info)
+		} else {
+			var err error
+			if stacks, err = compose.ListStacks(redcProject); err != nil {
+				if IsJSON() {
+					PrintJSONError(err)
+					return
+				}
+				gologger.Fatal().Msgf("%s", err.Error())
+			}
+		}
+
+		if IsJSON() {
+			PrintJSON(stacks)
+			return
+		}
+		if len(stacks) == 0 {
+			gologger.Info().Msg(i18n.T("compose_ps_empty"))
+			return
+		}
+		for _, s := range stacks {
+			if s.Paused {
+				gologger.Warning().Msgf("%s", i18n.Tf("compose_stack_paused", s.Name))
+			}
+			switch s.FileStatus {
+			case compose.StackFileModified:
+				gologger.Warning().Msgf("%s", i18n.Tf("compose_stack_file_modified", s.Name, s.File))
+			case compose.StackFileMissing:
+				gologger.Warning().Msgf("%s", i18n.Tf("compose_stack_file_missing", s.Name, s.File))
+			}
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "STACK\tSERVICE\tIMAGE\tCASE ID\tSTATE\tUPDATED")
+		for _, s := range stacks {
+			for _, svc := range s.Services {
+				caseID := svc.CaseID
+				if caseID == "" {
+					caseID = "-"
+				}
+				state := s.States[svc.Name]
+				if svc.Status != "deployed" {
+					state = fmt.Sprintf("%s (%s)", state, svc.Status)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, svc.Name, svc.Image, caseID, state, s.UpdatedAt.Format("2006-01-02 15:04"))
+			}
+		}
+		w.Flush()
+	},
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: i18n.T("compose_config_short"),
 	Long:  i18n.T("compose_config_long"),
 	Run: func(cmd *cobra.Command, args []string) {
+		if resolvedConfig {
+			printResolvedConfig()
+			return
+		}
 		opts := compose.ComposeOptions{
-			File:     "redc-compose.yaml",
+			File:     composeFile,
 			Profiles: profiles,
 			Project:  redcProject,
 		}
@@ -91,15 +352,64 @@ var configCmd = &cobra.Command{
 	},
 }
 
+// printResolvedConfig 输出合并 include/extends 并完成变量插值后的编排文件，已登记的敏感值会被遮蔽
+func printResolvedConfig() {
+	data, warnings, err := compose.ResolvedConfig(composeFile)
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return
+		}
+		gologger.Fatal().Msgf("%s", i18n.Tf("compose_config_failed", err))
+	}
+	for _, w := range warnings {
+		gologger.Warning().Msgf("%s", w)
+	}
+	out := secret.Redact(string(data))
+	if IsJSON() {
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+			PrintJSONError(err)
+			return
+		}
+		PrintJSON(doc)
+		return
+	}
+	fmt.Print(out)
+}
+
 func init() {
 	upCmd.Flags().StringVarP(&composeFile, "file", "f", "redc-compose.yaml", i18n.T("flag_compose_file"))
 	upCmd.Flags().StringSliceVarP(&profiles, "profile", "p", []string{}, i18n.T("flag_compose_profile"))
+	scaleCmd.Flags().StringVarP(&composeFile, "file", "f", "redc-compose.yaml", i18n.T("flag_compose_file"))
+	scaleCmd.Flags().StringSliceVarP(&profiles, "profile", "p", []string{}, i18n.T("flag_compose_profile"))
+	for _, c := range []*cobra.Command{upCmd, scaleCmd} {
+		c.Flags().StringVar(&onFailure, "on-failure", "", i18n.T("flag_compose_on_failure"))
+	}
 
 	downCmd.Flags().StringVarP(&composeFile, "file", "f", "redc-compose.yaml", i18n.T("flag_compose_file"))
 	downCmd.Flags().StringSliceVarP(&profiles, "profile", "p", []string{}, i18n.T("flag_compose_profile"))
 
+	for _, c := range []*cobra.Command{upCmd, downCmd, scaleCmd} {
+		c.Flags().StringVar(&composeStack, "stack", "", i18n.T("flag_compose_stack"))
+		c.Flags().IntVar(&parallelism, "parallel", 0, i18n.T("flag_compose_parallel"))
+		c.Flags().StringToIntVar(&providerLimits, "provider-limit", nil, i18n.T("flag_compose_provider_limit"))
+		c.Flags().BoolVar(&continueOnError, "continue-on-error", false, i18n.T("flag_compose_continue_on_error"))
+	}
+
+	composePlanCmd.Flags().StringVarP(&composeFile, "file", "f", "redc-compose.yaml", i18n.T("flag_compose_file"))
+	composePlanCmd.Flags().StringSliceVarP(&profiles, "profile", "p", []string{}, i18n.T("flag_compose_profile"))
+	composePlanCmd.Flags().StringVar(&composeStack, "stack", "", i18n.T("flag_compose_stack"))
+
+	configCmd.Flags().StringVarP(&composeFile, "file", "f", "redc-compose.yaml", i18n.T("flag_compose_file"))
+	configCmd.Flags().StringSliceVarP(&profiles, "profile", "p", []string{}, i18n.T("flag_compose_profile"))
+	configCmd.Flags().BoolVar(&resolvedConfig, "resolved", false, i18n.T("flag_compose_resolved"))
+
 	composeCmd.AddCommand(upCmd)
 	composeCmd.AddCommand(downCmd)
+	composeCmd.AddCommand(scaleCmd)
+	composeCmd.AddCommand(composePlanCmd)
+	composeCmd.AddCommand(psCmd)
 	composeCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(composeCmd)
 }
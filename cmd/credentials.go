@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	credKeyFile string
+	credTTL     time.Duration
+	credAccount string
+	credMFACode string
+)
+
+var credCmd = &cobra.Command{
+	Use:     "cred",
+	Aliases: []string{"credentials"},
+	Short:   i18n.T("cred_short"),
+	Long:    i18n.T("cred_long"),
+}
+
+// credConfigPath 当前使用的配置文件
+func credConfigPath() string {
+	if redc.ActiveConfigPath != "" {
+		return redc.ActiveConfigPath
+	}
+	p, _ := redc.GetConfigPath(cfgFile)
+	return p
+}
+
+// readPassphrase 优先读取 REDC_MASTER_PASSPHRASE，否则在终端提示输入，confirm 时要求输入两次
+func readPassphrase(prompt string, confirm bool) ([]byte, error) {
+	if p := os.Getenv(redc.EnvMasterPassphrase); p != "" {
+		return []byte(p), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("%s", i18n.T("cred_passphrase_required"))
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	p, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	if len(p) == 0 {
+		return nil, fmt.Errorf("%s", i18n.T("cred_passphrase_required"))
+	}
+	if confirm {
+		fmt.Fprint(os.Stderr, i18n.T("cred_prompt_confirm"))
+		again, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+		if string(again) != string(p) {
+			return nil, fmt.Errorf("%s", i18n.T("cred_passphrase_mismatch"))
+		}
+	}
+	return p, nil
+}
+
+// newSecret 口令或密钥文件，使用密钥文件时自动生成不存在的文件
+func newSecret() ([]byte, error) {
+	if credKeyFile != "" {
+		return nil, redc.GenerateKeyFile(credKeyFile)
+	}
+	return readPassphrase(i18n.T("cred_prompt_new"), true)
+}
+
+// promptMFAToken 角色配置了 mfa_serial 且未设置 REDC_MFA_TOKEN 时在终端输入动态口令
+func promptMFAToken(provider, serial string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("%s", i18n.Tf("role_mfa_required", provider, serial))
+	}
+	fmt.Fprint(os.Stderr, i18n.Tf("role_prompt_mfa", provider, serial))
+	var code string
+	fmt.Fscanln(os.Stdin, &code)
+	return strings.TrimSpace(code), nil
+}
+
+func credFail(err error) {
+	if IsJSON() {
+		PrintJSONError(err)
+		return
+	}
+	gologger.Error().Msgf("%s", err.Error())
+}
+
+var credMigrateCmd = &cobra.Command{
+	Use:     "migrate",
+	Short:   i18n.T("cred_migrate_short"),
+	Example: "redc cred migrate\nredc cred migrate --keyfile ~/.redc.key",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := credConfigPath()
+		var pass []byte
+		st, err := redc.GetCredentialStatus(path)
+		if err == nil && !st.Encrypted {
+			pass, err = newSecret()
+		}
+		if err != nil {
+			credFail(err)
+			return
+		}
+		n, err := redc.MigrateCredentials(path, pass, credKeyFile)
+		if err != nil {
+			credFail(err)
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]interface{}{"status": "ok", "encrypted": n})
+			return
+		}
+		gologger.Info().Msgf("%s", i18n.Tf("cred_migrated", n, path))
+	},
+}
+
+var credRotateCmd = &cobra.Command{
+	Use:     "rotate",
+	Short:   i18n.T("cred_rotate_short"),
+	Example: "redc cred rotate\nredc cred rotate --keyfile ~/.redc-new.key",
+	Run: func(cmd *cobra.Command, args []string) {
+		pass, err := newSecret()
+		if err != nil {
+			credFail(err)
+			return
+		}
+		n, err := redc.RotateCredentials(credConfigPath(), pass, credKeyFile)
+		if err != nil {
+			credFail(err)
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]interface{}{"status": "ok", "rotated": n})
+			return
+		}
+		gologger.Info().Msgf("%s", i18n.Tf("cred_rotated", n))
+	},
+}
+
+var credUnlockCmd = &cobra.Command{
+	Use:     "unlock",
+	Short:   i18n.T("cred_unlock_short"),
+	Example: "eval \"$(redc cred unlock --ttl 8h)\"",
+	Run: func(cmd *cobra.Command, args []string) {
+		pass, err := readPassphrase(i18n.T("cred_prompt_passphrase"), false)
+		var token string
+		if err == nil {
+			token, err = redc.UnlockCredentials(credConfigPath(), pass, credTTL)
+		}
+		if err != nil {
+			credFail(err)
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]string{"status": "ok", "expires": time.Now().Add(credTTL).Format(time.RFC3339), "session": token})
+			return
+		}
+		// 会话令牌输出到 stdout 供 eval 使用，提示输出到 stderr
+		gologger.Info().Msgf("%s", i18n.Tf("cred_unlocked_session", credTTL, redc.EnvCredentialSession))
+		fmt.Printf("export %s=%s\n", redc.EnvCredentialSession, token)
+	},
+}
+
+var credLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: i18n.T("cred_lock_short"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := redc.LockCredentials(); err != nil {
+			credFail(err)
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]string{"status": "ok"})
+			return
+		}
+		gologger.Info().Msg(i18n.T("cred_locked_done"))
+	},
+}
+
+var credStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: i18n.T("cred_status_short"),
+	Run: func(cmd *cobra.Command, args []string) {
+		st, err := redc.GetCredentialStatus(credConfigPath())
+		if err != nil {
+			credFail(err)
+			return
+		}
+		if IsJSON() {
+			PrintJSON(st)
+			return
+		}
+		state := "plain"
+		if st.Encrypted {
+			state = "unlocked"
+			if st.Locked {
+				state = "locked"
+			}
+		}
+		fmt.Printf("config: %s\nstore:  %s\n", st.ConfigPath, state)
+		if st.KeyFile != "" {
+			fmt.Printf("keyfile: %s\n", st.KeyFile)
+		}
+		if st.Process != "" {
+			fmt.Printf("credential_process: %s\n", st.Process)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "FIELD\tSOURCE")
+		for _, f := range st.Fields {
+			if f.Source == redc.CredSourceEmpty {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\n", f.Path, f.Source)
+		}
+		w.Flush()
+	},
+}
+
+var credAssumeCmd = &cobra.Command{
+	Use:     "assume [provider...]",
+	Short:   i18n.T("cred_assume_short"),
+	Example: "redc cred assume\nredc cred assume aws --mfa-code 123456\nredc cred assume aliyun tencentcloud --account prod",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, _, err := redc.ReadConfig(credConfigPath())
+		if err != nil {
+			credFail(err)
+			return
+		}
+		providers := args
+		if len(providers) == 0 {
+			for _, p := range []string{"aws", "aliyun", "tencentcloud"} {
+				if acc, err := conf.ForAccount(p, credAccount); err == nil && acc.HasRole(p) {
+					providers = append(providers, p)
+				}
+			}
+		}
+		type assumed struct {
+			Provider   string    `json:"provider"`
+			Expiration time.Time `json:"expiration"`
+		}
+		var results []assumed
+		for _, p := range providers {
+			acc, err := conf.ForAccount(p, credAccount)
+			if err != nil {
+				credFail(err)
+				return
+			}
+			creds, err := redc.RefreshRoleCredentials(acc, p, credMFACode)
+			if err == nil && creds == nil {
+				err = fmt.Errorf("%s", i18n.Tf("cred_no_role", p))
+			}
+			if err != nil {
+				credFail(err)
+				return
+			}
+			results = append(results, assumed{Provider: p, Expiration: creds.Expiration})
+		}
+		if IsJSON() {
+			PrintJSON(results)
+			return
+		}
+		if len(results) == 0 {
+			gologger.Info().Msg(i18n.T("cred_no_roles"))
+			return
+		}
+		for _, r := range results {
+			gologger.Info().Msgf("%s", i18n.Tf("cred_assumed", r.Provider, r.Expiration.Local().Format(time.RFC3339)))
+		}
+	},
+}
+
+var credEncryptCmd = &cobra.Command{
+	Use:     "encrypt",
+	Short:   i18n.T("cred_encrypt_short"),
+	Example: "redc cred encrypt\necho -n \"$LICENSE\" | redc cred encrypt",
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := readSecretValue()
+		if err == nil {
+			value, err = redc.SealSecret(credConfigPath(), value)
+		}
+		if err != nil {
+			credFail(err)
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]string{"value": value})
+			return
+		}
+		fmt.Println(value)
+	},
+}
+
+// readSecretValue 终端中隐藏输入，否则从标准输入读取 (去掉末尾换行)
+func readSecretValue() (string, error) {
+	var v []byte
+	var err error
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, i18n.T("cred_prompt_value"))
+		v, err = term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+	} else {
+		v, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimRight(string(v), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("%s", i18n.T("cred_value_required"))
+	}
+	return value, nil
+}
+
+func init() {
+	redc.MFATokenProvider = promptMFAToken
+	rootCmd.AddCommand(credCmd)
+	credCmd.AddCommand(credMigrateCmd)
+	credCmd.AddCommand(credRotateCmd)
+	credCmd.AddCommand(credUnlockCmd)
+	credCmd.AddCommand(credLockCmd)
+	credCmd.AddCommand(credStatusCmd)
+	credCmd.AddCommand(credAssumeCmd)
+	credCmd.AddCommand(credEncryptCmd)
+	credMigrateCmd.Flags().StringVar(&credKeyFile, "keyfile", "", i18n.T("flag_cred_keyfile"))
+	credRotateCmd.Flags().StringVar(&credKeyFile, "keyfile", "", i18n.T("flag_cred_keyfile"))
+	credUnlockCmd.Flags().DurationVar(&credTTL, "ttl", time.Hour, i18n.T("flag_cred_ttl"))
+	credAssumeCmd.Flags().StringVar(&credAccount, "account", "", i18n.T("flag_cred_account"))
+	credAssumeCmd.Flags().StringVar(&credMFACode, "mfa-code", "", i18n.T("flag_cred_mfa_code"))
+}
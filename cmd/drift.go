@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var driftInterval time.Duration
+
+var driftCmd = &cobra.Command{
+	Use:   "drift [id]",
+	Short: i18n.T("drift_short"),
+	Long:  i18n.T("drift_long"),
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		caseID := ""
+		if len(args) > 0 {
+			caseID = args[0]
+		}
+		if driftInterval <= 0 {
+			runDrift(caseID, nil)
+			return
+		}
+
+		// 周期检测模式：结果写入时间线
+		ts, err := redc.NewTimelineStore()
+		if err != nil {
+			gologger.Warning().Msgf("timeline store init failed: %v", err)
+		} else {
+			defer ts.Close()
+		}
+		gologger.Info().Msgf("%s", i18n.Tf("drift_watch_started", driftInterval))
+		ticker := time.NewTicker(driftInterval)
+		defer ticker.Stop()
+		for {
+			runDrift(caseID, ts)
+			<-ticker.C
+		}
+	},
+}
+
+// runDrift 执行一次漂移检测，ts 不为空时将漂移写入时间线
+func runDrift(caseID string, ts *redc.TimelineStore) {
+	var reports []*redc.DriftReport
+	if caseID != "" {
+		c, err := redcProject.GetCase(caseID)
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", caseID, err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", caseID, err))
+			return
+		}
+		report, err := c.DetectDrift()
+		if err != nil && report.Error == "" {
+			report.Error = err.Error()
+		}
+		reports = append(reports, report)
+	} else {
+		var err error
+		reports, err = redc.DetectProjectDrift(redc.Project)
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("drift_failed", err))
+			return
+		}
+	}
+
+	if ts != nil {
+		for _, r := range reports {
+			if !r.Drifted {
+				continue
+			}
+			detail, _ := json.Marshal(r.Resources)
+			ts.Log("drift", "drift_detected", r.CaseID, r.CaseName, i18n.Tf("drift_detected", r.CaseName, len(r.Resources)), string(detail), "warning")
+		}
+	}
+
+	if IsJSON() {
+		PrintJSON(reports)
+		return
+	}
+	printDriftReports(reports)
+}
+
+func printDriftReports(reports []*redc.DriftReport) {
+	if len(reports) == 0 {
+		gologger.Info().Msg(i18n.T("drift_no_running_cases"))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE\tADDRESS\tACTION\tCHANGED")
+	for _, r := range reports {
+		name := fmt.Sprintf("%s(%s)", r.CaseName, shortID(r.CaseID))
+		switch {
+		case r.Error != "":
+			fmt.Fprintf(w, "%s\t-\terror\t%s\n", name, r.Error)
+		case !r.Drifted:
+			fmt.Fprintf(w, "%s\t-\tin-sync\t-\n", name)
+		default:
+			for _, res := range r.Resources {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, res.Address, strings.Join(res.Actions, ","), strings.Join(res.Changed, ","))
+			}
+		}
+	}
+	w.Flush()
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+	driftCmd.Flags().DurationVar(&driftInterval, "interval", 0, i18n.T("flag_drift_interval"))
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"red-cloud/i18n"
+	redc "red-cloud/mod"
 	"red-cloud/mod/gologger"
 	"red-cloud/utils/sshutil"
 	"strings"
@@ -58,7 +61,12 @@ var execCmd = &cobra.Command{
 			gologger.Info().Msgf(i18n.T("exec_interactive_starting"))
 			err = client.RunInteractiveShell(commandStr)
 		} else {
-			err = client.RunCommand(commandStr)
+			// 输出同时写入场景操作日志
+			var out strings.Builder
+			err = client.RunCommandWithLogger(commandStr, io.MultiWriter(os.Stdout, &out))
+			if c, cerr := redcProject.GetCase(id); cerr == nil {
+				redc.RecordCaseOp(c, redc.OpKindSSH, commandStr, out.String(), err)
+			}
 		}
 
 		if err != nil {
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:     "history [id]",
+	Short:   i18n.T("history_short"),
+	Long:    i18n.T("history_long"),
+	Example: `redc history ecs-demo`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := redcProject.GetCase(args[0])
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			return
+		}
+		list, err := redc.ListStateTransitions(c.Id)
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(list)
+			return
+		}
+		if len(list) == 0 {
+			gologger.Info().Msgf("%s", i18n.Tf("history_empty", c.Name, c.State))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tFROM\tTO\tACTOR\tSOURCE")
+		for _, t := range list {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Time.Format("2006-01-02 15:04:05"), t.From, t.To, t.Actor, t.Source)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
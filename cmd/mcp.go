@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	redc "red-cloud/mod"
 	"red-cloud/mod/mcp"
 
 	"github.com/spf13/cobra"
@@ -24,6 +25,7 @@ var mcpStdioCmd = &cobra.Command{
 The server reads JSON-RPC requests from stdin and writes responses to stdout.
 This mode is suitable for integration with AI assistants and tools.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		redc.LockOwner = "mcp"
 		manager := mcp.NewMCPServerManager(redcProject, nil)
 		if err := manager.Start(mcp.TransportSTDIO, ""); err != nil {
 			return
@@ -49,6 +51,7 @@ Example:
 			addr = args[0]
 		}
 
+		redc.LockOwner = "mcp"
 		manager := mcp.NewMCPServerManager(redcProject, nil)
 		if err := manager.Start(mcp.TransportSSE, addr); err != nil {
 			return
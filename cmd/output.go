@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"red-cloud/mod/secret"
 )
 
 // OutputFormat holds the global output format setting
 var outputFormat string
 
+// revealSecrets disables secret masking in JSON output (redc status --reveal)
+var revealSecrets bool
+
 // JSONOutput is the standard JSON response structure
 type JSONOutput struct {
 	Success bool        `json:"success"`
@@ -24,15 +29,21 @@ func IsJSON() bool {
 // PrintJSON outputs a successful JSON response
 func PrintJSON(data interface{}) {
 	out := JSONOutput{Success: true, Data: data}
-	enc := json.NewEncoder(os.Stdout)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
 	enc.SetIndent("", "  ")
 	enc.SetEscapeHTML(false)
 	enc.Encode(out)
+	if revealSecrets {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	os.Stdout.WriteString(secret.Redact(buf.String()))
 }
 
 // PrintJSONError outputs an error JSON response
 func PrintJSONError(err error) {
-	out := JSONOutput{Success: false, Error: err.Error()}
+	out := JSONOutput{Success: false, Error: secret.Redact(err.Error())}
 	enc := json.NewEncoder(os.Stderr)
 	enc.SetIndent("", "  ")
 	enc.SetEscapeHTML(false)
@@ -15,6 +15,8 @@ var (
 	projectName  string
 	envVars      map[string]string
 	commandToRun string
+	caseTTL      string
+	caseAccount  string
 )
 
 var runCmd = &cobra.Command{
@@ -58,15 +60,27 @@ var planCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		templateName := args[0]
 		if c, err := planLogic(templateName); err == nil {
+			// 保存本次 plan，redc start --plan 可原样应用
+			sp, spErr := c.SnapshotPlan(redc.U)
+			if spErr != nil {
+				gologger.Warning().Msgf("%s", i18n.Tf("plan_save_failed", spErr))
+			}
 			if IsJSON() {
-				PrintJSON(map[string]interface{}{
+				result := map[string]interface{}{
 					"action": "plan",
 					"name":   c.Name,
 					"id":     c.Id,
-				})
+				}
+				if sp != nil {
+					result["planId"] = sp.ID
+				}
+				PrintJSON(result)
 				return
 			}
 			gologger.Info().Msgf(i18n.Tf("scene_plan_done", c.Name, c.Id))
+			if sp != nil {
+				gologger.Info().Msgf("%s", i18n.Tf("plan_saved", sp.ID, sp.Summary, c.GetId()))
+			}
 		}
 	},
 }
@@ -77,6 +91,21 @@ func planLogic(templateName string) (*redc.Case, error) {
 	if templateName == "pte" {
 		templateName = "pte_arm"
 	}
+	ttl, err := redc.ParseTTL(caseTTL)
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return nil, err
+		}
+		gologger.Error().Msgf("%s", err.Error())
+		return nil, err
+	}
+	if caseAccount != "" {
+		if envVars == nil {
+			envVars = map[string]string{}
+		}
+		envVars[redc.AccountVar] = caseAccount
+	}
 	// 创建 Case
 	c, err := redcProject.CaseCreate(templateName, userName, projectName, envVars)
 	if err != nil {
@@ -90,6 +119,14 @@ func planLogic(templateName string) (*redc.Case, error) {
 	if !IsJSON() {
 		gologger.Info().Msgf(i18n.Tf("scene_create_done", templateName))
 	}
+	if ttl > 0 {
+		lease, err := c.SetLease(ttl)
+		if err != nil {
+			gologger.Warning().Msgf("%s", i18n.Tf("lease_set_failed", c.Name, err))
+		} else if !IsJSON() {
+			gologger.Info().Msgf("%s", i18n.Tf("lease_set", c.Name, lease.ExpiresAt.Format("2006-01-02 15:04")))
+		}
+	}
 	return c, nil
 }
 
@@ -101,6 +138,8 @@ func init() {
 	CRCommonFlagSet.StringVarP(&userName, "user", "u", "system", i18n.T("flag_plan_user"))
 	CRCommonFlagSet.StringVarP(&projectName, "name", "n", "", i18n.T("flag_plan_name"))
 	CRCommonFlagSet.StringToStringVarP(&envVars, "env", "e", nil, i18n.T("flag_plan_env"))
+	CRCommonFlagSet.StringVar(&caseTTL, "ttl", "", i18n.T("flag_plan_ttl"))
+	CRCommonFlagSet.StringVar(&caseAccount, "account", "", i18n.T("flag_plan_account"))
 	planCmd.Flags().AddFlagSet(CRCommonFlagSet)
 	runCmd.Flags().AddFlagSet(CRCommonFlagSet)
 }
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var plansSave bool
+
+var plansCmd = &cobra.Command{
+	Use:     "plans [id]",
+	Short:   i18n.T("plans_short"),
+	Long:    i18n.T("plans_long"),
+	Example: "redc plans ecs-demo --save\nredc start ecs-demo --plan 3f9a1c2b7d4e",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := redcProject.GetCase(args[0])
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			return
+		}
+
+		if plansSave {
+			sp, err := c.SavePlan(redc.U)
+			if err != nil {
+				if IsJSON() {
+					PrintJSONError(err)
+					return
+				}
+				gologger.Error().Msgf("%s", i18n.Tf("plan_save_failed", err))
+				return
+			}
+			if IsJSON() {
+				PrintJSON(sp)
+				return
+			}
+			gologger.Info().Msgf("%s", i18n.Tf("plan_saved", sp.ID, sp.Summary, c.GetId()))
+			return
+		}
+
+		plans, err := c.ListSavedPlans()
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(plans)
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "PLAN ID\tCREATED\tSERIAL\tSUMMARY\tAPPLIED")
+		for _, p := range plans {
+			applied := "-"
+			if p.Applied() {
+				applied = p.AppliedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", p.ID, p.CreatedAt.Format("2006-01-02 15:04"), p.StateSerial, p.Summary, applied)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(plansCmd)
+	plansCmd.Flags().BoolVar(&plansSave, "save", false, i18n.T("flag_plans_save"))
+}
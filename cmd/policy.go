@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: i18n.T("policy_short"),
+	Long:  i18n.T("policy_long"),
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: i18n.T("policy_show_short"),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := redc.LoadActivePolicy()
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(p)
+			return
+		}
+		if p.Empty() {
+			gologger.Info().Msg(i18n.T("policy_none"))
+			return
+		}
+		data, _ := yaml.Marshal(p)
+		fmt.Print(string(data))
+	},
+}
+
+var policySetCmd = &cobra.Command{
+	Use:     "set [file]",
+	Short:   i18n.T("policy_set_short"),
+	Example: `redc policy set policy.yaml`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := os.ReadFile(args[0])
+		if err == nil {
+			var p redc.PolicyConfig
+			// YAML 解析器同样可以读取 JSON
+			if err = yaml.Unmarshal(data, &p); err == nil {
+				err = redc.SaveActivePolicy(&p)
+			}
+		}
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("policy_set_failed", err))
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]string{"status": "ok"})
+			return
+		}
+		gologger.Info().Msg(i18n.T("policy_updated"))
+	},
+}
+
+var policyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: i18n.T("policy_clear_short"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := redc.SaveActivePolicy(nil); err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]string{"status": "ok"})
+			return
+		}
+		gologger.Info().Msg(i18n.T("policy_cleared"))
+	},
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:     "check [id]",
+	Short:   i18n.T("policy_check_short"),
+	Example: `redc policy check ecs-demo`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := redcProject.GetCase(args[0])
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			return
+		}
+		report, err := c.CheckPolicy()
+		if err != nil && !redc.IsPolicyDenied(err) {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+		if IsJSON() {
+			PrintJSON(report)
+			return
+		}
+		if len(report.Violations) == 0 {
+			gologger.Info().Msgf("%s", i18n.Tf("policy_passed", c.Name))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "EFFECT\tRULE\tRESOURCE\tMESSAGE")
+		for _, v := range report.Violations {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Effect, v.Rule, v.Resource, v.Message)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policyShowCmd)
+	policyCmd.AddCommand(policySetCmd)
+	policyCmd.AddCommand(policyClearCmd)
+	policyCmd.AddCommand(policyCheckCmd)
+}
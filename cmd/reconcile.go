@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reconcileResume   string
+	reconcileRollback string
+	reconcileDismiss  string
+	reconcileList     bool
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: i18n.T("reconcile_short"),
+	Long:  i18n.T("reconcile_long"),
+	Example: `redc reconcile
+redc reconcile --list
+redc reconcile --resume ecs-demo
+redc reconcile --rollback ecs-demo`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case reconcileResume != "":
+			runInterrupted(reconcileResume, "resume", (*redc.Case).ResumeInterrupted)
+		case reconcileRollback != "":
+			runInterrupted(reconcileRollback, "rollback", (*redc.Case).RollbackInterrupted)
+		case reconcileDismiss != "":
+			runInterrupted(reconcileDismiss, "dismiss", func(c *redc.Case) error {
+				return redc.ClearInterruptedOp(c.Id)
+			})
+		case reconcileList:
+			listInterrupted()
+		default:
+			runReconcile()
+		}
+	},
+}
+
+func runReconcile() {
+	results, err := redcProject.ReconcileCases()
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return
+		}
+		gologger.Error().Msgf("%s", err.Error())
+		return
+	}
+	if IsJSON() {
+		PrintJSON(results)
+		return
+	}
+	if len(results) == 0 {
+		gologger.Info().Msgf("%s", i18n.T("reconcile_nothing"))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE\tNAME\tOLD\tNEW\tINTERRUPTED\tRESULT")
+	for _, r := range results {
+		result, interrupted := "corrected", "-"
+		switch {
+		case r.Error != "":
+			result = r.Error
+		case r.Skipped:
+			result = i18n.T("reconcile_skipped")
+		case r.Removed:
+			result = "removed"
+		}
+		if r.Interrupt != nil {
+			interrupted = r.Interrupt.Operation
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", shortID(r.CaseID), r.CaseName, r.OldState, r.NewState, interrupted, result)
+	}
+	w.Flush()
+	gologger.Info().Msgf("%s", i18n.T("reconcile_hint"))
+}
+
+// listInterrupted 列出等待 resume / rollback 的中断操作
+func listInterrupted() {
+	ops, err := redc.ListInterruptedOps(redcProject.ProjectName)
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return
+		}
+		gologger.Error().Msgf("%s", err.Error())
+		return
+	}
+	if IsJSON() {
+		PrintJSON(ops)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE\tNAME\tOPERATION\tFROM\tTO\tRESOURCES\tDETECTED")
+	for _, op := range ops {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", shortID(op.CaseID), op.CaseName, op.Operation,
+			op.FromState, op.ToState, op.Resources, op.DetectedAt.Format("2006-01-02 15:04:05"))
+	}
+	w.Flush()
+}
+
+func runInterrupted(id, action string, run func(*redc.Case) error) {
+	c, err := redcProject.GetCase(id)
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", id, err)))
+			return
+		}
+		gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", id, err))
+		return
+	}
+	op, err := redc.GetInterruptedOp(c.Id)
+	if err == nil {
+		err = run(c)
+	}
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return
+		}
+		gologger.Error().Msgf("%s", i18n.Tf("reconcile_action_failed", action, c.Name, err))
+		return
+	}
+	if IsJSON() {
+		PrintJSON(map[string]string{"case": c.Id, "action": action, "operation": op.Operation})
+		return
+	}
+	gologger.Info().Msgf("%s", i18n.Tf("reconcile_action_success", action, op.Operation, c.Name))
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.Flags().StringVar(&reconcileResume, "resume", "", i18n.T("flag_reconcile_resume"))
+	reconcileCmd.Flags().StringVar(&reconcileRollback, "rollback", "", i18n.T("flag_reconcile_rollback"))
+	reconcileCmd.Flags().StringVar(&reconcileDismiss, "dismiss", "", i18n.T("flag_reconcile_dismiss"))
+	reconcileCmd.Flags().BoolVar(&reconcileList, "list", false, i18n.T("flag_reconcile_list"))
+	reconcileCmd.MarkFlagsMutuallyExclusive("resume", "rollback", "dismiss", "list")
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ttlSet    string
+	ttlExtend string
+	ttlClear  bool
+)
+
+var ttlCmd = &cobra.Command{
+	Use:     "ttl [id]",
+	Short:   i18n.T("ttl_short"),
+	Long:    i18n.T("ttl_long"),
+	Example: "redc ttl ecs-demo --extend 2h",
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			listLeases()
+			return
+		}
+		c, err := redcProject.GetCase(args[0])
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			return
+		}
+
+		var lease *redc.CaseLease
+		switch {
+		case ttlClear:
+			err = redc.ClearCaseLease(c.Id)
+		case ttlSet != "":
+			dur, perr := redc.ParseTTL(ttlSet)
+			if perr != nil {
+				err = perr
+				break
+			}
+			lease, err = c.SetLease(dur)
+		case ttlExtend != "":
+			dur, perr := redc.ParseTTL(ttlExtend)
+			if perr != nil {
+				err = perr
+				break
+			}
+			lease, err = redc.ExtendCaseLease(c.Id, dur)
+		default:
+			lease, err = redc.GetCaseLease(c.Id)
+		}
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", err.Error())
+			return
+		}
+
+		if IsJSON() {
+			PrintJSON(map[string]interface{}{"caseId": c.Id, "lease": lease})
+			return
+		}
+		if lease == nil {
+			gologger.Info().Msgf("%s", i18n.Tf("lease_none", c.Name))
+			return
+		}
+		gologger.Info().Msgf("%s", i18n.Tf("lease_show", c.Name, lease.ExpiresAt.Format("2006-01-02 15:04:05"), redc.FormatLeaseRemaining(lease.Remaining())))
+	},
+}
+
+// listLeases 列出所有设置了 TTL 的场景
+func listLeases() {
+	leases, err := redc.ListCaseLeases()
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return
+		}
+		gologger.Error().Msgf("%s", err.Error())
+		return
+	}
+	if IsJSON() {
+		PrintJSON(leases)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE ID\tNAME\tPROJECT\tEXPIRES\tREMAINING")
+	for _, l := range leases {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", shortID(l.CaseID), l.CaseName, l.ProjectID,
+			l.ExpiresAt.Format("2006-01-02 15:04"), redc.FormatLeaseRemaining(l.Remaining()))
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(ttlCmd)
+	ttlCmd.Flags().StringVar(&ttlSet, "set", "", i18n.T("ttl_flag_set"))
+	ttlCmd.Flags().StringVar(&ttlExtend, "extend", "", i18n.T("ttl_flag_extend"))
+	ttlCmd.Flags().BoolVar(&ttlClear, "clear", false, i18n.T("ttl_flag_clear"))
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	redc "red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var unlockList bool
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock [id]",
+	Short: i18n.T("unlock_short"),
+	Long:  i18n.T("unlock_long"),
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if unlockList || len(args) == 0 {
+			listCaseLocks()
+			return
+		}
+		c, err := redcProject.GetCase(args[0])
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(fmt.Errorf("%s", i18n.Tf("action_case_not_found", args[0], err)))
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("action_case_not_found", args[0], err))
+			return
+		}
+		removed, err := redc.ForceUnlockCase(c.Id)
+		if err != nil {
+			if IsJSON() {
+				PrintJSONError(err)
+				return
+			}
+			gologger.Error().Msgf("%s", i18n.Tf("unlock_failed", c.Name, err))
+			return
+		}
+		if IsJSON() {
+			PrintJSON(map[string]interface{}{"caseId": c.Id, "removed": removed})
+			return
+		}
+		if removed == nil {
+			gologger.Info().Msgf("%s", i18n.Tf("unlock_not_locked", c.Name))
+			return
+		}
+		if !removed.Stale() {
+			gologger.Warning().Msgf("%s", i18n.Tf("unlock_active_warning", removed.Operation, removed.Owner, removed.PID))
+		}
+		gologger.Info().Msgf("%s", i18n.Tf("unlock_done", c.Name, removed.Operation, removed.Owner))
+	},
+}
+
+// listCaseLocks 列出当前所有场景锁
+func listCaseLocks() {
+	locks, err := redc.ListCaseLocks()
+	if err != nil {
+		if IsJSON() {
+			PrintJSONError(err)
+			return
+		}
+		gologger.Error().Msgf("%s", err.Error())
+		return
+	}
+	if IsJSON() {
+		PrintJSON(locks)
+		return
+	}
+	if len(locks) == 0 {
+		gologger.Info().Msgf("%s", i18n.T("unlock_no_locks"))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE ID\tOPERATION\tOWNER\tPID\tHOST\tACQUIRED\tSTALE")
+	for _, l := range locks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%v\n", shortID(l.CaseID), l.Operation, l.Owner, l.PID, l.Host,
+			l.AcquiredAt.Format("2006-01-02 15:04:05"), l.Stale())
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(unlockCmd)
+	unlockCmd.Flags().BoolVar(&unlockList, "list", false, i18n.T("unlock_flag_list"))
+}
@@ -40,6 +40,7 @@ export const i18n = {
     selectRegion: '请选择地域...',
     path: '路径',
     create: '创建', createAndRun: '创建并运行', templateParams: '模板参数',
+    caseTtl: 'TTL', caseTtlPlaceholder: '如 8h、2d', helpCaseTtl: '到期后自动销毁场景资源，到期前会发送提醒。留空表示不限时。',
     creating: '正在创建中...', initializing: '初始化中...', createSuccess: '创建成功', createFailed: '创建失败',
     id: 'ID', type: '类型', state: '状态', time: '时间', actions: '操作',
     start: '启动', stop: '停止', delete: '删除', rename: '重命名', noScene: '暂无场景',
@@ -49,7 +50,7 @@ export const i18n = {
     copy: '复制', paste: '粘贴', terminal: 'Terminal', clear: '清空', waitOutput: '等待输出...',
     consoleErrors: '错误', consoleWarns: '警告', consoleSearch: '搜索日志...', consoleExport: '导出日志', consoleHint: '部署、启动、停止等操作的日志将在此显示', consoleNoMatch: '无匹配日志', consoleClearFilter: '清除筛选', consoleCopyLine: '点击复制', consoleScrollBottom: '跳到底部',
     consoleTabLogs: '终端日志', consoleTabTimeline: '事件时间线',
-    timelineCatAll: '全部', timelineCatScene: '场景', timelineCatPlugin: '插件', timelineCatSpot: '抢占', timelineCatSSH: 'SSH', timelineCatSystem: '系统',
+    timelineCatAll: '全部', timelineCatScene: '场景', timelineCatPlugin: '插件', timelineCatSpot: '抢占', timelineCatSSH: 'SSH', timelineCatSystem: '系统', timelineCatCompose: '编排',
     timelineEmpty: '暂无事件记录', timelineSearch: '搜索事件...', timelineClear: '清空时间线', timelineClearConfirm: '确定清空所有事件记录？', timelinePrev: '上一页', timelineNext: '下一页', timelinePage: '页',
     saveAsTemplate: '保存为模板',
     deploymentPreview: '部署预览', validationPassed: '验证通过', validationSuccess: '验证成功',
@@ -78,6 +79,9 @@ export const i18n = {
     composeSourceTemplate: '从模板选择', composeSourceFile: '自定义文件路径',
     composeAdvanced: '高级选项',
     composeStatusRunning: '运行中', composeStatusStopped: '已停止', composeStatusNotDeployed: '未部署', composeStatusCreated: '已创建',
+    composeStacks: '已部署的编排', composeStackFileModified: '编排文件已修改', composeStackFileMissing: '编排文件已不存在',
+    composeStackDown: '销毁', composeStackDestroyDesc: '将按部署记录销毁编排 {name} 的所有服务，不依赖编排文件，不可撤销',
+    composeStackUpdated: '更新于',
     composeGuideHint: '选择 Compose 模板或指定文件路径开始编排',
     serviceStatus: '状态',
     serviceName: '服务', serviceTemplate: '模板', serviceProvider: '云厂商', serviceDepends: '依赖', serviceReplicas: '副本',
@@ -482,7 +486,7 @@ export const i18n = {
     toolGetTemplateInfo: '模板详情', toolDeleteTemplate: '删除模板',
     toolGetCaseOutputs: '获取输出', toolGetConfig: '获取配置', toolValidateConfig: '验证配置',
     toolListUserdataTemplates: '列出部署脚本', toolExecUserdata: '执行部署脚本',
-    toolSaveComposeFile: '保存编排文件', toolComposePreview: '预览编排', toolComposeUp: '启动编排', toolComposeDown: '销毁编排',
+    toolSaveComposeFile: '保存编排文件', toolComposePreview: '预览编排', toolComposeUp: '启动编排', toolComposeDown: '销毁编排', toolComposePs: '查看编排', toolComposeScale: '调整编排副本', toolComposePlan: '计划编排变更',
     toolSaveTemplateFiles: '保存模板文件', toolAskUser: '用户决策', toolUpdatePlan: '更新计划',
     toolGetTemplateFiles: '读取模板文件',
     toolGetCostEstimate: '成本估算', toolGetBalances: '余额查询', toolGetResourceSummary: '资源汇总', toolGetPredictedMonthlyCost: '月度预测',
@@ -661,6 +665,7 @@ export const i18n = {
     noMatchingInstanceTypes: 'No matching instance types', selectedInstanceType: 'Selected instance type',
     path: 'Path',
     create: 'Create', createAndRun: 'Create & Run', templateParams: 'Template Parameters',
+    caseTtl: 'TTL', caseTtlPlaceholder: 'e.g. 8h, 2d', helpCaseTtl: 'Destroy the case resources automatically when the TTL expires. A reminder is sent before expiry. Leave empty for no limit.',
     creating: 'Creating...', initializing: 'Initializing...', createSuccess: 'Created', createFailed: 'Create failed',
     id: 'ID', type: 'Type', state: 'State', time: 'Time', actions: 'Actions',
     start: 'Start', stop: 'Stop', delete: 'Delete', rename: 'Rename', noScene: 'No Scene',
@@ -671,7 +676,7 @@ export const i18n = {
     copy: 'Copy', paste: 'Paste', terminal: 'Terminal', clear: 'Clear', waitOutput: 'Waiting for output...',
     consoleErrors: 'err', consoleWarns: 'warn', consoleSearch: 'Search logs...', consoleExport: 'Export Logs', consoleHint: 'Logs from deploy, start, stop operations will appear here', consoleNoMatch: 'No matching logs', consoleClearFilter: 'Clear filter', consoleCopyLine: 'Click to copy', consoleScrollBottom: 'Scroll to bottom',
     consoleTabLogs: 'Terminal Logs', consoleTabTimeline: 'Event Timeline',
-    timelineCatAll: 'All', timelineCatScene: 'Scene', timelineCatPlugin: 'Plugin', timelineCatSpot: 'Spot', timelineCatSSH: 'SSH', timelineCatSystem: 'System',
+    timelineCatAll: 'All', timelineCatScene: 'Scene', timelineCatPlugin: 'Plugin', timelineCatSpot: 'Spot', timelineCatSSH: 'SSH', timelineCatSystem: 'System', timelineCatCompose: 'Compose',
     timelineEmpty: 'No events recorded', timelineSearch: 'Search events...', timelineClear: 'Clear Timeline', timelineClearConfirm: 'Clear all event records?', timelinePrev: 'Prev', timelineNext: 'Next', timelinePage: 'page',
     copied: 'Copied', copyScript: 'Copy Script', copiedSuccess: 'Copied!',
     deploymentPreview: 'Deployment Preview', validationPassed: 'Validation Passed', validationSuccess: 'Validation Passed',
@@ -702,6 +707,9 @@ export const i18n = {
     composeSourceTemplate: 'From Template', composeSourceFile: 'Custom File Path',
     composeAdvanced: 'Advanced Options',
     composeStatusRunning: 'Running', composeStatusStopped: 'Stopped', composeStatusNotDeployed: 'Not Deployed', composeStatusCreated: 'Created',
+    composeStacks: 'Deployed Stacks', composeStackFileModified: 'Compose file modified', composeStackFileMissing: 'Compose file missing',
+    composeStackDown: 'Destroy', composeStackDestroyDesc: 'All services of stack {name} will be destroyed from its deployment record, without using the compose file. This cannot be undone.',
+    composeStackUpdated: 'Updated',
     composeGuideHint: 'Select a Compose template or specify a file path to start',
     serviceStatus: 'Status',
     serviceName: 'Service', serviceTemplate: 'Template', serviceProvider: 'Provider', serviceDepends: 'Depends', serviceReplicas: 'Replicas',
@@ -1105,7 +1113,7 @@ export const i18n = {
     toolGetTemplateInfo: 'Template Info', toolDeleteTemplate: 'Delete Template',
     toolGetCaseOutputs: 'Get Outputs', toolGetConfig: 'Get Config', toolValidateConfig: 'Validate Config',
     toolListUserdataTemplates: 'List Userdata Scripts', toolExecUserdata: 'Execute Userdata',
-    toolSaveComposeFile: 'Save Compose File', toolComposePreview: 'Preview Compose', toolComposeUp: 'Compose Up', toolComposeDown: 'Compose Down',
+    toolSaveComposeFile: 'Save Compose File', toolComposePreview: 'Preview Compose', toolComposeUp: 'Compose Up', toolComposeDown: 'Compose Down', toolComposePs: 'List Compose Stacks', toolComposeScale: 'Scale Compose', toolComposePlan: 'Plan Compose Changes',
     toolSaveTemplateFiles: 'Save Template Files', toolAskUser: 'Ask User', toolUpdatePlan: 'Update Plan',
     toolGetTemplateFiles: 'Read Template Files',
     toolGetCostEstimate: 'Cost Estimate', toolGetBalances: 'Query Balance', toolGetResourceSummary: 'Resource Summary', toolGetPredictedMonthlyCost: 'Monthly Forecast',
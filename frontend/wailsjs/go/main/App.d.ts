@@ -49,9 +49,9 @@ export function CopyFileTo(arg1:string,arg2:string):Promise<void>;
 
 export function CopyTemplate(arg1:string,arg2:string):Promise<void>;
 
-export function CreateAndRunCase(arg1:string,arg2:string,arg3:Record<string, string>):Promise<void>;
+export function CreateAndRunCase(arg1:string,arg2:string,arg3:Record<string, string>,arg4:string):Promise<void>;
 
-export function CreateCase(arg1:string,arg2:string,arg3:Record<string, string>):Promise<void>;
+export function CreateCase(arg1:string,arg2:string,arg3:Record<string, string>,arg4:string):Promise<void>;
 
 export function CreateCustomDeployment(arg1:mod.DeploymentConfig):Promise<mod.CustomDeployment>;
 
@@ -452,3 +452,91 @@ export function WriteToTerminal(arg1:string,arg2:string):Promise<void>;
 export function ListTimelineEvents(arg1:number,arg2:number,arg3:string,arg4:string):Promise<mod.TimelineListResult>;
 
 export function ClearTimeline():Promise<void>;
+
+export function DetectCaseDrift(arg1:string):Promise<mod.DriftReport>;
+
+export function DetectProjectDrift():Promise<Array<mod.DriftReport>>;
+
+export function GetDriftMonitorEnabled():Promise<boolean>;
+
+export function SetDriftMonitorEnabled(arg1:boolean):Promise<void>;
+
+export function SetDriftMonitorInterval(arg1:number):Promise<void>;
+
+export function PlanCaseChange(arg1:string,arg2:Record<string, string>):Promise<main.PlanPreview>;
+
+export function ApplyCaseChange(arg1:string):Promise<void>;
+
+export function GetCaseChangeHistory(arg1:string):Promise<Array<mod.CaseChangeHistory>>;
+
+export function GetCaseLocks():Promise<Array<mod.CaseLock>>;
+
+export function UnlockCase(arg1:string):Promise<mod.CaseLock>;
+
+export function SetCaseTTL(arg1:string,arg2:string):Promise<mod.CaseLease>;
+
+export function ExtendCaseTTL(arg1:string,arg2:string):Promise<mod.CaseLease>;
+
+export function GetCaseTTL(arg1:string):Promise<mod.CaseLease>;
+
+export function GetCaseTTLWarnMinutes():Promise<number>;
+
+export function SetCaseTTLWarnMinutes(arg1:number):Promise<void>;
+
+export function AdoptCase(arg1:string,arg2:string,arg3:Record<string, string>,arg4:Array<string>):Promise<main.AdoptResult>;
+
+export function FinishCaseAdoption(arg1:string):Promise<void>;
+
+export function GetCaseResourceAddresses(arg1:string):Promise<Array<string>>;
+
+export function ApplyCaseTargets(arg1:string,arg2:Array<string>,arg3:Array<string>):Promise<void>;
+
+export function DestroyCaseTargets(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SaveCasePlan(arg1:string):Promise<mod.SavedPlan>;
+
+export function ListCasePlans(arg1:string):Promise<Array<mod.SavedPlan>>;
+
+export function GetSavedPlanPreview(arg1:string,arg2:string):Promise<main.PlanPreview>;
+
+export function StartCaseWithPlan(arg1:string,arg2:string):Promise<void>;
+
+export function ListCaseOps(arg1:string):Promise<Array<mod.CaseOp>>;
+
+export function GetCaseOpLog(arg1:string,arg2:number,arg3:number):Promise<mod.CaseOpLogChunk>;
+
+export function ReconcileCases():Promise<Array<mod.ReconcileResult>>;
+
+export function ListInterruptedCases():Promise<Array<mod.InterruptedOp>>;
+
+export function ResumeInterruptedCase(arg1:string):Promise<void>;
+
+export function RollbackInterruptedCase(arg1:string):Promise<void>;
+
+export function DismissInterruptedCase(arg1:string):Promise<void>;
+
+export function GetCaseStateHistory(arg1:string):Promise<Array<mod.StateTransition>>;
+
+export function GetPolicy():Promise<mod.PolicyConfig>;
+
+export function SetPolicy(arg1:mod.PolicyConfig):Promise<void>;
+
+export function CheckCasePolicy(arg1:string):Promise<mod.PolicyReport>;
+
+export function RevealCaseOutput(arg1:string,arg2:string):Promise<string>;
+
+export function GetCredentialStatus():Promise<mod.CredentialStatus>;
+
+export function UnlockCredentials(arg1:string):Promise<void>;
+
+export function LockCredentials():Promise<void>;
+
+export function AssumeRole(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ListComposeStacks():Promise<Array<any>>;
+
+export function ComposeDownStack(arg1:string):Promise<void>;
+
+export function ComposeScaleStackSync(arg1:string,arg2:Record<string, number>):Promise<any>;
+
+export function ComposePlan(arg1:string,arg2:Array<string>):Promise<any>;
@@ -86,12 +86,12 @@ export function CopyTemplate(arg1, arg2) {
   return window['go']['main']['App']['CopyTemplate'](arg1, arg2);
 }
 
-export function CreateAndRunCase(arg1, arg2, arg3) {
-  return window['go']['main']['App']['CreateAndRunCase'](arg1, arg2, arg3);
+export function CreateAndRunCase(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreateAndRunCase'](arg1, arg2, arg3, arg4);
 }
 
-export function CreateCase(arg1, arg2, arg3) {
-  return window['go']['main']['App']['CreateCase'](arg1, arg2, arg3);
+export function CreateCase(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreateCase'](arg1, arg2, arg3, arg4);
 }
 
 export function CreateCustomDeployment(arg1) {
@@ -933,3 +933,179 @@ export function DownloadUpdate() {
 export function ApplyUpdateAndRestart() {
   return window['go']['main']['App']['ApplyUpdateAndRestart']();
 }
+
+export function DetectCaseDrift(arg1) {
+  return window['go']['main']['App']['DetectCaseDrift'](arg1);
+}
+
+export function DetectProjectDrift() {
+  return window['go']['main']['App']['DetectProjectDrift']();
+}
+
+export function GetDriftMonitorEnabled() {
+  return window['go']['main']['App']['GetDriftMonitorEnabled']();
+}
+
+export function SetDriftMonitorEnabled(arg1) {
+  return window['go']['main']['App']['SetDriftMonitorEnabled'](arg1);
+}
+
+export function SetDriftMonitorInterval(arg1) {
+  return window['go']['main']['App']['SetDriftMonitorInterval'](arg1);
+}
+
+export function PlanCaseChange(arg1, arg2) {
+  return window['go']['main']['App']['PlanCaseChange'](arg1, arg2);
+}
+
+export function ApplyCaseChange(arg1) {
+  return window['go']['main']['App']['ApplyCaseChange'](arg1);
+}
+
+export function GetCaseChangeHistory(arg1) {
+  return window['go']['main']['App']['GetCaseChangeHistory'](arg1);
+}
+
+export function GetCaseLocks() {
+  return window['go']['main']['App']['GetCaseLocks']();
+}
+
+export function UnlockCase(arg1) {
+  return window['go']['main']['App']['UnlockCase'](arg1);
+}
+
+export function SetCaseTTL(arg1, arg2) {
+  return window['go']['main']['App']['SetCaseTTL'](arg1, arg2);
+}
+
+export function ExtendCaseTTL(arg1, arg2) {
+  return window['go']['main']['App']['ExtendCaseTTL'](arg1, arg2);
+}
+
+export function GetCaseTTL(arg1) {
+  return window['go']['main']['App']['GetCaseTTL'](arg1);
+}
+
+export function GetCaseTTLWarnMinutes() {
+  return window['go']['main']['App']['GetCaseTTLWarnMinutes']();
+}
+
+export function SetCaseTTLWarnMinutes(arg1) {
+  return window['go']['main']['App']['SetCaseTTLWarnMinutes'](arg1);
+}
+
+export function AdoptCase(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['AdoptCase'](arg1, arg2, arg3, arg4);
+}
+
+export function FinishCaseAdoption(arg1) {
+  return window['go']['main']['App']['FinishCaseAdoption'](arg1);
+}
+
+export function GetCaseResourceAddresses(arg1) {
+  return window['go']['main']['App']['GetCaseResourceAddresses'](arg1);
+}
+
+export function ApplyCaseTargets(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApplyCaseTargets'](arg1, arg2, arg3);
+}
+
+export function DestroyCaseTargets(arg1, arg2) {
+  return window['go']['main']['App']['DestroyCaseTargets'](arg1, arg2);
+}
+
+export function SaveCasePlan(arg1) {
+  return window['go']['main']['App']['SaveCasePlan'](arg1);
+}
+
+export function ListCasePlans(arg1) {
+  return window['go']['main']['App']['ListCasePlans'](arg1);
+}
+
+export function GetSavedPlanPreview(arg1, arg2) {
+  return window['go']['main']['App']['GetSavedPlanPreview'](arg1, arg2);
+}
+
+export function StartCaseWithPlan(arg1, arg2) {
+  return window['go']['main']['App']['StartCaseWithPlan'](arg1, arg2);
+}
+
+export function ListCaseOps(arg1) {
+  return window['go']['main']['App']['ListCaseOps'](arg1);
+}
+
+export function GetCaseOpLog(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetCaseOpLog'](arg1, arg2, arg3);
+}
+
+export function ReconcileCases() {
+  return window['go']['main']['App']['ReconcileCases']();
+}
+
+export function ListInterruptedCases() {
+  return window['go']['main']['App']['ListInterruptedCases']();
+}
+
+export function ResumeInterruptedCase(arg1) {
+  return window['go']['main']['App']['ResumeInterruptedCase'](arg1);
+}
+
+export function RollbackInterruptedCase(arg1) {
+  return window['go']['main']['App']['RollbackInterruptedCase'](arg1);
+}
+
+export function DismissInterruptedCase(arg1) {
+  return window['go']['main']['App']['DismissInterruptedCase'](arg1);
+}
+
+export function GetCaseStateHistory(arg1) {
+  return window['go']['main']['App']['GetCaseStateHistory'](arg1);
+}
+
+export function GetPolicy() {
+  return window['go']['main']['App']['GetPolicy']();
+}
+
+export function SetPolicy(arg1) {
+  return window['go']['main']['App']['SetPolicy'](arg1);
+}
+
+export function CheckCasePolicy(arg1) {
+  return window['go']['main']['App']['CheckCasePolicy'](arg1);
+}
+
+export function RevealCaseOutput(arg1, arg2) {
+  return window['go']['main']['App']['RevealCaseOutput'](arg1, arg2);
+}
+
+export function GetCredentialStatus() {
+  return window['go']['main']['App']['GetCredentialStatus']();
+}
+
+export function UnlockCredentials(arg1) {
+  return window['go']['main']['App']['UnlockCredentials'](arg1);
+}
+
+export function LockCredentials() {
+  return window['go']['main']['App']['LockCredentials']();
+}
+
+export function AssumeRole(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AssumeRole'](arg1, arg2, arg3);
+}
+
+export function ListComposeStacks() {
+  return window['go']['main']['App']['ListComposeStacks']();
+}
+
+export function ComposeDownStack(arg1) {
+  return window['go']['main']['App']['ComposeDownStack'](arg1);
+}
+
+export function ComposeScaleStackSync(arg1, arg2) {
+  return window['go']['main']['App']['ComposeScaleStackSync'](arg1, arg2);
+}
+
+export function ComposePlan(arg1, arg2) {
+  return window['go']['main']['App']['ComposePlan'](arg1, arg2);
+}
@@ -104,6 +104,7 @@ export namespace main {
 	
 	export class BalanceInfo {
 	    provider: string;
+	    account?: string;
 	    amount: string;
 	    currency: string;
 	    updatedAt: string;
@@ -116,6 +117,7 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.provider = source["provider"];
+	        this.account = source["account"];
 	        this.amount = source["amount"];
 	        this.currency = source["currency"];
 	        this.updatedAt = source["updatedAt"];
@@ -124,6 +126,7 @@ export namespace main {
 	}
 	export class BillInfo {
 	    provider: string;
+	    account?: string;
 	    month: string;
 	    totalAmount: string;
 	    currency: string;
@@ -138,6 +141,7 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.provider = source["provider"];
+	        this.account = source["account"];
 	        this.month = source["month"];
 	        this.totalAmount = source["totalAmount"];
 	        this.currency = source["currency"];
@@ -11,6 +11,8 @@ import (
 	"net/http"
 	"os"
 	redc "red-cloud/mod"
+	"red-cloud/mod/compose"
+	"red-cloud/mod/secret"
 	"reflect"
 	"strings"
 	"sync"
@@ -112,6 +114,7 @@ var methodMinRole = map[string]string{
 	"GetLanguage": "viewer", "GetShowWelcomeDialog": "viewer",
 	"GetNotificationEnabled": "viewer", "GetDisableRightClick": "viewer",
 	"GetSpotMonitorEnabled": "viewer", "GetSpotAutoRecoverEnabled": "viewer",
+	"GetDriftMonitorEnabled": "viewer",
 	"GetWebhookConfig": "viewer", "GetAllCaseTags": "viewer", "GetAllTagNames": "viewer",
 	"GetHTTPServerStatus": "viewer",
 	"ListCases": "viewer", "GetCaseOutputs": "viewer", "GetCasePlanPreview": "viewer",
@@ -124,6 +127,14 @@ var methodMinRole = map[string]string{
 	"GetTemplateFiles": "viewer", "GetTemplateMetadata": "viewer", "GetBaseTemplates": "viewer",
 	"ListUserdataTemplates": "viewer", "ListComposeTemplates": "viewer",
 	"ListCustomDeployments": "viewer", "GetDeploymentHistory": "viewer",
+	"GetCaseChangeHistory": "viewer", "GetCaseLocks": "viewer",
+	"GetCaseTTL": "viewer", "GetCaseTTLWarnMinutes": "viewer",
+	"ListInterruptedCases": "viewer", "GetCaseStateHistory": "viewer",
+	"GetPolicy": "viewer", "CheckCasePolicy": "viewer",
+	"GetCredentialStatus": "viewer",
+	"GetCaseResourceAddresses": "viewer",
+	"ListCasePlans": "viewer", "GetSavedPlanPreview": "viewer",
+	"ListCaseOps": "viewer", "GetCaseOpLog": "viewer",
 	"GetDeploymentPlanPreview": "viewer",
 	"GetCostEstimate": "viewer",
 	"ListPlugins": "viewer", "GetPluginConfig": "viewer", "FetchPluginRegistry": "viewer",
@@ -150,16 +161,27 @@ var methodMinRole = map[string]string{
 	"MCPListCustomDeployments": "viewer", "MCPListProjects": "viewer",
 	"MCPListProfiles": "viewer", "MCPGetActiveProfile": "viewer",
 	"MCPListScheduledTasks": "viewer",
+	"ListComposeStacks": "viewer", "MCPListComposeStacks": "viewer",
 
 	// === Operator: create + operate ===
 	"StartCase": "operator", "StopCase": "operator",
+	"DetectCaseDrift": "operator", "DetectProjectDrift": "operator",
+	"PlanCaseChange": "operator", "ApplyCaseChange": "operator",
+	"SetCaseTTL": "operator", "ExtendCaseTTL": "operator",
+	"ReconcileCases": "operator", "DismissInterruptedCase": "operator",
+	"ResumeInterruptedCase": "operator", "RollbackInterruptedCase": "operator",
+	"RevealCaseOutput": "operator",
+	"AdoptCase": "operator", "FinishCaseAdoption": "operator",
+	"ApplyCaseTargets": "operator", "DestroyCaseTargets": "operator",
+	"SaveCasePlan": "operator", "StartCaseWithPlan": "operator",
 	"CreateCase": "operator", "CreateAndRunCase": "operator",
 	"DeployCase": "operator", "CloneCase": "operator",
 	"CreateCustomDeployment": "operator", "StartCustomDeployment": "operator",
 	"StopCustomDeployment": "operator", "CloneCustomDeployment": "operator",
 	"BatchStartCustomDeployments": "operator", "BatchStopCustomDeployments": "operator",
 	"ComposePreview": "operator", "ComposeUp": "operator", "ComposeDown": "operator",
-	"SelectComposeFile": "operator",
+	"SelectComposeFile": "operator", "ComposeDownStack": "operator",
+	"ComposeScaleStackSync": "operator", "ComposePlan": "operator",
 	"ExecCommand": "operator", "ExecUserdata": "operator",
 	"UploadUserdataScript": "operator", "UploadFile": "operator", "DownloadFile": "operator",
 	"StartSSHTerminal": "operator", "StartSSHTerminalInstance": "operator",
@@ -191,6 +213,7 @@ var methodMinRole = map[string]string{
 	"SavePluginConfig": "operator",
 	// MCP write
 	"MCPComposePreview": "operator", "MCPComposeUp": "operator", "MCPComposeDown": "operator",
+	"MCPComposePlan": "operator",
 	"MCPStartCustomDeployment": "operator", "MCPStopCustomDeployment": "operator",
 	"MCPSwitchProject": "operator", "MCPSetActiveProfile": "operator",
 	"MCPScheduleTask": "operator", "MCPCancelScheduledTask": "operator",
@@ -259,9 +282,9 @@ func (s *HTTPServer) auditLog(username, role, method string, args []json.RawMess
 	if s.app.auditStore == nil {
 		return
 	}
-	// Extract first 2 args for context (e.g. caseID, name)
+	// Extract first 2 args for context (e.g. caseID, name); credential methods are never captured
 	argsStr := ""
-	if len(args) > 0 {
+	if len(args) > 0 && !secretArgMethods[method] {
 		preview := make([]json.RawMessage, 0, 2)
 		for i, a := range args {
 			if i >= 2 {
@@ -278,7 +301,20 @@ func (s *HTTPServer) auditLog(username, role, method string, args []json.RawMess
 	if idx := strings.LastIndex(ip, ":"); idx > 0 {
 		clientIP = ip[:idx]
 	}
-	go s.app.auditStore.Log(username, role, method, argsStr, clientIP, success, errMsg)
+	go s.app.auditStore.Log(username, role, method, secret.Redact(argsStr), clientIP, success, secret.Redact(errMsg))
+}
+
+// secretArgMethods take passphrases, keys or webhook secrets as arguments. Their arguments
+// are not written to the audit log because the values are not registered for masking.
+var secretArgMethods = map[string]bool{
+	"UnlockCredentials":              true,
+	"AssumeRole":                     true,
+	"SaveProvidersConfig":            true,
+	"UpdateProfileAIConfig":          true,
+	"UpdateProfileFallbackProviders": true,
+	"TestWebhook":                    true,
+	"SetHTTPServerConfig":            true,
+	"StartHTTPServer":                true,
 }
 
 // isAuditableMethod returns true for methods that should be recorded in the audit log.
@@ -345,7 +381,8 @@ func (s *HTTPServer) Start(staticFS fs.FS) error {
 			return
 		}
 
-		result, err := s.dispatch(req.Method, req.Args)
+		// 场景状态变迁与场景锁记录认证用户和 http 来源
+		result, err := s.dispatch(caller{actor: username, source: redc.SourceHTTP}, req.Method, req.Args)
 
 		// Audit: log write operations (operator+admin methods)
 		if isAuditableMethod(req.Method) {
@@ -556,13 +593,78 @@ var blockedInHTTPMode = map[string]bool{
 	"SelectSaveFile":  true,
 }
 
-// dispatch calls an App method by name using reflection
-func (s *HTTPServer) dispatch(method string, args []json.RawMessage) (interface{}, error) {
+// httpCaller exposes the App to a single HTTP request. Methods that operate on cases are
+// overridden to run on behalf of the authenticated user; all other methods are promoted from App.
+type httpCaller struct {
+	*App
+	who caller
+}
+
+func (h *httpCaller) StartCase(caseID string) error {
+	return h.startCase(caseID, h.who)
+}
+func (h *httpCaller) StopCase(caseID string) error {
+	return h.stopCase(caseID, h.who)
+}
+func (h *httpCaller) RemoveCase(caseID string) error {
+	return h.removeCase(caseID, h.who)
+}
+func (h *httpCaller) CreateCase(templateName string, name string, vars map[string]string, ttl string) error {
+	return h.createCase(templateName, name, vars, ttl, h.who)
+}
+func (h *httpCaller) CreateAndRunCase(templateName string, name string, vars map[string]string, ttl string) error {
+	return h.createAndRunCase(templateName, name, vars, ttl, h.who)
+}
+func (h *httpCaller) DeployCase(templateName string, name string, vars map[string]string) error {
+	return h.createCase(templateName, name, vars, "", h.who)
+}
+func (h *httpCaller) CloneCase(caseID string, cloneName string) error {
+	return h.cloneCase(caseID, cloneName, h.who)
+}
+func (h *httpCaller) ApplyCaseChange(caseID string) error {
+	return h.applyCaseChange(caseID, h.who)
+}
+func (h *httpCaller) StartCaseWithPlan(caseID string, planID string) error {
+	return h.startCaseWithPlan(caseID, planID, h.who)
+}
+func (h *httpCaller) AdoptCase(templateName string, name string, vars map[string]string, imports []string) (*AdoptResult, error) {
+	return h.adoptCase(templateName, name, vars, imports, h.who)
+}
+func (h *httpCaller) FinishCaseAdoption(caseID string) error {
+	return h.finishCaseAdoption(caseID, h.who)
+}
+func (h *httpCaller) ApplyCaseTargets(caseID string, targets []string, replace []string) error {
+	return h.runCaseTargets(caseID, redc.TargetSpec{Targets: targets, Replace: replace}, h.who)
+}
+func (h *httpCaller) DestroyCaseTargets(caseID string, targets []string) error {
+	return h.runCaseTargets(caseID, redc.TargetSpec{Targets: targets, Destroy: true}, h.who)
+}
+func (h *httpCaller) ResumeInterruptedCase(caseID string) error {
+	return h.runInterrupted(caseID, "resume", (*redc.Case).ResumeInterrupted, h.who)
+}
+func (h *httpCaller) RollbackInterruptedCase(caseID string) error {
+	return h.runInterrupted(caseID, "rollback", (*redc.Case).RollbackInterrupted, h.who)
+}
+func (h *httpCaller) ComposeUp(filePath string, profiles []string) error {
+	return h.composeUp(filePath, profiles, h.who)
+}
+func (h *httpCaller) ComposeDown(filePath string, profiles []string) error {
+	return h.composeDown(filePath, profiles, h.who)
+}
+func (h *httpCaller) ComposeDownStack(stack string) error {
+	return h.composeDownStack(stack, h.who)
+}
+func (h *httpCaller) ComposeScaleStackSync(stack string, replicas map[string]int) (*compose.ComposeScaleResult, error) {
+	return h.composeScaleStack(stack, replicas, h.who)
+}
+
+// dispatch calls an App method by name using reflection, on behalf of who
+func (s *HTTPServer) dispatch(who caller, method string, args []json.RawMessage) (interface{}, error) {
 	if blockedInHTTPMode[method] {
 		return nil, fmt.Errorf("此功能在浏览器模式下不可用，请使用桌面应用")
 	}
 
-	appVal := reflect.ValueOf(s.app)
+	appVal := reflect.ValueOf(&httpCaller{App: s.app, who: who})
 	m := appVal.MethodByName(method)
 	if !m.IsValid() {
 		return nil, fmt.Errorf("method %s not found", method)
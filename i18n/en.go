@@ -579,4 +579,389 @@ var enMessages = map[string]string{
 
 	// Timeline
 	"app_timeline_app_started": "Application started",
+
+	// Drift detection
+	"drift_short":                  "Detect drift between state and real cloud resources",
+	"drift_long":                   "Run a refresh-only plan for a case (or all running cases in the project when no id is given) and report resources whose real configuration differs from terraform state.",
+	"flag_drift_interval":          "Re-run drift detection periodically at this interval (e.g. 30m) and record findings in the timeline",
+	"drift_watch_started":          "Periodic drift detection started, interval: %s",
+	"drift_plan_failed":            "refresh-only plan failed: %v",
+	"drift_case_not_running":       "Case %s is not running (state: %s), skip drift detection",
+	"drift_case_failed":            "Drift detection failed for %s: %v",
+	"drift_failed":                 "Drift detection failed: %v",
+	"drift_detected":               "Drift detected in %s: %d resource(s) changed outside terraform",
+	"drift_no_running_cases":       "No running cases to check",
+	"app_drift_monitor_started":    "Drift monitor started",
+	"app_drift_monitor_stopped":    "Drift monitor stopped",
+	"app_drift_interval_too_short": "Drift check interval must be at least 5 minutes",
+	"notify_drift_detected":        "Drift Detected",
+	"notify_drift_detected_msg":    "Case %s: %d resource(s) drifted from terraform state",
+
+	// Case change
+	"flag_change_yes":             "Apply the change without asking for confirmation",
+	"change_confirm_prompt":       "Plan: %s. Apply these changes? [y/N]",
+	"case_change_plan_failed":     "Failed to plan change: %v",
+	"case_change_plan_changed":    "The reviewed plan of %s is missing or was replaced by another command, plan the change again",
+	"case_change_applying":        "Applying change to %s (%s)",
+	"case_change_history_failed":  "Failed to record change history: %v",
+	"case_change_rebuilt":         "Case destroyed and rebuilt with new parameters",
+	"case_change_params_only":     "Parameters updated, takes effect on next start",
+	"case_change_params_saved":    "Parameters of %s saved, will take effect on next start",
+	"case_change_no_changes":      "No resource changes for %s, parameters saved",
+	"case_change_cancelled":       "Change cancelled (use --yes to apply without confirmation)",
+	"app_case_change_not_running": "Case %s is not running, in-place change is only available for running cases",
+	"app_case_change_no_plan":     "No pending change for this case, please preview the change first",
+	"app_case_change_applying":    "Applying change to %s: %s",
+	"app_case_change_success":     "Case %s changed successfully",
+	"app_case_change_failed":      "Failed to change case %s: %v",
+
+	// Remote state backend
+	"backend_short":               "Manage the project's remote state backend",
+	"backend_show_short":          "Show the remote state backend of the current project",
+	"backend_migrate_short":       "Migrate local case states into the configured remote backend",
+	"backend_migrate_long":        "Move the terraform state of every case in the current project into the remote backend configured under `backends.<project>` in config.yaml. Cases already using the backend are skipped; the local state is kept as terraform.tfstate.migrated.",
+	"backend_local":               "Project %s uses local state (no backend configured)",
+	"backend_missing_field":       "%s backend requires field: %s",
+	"backend_unsupported_type":    "Unsupported backend type: %s (supported: s3, http)",
+	"backend_not_configured":      "No remote backend configured for project %s",
+	"backend_template_declared":   "Template in %s already declares a backend, skip redc backend",
+	"backend_config_failed":       "Failed to configure remote backend: %v",
+	"backend_migrate_case_failed": "Failed to migrate state of %s: %v",
+	"backend_migrate_case_done":   "State of %s migrated to remote backend",
+
+	// Case lock
+	"lock_case_locked":      "case %s is locked by another operation (%s, owner %s, pid %d@%s, since %s); use 'redc unlock' if the holder is gone",
+	"lock_release_failed":   "failed to release lock of case %s: %v",
+	"lock_renew_failed":     "failed to renew lock of case %s: %v",
+	"lock_stale_taken_over": "taking over stale lock of case %s (%s, owner %s, pid %d)",
+	"unlock_short":          "Force release a case lock",
+	"unlock_long":           "Force release the cross-process lock of a case left behind by a crashed process.\nWithout arguments (or with --list) all current locks are listed.",
+	"unlock_flag_list":      "list all case locks",
+	"unlock_failed":         "failed to unlock case %s: %v",
+	"unlock_not_locked":     "case %s is not locked",
+	"unlock_active_warning": "lock is still held by a live process (%s, owner %s, pid %d), the running operation may conflict",
+	"unlock_done":           "case %s unlocked (was %s by %s)",
+	"unlock_no_locks":       "no case locks",
+
+	// Case export/import bundle
+	"bundle_plaintext_warning":   "no passphrase given, secrets (state, parameters, keys) are stored in the bundle in plaintext",
+	"bundle_create_failed":       "failed to create bundle %s: %v",
+	"bundle_state_failed":        "failed to read terraform state: %v",
+	"bundle_exported":            "case %s exported to %s (%d entries)",
+	"bundle_open_failed":         "failed to open bundle %s: %v",
+	"bundle_invalid":             "invalid bundle: %v",
+	"bundle_version_unsupported": "unsupported bundle version %d, please upgrade redc",
+	"bundle_passphrase_required": "bundle is encrypted, a passphrase is required",
+	"bundle_checksum_mismatch":   "checksum mismatch for %s, bundle is corrupted or tampered",
+	"bundle_decrypt_failed":      "failed to decrypt bundle, wrong passphrase?",
+	"bundle_template_missing":    "template %s referenced by the bundle is not installed: %v",
+	"bundle_case_exists":         "case %s already exists in project %s",
+	"bundle_extract_failed":      "failed to extract bundle: %v",
+	"bundle_init_failed":         "terraform init for imported case %s failed, please check providers: %v",
+	"bundle_tags_failed":         "failed to restore tags: %v",
+	"bundle_imported":            "case %s (%s) imported into project %s",
+	"export_short":               "Export a case into a bundle for handover",
+	"export_long":                "Package the case directory, terraform state, metadata, plugin outputs and tags into a single bundle with a manifest and checksums.\nUse --passphrase (or REDC_BUNDLE_PASSPHRASE) to encrypt the secrets in the bundle.",
+	"export_flag_output":         "output file (default <name>-<id>.redcbundle)",
+	"bundle_flag_passphrase":     "passphrase for encrypting/decrypting secrets (env REDC_BUNDLE_PASSPHRASE)",
+	"import_short":               "Import a case bundle into the current project",
+	"import_long":                "Restore a bundle created by 'redc export' into the project selected with --project, rebuild its record and run terraform init.\nThe template referenced by the case must be installed.",
+	"import_flag_skip_init":      "skip terraform init after import",
+	"import_flag_verify":         "only verify the bundle checksums and print the manifest",
+
+	// Case TTL
+	"lease_invalid_ttl":          "invalid TTL %q, use values like 30m, 8h or 2d",
+	"lease_not_set":              "case %s has no TTL, use --set first",
+	"lease_clear_failed":         "failed to clear TTL of case %s: %v",
+	"lease_set_failed":           "failed to set TTL of case %s: %v",
+	"lease_set":                  "case %s will be destroyed automatically at %s",
+	"lease_extended":             "TTL of case %s extended, now expires at %s",
+	"lease_cleared":              "TTL of case %s cleared",
+	"lease_none":                 "case %s has no TTL",
+	"lease_show":                 "case %s expires at %s (remaining %s)",
+	"lease_warn_title":           "Case TTL expiring",
+	"lease_warn_message":         "Case [%s] expires in %s (%s) and will be destroyed automatically. Extend it with 'redc ttl --extend' if still needed.",
+	"lease_warn_minutes_invalid": "warning minutes must be greater than 0",
+	"flag_plan_ttl":              "time-to-live, the case is destroyed automatically when it expires (e.g. 8h, 2d)",
+	"ttl_short":                  "Show, set or extend the TTL of a case",
+	"ttl_long":                   "Manage the time-to-live of cases. Expired cases are destroyed automatically by the task scheduler of the GUI / HTTP server, with a warning sent via notifications and webhooks beforehand.\nWithout arguments all cases with a TTL are listed.",
+	"ttl_flag_set":               "set a new TTL counted from now (0 clears)",
+	"ttl_flag_extend":            "extend the current TTL by the given duration",
+	"ttl_flag_clear":             "clear the TTL",
+
+	// adopt - 纳管已有资源
+	"tf_import_failed":       "failed to import %s (%s): %v",
+	"adopt_short":            "Adopt existing cloud resources into a new case",
+	"adopt_long":             "Create a case from a template and terraform import existing resources given as address=id pairs.\nThe remaining plan diff is shown so it can be reconciled before the case is marked running.\nUse --finish <case> to re-check and complete a pending adoption.",
+	"flag_adopt_import":      "resource to import as address=id (repeatable)",
+	"flag_adopt_finish":      "complete the adoption of an existing pending case",
+	"flag_adopt_yes":         "apply the remaining diff without confirmation",
+	"adopt_invalid_target":   "invalid import target %q, expected address=id",
+	"adopt_duplicate_target": "resource address %s is imported more than once",
+	"adopt_no_targets":       "no resources to import, use --import address=id",
+	"adopt_unknown_address":  "resource address %s is not defined by the template, available: %s",
+	"adopt_importing":        "Importing %s (%s)",
+	"adopt_cleanup_failed":   "failed to clean up case %s after import error: %v",
+	"adopt_pending_changes":  "case %s still differs from the template (%s), apply or reconcile before marking it running",
+	"adopt_finished":         "Case %s adopted and marked running",
+	"adopt_failed":           "Adoption failed: %v",
+	"adopt_left_pending":     "Case %s (%s) left pending, reconcile the template and run: redc adopt --finish %[2]s",
+	"app_adopt_importing":    "Adopting resources with template %s (%d to import)...",
+	"app_adopt_imported":     "Imported %[2]d resources into case %[1]s",
+
+	// targeted change - 针对单个资源的变更
+	"flag_change_target":             "only change this resource address (repeatable)",
+	"flag_change_replace":            "force recreation of this resource instance (repeatable)",
+	"flag_change_destroy":            "destroy the --target resources instead of applying",
+	"target_destroy_requires_target": "--destroy requires at least one --target address",
+	"target_destroy_with_replace":    "--destroy cannot be combined with --replace",
+	"target_not_in_state":            "resource address %s not found in case state, available: %s",
+	"target_with_rebuild":            "--target/--replace cannot be combined with --rm",
+	"target_case_not_running":        "case %s is not running, targeted changes need existing resources",
+	"app_case_target_start":          "Applying targeted change to %s: %s",
+	"app_case_target_success":        "Targeted change of %s completed",
+	"app_case_target_failed":         "Targeted change of %s failed: %v",
+
+	// saved plan - 已保存的 plan
+	"plans_short":              "List or save reviewed plans of a case",
+	"plans_long":               "Saved plans keep the exact terraform plan file with its SHA256 hash, timestamp and state serial.\n`redc start <case> --plan <plan id>` applies exactly that file and refuses if the state changed since planning.",
+	"flag_plans_save":          "run terraform plan now and save the result",
+	"flag_start_plan":          "apply this saved plan instead of planning again",
+	"plan_saved":               "Plan %s saved (%s), apply it with: redc start %s --plan %[1]s",
+	"plan_save_failed":         "Failed to save plan: %v",
+	"plan_not_found":           "saved plan %s not found",
+	"plan_file_missing":        "saved plan file unavailable: %v",
+	"plan_hash_mismatch":       "plan %s was modified after it was saved (SHA256 mismatch)",
+	"plan_already_applied":     "plan %s was already applied at %s",
+	"plan_stale_serial":        "plan %s is stale: state serial was %d when planned, now %d; create a new plan",
+	"plan_stale_lineage":       "plan %s belongs to a different state lineage; create a new plan",
+	"plan_state_parse_failed":  "failed to parse terraform state: %v",
+	"plan_applying":            "Applying saved plan %s to %s (%s)",
+	"plan_mark_applied_failed": "failed to mark plan %s as applied: %v",
+	"app_plan_apply_start":     "Applying saved plan %s to %s",
+	"app_plan_apply_success":   "Saved plan %s applied to %s",
+	"app_plan_apply_failed":    "Failed to apply saved plan %s to %s: %v",
+
+	// case operation logs - 场景操作日志
+	"case_logs_short":     "Show the persisted operation logs of a case",
+	"case_logs_long":      "Every terraform operation, SSH command and plugin hook on a case is logged to its own file.\nWithout --op the latest operation is shown; -f follows it until it finishes.",
+	"flag_case_logs_op":   "operation number to show (default: latest)",
+	"flag_case_logs_list": "list recorded operations",
+	"oplog_none":          "case %s has no recorded operations",
+	"oplog_not_found":     "operation %d of case %s not found",
+	"oplog_case_error":    "case %s entered error state",
+
+	// Reconcile interrupted operations
+	"reconcile_short":          "Correct cases stuck in starting/stopping/removing",
+	"reconcile_long":           "Inspect cases left in a transitional state by an interrupted process, correct their state from the terraform state and record the interrupted operation. Use --resume or --rollback to finish or undo it.",
+	"flag_reconcile_resume":    "Continue the interrupted operation of the case",
+	"flag_reconcile_rollback":  "Undo the interrupted operation of the case",
+	"flag_reconcile_dismiss":   "Forget the interrupted operation and keep the corrected state",
+	"flag_reconcile_list":      "List interrupted operations waiting for resume or rollback",
+	"reconcile_nothing":        "No case is stuck in a transitional state",
+	"reconcile_skipped":        "operation still running (lock held)",
+	"reconcile_hint":           "Run redc reconcile --resume <case> or --rollback <case> to handle interrupted operations",
+	"reconcile_corrected":      "Case %s was stuck in %s, corrected to %s (%d resources in state)",
+	"reconcile_removed":        "Case %s was being removed and its directory is gone, record deleted",
+	"reconcile_record_failed":  "Failed to record interrupted operation of %s: %v",
+	"reconcile_no_interrupted": "Case %s has no interrupted operation",
+	"reconcile_failed":         "Reconcile failed: %v",
+	"reconcile_case_failed":    "Failed to reconcile case %s: %s",
+	"reconcile_dismissed":      "Interrupted operation of %s dismissed",
+	"reconcile_action_start":   "Running %s of interrupted %s for %s",
+	"reconcile_action_success": "%s of interrupted %s for %s finished",
+	"reconcile_action_failed":  "%s for %s failed: %v",
+
+	// Case state machine
+	"state_transition_invalid":       "Case %s cannot change state from %s to %s",
+	"state_transition_record_failed": "Failed to record state transition of case %s: %v",
+	"history_short":                  "Show the state transition history of a case",
+	"history_long":                   "Show every state change of a case with the user and entry point (cli/gui/http/mcp/scheduler) that caused it, newest first.",
+	"history_empty":                  "Case %s has no recorded state transitions (current state: %s)",
+
+	// Deployment policy
+	"policy_rule_missing_field":   "policy rule %d (%s) requires %s",
+	"policy_rule_bad_size":        "policy rule %d: unrecognized maxSize %q",
+	"policy_rule_bad_type":        "policy rule %d: unknown type %q (region, instance_type, open_port, spot, cost, resource_type)",
+	"policy_rule_bad_effect":      "policy rule %d: effect must be deny or warn, got %q",
+	"policy_denied":               "Deployment of %s denied by policy:\n%s",
+	"policy_warning":              "Policy warning for %s: %s",
+	"policy_load_failed":          "Failed to load the deployment policy, refusing to deploy %s: %v",
+	"policy_msg_resource_type":    "resource type %s is not allowed",
+	"policy_msg_region":           "region %s is not allowed",
+	"policy_msg_instance_type":    "instance type %s is not allowed",
+	"policy_msg_instance_size":    "instance type %s is larger than %s",
+	"policy_msg_open_port":        "management port %s is open to 0.0.0.0/0",
+	"policy_msg_spot":             "instance is not a spot instance",
+	"policy_msg_cost_unavailable": "cost could not be estimated, cost rule skipped",
+	"policy_msg_cost_unknown":     "cost could not be estimated, so the cost limit cannot be verified",
+	"policy_msg_cost_monthly":     "estimated monthly cost %.2f %s exceeds %.2f",
+	"policy_msg_cost_hourly":      "estimated hourly cost %.4f %s exceeds %.4f",
+	"policy_updated":              "Deployment policy updated",
+	"policy_cleared":              "Deployment policy cleared",
+	"policy_none":                 "No deployment policy in the active profile",
+	"policy_passed":               "Case %s passes the deployment policy",
+	"policy_set_failed":           "Failed to set policy: %v",
+	"policy_short":                "Manage deployment guardrail policies",
+	"policy_long":                 "Deployment policies are stored in the active profile and checked against the terraform plan and cost estimate before every apply. Rules with effect deny block the apply; warn only reports.",
+	"policy_show_short":           "Show the deployment policy",
+	"policy_set_short":            "Set the deployment policy from a YAML or JSON file",
+	"policy_clear_short":          "Remove the deployment policy",
+	"policy_check_short":          "Check the current plan of a case against the policy",
+
+	// Secret masking
+	"secret_revealed":    "Sensitive output %s of case %s was revealed",
+	"flag_status_reveal": "Show sensitive outputs in clear text",
+
+	// Credential store
+	"cred_locked":              "credentials are encrypted and locked",
+	"cred_locked_hint":         "Encrypted credentials are locked; run redc cred unlock or set REDC_MASTER_PASSPHRASE",
+	"cred_seal_locked":         "Encrypted credentials are locked, refusing to store the secret in plain text; run redc cred unlock or set REDC_MASTER_PASSPHRASE",
+	"cred_resolve_failed":      "Failed to resolve credentials: %v",
+	"cred_decrypt_failed":      "failed to decrypt credential",
+	"cred_wrong_passphrase":    "wrong master passphrase or keyfile",
+	"cred_keyfile_read_failed": "failed to read keyfile %s: %v",
+	"cred_command_failed":      "credential command %q failed: %v",
+	"cred_process_bad_output":  "credential_process output is not a JSON object: %v",
+	"cred_passphrase_required": "a master passphrase is required (prompt or REDC_MASTER_PASSPHRASE)",
+	"cred_passphrase_mismatch": "passphrases do not match",
+	"cred_not_encrypted":       "credential store is not initialized, run redc cred migrate first",
+	"cred_prompt_new":          "New master passphrase: ",
+	"cred_prompt_confirm":      "Confirm master passphrase: ",
+	"cred_prompt_passphrase":   "Master passphrase: ",
+	"cred_migrated":            "Encrypted %d credentials in %s",
+	"cred_rotated":             "Re-encrypted %d credentials with the new master key",
+	"cred_unlocked_gui":        "Credentials unlocked",
+	"cred_locked_done":         "Credentials locked",
+	"cred_short":               "Manage the encrypted credential store",
+	"cred_long":                "Provider credentials in config.yaml can be stored encrypted (AES-GCM, key derived from a master passphrase or keyfile), or as env:VAR / exec:command references. An empty field can also be filled by the credentials.credential_process command.",
+	"cred_migrate_short":       "Encrypt plain-text credentials in the config",
+	"cred_rotate_short":        "Re-encrypt credentials with a new passphrase or keyfile",
+	"cred_unlock_short":        "Unlock encrypted credentials for a period of time",
+	"cred_lock_short":          "Lock encrypted credentials",
+	"cred_status_short":        "Show how each credential is stored",
+	"flag_cred_keyfile":        "Use a keyfile instead of a passphrase (generated if missing)",
+	"flag_cred_ttl":            "How long the unlock lasts",
+
+	// Named accounts
+	"account_not_found":          "account %s is not defined under any provider",
+	"account_not_found_provider": "account %s is not defined for provider %s",
+	"flag_plan_account":          "Named provider account to use (accounts: in config.yaml)",
+
+	// Assume role
+	"role_duration_invalid":  "invalid session_duration for %s: %q",
+	"role_mfa_required":      "MFA code required for %s (%s): set REDC_MFA_TOKEN or run redc cred assume --mfa-code",
+	"role_mfa_unsupported":   "%s AssumeRole does not accept MFA codes, enforce MFA in the role trust policy instead",
+	"role_assume_failed":     "assume role failed for %s (%s): %v",
+	"role_cache_save_failed": "failed to cache temporary credentials: %v",
+	"role_prompt_mfa":        "MFA code for %s (%s): ",
+	"cred_assume_short":      "Assume the configured roles and cache temporary credentials",
+	"cred_assumed":           "%s: temporary credentials valid until %s",
+	"cred_no_role":           "no role_arn configured for %s",
+	"cred_no_roles":          "no provider has a role_arn configured",
+	"flag_cred_account":      "named account whose roles to assume",
+	"flag_cred_mfa_code":     "MFA code for roles with mfa_serial",
+
+	// Compose health checks
+	"compose_health_invalid": "invalid healthcheck for service %s: %s",
+	"compose_health_start":   "[%s] Waiting for %s health check...",
+	"compose_health_waiting": "[%s] Health check %d/%d not passed: %v",
+	"compose_health_passed":  "[%s] %s health check passed (attempt %d)",
+	"compose_health_failed":  "service [%s] failed %s health check after %d attempts: %v",
+
+	// Compose 并发调度
+	"compose_parallel_invalid":       "Invalid compose parallel limit %s: %d (must be positive)",
+	"compose_service_skipped":        "Service [%s] skipped: a dependency failed or execution stopped after an error",
+	"flag_compose_parallel":          "Maximum number of services deployed/destroyed at the same time (default 4, 1 = sequential)",
+	"flag_compose_provider_limit":    "Per-provider concurrency limit, e.g. aliyun=2 (cloud image prefix or provider alias)",
+	"flag_compose_continue_on_error": "Keep running unaffected services after an error instead of failing fast",
+
+	// Compose 编排记录
+	"compose_stack_not_found":     "Compose stack not found: %s",
+	"compose_stack_save_failed":   "Failed to save compose stack record %s: %v",
+	"compose_stack_down":          "Destroying compose stack %s from its record (file: %s)",
+	"compose_stack_file_modified": "Compose stack %s: file %s changed since the last up, showing the recorded services",
+	"compose_stack_file_missing":  "Compose stack %s: file %s no longer exists, showing the recorded services",
+	"compose_ps_short":            "List deployed compose stacks and their cases",
+	"compose_ps_empty":            "No compose stacks deployed in this project",
+	"flag_compose_stack":          "Compose stack name (default: name in the compose file, or its directory name)",
+
+	// Compose 失败处理
+	"compose_on_failure_invalid":     "Invalid compose on_failure policy: %s (expected rollback, keep or pause)",
+	"compose_paused":                 "Compose stack %s paused after a failure: %v",
+	"compose_paused_kept":            "Compose stack %s kept as is; run `redc compose up` again to resume or `redc compose down %s` to destroy it",
+	"compose_pause_prompt":           "Roll back the cases created in this run? [y/N]",
+	"compose_rollback_start":         "Rolling back compose stack %s: destroying %d case(s) created in this run",
+	"compose_rollback_service":       "Rolling back service [%s]...",
+	"compose_rollback_service_done":  "Service [%s] rolled back",
+	"compose_rollback_remove_failed": "Service [%s] destroyed but its case could not be removed: %v",
+	"compose_rollback_failed":        "Rollback of compose stack %s failed: %v",
+	"compose_rollback_done":          "Compose stack %s rolled back, %d case(s) destroyed",
+	"compose_setup_failed":           "Setup task [%s] failed on service [%s]: %v",
+	"compose_stack_paused":           "Compose stack %s is paused after a failed up",
+	"flag_compose_on_failure":        "Action when up fails: rollback (destroy cases created in this run), keep (default) or pause (ask)",
+
+	// Compose 定向操作与扩缩容
+	"compose_service_not_found":        "Service %s is not defined in the compose file or not active in the selected profiles",
+	"compose_services_with_deps":       "Targeting %s, including dependencies: %s",
+	"compose_services_with_dependents": "Targeting %s, including services that depend on them: %s",
+	"compose_dependency_running":       "Dependency [%s] is already running, reusing its case",
+	"compose_scale_invalid":            "Invalid replica count for %s: %d (must be at least 1, use compose down to remove a service)",
+	"compose_scale_arg_invalid":        "Invalid scale argument %s, expected service=replicas",
+	"compose_scale_start":              "Scaling compose stack %s: %d instance(s) to add, %d to remove",
+	"compose_scale_done":               "Compose stack %s scaled: %d instance(s) added, %d removed",
+	"compose_scale_remove_failed":      "Instance [%s] destroyed but its case could not be removed: %v",
+	"compose_scale_failed":             "Compose scale failed: %v",
+	"compose_scale_short":              "Change the replica count of services in a deployed compose stack",
+	"compose_scale_long":               "Scale services of a deployed stack, e.g. redc compose scale scanner=5. Only the missing instances are deployed and the extra ones destroyed; setup tasks that reference the scaled services run again. The replicas in the compose file are not changed.",
+
+	// App Compose 扩缩容
+	"app_compose_scale_start":  "Starting compose scale: %s",
+	"app_compose_scale_failed": "compose scale failed: %v",
+	"app_compose_scale_done":   "compose scale completed",
+
+	// Compose 变量插值与文件合并
+	"compose_var_unset":        "Variable %s is not set (used at %s), substituting an empty string",
+	"compose_var_required":     "Required variable %s is not set (used at %s): %s",
+	"compose_include_invalid":  "Invalid include/extends reference: %s",
+	"compose_include_cycle":    "Compose file %s includes or extends itself",
+	"compose_include_conflict": "%s.%s is defined more than once (while merging %s)",
+	"compose_env_file_failed":  "Failed to read env file %s: %v",
+	"compose_env_file_invalid": "Invalid env file %s: line %d is not KEY=VALUE",
+	"flag_compose_resolved":    "Print the compose file after merging include/extends and interpolating variables",
+
+	// Compose plan
+	"compose_plan_short":          "Show what compose up would change in a deployed stack",
+	"compose_plan_long":           "Compare the compose file with the live cases of its stack and their parameters without changing anything: services added or removed, replica changes, environment/configs changes that force a re-apply, the Terraform plan of every affected case and the estimated monthly cost change.",
+	"compose_plan_service":        "Planning service [%s]...",
+	"compose_plan_vars_pending":   "variables depend on services that are not deployed yet (%v), resource changes are known after apply",
+	"compose_plan_cost_failed":    "Cost estimate for [%s] failed: %v",
+	"compose_plan_currency_mixed": "[%s] is priced in %s, not included in the %s total",
+	"compose_plan_summary":        "Plan: %d instance(s) to create, %d to update, %d to destroy; resources: create %d, update %d, replace %d, destroy %d",
+	"compose_plan_no_changes":     "No changes. Compose stack %s matches the compose file",
+	"compose_plan_not_deployed":   "Compose stack %s has not been deployed yet, every service will be created",
+	"compose_plan_added":          "Services added: %s",
+	"compose_plan_removed":        "Services removed from the compose file: %s (compose up keeps them, destroy with compose scale or compose down)",
+	"compose_plan_replicas":       "Replicas of %s: %d -> %d",
+	"compose_plan_cost_delta":     "Estimated monthly cost change: %+.2f %s",
+	"compose_plan_failed":         "Compose plan failed: %v",
+	"compose_reapply_service":     "[%s] environment/configs changed, re-applying: %s",
+	"app_compose_plan_start":      "Starting compose plan: %s",
+	"app_compose_plan_done":       "compose plan completed: %s",
+
+	// compose secrets
+	"compose_secret_source":     "secret '%s' must set exactly one of file, environment, encrypted or generate",
+	"compose_secret_length":     "secret '%s' generate length %d is out of range (%d-%d)",
+	"compose_secret_undefined":  "secret '%s' referenced by %s is not defined in secrets",
+	"compose_secret_failed":     "failed to load secret '%s': %v",
+	"compose_secret_too_short":  "secret '%s' is shorter than %d characters and will not be masked in logs",
+	"compose_secret_env_unset":  "environment variable %s is not set",
+	"compose_secret_generated":  "generated secret '%s' for stack %s",
+	"compose_secret_not_loaded": "secret '%s' is not loaded",
+	"cred_encrypt_short":        "Encrypt a value with the credential store master key (for compose secrets)",
+	"cred_prompt_value":         "Value to encrypt: ",
+	"cred_value_required":       "a value is required (prompt or stdin)",
+
+	// credential session token
+	"cred_unlocked_session": "Credentials unlocked for %s; export %s in this shell (eval \"$(redc cred unlock)\") to use the session",
 }
@@ -579,4 +579,389 @@ var zhMessages = map[string]string{
 
 	// Timeline
 	"app_timeline_app_started": "应用启动",
+
+	// Drift detection
+	"drift_short":                  "检测场景状态与云上实际资源的漂移",
+	"drift_long":                   "对指定场景（未指定时为项目内所有运行中场景）执行 refresh-only plan，列出云上实际配置与 terraform state 不一致的资源。",
+	"flag_drift_interval":          "按指定间隔（如 30m）周期性检测漂移，并将结果写入时间线",
+	"drift_watch_started":          "周期漂移检测已启动，间隔: %s",
+	"drift_plan_failed":            "refresh-only plan 执行失败: %v",
+	"drift_case_not_running":       "场景 %s 未运行 (状态: %s)，跳过漂移检测",
+	"drift_case_failed":            "场景 %s 漂移检测失败: %v",
+	"drift_failed":                 "漂移检测失败: %v",
+	"drift_detected":               "场景 %s 检测到漂移: %d 个资源在 terraform 之外被修改",
+	"drift_no_running_cases":       "没有需要检测的运行中场景",
+	"app_drift_monitor_started":    "漂移检测已启动",
+	"app_drift_monitor_stopped":    "漂移检测已停止",
+	"app_drift_interval_too_short": "漂移检测间隔不能小于 5 分钟",
+	"notify_drift_detected":        "检测到资源漂移",
+	"notify_drift_detected_msg":    "场景 %s: %d 个资源与 terraform state 不一致",
+
+	// Case change
+	"flag_change_yes":             "跳过确认直接应用变更",
+	"change_confirm_prompt":       "变更计划: %s。确认应用这些变更吗? [y/N]",
+	"case_change_plan_failed":     "生成变更计划失败: %v",
+	"case_change_plan_changed":    "%s 审阅过的 plan 文件缺失或已被其他命令覆盖，请重新生成变更计划",
+	"case_change_applying":        "正在应用场景 %s 的变更 (%s)",
+	"case_change_history_failed":  "记录变更历史失败: %v",
+	"case_change_rebuilt":         "场景已按新参数销毁重建",
+	"case_change_params_only":     "参数已更新，下次启动时生效",
+	"case_change_params_saved":    "场景 %s 参数已保存，下次启动时生效",
+	"case_change_no_changes":      "场景 %s 无资源变更，参数已保存",
+	"case_change_cancelled":       "变更已取消 (使用 --yes 可跳过确认)",
+	"app_case_change_not_running": "场景 %s 未运行，原地变更仅适用于运行中的场景",
+	"app_case_change_no_plan":     "该场景没有待确认的变更，请先预览变更",
+	"app_case_change_applying":    "正在变更场景 %s: %s",
+	"app_case_change_success":     "场景 %s 变更成功",
+	"app_case_change_failed":      "场景 %s 变更失败: %v",
+
+	// Remote state backend
+	"backend_short":               "管理项目的远程 state backend",
+	"backend_show_short":          "查看当前项目的远程 state backend",
+	"backend_migrate_short":       "将本地场景 state 迁移到已配置的远程 backend",
+	"backend_migrate_long":        "将当前项目所有场景的 terraform state 迁移到 config.yaml 中 `backends.<项目名>` 配置的远程 backend。已使用远程 backend 的场景会被跳过，本地 state 保留为 terraform.tfstate.migrated。",
+	"backend_local":               "项目 %s 使用本地 state (未配置远程 backend)",
+	"backend_missing_field":       "%s backend 缺少必填字段: %s",
+	"backend_unsupported_type":    "不支持的 backend 类型: %s (支持: s3, http)",
+	"backend_not_configured":      "项目 %s 未配置远程 backend",
+	"backend_template_declared":   "%s 中的模板已声明 backend，跳过 redc 远程 backend 配置",
+	"backend_config_failed":       "配置远程 backend 失败: %v",
+	"backend_migrate_case_failed": "场景 %s state 迁移失败: %v",
+	"backend_migrate_case_done":   "场景 %s state 已迁移到远程 backend",
+
+	// Case lock
+	"lock_case_locked":      "场景 %s 正被其他操作锁定 (%s, 持有者 %s, pid %d@%s, 自 %s 起)；如持有进程已退出可使用 'redc unlock' 解锁",
+	"lock_release_failed":   "释放场景 %s 的锁失败: %v",
+	"lock_renew_failed":     "场景 %s 锁续约失败: %v",
+	"lock_stale_taken_over": "接管场景 %s 的过期锁 (%s, 持有者 %s, pid %d)",
+	"unlock_short":          "强制释放场景锁",
+	"unlock_long":           "强制释放崩溃进程遗留的场景跨进程锁。\n不带参数 (或使用 --list) 时列出当前所有锁。",
+	"unlock_flag_list":      "列出所有场景锁",
+	"unlock_failed":         "解锁场景 %s 失败: %v",
+	"unlock_not_locked":     "场景 %s 未被锁定",
+	"unlock_active_warning": "锁仍由存活进程持有 (%s, 持有者 %s, pid %d)，正在执行的操作可能冲突",
+	"unlock_done":           "场景 %s 已解锁 (原操作 %s, 持有者 %s)",
+	"unlock_no_locks":       "当前没有场景锁",
+
+	// Case export/import bundle
+	"bundle_plaintext_warning":   "未设置口令，导出包中的敏感内容 (state、参数、密钥) 将以明文保存",
+	"bundle_create_failed":       "创建导出包 %s 失败: %v",
+	"bundle_state_failed":        "读取 terraform state 失败: %v",
+	"bundle_exported":            "场景 %s 已导出到 %s (共 %d 个条目)",
+	"bundle_open_failed":         "打开导出包 %s 失败: %v",
+	"bundle_invalid":             "无效的导出包: %v",
+	"bundle_version_unsupported": "不支持的导出包版本 %d，请升级 redc",
+	"bundle_passphrase_required": "导出包已加密，需要提供口令",
+	"bundle_checksum_mismatch":   "%s 校验和不匹配，导出包已损坏或被篡改",
+	"bundle_decrypt_failed":      "解密导出包失败，口令是否正确？",
+	"bundle_template_missing":    "导出包引用的模版 %s 未安装: %v",
+	"bundle_case_exists":         "场景 %s 已存在于项目 %s 中",
+	"bundle_extract_failed":      "解压导出包失败: %v",
+	"bundle_init_failed":         "导入的场景 %s 执行 terraform init 失败，请检查 provider: %v",
+	"bundle_tags_failed":         "恢复标签失败: %v",
+	"bundle_imported":            "场景 %s (%s) 已导入到项目 %s",
+	"export_short":               "导出场景为交接包",
+	"export_long":                "将场景目录、terraform state、元数据、插件输出和标签打包为带清单和校验和的单个文件。\n使用 --passphrase (或 REDC_BUNDLE_PASSPHRASE) 加密包中的敏感内容。",
+	"export_flag_output":         "输出文件 (默认 <name>-<id>.redcbundle)",
+	"bundle_flag_passphrase":     "加解密敏感内容的口令 (环境变量 REDC_BUNDLE_PASSPHRASE)",
+	"import_short":               "将场景交接包导入当前项目",
+	"import_long":                "将 'redc export' 生成的交接包恢复到 --project 指定的项目，重建场景记录并执行 terraform init。\n场景引用的模版必须已安装。",
+	"import_flag_skip_init":      "导入后不执行 terraform init",
+	"import_flag_verify":         "仅校验导出包并打印清单",
+
+	// Case TTL
+	"lease_invalid_ttl":          "无效的 TTL %q，请使用 30m、8h、2d 等格式",
+	"lease_not_set":              "场景 %s 未设置 TTL，请先使用 --set",
+	"lease_clear_failed":         "清除场景 %s 的 TTL 失败: %v",
+	"lease_set_failed":           "设置场景 %s 的 TTL 失败: %v",
+	"lease_set":                  "场景 %s 将于 %s 自动销毁",
+	"lease_extended":             "场景 %s 的 TTL 已延长，新的到期时间 %s",
+	"lease_cleared":              "场景 %s 的 TTL 已取消",
+	"lease_none":                 "场景 %s 未设置 TTL",
+	"lease_show":                 "场景 %s 将于 %s 到期 (剩余 %s)",
+	"lease_warn_title":           "场景即将到期",
+	"lease_warn_message":         "场景 [%s] 将在 %s 后 (%s) 到期并自动销毁，如仍需使用请通过 'redc ttl --extend' 延长。",
+	"lease_warn_minutes_invalid": "提醒时间必须大于 0 分钟",
+	"flag_plan_ttl":              "存活时间，到期后自动销毁场景 (如 8h、2d)",
+	"ttl_short":                  "查看、设置或延长场景 TTL",
+	"ttl_long":                   "管理场景的存活时间。到期的场景由 GUI / HTTP 服务的任务调度器自动销毁，并提前通过通知和 webhook 发送提醒。\n不带参数时列出所有设置了 TTL 的场景。",
+	"ttl_flag_set":               "设置从现在开始计算的新 TTL (0 表示取消)",
+	"ttl_flag_extend":            "在当前 TTL 基础上延长指定时长",
+	"ttl_flag_clear":             "取消 TTL",
+
+	// adopt - 纳管已有资源
+	"tf_import_failed":       "导入 %s (%s) 失败: %v",
+	"adopt_short":            "将已有云资源纳管为新场景",
+	"adopt_long":             "基于模板创建场景，并通过 terraform import 导入 address=id 形式指定的已有资源。\n导入后展示剩余的 plan 差异，确认调和后场景才会标记为运行中。\n使用 --finish <case> 重新检查并完成待定的纳管。",
+	"flag_adopt_import":      "要导入的资源，格式 address=id (可重复)",
+	"flag_adopt_finish":      "完成已有待定场景的纳管",
+	"flag_adopt_yes":         "不经确认直接应用剩余差异",
+	"adopt_invalid_target":   "无效的导入参数 %q，格式应为 address=id",
+	"adopt_duplicate_target": "资源地址 %s 被重复导入",
+	"adopt_no_targets":       "没有要导入的资源，请使用 --import address=id",
+	"adopt_unknown_address":  "模板中未定义资源地址 %s，可用地址: %s",
+	"adopt_importing":        "正在导入 %s (%s)",
+	"adopt_cleanup_failed":   "导入失败后清理场景 %s 失败: %v",
+	"adopt_pending_changes":  "场景 %s 与模板仍有差异 (%s)，需应用或调和后才能标记为运行中",
+	"adopt_finished":         "场景 %s 已纳管并标记为运行中",
+	"adopt_failed":           "纳管失败: %v",
+	"adopt_left_pending":     "场景 %s (%s) 保持待定，调和模板后执行: redc adopt --finish %[2]s",
+	"app_adopt_importing":    "使用模板 %s 纳管资源 (导入 %d 个)...",
+	"app_adopt_imported":     "已向场景 %[1]s 导入 %[2]d 个资源",
+
+	// targeted change - 针对单个资源的变更
+	"flag_change_target":             "只变更该资源地址 (可重复)",
+	"flag_change_replace":            "强制重建该资源实例 (可重复)",
+	"flag_change_destroy":            "销毁 --target 指定的资源而不是应用",
+	"target_destroy_requires_target": "--destroy 至少需要一个 --target 地址",
+	"target_destroy_with_replace":    "--destroy 不能与 --replace 同时使用",
+	"target_not_in_state":            "场景 state 中不存在资源地址 %s，可用地址: %s",
+	"target_with_rebuild":            "--target/--replace 不能与 --rm 同时使用",
+	"target_case_not_running":        "场景 %s 未运行，针对资源的变更需要已存在的资源",
+	"app_case_target_start":          "正在对 %s 执行资源级变更: %s",
+	"app_case_target_success":        "%s 的资源级变更已完成",
+	"app_case_target_failed":         "%s 的资源级变更失败: %v",
+
+	// saved plan - 已保存的 plan
+	"plans_short":              "列出或保存场景的已审阅 plan",
+	"plans_long":               "已保存的 plan 会保留 terraform plan 文件本身及其 SHA256、时间戳和 state serial。\n`redc start <case> --plan <plan id>` 原样应用该文件，若生成 plan 后 state 有变化则拒绝执行。",
+	"flag_plans_save":          "立即执行 terraform plan 并保存结果",
+	"flag_start_plan":          "应用该已保存的 plan，而不是重新生成",
+	"plan_saved":               "plan %s 已保存 (%s)，应用命令: redc start %s --plan %[1]s",
+	"plan_save_failed":         "保存 plan 失败: %v",
+	"plan_not_found":           "未找到已保存的 plan %s",
+	"plan_file_missing":        "plan 文件不可用: %v",
+	"plan_hash_mismatch":       "plan %s 保存后被修改 (SHA256 不匹配)",
+	"plan_already_applied":     "plan %s 已于 %s 应用",
+	"plan_stale_serial":        "plan %s 已过期: 生成时 state serial 为 %d，当前为 %d，请重新生成 plan",
+	"plan_stale_lineage":       "plan %s 属于不同的 state lineage，请重新生成 plan",
+	"plan_state_parse_failed":  "解析 terraform state 失败: %v",
+	"plan_applying":            "正在向 %[2]s 应用已保存的 plan %[1]s (%[3]s)",
+	"plan_mark_applied_failed": "标记 plan %s 为已应用失败: %v",
+	"app_plan_apply_start":     "正在向 %[2]s 应用已保存的 plan %[1]s",
+	"app_plan_apply_success":   "已保存的 plan %s 已应用到 %s",
+	"app_plan_apply_failed":    "向 %[2]s 应用已保存的 plan %[1]s 失败: %[3]v",
+
+	// case operation logs - 场景操作日志
+	"case_logs_short":     "查看场景的持久化操作日志",
+	"case_logs_long":      "场景上的每次 terraform 操作、SSH 命令和插件 hook 都会记录到独立的日志文件。\n未指定 --op 时显示最近一次操作；-f 持续跟随直到操作结束。",
+	"flag_case_logs_op":   "要查看的操作编号 (默认最近一次)",
+	"flag_case_logs_list": "列出已记录的操作",
+	"oplog_none":          "场景 %s 没有操作记录",
+	"oplog_not_found":     "未找到场景 %[2]s 的操作 %[1]d",
+	"oplog_case_error":    "场景 %s 进入错误状态",
+
+	// Reconcile interrupted operations
+	"reconcile_short":          "修正卡在 starting/stopping/removing 状态的场景",
+	"reconcile_long":           "检查因进程中断而停留在过渡状态的场景，按 terraform state 修正其状态并记录被中断的操作。可使用 --resume 继续或 --rollback 回滚该操作。",
+	"flag_reconcile_resume":    "继续执行该场景被中断的操作",
+	"flag_reconcile_rollback":  "回滚该场景被中断的操作",
+	"flag_reconcile_dismiss":   "忽略被中断的操作，保留修正后的状态",
+	"flag_reconcile_list":      "列出等待继续或回滚的中断操作",
+	"reconcile_nothing":        "没有卡在过渡状态的场景",
+	"reconcile_skipped":        "操作仍在进行 (锁有效)",
+	"reconcile_hint":           "使用 redc reconcile --resume <case> 或 --rollback <case> 处理被中断的操作",
+	"reconcile_corrected":      "场景 %s 卡在 %s，已修正为 %s (state 中有 %d 个资源)",
+	"reconcile_removed":        "场景 %s 删除中且目录已不存在，已删除残留记录",
+	"reconcile_record_failed":  "记录场景 %s 的中断操作失败: %v",
+	"reconcile_no_interrupted": "场景 %s 没有被中断的操作",
+	"reconcile_failed":         "修正场景状态失败: %v",
+	"reconcile_case_failed":    "修正场景 %s 失败: %s",
+	"reconcile_dismissed":      "已忽略场景 %s 的中断操作",
+	"reconcile_action_start":   "正在对场景 %[3]s 被中断的 %[2]s 执行 %[1]s",
+	"reconcile_action_success": "场景 %[3]s 被中断的 %[2]s 已完成 %[1]s",
+	"reconcile_action_failed":  "场景 %[2]s 执行 %[1]s 失败: %[3]v",
+
+	// Case state machine
+	"state_transition_invalid":       "场景 %s 不能从 %s 状态变为 %s",
+	"state_transition_record_failed": "记录场景 %s 的状态变迁失败: %v",
+	"history_short":                  "查看场景的状态变迁历史",
+	"history_long":                   "按时间倒序显示场景的每次状态变化，以及触发它的用户和入口 (cli/gui/http/mcp/scheduler)。",
+	"history_empty":                  "场景 %s 没有状态变迁记录 (当前状态: %s)",
+
+	// Deployment policy
+	"policy_rule_missing_field":   "策略规则 %d (%s) 需要设置 %s",
+	"policy_rule_bad_size":        "策略规则 %d: 无法识别的 maxSize %q",
+	"policy_rule_bad_type":        "策略规则 %d: 未知类型 %q (region、instance_type、open_port、spot、cost、resource_type)",
+	"policy_rule_bad_effect":      "策略规则 %d: effect 只能是 deny 或 warn，当前为 %q",
+	"policy_denied":               "%s 的部署被策略拒绝:\n%s",
+	"policy_warning":              "%s 策略警告: %s",
+	"policy_load_failed":          "无法加载部署策略，拒绝部署 %s: %v",
+	"policy_msg_resource_type":    "不允许使用资源类型 %s",
+	"policy_msg_region":           "不允许使用地域 %s",
+	"policy_msg_instance_type":    "不允许使用规格 %s",
+	"policy_msg_instance_size":    "规格 %s 超过上限 %s",
+	"policy_msg_open_port":        "管理端口 %s 对 0.0.0.0/0 开放",
+	"policy_msg_spot":             "实例未使用抢占式 (spot)",
+	"policy_msg_cost_unavailable": "无法估算成本，已跳过成本规则",
+	"policy_msg_cost_unknown":     "无法估算成本，不能确认是否超出成本上限",
+	"policy_msg_cost_monthly":     "预估月成本 %.2f %s 超过上限 %.2f",
+	"policy_msg_cost_hourly":      "预估小时成本 %.4f %s 超过上限 %.4f",
+	"policy_updated":              "部署策略已更新",
+	"policy_cleared":              "部署策略已清除",
+	"policy_none":                 "当前 profile 未配置部署策略",
+	"policy_passed":               "场景 %s 通过部署策略检查",
+	"policy_set_failed":           "设置策略失败: %v",
+	"policy_short":                "管理部署策略",
+	"policy_long":                 "部署策略保存在当前 profile 中，每次 apply 前根据 terraform plan 和成本估算进行检查。effect 为 deny 的规则会阻止 apply，warn 只提示。",
+	"policy_show_short":           "查看部署策略",
+	"policy_set_short":            "从 YAML 或 JSON 文件设置部署策略",
+	"policy_clear_short":          "清除部署策略",
+	"policy_check_short":          "用部署策略检查场景当前的 plan",
+
+	// Secret masking
+	"secret_revealed":    "敏感输出 %s (场景 %s) 已被查看",
+	"flag_status_reveal": "明文显示敏感输出",
+
+	// Credential store
+	"cred_locked":              "凭据已加密且未解锁",
+	"cred_locked_hint":         "加密凭据未解锁，请执行 redc cred unlock 或设置 REDC_MASTER_PASSPHRASE",
+	"cred_seal_locked":         "加密凭据未解锁，拒绝以明文保存 secret；请执行 redc cred unlock 或设置 REDC_MASTER_PASSPHRASE",
+	"cred_resolve_failed":      "解析凭据失败: %v",
+	"cred_decrypt_failed":      "凭据解密失败",
+	"cred_wrong_passphrase":    "主口令或密钥文件错误",
+	"cred_keyfile_read_failed": "读取密钥文件 %s 失败: %v",
+	"cred_command_failed":      "凭据命令 %q 执行失败: %v",
+	"cred_process_bad_output":  "credential_process 输出不是 JSON 对象: %v",
+	"cred_passphrase_required": "需要主口令 (交互输入或 REDC_MASTER_PASSPHRASE)",
+	"cred_passphrase_mismatch": "两次输入的口令不一致",
+	"cred_not_encrypted":       "凭据存储未初始化，请先执行 redc cred migrate",
+	"cred_prompt_new":          "新的主口令: ",
+	"cred_prompt_confirm":      "确认主口令: ",
+	"cred_prompt_passphrase":   "主口令: ",
+	"cred_migrated":            "已加密 %d 个凭据 (%s)",
+	"cred_rotated":             "已使用新主密钥重新加密 %d 个凭据",
+	"cred_unlocked_gui":        "凭据已解锁",
+	"cred_locked_done":         "凭据已锁定",
+	"cred_short":               "管理加密凭据存储",
+	"cred_long":                "config.yaml 中的云厂商凭据可以加密存储 (AES-GCM，密钥由主口令或密钥文件派生)，也可以写成 env:变量名 / exec:命令 引用。为空的字段还可以由 credentials.credential_process 命令提供。",
+	"cred_migrate_short":       "加密配置文件中的明文凭据",
+	"cred_rotate_short":        "使用新的口令或密钥文件重新加密凭据",
+	"cred_unlock_short":        "在一段时间内解锁加密凭据",
+	"cred_lock_short":          "锁定加密凭据",
+	"cred_status_short":        "查看各凭据的存储方式",
+	"flag_cred_keyfile":        "使用密钥文件代替口令 (不存在时自动生成)",
+	"flag_cred_ttl":            "解锁有效期",
+
+	// Named accounts
+	"account_not_found":          "未在任何云厂商下找到账号 %s",
+	"account_not_found_provider": "云厂商 %[2]s 下未找到账号 %[1]s",
+	"flag_plan_account":          "使用的命名账号 (config.yaml 中的 accounts)",
+
+	// Assume role
+	"role_duration_invalid":  "%s 的 session_duration 无效: %q",
+	"role_mfa_required":      "%s 需要 MFA 动态口令 (%s)：请设置 REDC_MFA_TOKEN 或执行 redc cred assume --mfa-code",
+	"role_mfa_unsupported":   "%s 的 AssumeRole 不支持 MFA 参数，请在角色信任策略中限制 MFA",
+	"role_assume_failed":     "%s 扮演角色失败 (%s): %v",
+	"role_cache_save_failed": "缓存临时凭据失败: %v",
+	"role_prompt_mfa":        "请输入 %s 的 MFA 动态口令 (%s): ",
+	"cred_assume_short":      "扮演已配置的角色并缓存临时凭据",
+	"cred_assumed":           "%s: 临时凭据有效期至 %s",
+	"cred_no_role":           "%s 未配置 role_arn",
+	"cred_no_roles":          "没有配置 role_arn 的云厂商",
+	"flag_cred_account":      "扮演该命名账号配置的角色",
+	"flag_cred_mfa_code":     "配置了 mfa_serial 的角色使用的 MFA 动态口令",
+
+	// Compose health checks
+	"compose_health_invalid": "服务 %s 的 healthcheck 配置无效: %s",
+	"compose_health_start":   "[%s] 等待 %s 健康检查通过...",
+	"compose_health_waiting": "[%s] 健康检查 %d/%d 未通过: %v",
+	"compose_health_passed":  "[%s] %s 健康检查通过 (第 %d 次)",
+	"compose_health_failed":  "服务 [%s] 的 %s 健康检查 %d 次均未通过: %v",
+
+	// Compose 并发调度
+	"compose_parallel_invalid":       "无效的编排并发限制 %s: %d (必须为正数)",
+	"compose_service_skipped":        "服务 [%s] 已跳过: 依赖失败或出错后停止执行",
+	"flag_compose_parallel":          "同时部署/销毁的最大服务数 (默认 4，1 为串行)",
+	"flag_compose_provider_limit":    "按云厂商限制并发，如 aliyun=2 (image 云厂商前缀或 provider 别名)",
+	"flag_compose_continue_on_error": "出错后继续执行不受影响的服务，而不是立即停止",
+
+	// Compose 编排记录
+	"compose_stack_not_found":     "编排记录不存在: %s",
+	"compose_stack_save_failed":   "保存编排记录 %s 失败: %v",
+	"compose_stack_down":          "按编排记录销毁 %s (文件: %s)",
+	"compose_stack_file_modified": "编排 %s: 文件 %s 自上次部署后已修改，显示记录中的服务",
+	"compose_stack_file_missing":  "编排 %s: 文件 %s 已不存在，显示记录中的服务",
+	"compose_ps_short":            "列出已部署的编排及其场景",
+	"compose_ps_empty":            "当前项目没有已部署的编排",
+	"flag_compose_stack":          "编排名称 (默认为编排文件中的 name 或所在目录名)",
+
+	// Compose 失败处理
+	"compose_on_failure_invalid":     "无效的编排失败处理策略: %s (可选 rollback、keep、pause)",
+	"compose_paused":                 "编排 %s 部署失败，已暂停: %v",
+	"compose_paused_kept":            "编排 %s 已保留现状；重新执行 `redc compose up` 继续部署，或执行 `redc compose down %s` 销毁",
+	"compose_pause_prompt":           "是否回滚本次创建的场景? [y/N]",
+	"compose_rollback_start":         "开始回滚编排 %s: 销毁本次创建的 %d 个场景",
+	"compose_rollback_service":       "正在回滚服务 [%s]...",
+	"compose_rollback_service_done":  "服务 [%s] 已回滚",
+	"compose_rollback_remove_failed": "服务 [%s] 已销毁，但删除场景失败: %v",
+	"compose_rollback_failed":        "编排 %s 回滚失败: %v",
+	"compose_rollback_done":          "编排 %s 回滚完成，已销毁 %d 个场景",
+	"compose_setup_failed":           "Setup 任务 [%s] 在服务 [%s] 上执行失败: %v",
+	"compose_stack_paused":           "编排 %s 上次部署失败后处于暂停状态",
+	"flag_compose_on_failure":        "部署失败时的处理: rollback (销毁本次创建的场景)、keep (保留，默认)、pause (询问)",
+
+	// Compose 定向操作与扩缩容
+	"compose_service_not_found":        "编排文件中未定义服务 %s，或该服务不在激活的 Profile 中",
+	"compose_services_with_deps":       "只操作 %s，同时包含其依赖: %s",
+	"compose_services_with_dependents": "只操作 %s，同时包含依赖它们的服务: %s",
+	"compose_dependency_running":       "依赖服务 [%s] 已在运行，直接使用现有场景",
+	"compose_scale_invalid":            "服务 %s 的副本数无效: %d (至少为 1，删除服务请使用 compose down)",
+	"compose_scale_arg_invalid":        "无效的扩缩容参数 %s，格式应为 服务=副本数",
+	"compose_scale_start":              "开始调整编排 %s: 新增 %d 个实例，移除 %d 个实例",
+	"compose_scale_done":               "编排 %s 调整完成: 新增 %d 个实例，移除 %d 个实例",
+	"compose_scale_remove_failed":      "实例 [%s] 已销毁，但删除场景失败: %v",
+	"compose_scale_failed":             "编排扩缩容失败: %v",
+	"compose_scale_short":              "调整已部署编排中服务的副本数",
+	"compose_scale_long":               "调整已部署编排中服务的副本数，例如 redc compose scale scanner=5。只部署缺少的实例、销毁多余的实例，并重新执行引用这些服务的 setup 任务。编排文件中的 replicas 不会被修改。",
+
+	// App Compose 扩缩容
+	"app_compose_scale_start":  "开始调整编排副本数: %s",
+	"app_compose_scale_failed": "编排扩缩容失败: %v",
+	"app_compose_scale_done":   "编排扩缩容完成",
+
+	// Compose 变量插值与文件合并
+	"compose_var_unset":        "变量 %s 未设置 (位于 %s)，替换为空字符串",
+	"compose_var_required":     "必需的变量 %s 未设置 (位于 %s): %s",
+	"compose_include_invalid":  "无效的 include/extends 引用: %s",
+	"compose_include_cycle":    "编排文件 %s 存在循环 include/extends",
+	"compose_include_conflict": "%s.%s 重复定义 (合并 %s 时)",
+	"compose_env_file_failed":  "读取 env 文件 %s 失败: %v",
+	"compose_env_file_invalid": "env 文件 %s 格式错误: 第 %d 行不是 KEY=VALUE",
+	"flag_compose_resolved":    "输出合并 include/extends 并完成变量插值后的编排文件",
+
+	// Compose plan
+	"compose_plan_short":          "显示 compose up 将对已部署编排做出的变更",
+	"compose_plan_long":           "比较编排文件与编排中已部署的场景及其参数，不修改任何资源：新增或移除的服务、副本数变化、需要重新 apply 的 environment/configs 变化、每个受影响场景的 Terraform plan 以及预估的月度成本变化。",
+	"compose_plan_service":        "正在计划服务 [%s]...",
+	"compose_plan_vars_pending":   "变量依赖尚未部署的服务 (%v)，资源变更在 apply 后才能确定",
+	"compose_plan_cost_failed":    "估算 [%s] 的成本失败: %v",
+	"compose_plan_currency_mixed": "[%s] 以 %s 计价，未计入 %s 合计",
+	"compose_plan_summary":        "计划: 新建 %d 个实例，更新 %d 个，销毁 %d 个；资源: 创建 %d，更新 %d，替换 %d，销毁 %d",
+	"compose_plan_no_changes":     "没有变更，编排 %s 与编排文件一致",
+	"compose_plan_not_deployed":   "编排 %s 尚未部署，所有服务都将新建",
+	"compose_plan_added":          "新增服务: %s",
+	"compose_plan_removed":        "已从编排文件移除的服务: %s (compose up 不会销毁，请使用 compose scale 或 compose down)",
+	"compose_plan_replicas":       "%s 副本数: %d -> %d",
+	"compose_plan_cost_delta":     "预估月度成本变化: %+.2f %s",
+	"compose_plan_failed":         "编排计划失败: %v",
+	"compose_reapply_service":     "[%s] environment/configs 已修改，重新 apply: %s",
+	"app_compose_plan_start":      "开始计划编排: %s",
+	"app_compose_plan_done":       "编排计划完成: %s",
+
+	// compose secrets
+	"compose_secret_source":     "secret '%s' 必须且只能设置 file、environment、encrypted、generate 中的一种",
+	"compose_secret_length":     "secret '%s' 的 generate 长度 %d 超出范围 (%d-%d)",
+	"compose_secret_undefined":  "%[2]s 引用的 secret '%[1]s' 未在 secrets 中定义",
+	"compose_secret_failed":     "读取 secret '%s' 失败: %v",
+	"compose_secret_too_short":  "secret '%s' 短于 %d 个字符，日志中不会脱敏",
+	"compose_secret_env_unset":  "环境变量 %s 未设置",
+	"compose_secret_generated":  "已为编排 %[2]s 生成 secret '%[1]s'",
+	"compose_secret_not_loaded": "secret '%s' 未加载",
+	"cred_encrypt_short":        "使用凭据存储主密钥加密一个值 (用于编排 secrets)",
+	"cred_prompt_value":         "要加密的值: ",
+	"cred_value_required":       "需要输入要加密的值 (交互输入或标准输入)",
+
+	// credential session token
+	"cred_unlocked_session": "凭据已解锁，有效期 %s；在当前 shell 中导出 %s (eval \"$(redc cred unlock)\") 后生效",
 }
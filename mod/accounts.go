@@ -0,0 +1,319 @@
+package mod
+
+import (
+	"fmt"
+	"red-cloud/i18n"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ProviderAccounts 云厂商下的命名账号：账号名 -> 配置项。
+// 配置项的键与该厂商配置相同 (如 AWS_ACCESS_KEY_ID、region)，值同样支持 enc: / env: / exec:
+type ProviderAccounts map[string]map[string]string
+
+// AccountVar CaseCreate 的 vars 中指定账号的保留键，不会作为 terraform 变量传递
+const AccountVar = "_account"
+
+const caseAccountBucket = "Case_Accounts"
+
+// providerAliases 模板名 / 定价服务中使用的厂商名 -> 配置文件中的厂商名
+var providerAliases = map[string]string{
+	"alicloud": "aliyun",
+	"tencent":  "tencentcloud",
+	"huawei":   "huaweicloud",
+	"gcp":      "google",
+	"oci":      "oracle",
+}
+
+// accountClearedEnv 使用命名账号时需要清除的默认账号环境变量
+var accountClearedEnv = map[string][]string{
+	"aws": {"AWS_SESSION_TOKEN"},
+}
+
+func normalizeProvider(p string) string {
+	p = strings.ToLower(strings.TrimSpace(p))
+	if alias, ok := providerAliases[p]; ok {
+		return alias
+	}
+	return p
+}
+
+// SplitProviderAccount 解析 provider:account 形式的厂商名，未指定账号时 account 为空
+func SplitProviderAccount(spec string) (provider, account string) {
+	provider, account, _ = strings.Cut(spec, ":")
+	return provider, account
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func yamlKey(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+	if name == "" {
+		name = f.Name
+	}
+	return name
+}
+
+// sectionEnvKeys 厂商配置中凭据字段的 yaml 键 -> 环境变量名
+func sectionEnvKeys(section reflect.Value) map[string]string {
+	keys := map[string]string{}
+	t := section.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("env"); tag != "" && tag != "-" {
+			keys[yamlKey(t.Field(i))] = tag
+		}
+	}
+	return keys
+}
+
+// providerSections 配置文件中的厂商名 -> 厂商配置
+func providerSections(conf *Config) map[string]reflect.Value {
+	sections := map[string]reflect.Value{}
+	v := reflect.ValueOf(&conf.Providers).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		sections[yamlKey(v.Type().Field(i))] = v.Field(i)
+	}
+	return sections
+}
+
+func sectionAccounts(section reflect.Value) ProviderAccounts {
+	for i := 0; i < section.NumField(); i++ {
+		if accounts, ok := section.Field(i).Interface().(ProviderAccounts); ok {
+			return accounts
+		}
+	}
+	return nil
+}
+
+// applyAccount 用账号的配置项覆盖厂商配置。账号中未设置的凭据字段和角色配置置空，避免混用默认账号的凭据；
+// region 等非凭据字段继承默认值
+func applyAccount(section reflect.Value, values map[string]string) {
+	t := section.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := section.Field(i)
+		if role, ok := f.Addr().Interface().(*RoleConfig); ok {
+			*role = roleFromValues(values)
+			continue
+		}
+		if f.Kind() != reflect.String {
+			continue
+		}
+		if v, ok := values[yamlKey(t.Field(i))]; ok {
+			f.SetString(v)
+		} else if tag := t.Field(i).Tag.Get("env"); tag != "" && tag != "-" {
+			f.SetString("")
+		}
+	}
+}
+
+// cloneAccounts 深拷贝命名账号，配置副本修改账号时不影响原配置
+func (c *Config) cloneAccounts() {
+	for _, section := range providerSections(c) {
+		for i := 0; i < section.NumField(); i++ {
+			accounts, ok := section.Field(i).Interface().(ProviderAccounts)
+			if !ok || accounts == nil {
+				continue
+			}
+			cp := make(ProviderAccounts, len(accounts))
+			for name, values := range accounts {
+				m := make(map[string]string, len(values))
+				for k, v := range values {
+					m[k] = v
+				}
+				cp[name] = m
+			}
+			section.Field(i).Set(reflect.ValueOf(cp))
+		}
+	}
+}
+
+// AccountNames 返回厂商下的命名账号，provider 为空时返回所有厂商的账号 (去重)
+func (c *Config) AccountNames(provider string) []string {
+	set := map[string]bool{}
+	for name, section := range providerSections(c) {
+		if provider != "" && name != normalizeProvider(provider) {
+			continue
+		}
+		for account := range sectionAccounts(section) {
+			set[account] = true
+		}
+	}
+	return sortedKeys(set)
+}
+
+// HasAccount 判断账号是否存在，provider 为空时在所有厂商中查找
+func (c *Config) HasAccount(provider, account string) bool {
+	for _, name := range c.AccountNames(provider) {
+		if name == account {
+			return true
+		}
+	}
+	return false
+}
+
+// ForAccount 返回使用命名账号的配置副本。provider 为空时替换所有定义了该账号的厂商；account 为空时返回原配置
+func (c *Config) ForAccount(provider, account string) (*Config, error) {
+	if account == "" {
+		return c, nil
+	}
+	cp := *c
+	found := false
+	for name, section := range providerSections(&cp) {
+		if provider != "" && name != normalizeProvider(provider) {
+			continue
+		}
+		values, ok := sectionAccounts(section)[account]
+		if !ok {
+			continue
+		}
+		applyAccount(section, values)
+		found = true
+	}
+	if !found {
+		if provider != "" {
+			return nil, fmt.Errorf("%s", i18n.Tf("account_not_found_provider", account, provider))
+		}
+		return nil, fmt.Errorf("%s", i18n.Tf("account_not_found", account))
+	}
+	return &cp, nil
+}
+
+// AccountEnv 返回账号对应的 terraform 环境变量，覆盖所有定义了该账号的厂商
+func AccountEnv(conf *Config, account string) (map[string]string, error) {
+	acc, err := conf.ForAccount("", account)
+	if err != nil {
+		return nil, err
+	}
+	env := map[string]string{}
+	for name, section := range providerSections(acc) {
+		if _, ok := sectionAccounts(section)[account]; !ok {
+			continue
+		}
+		t := section.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if tag := t.Field(i).Tag.Get("env"); tag != "" && tag != "-" && section.Field(i).Kind() == reflect.String {
+				env[tag] = section.Field(i).String()
+			}
+		}
+		for _, k := range accountClearedEnv[name] {
+			env[k] = ""
+		}
+	}
+	return env, nil
+}
+
+// activeConfig 当前加载的配置，未加载时读取配置文件
+func activeConfig() (*Config, error) {
+	if LoadedConfig != nil {
+		return LoadedConfig, nil
+	}
+	conf, _, err := ReadConfig(ActiveConfigPath)
+	return conf, err
+}
+
+// ValidateAccount 检查账号在当前配置中是否存在
+func ValidateAccount(account string) error {
+	if account == "" {
+		return nil
+	}
+	conf, err := activeConfig()
+	if err != nil {
+		return err
+	}
+	if !conf.HasAccount("", account) {
+		return fmt.Errorf("%s", i18n.Tf("account_not_found", account))
+	}
+	return nil
+}
+
+// dirAccounts terraform 工作目录 -> 账号名，NewTerraformExecutor 据此为子进程设置凭据
+var dirAccounts sync.Map
+
+// SetDirAccount 设置工作目录使用的账号，account 为空时使用默认凭据
+func SetDirAccount(dir, account string) {
+	if account == "" {
+		dirAccounts.Delete(dir)
+		return
+	}
+	dirAccounts.Store(dir, account)
+}
+
+// credentialEnvForDir 返回工作目录的凭据环境变量：指定了账号时覆盖默认凭据，
+// 模板使用的厂商中配置了角色的替换为临时凭据
+func credentialEnvForDir(dir string) (map[string]string, error) {
+	v, ok := dirAccounts.Load(dir)
+	conf, err := activeConfig()
+	if err != nil {
+		// 未指定账号时沿用进程的环境变量
+		if !ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	env := map[string]string{}
+	if ok {
+		account := v.(string)
+		if env, err = AccountEnv(conf, account); err != nil {
+			return nil, err
+		}
+		conf, _ = conf.ForAccount("", account)
+	}
+	for k, val := range RoleEnv(conf, templateProviders(dir)) {
+		env[k] = val
+	}
+	return env, nil
+}
+
+// putCaseAccount 写入场景使用的账号，account 为空时删除记录
+func putCaseAccount(tx *bolt.Tx, caseID, account string) error {
+	if account == "" {
+		if b := tx.Bucket([]byte(caseAccountBucket)); b != nil {
+			return b.Delete([]byte(caseID))
+		}
+		return nil
+	}
+	b, err := tx.CreateBucketIfNotExists([]byte(caseAccountBucket))
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(caseID), []byte(account))
+}
+
+// attachAccounts 为加载的场景填充账号并登记工作目录的账号。LoadProjectCases 返回的场景不经过 bindHandlers，
+// 漂移检测、Spot 恢复等直接对其执行 terraform 时同样需要使用场景的账号
+func attachAccounts(tx *bolt.Tx, cases []*Case) {
+	b := tx.Bucket([]byte(caseAccountBucket))
+	for _, c := range cases {
+		if b != nil {
+			if v := b.Get([]byte(c.Id)); v != nil {
+				c.Account = string(v)
+			}
+		}
+		if c.Path != "" {
+			SetDirAccount(c.Path, c.Account)
+		}
+	}
+}
+
+// GetCaseAccount 返回场景使用的账号，使用默认凭据时为空
+func GetCaseAccount(caseID string) string {
+	var account string
+	dbExec(func(tx *bolt.Tx) error {
+		if b := tx.Bucket([]byte(caseAccountBucket)); b != nil {
+			account = string(b.Get([]byte(caseID)))
+		}
+		return nil
+	})
+	return account
+}
@@ -0,0 +1,121 @@
+package mod
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testAccountsConfig() *Config {
+	var conf Config
+	conf.Providers.Aws.AccessKey = "AKIADEFAULT"
+	conf.Providers.Aws.SecretKey = "default-secret"
+	conf.Providers.Aws.Region = "us-east-1"
+	conf.Providers.Aws.Accounts = ProviderAccounts{
+		"op-a": {"AWS_ACCESS_KEY_ID": "AKIAOPA", "AWS_SECRET_ACCESS_KEY": "op-a-secret"},
+		"op-b": {"AWS_ACCESS_KEY_ID": "AKIAOPB", "region": "eu-west-1"},
+	}
+	conf.Providers.Alicloud.Accounts = ProviderAccounts{
+		"op-a": {"ALICLOUD_ACCESS_KEY": "LTAIOPA", "ALICLOUD_SECRET_KEY": "ali-op-a-secret"},
+	}
+	return &conf
+}
+
+func TestConfigForAccount(t *testing.T) {
+	conf := testAccountsConfig()
+	acc, err := conf.ForAccount("aws", "op-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Providers.Aws.AccessKey != "AKIAOPA" || acc.Providers.Aws.Region != "us-east-1" {
+		t.Errorf("op-a = %+v", acc.Providers.Aws)
+	}
+	if conf.Providers.Aws.AccessKey != "AKIADEFAULT" {
+		t.Error("ForAccount must not modify the original config")
+	}
+	// 账号未设置的凭据不继承默认账号
+	acc, _ = conf.ForAccount("aws", "op-b")
+	if acc.Providers.Aws.SecretKey != "" || acc.Providers.Aws.Region != "eu-west-1" {
+		t.Errorf("op-b = %+v", acc.Providers.Aws)
+	}
+	if _, err := conf.ForAccount("alicloud", "op-b"); err == nil {
+		t.Error("op-b is not defined for aliyun")
+	}
+	if got := conf.AccountNames(""); strings.Join(got, ",") != "op-a,op-b" {
+		t.Errorf("AccountNames = %v", got)
+	}
+}
+
+func TestAccountEnv(t *testing.T) {
+	env, err := AccountEnv(testAccountsConfig(), "op-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"AWS_ACCESS_KEY_ID":   "AKIAOPA",
+		"ALICLOUD_SECRET_KEY": "ali-op-a-secret",
+		"AWS_SESSION_TOKEN":   "",
+	}
+	for k, v := range want {
+		if got, ok := env[k]; !ok || got != v {
+			t.Errorf("env[%s] = %q, want %q", k, got, v)
+		}
+	}
+	if _, ok := env["TENCENTCLOUD_SECRET_ID"]; ok {
+		t.Error("providers without the account should keep their default credentials")
+	}
+	if _, err := AccountEnv(testAccountsConfig(), "missing"); err == nil {
+		t.Error("unknown account should fail")
+	}
+}
+
+func TestAccountCredentialsEncrypted(t *testing.T) {
+	path := setupCredentialTest(t)
+	writeTestConfig(t, path, testAccountsConfig())
+	if _, err := MigrateCredentials(path, []byte("pass"), ""); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := readRawConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := raw.Providers.Aws.Accounts["op-a"]["AWS_SECRET_ACCESS_KEY"]; !strings.HasPrefix(v, credEncPrefix) {
+		t.Errorf("account secret not encrypted: %q", v)
+	}
+	if raw.Providers.Aws.Accounts["op-b"]["region"] != "eu-west-1" {
+		t.Error("non-credential account keys should stay in clear text")
+	}
+	conf, _, err := ReadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Providers.Aws.Accounts["op-a"]["AWS_SECRET_ACCESS_KEY"] != "op-a-secret" {
+		t.Error("account secret should be decrypted on read")
+	}
+}
+
+func TestCaseAccountPersisted(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "acct-case-1", Name: "web", ProjectID: "default", Account: "op-a", CreateTime: "2024-01-01 00:00:00", Path: filepath.Join(RedcPath, "acct-case-1")}
+	if err := c.DBSave(); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetCaseAccount(c.Id); got != "op-a" {
+		t.Errorf("GetCaseAccount = %q", got)
+	}
+	cases, err := LoadProjectCases("default")
+	if err != nil || len(cases) != 1 || cases[0].Account != "op-a" {
+		t.Fatalf("LoadProjectCases = %v, %v", cases, err)
+	}
+	// 加载的场景不经过 bindHandlers，工作目录同样要登记账号
+	if v, ok := dirAccounts.Load(c.Path); !ok || v.(string) != "op-a" {
+		t.Errorf("dir account = %v, %v", v, ok)
+	}
+	SetDirAccount(c.Path, "")
+	if err := c.DBRemove(); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetCaseAccount(c.Id); got != "" {
+		t.Errorf("account should be removed with the case, got %q", got)
+	}
+}
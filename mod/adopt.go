@@ -0,0 +1,183 @@
+package mod
+
+import (
+	"fmt"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"sort"
+	"strings"
+)
+
+// ImportTarget 需要纳管的已有资源：terraform 资源地址 + 云上资源 ID
+type ImportTarget struct {
+	Address string `json:"address"`
+	ID      string `json:"id"`
+}
+
+// ParseImportTargets 解析 address=id 形式的导入参数，按第一个 "=" 切分
+func ParseImportTargets(pairs []string) ([]ImportTarget, error) {
+	targets := make([]ImportTarget, 0, len(pairs))
+	seen := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		addr, id, ok := strings.Cut(pair, "=")
+		addr, id = strings.TrimSpace(addr), strings.TrimSpace(id)
+		if !ok || addr == "" || id == "" {
+			return nil, fmt.Errorf("%s", i18n.Tf("adopt_invalid_target", pair))
+		}
+		if seen[addr] {
+			return nil, fmt.Errorf("%s", i18n.Tf("adopt_duplicate_target", addr))
+		}
+		seen[addr] = true
+		targets = append(targets, ImportTarget{Address: addr, ID: id})
+	}
+	return targets, nil
+}
+
+// planAddresses 读取当前 case.tfplan 中的资源地址
+func planAddresses(path string) (map[string]bool, error) {
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	te, err := NewTerraformExecutor(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	changes, err := te.GetPlanResourceChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make(map[string]bool, len(changes))
+	for _, rc := range changes {
+		if rc != nil {
+			addrs[rc.Address] = true
+		}
+	}
+	return addrs, nil
+}
+
+// AdoptCase 基于模板创建场景，并将已有云资源 import 到 state 中。
+// 返回导入后剩余的 plan 差异，场景保持未启动状态，由操作者确认后调用 FinishAdoption
+func (p *RedcProject) AdoptCase(template, user, name string, vars map[string]string, targets []ImportTarget) (*Case, *ChangePlan, error) {
+	if len(targets) == 0 {
+		return nil, nil, fmt.Errorf("%s", i18n.T("adopt_no_targets"))
+	}
+	c, err := p.CaseCreate(template, user, name, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan, err := c.importTargets(targets)
+	if err != nil {
+		// 只删除本地目录和记录，不会触碰已存在的云资源
+		if rmErr := c.Remove(); rmErr != nil {
+			gologger.Warning().Msgf("%s", i18n.Tf("adopt_cleanup_failed", c.Name, rmErr))
+		}
+		return nil, nil, err
+	}
+	return c, plan, nil
+}
+
+// importTargets 校验地址并逐个导入，完成后重新生成 plan
+func (c *Case) importTargets(targets []ImportTarget) (*ChangePlan, error) {
+	release, lockErr := c.acquireLock("adopt")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer release()
+
+	addrs, err := planAddresses(c.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		if !addrs[t.Address] {
+			known := make([]string, 0, len(addrs))
+			for a := range addrs {
+				known = append(known, a)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("%s", i18n.Tf("adopt_unknown_address", t.Address, strings.Join(known, ", ")))
+		}
+	}
+	for _, t := range targets {
+		gologger.Info().Msgf("%s", i18n.Tf("adopt_importing", t.Address, t.ID))
+		if err := TfImport(c.Path, t.Address, t.ID, c.Parameter...); err != nil {
+			return nil, err
+		}
+	}
+	return c.adoptPlanLocked()
+}
+
+// AdoptPlan 重新生成 plan，返回导入资源与模板之间仍需调和的差异
+func (c *Case) AdoptPlan() (*ChangePlan, error) {
+	release, lockErr := c.acquireLock("adopt-plan")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer release()
+	return c.adoptPlanLocked()
+}
+
+// adoptPlanLocked AdoptPlan 的实现，调用方须已持有场景锁
+func (c *Case) adoptPlanLocked() (*ChangePlan, error) {
+	plan := &ChangePlan{
+		CaseID:       c.Id,
+		OldParameter: append([]string(nil), c.Parameter...),
+		NewParameter: append([]string(nil), c.Parameter...),
+	}
+	if err := TfPlan(c.Path, c.Parameter...); err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("case_change_plan_failed", err))
+	}
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	te, err := NewTerraformExecutor(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	changes, err := te.GetPlanResourceChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summarizeResourceChanges(plan, changes)
+	if err := c.recordPlanFile(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// FinishAdoption 将纳管场景标记为运行中。plan 仍有差异时必须 apply=true，
+// apply 使用 AdoptPlan 生成的 case.tfplan；失败时不会销毁资源，避免误删已有资产
+func (c *Case) FinishAdoption(plan *ChangePlan, apply bool, operator string) error {
+	release, lockErr := c.acquireLock("adopt-finish")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+
+	if c.State == StateRunning {
+		return fmt.Errorf("%s", i18n.T("case_scene_running"))
+	}
+	if plan.HasChanges() {
+		if !apply {
+			return fmt.Errorf("%s", i18n.Tf("adopt_pending_changes", c.Name, plan.Summary()))
+		}
+		if err := c.verifyPlanFile(plan); err != nil {
+			return err
+		}
+		if _, err := CheckPlanPolicy(c.Path, plan.PlanFile, c.Name); err != nil {
+			return err
+		}
+		gologger.Info().Msgf("%s", i18n.Tf("case_change_applying", c.Name, plan.Summary()))
+		c.runPluginHook("pre-apply")
+		if err := TfApplyPlanFile(c.Path, plan.PlanFile); err != nil {
+			return err
+		}
+		c.runPluginHook("post-apply")
+	}
+
+	if _, err := c.TfOutput(); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("case_get_output_failed", err))
+	}
+	c.StatusChange(StateRunning)
+	c.recordChange(ChangeTypeCreate, nil, c.Parameter, operator, "adopt: "+plan.Summary())
+	gologger.Info().Msgf("%s", i18n.Tf("adopt_finished", c.Name))
+	return nil
+}
@@ -0,0 +1,30 @@
+package mod
+
+import "testing"
+
+func TestParseImportTargets(t *testing.T) {
+	targets, err := ParseImportTargets([]string{
+		"alicloud_instance.instance=i-bp1abc",
+		`aws_route53_record.r["www"]=Z123_www.example.com_A`,
+		"tencentcloud_instance.vm[0] = ins-1=2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ImportTarget{
+		{Address: "alicloud_instance.instance", ID: "i-bp1abc"},
+		{Address: `aws_route53_record.r["www"]`, ID: "Z123_www.example.com_A"},
+		{Address: "tencentcloud_instance.vm[0]", ID: "ins-1=2"},
+	}
+	for i, w := range want {
+		if targets[i] != w {
+			t.Errorf("target %d = %+v, want %+v", i, targets[i], w)
+		}
+	}
+
+	for _, bad := range [][]string{{"no-separator"}, {"=i-123"}, {"addr="}, {"a.b=1", "a.b=2"}} {
+		if _, err := ParseImportTargets(bad); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
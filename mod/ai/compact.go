@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
 )
 
 // CompactOptions controls LLM-based context compaction behavior.
@@ -257,5 +258,5 @@ func SaveTranscript(messages []Message, filePath string) error {
 	if err != nil {
 		return fmt.Errorf("marshal transcript: %w", err)
 	}
-	return os.WriteFile(filePath, data, 0644)
+	return os.WriteFile(filePath, []byte(secret.Redact(string(data))), 0600)
 }
@@ -79,10 +79,12 @@ func (hc *HookChain) RunPostHooks(toolName string, args map[string]interface{},
 
 // dangerousTools lists tools that perform destructive/irreversible operations.
 var dangerousTools = map[string]string{
-	"kill_case":       "Force-kill a running case (resources may not be cleaned up)",
-	"delete_template": "Permanently delete a local template",
-	"compose_down":    "Destroy all services in a compose deployment",
-	"stop_case":       "Stop a running case (terraform destroy)",
+	"kill_case":        "Force-kill a running case (resources may not be cleaned up)",
+	"delete_template":  "Permanently delete a local template",
+	"compose_down":     "Destroy all services in a compose deployment",
+	"compose_scale":    "Scale compose services (scaling down destroys instances)",
+	"stop_case":        "Stop a running case (terraform destroy)",
+	"replace_resource": "Destroy and recreate resources in a running case",
 }
 
 // DangerousOpHook asks for user confirmation before destructive operations.
@@ -115,11 +117,37 @@ func DangerousOpHook(toolName string, args map[string]interface{}) HookResult {
 	}
 }
 
+// PolicyChecker evaluates deployment guardrail policies for a tool call and
+// returns the deny and warn messages (both empty when the call is allowed).
+type PolicyChecker func(toolName string, args map[string]interface{}) (deny []string, warn []string)
+
+// PolicyHook blocks tool calls denied by the deployment policy and asks for
+// confirmation when the policy only warns. It runs alongside DangerousOpHook.
+func PolicyHook(check PolicyChecker) PreToolHook {
+	return func(toolName string, args map[string]interface{}) HookResult {
+		deny, warn := check(toolName, args)
+		if len(deny) > 0 {
+			return HookResult{
+				Action:  HookBlock,
+				Message: "Denied by deployment policy:\n- " + strings.Join(deny, "\n- "),
+			}
+		}
+		if len(warn) > 0 {
+			return HookResult{
+				Action:  HookConfirm,
+				Message: fmt.Sprintf("⚠️ Deployment policy warnings for %s:\n- %s\nConfirm execution?", toolName, strings.Join(warn, "\n- ")),
+			}
+		}
+		return HookResult{Action: HookContinue}
+	}
+}
+
 // costlyTools lists tools that may incur cloud costs.
 var costlyTools = map[string]string{
 	"start_case":  "Starts cloud resources (billing begins)",
 	"plan_case":   "Creates a case that will provision cloud resources when started",
 	"compose_up":  "Deploys multiple cloud services (billing begins for all)",
+	"compose_scale": "May deploy additional instances of compose services",
 }
 
 // CostAwareHook adds a cost warning annotation to tool calls that may incur charges.
@@ -0,0 +1,481 @@
+package mod
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	alists "github.com/aliyun/alibaba-cloud-sdk-go/services/sts"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+)
+
+// RoleConfig 通过 STS AssumeRole 获取临时凭据的角色配置 (AWS IAM Role / 阿里云 RAM 角色 / 腾讯云 CAM 角色)。
+// 配置了 role_arn 后，terraform、余额查询和价格查询都使用临时凭据，厂商下的密钥仅用于调用 AssumeRole
+type RoleConfig struct {
+	RoleArn         string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
+	ExternalId      string `yaml:"external_id,omitempty" json:"external_id,omitempty"`
+	SessionName     string `yaml:"session_name,omitempty" json:"session_name,omitempty"`
+	SessionDuration string `yaml:"session_duration,omitempty" json:"session_duration,omitempty"` // 如 1h、90m，默认 1h
+	MfaSerial       string `yaml:"mfa_serial,omitempty" json:"mfa_serial,omitempty"`
+}
+
+// EnvMFAToken 提供 MFA 动态口令的环境变量，优先于 MFATokenProvider
+const EnvMFAToken = "REDC_MFA_TOKEN"
+
+const (
+	defaultRoleSessionName = "redc"
+	defaultRoleDuration    = time.Hour
+	// roleRefreshMargin 临时凭据剩余有效期小于该值时重新获取
+	roleRefreshMargin = 5 * time.Minute
+)
+
+// MFATokenProvider 需要 MFA 时获取动态口令，CLI 中设置为终端输入
+var MFATokenProvider func(provider, serial string) (string, error)
+
+// TempCredentials STS 返回的临时凭据
+type TempCredentials struct {
+	AccessKey    string    `json:"access_key"`
+	SecretKey    string    `json:"secret_key"`
+	SessionToken string    `json:"session_token"`
+	Expiration   time.Time `json:"expiration"`
+}
+
+func (c *TempCredentials) valid() bool {
+	return c != nil && time.Until(c.Expiration) > roleRefreshMargin
+}
+
+// roleRequest 一次 AssumeRole 调用的参数，accessKey 为调用方的长期密钥
+type roleRequest struct {
+	provider  string
+	accessKey string
+	secretKey string
+	region    string
+	role      RoleConfig
+	duration  time.Duration
+}
+
+// cacheKey 同一密钥、角色和会话参数共用缓存
+func (r *roleRequest) cacheKey() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{r.provider, r.accessKey, r.role.RoleArn, r.role.ExternalId, r.role.SessionName, r.role.MfaSerial, r.duration.String()}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// roleEnvKeys 厂商 -> terraform 使用的 access key / secret key / token 环境变量
+var roleEnvKeys = map[string][3]string{
+	"aws":          {"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"},
+	"aliyun":       {"ALICLOUD_ACCESS_KEY", "ALICLOUD_SECRET_KEY", "ALICLOUD_SECURITY_TOKEN"},
+	"tencentcloud": {"TENCENTCLOUD_SECRET_ID", "TENCENTCLOUD_SECRET_KEY", "TENCENTCLOUD_SECURITY_TOKEN"},
+}
+
+// assumeRoleFuncs 各厂商的 AssumeRole 实现，mfaCode 仅在配置了 mfa_serial 时非空
+var assumeRoleFuncs = map[string]func(req *roleRequest, mfaCode string) (*TempCredentials, error){
+	"aws":          assumeAWSRole,
+	"aliyun":       assumeAliyunRole,
+	"tencentcloud": assumeTencentRole,
+}
+
+// roleFromValues 从命名账号的配置项读取角色配置
+func roleFromValues(values map[string]string) RoleConfig {
+	return RoleConfig{
+		RoleArn:         values["role_arn"],
+		ExternalId:      values["external_id"],
+		SessionName:     values["session_name"],
+		SessionDuration: values["session_duration"],
+		MfaSerial:       values["mfa_serial"],
+	}
+}
+
+// roleRequestFor 返回厂商的 AssumeRole 参数，未配置 role_arn 时返回 nil
+func roleRequestFor(conf *Config, provider string) (*roleRequest, error) {
+	p := &conf.Providers
+	var r *roleRequest
+	switch name := normalizeProvider(provider); name {
+	case "aws":
+		r = &roleRequest{provider: name, accessKey: p.Aws.AccessKey, secretKey: p.Aws.SecretKey, region: p.Aws.Region, role: p.Aws.Role}
+	case "aliyun":
+		r = &roleRequest{provider: name, accessKey: p.Alicloud.AccessKey, secretKey: p.Alicloud.SecretKey, region: p.Alicloud.Region, role: p.Alicloud.Role}
+	case "tencentcloud":
+		r = &roleRequest{provider: name, accessKey: p.Tencentcloud.SecretId, secretKey: p.Tencentcloud.SecretKey, region: p.Tencentcloud.Region, role: p.Tencentcloud.Role}
+	default:
+		return nil, nil
+	}
+	if r.role.RoleArn == "" {
+		return nil, nil
+	}
+	if r.role.SessionName == "" {
+		r.role.SessionName = defaultRoleSessionName
+	}
+	r.duration = defaultRoleDuration
+	if r.role.SessionDuration != "" {
+		d, err := time.ParseDuration(r.role.SessionDuration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s", i18n.Tf("role_duration_invalid", r.provider, r.role.SessionDuration))
+		}
+		r.duration = d
+	}
+	return r, nil
+}
+
+// HasRole 厂商是否配置了 role_arn
+func (c *Config) HasRole(provider string) bool {
+	r, _ := roleRequestFor(c, provider)
+	return r != nil
+}
+
+var (
+	roleMu    sync.Mutex
+	roleCache = map[string]*TempCredentials{}
+)
+
+// roleCachePath 临时凭据的磁盘缓存，CLI 的多次调用和 GUI 之间共用，避免重复输入 MFA。
+// 内容使用凭据存储的主密钥加密；未启用加密存储或未解锁时只缓存在内存中
+func roleCachePath() string {
+	return filepath.Join(RedcPath, "role_cache.json")
+}
+
+// roleCacheKey 加密磁盘缓存的主密钥，不可用时返回 nil
+func roleCacheKey() []byte {
+	raw, err := readRawConfig(ActiveConfigPath)
+	if err != nil || !raw.Credentials.Encrypted() {
+		return nil
+	}
+	key, _ := masterKey(raw.Credentials)
+	return key
+}
+
+func loadRoleDiskCache(key []byte) map[string]*TempCredentials {
+	cache := map[string]*TempCredentials{}
+	data, err := os.ReadFile(roleCachePath())
+	if err != nil || key == nil {
+		return cache
+	}
+	if !strings.HasPrefix(string(data), credEncPrefix) {
+		// 旧版本写入的明文缓存
+		os.Remove(roleCachePath())
+		return cache
+	}
+	plain, err := openCredential(key, string(data))
+	if err != nil {
+		return cache
+	}
+	json.Unmarshal([]byte(plain), &cache)
+	return cache
+}
+
+// saveRoleDiskCache 加密写入临时凭据并清理已过期的条目，主密钥不可用时不写磁盘
+func saveRoleDiskCache(name string, creds *TempCredentials) error {
+	key := roleCacheKey()
+	if key == nil {
+		return nil
+	}
+	cache := loadRoleDiskCache(key)
+	for k, c := range cache {
+		if !c.valid() {
+			delete(cache, k)
+		}
+	}
+	cache[name] = creds
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealCredential(key, string(data))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(roleCachePath(), []byte(sealed), 0600)
+}
+
+// ClearRoleCache 删除内存和磁盘中缓存的临时凭据
+func ClearRoleCache() error {
+	roleMu.Lock()
+	defer roleMu.Unlock()
+	roleCache = map[string]*TempCredentials{}
+	if err := os.Remove(roleCachePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func mfaToken(provider, serial string) (string, error) {
+	if code := os.Getenv(EnvMFAToken); code != "" {
+		return code, nil
+	}
+	if MFATokenProvider != nil {
+		return MFATokenProvider(provider, serial)
+	}
+	return "", fmt.Errorf("%s", i18n.Tf("role_mfa_required", provider, serial))
+}
+
+// RoleCredentials 返回厂商角色的临时凭据，缓存的凭据即将过期时重新 AssumeRole；未配置 role_arn 时返回 nil
+func RoleCredentials(conf *Config, provider string) (*TempCredentials, error) {
+	return roleCredentials(conf, provider, "", false)
+}
+
+// RefreshRoleCredentials 忽略缓存重新 AssumeRole，mfaCode 为空时按需通过 REDC_MFA_TOKEN 或 MFATokenProvider 获取
+func RefreshRoleCredentials(conf *Config, provider, mfaCode string) (*TempCredentials, error) {
+	return roleCredentials(conf, provider, mfaCode, true)
+}
+
+func roleCredentials(conf *Config, provider, code string, refresh bool) (*TempCredentials, error) {
+	req, err := roleRequestFor(conf, provider)
+	if err != nil || req == nil {
+		return nil, err
+	}
+	// 串行获取，并发的查询不会重复调用 STS 或重复要求输入 MFA
+	roleMu.Lock()
+	defer roleMu.Unlock()
+	key := req.cacheKey()
+	if !refresh {
+		if c := roleCache[key]; c.valid() {
+			return c, nil
+		}
+		if c := loadRoleDiskCache(roleCacheKey())[key]; c.valid() {
+			registerTempSecrets(c)
+			roleCache[key] = c
+			return c, nil
+		}
+	}
+	if req.role.MfaSerial != "" && code == "" {
+		if code, err = mfaToken(req.provider, req.role.MfaSerial); err != nil {
+			return nil, err
+		}
+	}
+	creds, err := assumeRoleFuncs[req.provider](req, code)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("role_assume_failed", req.provider, req.role.RoleArn, err))
+	}
+	registerTempSecrets(creds)
+	roleCache[key] = creds
+	if err := saveRoleDiskCache(key, creds); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("role_cache_save_failed", err))
+	}
+	return creds, nil
+}
+
+func registerTempSecrets(c *TempCredentials) {
+	secret.Register(c.SecretKey)
+	secret.Register(c.SessionToken)
+}
+
+// SessionCredentials 返回调用云 API 使用的凭据：配置了角色时为临时凭据，否则为配置中的长期密钥 (token 为空)
+func SessionCredentials(conf *Config, provider string) (accessKey, secretKey, sessionToken string, err error) {
+	creds, err := RoleCredentials(conf, provider)
+	if err != nil {
+		return "", "", "", err
+	}
+	if creds != nil {
+		return creds.AccessKey, creds.SecretKey, creds.SessionToken, nil
+	}
+	accessKey, secretKey = providerCredentials(conf, provider)
+	return accessKey, secretKey, "", nil
+}
+
+// RoleEnv 返回 providers 中配置了角色的厂商的临时凭据环境变量，覆盖 terraform 子进程中的长期密钥。
+// 某个厂商获取失败时只清空该厂商的凭据 (避免回退到其他账号的长期密钥)，不影响其他厂商
+func RoleEnv(conf *Config, providers map[string]bool) map[string]string {
+	env := map[string]string{}
+	for _, provider := range sortedKeys(roleEnvKeys) {
+		if !providers[provider] {
+			continue
+		}
+		keys := roleEnvKeys[provider]
+		creds, err := RoleCredentials(conf, provider)
+		if err != nil {
+			gologger.Warning().Msgf("%s", err)
+			env[keys[0]], env[keys[1]], env[keys[2]] = "", "", ""
+			continue
+		}
+		if creds == nil {
+			continue
+		}
+		env[keys[0]] = creds.AccessKey
+		env[keys[1]] = creds.SecretKey
+		env[keys[2]] = creds.SessionToken
+	}
+	return env
+}
+
+var templateProviderRe = regexp.MustCompile(`(?m)^\s*provider\s+"([a-z0-9_-]+)"|source\s*=\s*"(?:[^"/]+/)+([a-z0-9_-]+)"|^\s*(?:resource|data)\s+"([a-z0-9]+)_`)
+
+// templateProviders 工作目录 (含本地模块) 中的 terraform 使用的厂商，按配置文件中的厂商名返回
+func templateProviders(dir string) map[string]bool {
+	providers := map[string]bool{}
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".tf") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		for _, m := range templateProviderRe.FindAllStringSubmatch(string(data), -1) {
+			for _, name := range m[1:] {
+				if name != "" {
+					providers[normalizeProvider(name)] = true
+				}
+			}
+		}
+		return nil
+	})
+	return providers
+}
+
+func durationSeconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+func assumeAWSRole(req *roleRequest, mfaCode string) (*TempCredentials, error) {
+	region := req.region
+	if region == "" {
+		region = "us-east-1"
+	}
+	cfg := &aws.Config{Region: aws.String(region)}
+	// 未配置密钥时使用默认凭据链 (环境变量、~/.aws/credentials、实例角色)
+	if req.accessKey != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(req.accessKey, req.secretKey, "")
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	input := &awssts.AssumeRoleInput{
+		RoleArn:         aws.String(req.role.RoleArn),
+		RoleSessionName: aws.String(req.role.SessionName),
+		DurationSeconds: aws.Int64(durationSeconds(req.duration)),
+	}
+	if req.role.ExternalId != "" {
+		input.ExternalId = aws.String(req.role.ExternalId)
+	}
+	if req.role.MfaSerial != "" {
+		input.SerialNumber = aws.String(req.role.MfaSerial)
+		input.TokenCode = aws.String(mfaCode)
+	}
+	out, err := awssts.New(sess).AssumeRole(input)
+	if err != nil {
+		return nil, err
+	}
+	if out.Credentials == nil {
+		return nil, fmt.Errorf("empty AssumeRole response")
+	}
+	return &TempCredentials{
+		AccessKey:    aws.StringValue(out.Credentials.AccessKeyId),
+		SecretKey:    aws.StringValue(out.Credentials.SecretAccessKey),
+		SessionToken: aws.StringValue(out.Credentials.SessionToken),
+		Expiration:   aws.TimeValue(out.Credentials.Expiration),
+	}, nil
+}
+
+func assumeAliyunRole(req *roleRequest, mfaCode string) (*TempCredentials, error) {
+	// 阿里云 AssumeRole 不支持在请求中携带 MFA，需在 RAM 角色的信任策略中限制
+	if req.role.MfaSerial != "" {
+		return nil, fmt.Errorf("%s", i18n.Tf("role_mfa_unsupported", req.provider))
+	}
+	region := req.region
+	if region == "" {
+		region = "cn-hangzhou"
+	}
+	client, err := alists.NewClientWithAccessKey(region, req.accessKey, req.secretKey)
+	if err != nil {
+		return nil, err
+	}
+	request := alists.CreateAssumeRoleRequest()
+	request.Scheme = "https"
+	request.RoleArn = req.role.RoleArn
+	request.RoleSessionName = req.role.SessionName
+	request.ExternalId = req.role.ExternalId
+	request.DurationSeconds = requests.NewInteger64(durationSeconds(req.duration))
+	resp, err := client.AssumeRole(request)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := time.Parse(time.RFC3339, resp.Credentials.Expiration)
+	if err != nil {
+		return nil, err
+	}
+	return &TempCredentials{
+		AccessKey:    resp.Credentials.AccessKeyId,
+		SecretKey:    resp.Credentials.AccessKeySecret,
+		SessionToken: resp.Credentials.SecurityToken,
+		Expiration:   exp,
+	}, nil
+}
+
+func assumeTencentRole(req *roleRequest, mfaCode string) (*TempCredentials, error) {
+	region := req.region
+	if region == "" {
+		region = "ap-guangzhou"
+	}
+	cpf := profile.NewClientProfile()
+	cpf.HttpProfile.Endpoint = "sts.tencentcloudapi.com"
+	client := common.NewCommonClient(common.NewCredential(req.accessKey, req.secretKey), region, cpf)
+	params := map[string]interface{}{
+		"RoleArn":         req.role.RoleArn,
+		"RoleSessionName": req.role.SessionName,
+		"DurationSeconds": durationSeconds(req.duration),
+	}
+	if req.role.ExternalId != "" {
+		params["ExternalId"] = req.role.ExternalId
+	}
+	if req.role.MfaSerial != "" {
+		params["SerialNumber"] = req.role.MfaSerial
+		params["TokenCode"] = mfaCode
+	}
+	request := tchttp.NewCommonRequest("sts", "2018-08-13", "AssumeRole")
+	if err := request.SetActionParameters(params); err != nil {
+		return nil, err
+	}
+	response := tchttp.NewCommonResponse()
+	if err := client.Send(request, response); err != nil {
+		return nil, err
+	}
+	var body struct {
+		Response struct {
+			Credentials struct {
+				Token        string
+				TmpSecretId  string
+				TmpSecretKey string
+			}
+			ExpiredTime int64
+		}
+	}
+	if err := json.Unmarshal(response.GetBody(), &body); err != nil {
+		return nil, err
+	}
+	c := body.Response.Credentials
+	if c.TmpSecretId == "" {
+		return nil, fmt.Errorf("empty AssumeRole response")
+	}
+	return &TempCredentials{
+		AccessKey:    c.TmpSecretId,
+		SecretKey:    c.TmpSecretKey,
+		SessionToken: c.Token,
+		Expiration:   time.Unix(body.Response.ExpiredTime, 0),
+	}, nil
+}
@@ -0,0 +1,229 @@
+package mod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// stubAssumeRole 替换 AWS 的 AssumeRole 实现，返回调用次数和最近一次的 MFA 口令
+func stubAssumeRole(t *testing.T, ttl time.Duration) (calls *int, lastCode *string) {
+	t.Helper()
+	setupCredentialTest(t)
+	t.Setenv(EnvMFAToken, "")
+	old, oldMFA := assumeRoleFuncs["aws"], MFATokenProvider
+	calls, lastCode = new(int), new(string)
+	assumeRoleFuncs["aws"] = func(req *roleRequest, mfaCode string) (*TempCredentials, error) {
+		*calls++
+		*lastCode = mfaCode
+		return &TempCredentials{
+			AccessKey:    fmt.Sprintf("ASIATEMP%d", *calls),
+			SecretKey:    "temp-secret",
+			SessionToken: "temp-token",
+			Expiration:   time.Now().Add(ttl),
+		}, nil
+	}
+	MFATokenProvider = nil
+	ClearRoleCache()
+	t.Cleanup(func() {
+		assumeRoleFuncs["aws"], MFATokenProvider = old, oldMFA
+		ClearRoleCache()
+	})
+	return calls, lastCode
+}
+
+func testRoleConfig() *Config {
+	var conf Config
+	conf.Providers.Aws.AccessKey = "AKIABASE"
+	conf.Providers.Aws.SecretKey = "base-secret"
+	conf.Providers.Aws.Role = RoleConfig{RoleArn: "arn:aws:iam::123456789012:role/redc", ExternalId: "ext", SessionDuration: "1h"}
+	return &conf
+}
+
+func TestRoleCredentialsCached(t *testing.T) {
+	calls, _ := stubAssumeRole(t, time.Hour)
+	conf := testRoleConfig()
+	first, err := RoleCredentials(conf, "aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := RoleCredentials(conf, "aws")
+	if *calls != 1 || again.AccessKey != first.AccessKey {
+		t.Errorf("credentials should be cached, calls = %d", *calls)
+	}
+	// 未启用加密存储时只缓存在内存中
+	if _, err := os.Stat(roleCachePath()); !os.IsNotExist(err) {
+		t.Errorf("plain credential store should not write role_cache.json, stat err = %v", err)
+	}
+	ak, sk, token, err := SessionCredentials(conf, "aws")
+	if err != nil || ak != first.AccessKey || sk != "temp-secret" || token != "temp-token" {
+		t.Errorf("SessionCredentials = %s %s %s %v", ak, sk, token, err)
+	}
+	conf.Providers.Aws.Role = RoleConfig{}
+	if ak, _, token, _ := SessionCredentials(conf, "aws"); ak != "AKIABASE" || token != "" {
+		t.Errorf("without a role the static keys should be used, got %s", ak)
+	}
+}
+
+func TestRoleDiskCacheSealed(t *testing.T) {
+	calls, _ := stubAssumeRole(t, time.Hour)
+	path := filepath.Join(RedcPath, "config.yaml")
+	writeTestConfig(t, path, &Config{})
+	if _, err := MigrateCredentials(path, []byte("correct horse"), ""); err != nil {
+		t.Fatal(err)
+	}
+	oldActive := ActiveConfigPath
+	ActiveConfigPath = path
+	t.Cleanup(func() { ActiveConfigPath = oldActive })
+
+	conf := testRoleConfig()
+	if _, err := RoleCredentials(conf, "aws"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(roleCachePath())
+	if err != nil || !strings.HasPrefix(string(data), credEncPrefix) || strings.Contains(string(data), "temp-token") {
+		t.Fatalf("role cache should be sealed, got %q, %v", data, err)
+	}
+	// 新进程从磁盘缓存读取
+	roleMu.Lock()
+	roleCache = map[string]*TempCredentials{}
+	roleMu.Unlock()
+	if c, _ := RoleCredentials(conf, "aws"); *calls != 1 || c.SessionToken != "temp-token" {
+		t.Errorf("disk cache not used, calls = %d", *calls)
+	}
+	// 锁定后不能读取磁盘缓存
+	rememberCredentialKey(nil)
+	roleMu.Lock()
+	roleCache = map[string]*TempCredentials{}
+	roleMu.Unlock()
+	if RoleCredentials(conf, "aws"); *calls != 2 {
+		t.Errorf("locked store should not read the disk cache, calls = %d", *calls)
+	}
+}
+
+func TestRoleCredentialsRefreshBeforeExpiry(t *testing.T) {
+	calls, _ := stubAssumeRole(t, roleRefreshMargin-time.Minute)
+	conf := testRoleConfig()
+	RoleCredentials(conf, "aws")
+	RoleCredentials(conf, "aws")
+	if *calls != 2 {
+		t.Errorf("credentials about to expire should be refreshed, calls = %d", *calls)
+	}
+	conf.Providers.Aws.Role.SessionDuration = "soon"
+	if _, err := RoleCredentials(conf, "aws"); err == nil {
+		t.Error("invalid session_duration should fail")
+	}
+}
+
+func TestRoleCredentialsMFA(t *testing.T) {
+	_, lastCode := stubAssumeRole(t, time.Hour)
+	conf := testRoleConfig()
+	conf.Providers.Aws.Role.MfaSerial = "arn:aws:iam::123456789012:mfa/ops"
+	if _, err := RoleCredentials(conf, "aws"); err == nil {
+		t.Fatal("MFA role without a code should fail")
+	}
+	MFATokenProvider = func(provider, serial string) (string, error) { return "654321", nil }
+	if _, err := RoleCredentials(conf, "aws"); err != nil || *lastCode != "654321" {
+		t.Fatalf("code = %q, err = %v", *lastCode, err)
+	}
+	t.Setenv(EnvMFAToken, "123456")
+	if _, err := RefreshRoleCredentials(conf, "aws", ""); err != nil || *lastCode != "123456" {
+		t.Errorf("REDC_MFA_TOKEN should take precedence, code = %q", *lastCode)
+	}
+}
+
+func TestCredentialEnvForDirWithRole(t *testing.T) {
+	stubAssumeRole(t, time.Hour)
+	conf := testRoleConfig()
+	conf.Providers.Aws.Accounts = ProviderAccounts{
+		"prod":   {"AWS_ACCESS_KEY_ID": "AKIAPROD", "AWS_SECRET_ACCESS_KEY": "prod-secret", "role_arn": "arn:aws:iam::210987654321:role/redc"},
+		"static": {"AWS_ACCESS_KEY_ID": "AKIASTATIC", "AWS_SECRET_ACCESS_KEY": "static-secret"},
+	}
+	LoadedConfig = conf
+	env, err := credentialEnvForDir(awsTemplateDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["AWS_SESSION_TOKEN"] != "temp-token" || env["AWS_ACCESS_KEY_ID"] != "ASIATEMP1" {
+		t.Errorf("default role env = %v", env)
+	}
+	dir := awsTemplateDir(t)
+	SetDirAccount(dir, "prod")
+	defer SetDirAccount(dir, "")
+	if env, _ = credentialEnvForDir(dir); env["AWS_ACCESS_KEY_ID"] != "ASIATEMP2" {
+		t.Errorf("account role should be assumed separately, env = %v", env)
+	}
+	// 账号未配置角色时不继承默认账号的角色
+	SetDirAccount(dir, "static")
+	if env, _ = credentialEnvForDir(dir); env["AWS_ACCESS_KEY_ID"] != "AKIASTATIC" || env["AWS_SESSION_TOKEN"] != "" {
+		t.Errorf("static account env = %v", env)
+	}
+}
+
+// awsTemplateDir 只使用 AWS 的模板目录
+func awsTemplateDir(t *testing.T) string {
+	dir := t.TempDir()
+	tf := "terraform {\n  required_providers {\n    aws = {\n      source = \"hashicorp/aws\"\n    }\n  }\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), []byte(tf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRoleEnvOnlyTemplateProviders(t *testing.T) {
+	calls, _ := stubAssumeRole(t, time.Hour)
+	conf := testRoleConfig()
+	conf.Providers.Alicloud.AccessKey = "LTAIBASE"
+	conf.Providers.Alicloud.SecretKey = "ali-secret"
+	conf.Providers.Alicloud.Role = RoleConfig{RoleArn: "acs:ram::123:role/redc"}
+	old := assumeRoleFuncs["aliyun"]
+	assumeRoleFuncs["aliyun"] = func(req *roleRequest, mfaCode string) (*TempCredentials, error) {
+		return nil, fmt.Errorf("role expired")
+	}
+	defer func() { assumeRoleFuncs["aliyun"] = old }()
+	LoadedConfig = conf
+
+	// 阿里云模板：不为 AWS 调用 AssumeRole，阿里云角色失败时清空其凭据
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "modules", "ecs"), 0755)
+	os.WriteFile(filepath.Join(dir, "main.tf"), []byte("module \"ecs\" {\n  source = \"./modules/ecs\"\n}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "modules", "ecs", "main.tf"), []byte("resource \"alicloud_instance\" \"vm\" {}\n"), 0644)
+	if p := templateProviders(dir); !p["aliyun"] || p["aws"] {
+		t.Fatalf("templateProviders = %v", p)
+	}
+	env, err := credentialEnvForDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 0 {
+		t.Errorf("unused AWS role should not be assumed, calls = %d", *calls)
+	}
+	if v, ok := env["ALICLOUD_ACCESS_KEY"]; !ok || v != "" {
+		t.Errorf("failed role should clear the provider credentials, env = %v", env)
+	}
+
+	// AWS 模板不受阿里云角色失败影响
+	env, err = credentialEnvForDir(awsTemplateDir(t))
+	if err != nil || env["AWS_ACCESS_KEY_ID"] != "ASIATEMP1" {
+		t.Errorf("aws env = %v, %v", env, err)
+	}
+	if _, ok := env["ALICLOUD_ACCESS_KEY"]; ok {
+		t.Errorf("unused provider should not be touched, env = %v", env)
+	}
+}
+
+func TestRoleConfigYAML(t *testing.T) {
+	var conf Config
+	data := []byte("providers:\n  aliyun:\n    region: cn-beijing\n    role_arn: acs:ram::123:role/redc\n    session_duration: 2h\n")
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		t.Fatal(err)
+	}
+	if !conf.HasRole("alicloud") || conf.Providers.Alicloud.Role.SessionDuration != "2h" || conf.HasRole("tencentcloud") {
+		t.Errorf("role = %+v", conf.Providers.Alicloud.Role)
+	}
+}
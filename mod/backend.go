@@ -0,0 +1,330 @@
+package mod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// RedcBackendFile redc 生成的 backend 声明文件
+const RedcBackendFile = "redc_backend.tf"
+
+// 支持的远程 state backend 类型
+const (
+	BackendS3   = "s3"
+	BackendHTTP = "http"
+)
+
+// BackendConfig 项目级远程 state backend 配置
+// 在 config.yaml 的 backends 下按项目名配置，同一项目的所有场景共享
+type BackendConfig struct {
+	Type string `yaml:"type" json:"type"` // s3 | http
+
+	// S3 兼容存储 (AWS S3 / MinIO / OSS 等)
+	Bucket       string `yaml:"bucket,omitempty" json:"bucket,omitempty"`
+	Region       string `yaml:"region,omitempty" json:"region,omitempty"`
+	Endpoint     string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	AccessKey    string `yaml:"access_key,omitempty" json:"access_key,omitempty"`
+	SecretKey    string `yaml:"secret_key,omitempty" json:"secret_key,omitempty"`
+	KeyPrefix    string `yaml:"key_prefix,omitempty" json:"key_prefix,omitempty"`
+	UsePathStyle bool   `yaml:"use_path_style,omitempty" json:"use_path_style,omitempty"` // MinIO 需要开启
+	LockTable    string `yaml:"dynamodb_table,omitempty" json:"dynamodb_table,omitempty"` // 不填则使用 S3 lockfile 锁
+
+	// Terraform HTTP backend
+	Address       string `yaml:"address,omitempty" json:"address,omitempty"` // 支持 {project} {case} 占位符
+	LockAddress   string `yaml:"lock_address,omitempty" json:"lock_address,omitempty"`
+	UnlockAddress string `yaml:"unlock_address,omitempty" json:"unlock_address,omitempty"`
+	LockMethod    string `yaml:"lock_method,omitempty" json:"lock_method,omitempty"`
+	UnlockMethod  string `yaml:"unlock_method,omitempty" json:"unlock_method,omitempty"`
+	Username      string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password      string `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+// Validate 校验 backend 配置
+func (b *BackendConfig) Validate() error {
+	switch b.Type {
+	case BackendS3:
+		if b.Bucket == "" {
+			return fmt.Errorf("%s", i18n.Tf("backend_missing_field", b.Type, "bucket"))
+		}
+	case BackendHTTP:
+		if b.Address == "" {
+			return fmt.Errorf("%s", i18n.Tf("backend_missing_field", b.Type, "address"))
+		}
+	default:
+		return fmt.Errorf("%s", i18n.Tf("backend_unsupported_type", b.Type))
+	}
+	return nil
+}
+
+// stateKey 单个场景在远程存储中的 state 路径
+func (b *BackendConfig) stateKey(project, caseID string) string {
+	key := fmt.Sprintf("%s/%s/terraform.tfstate", project, caseID)
+	if prefix := strings.Trim(b.KeyPrefix, "/"); prefix != "" {
+		key = prefix + "/" + key
+	}
+	return key
+}
+
+// expandAddress 替换 HTTP 地址中的 {project} {case} 占位符
+func expandAddress(addr, project, caseID string) string {
+	return strings.NewReplacer("{project}", project, "{case}", caseID).Replace(addr)
+}
+
+// backendAttr backend 块中的单个属性
+type backendAttr struct {
+	key   string
+	value string // 已是 HCL 字面量
+}
+
+// backendAttributes 生成写入 backend 块的非敏感属性
+func (b *BackendConfig) backendAttributes(project, caseID string) []backendAttr {
+	var attrs []backendAttr
+	str := func(k, v string) {
+		if v != "" {
+			attrs = append(attrs, backendAttr{k, strconv.Quote(v)})
+		}
+	}
+	flag := func(k string) {
+		attrs = append(attrs, backendAttr{k, "true"})
+	}
+	switch b.Type {
+	case BackendS3:
+		str("bucket", b.Bucket)
+		str("key", b.stateKey(project, caseID))
+		region := b.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		str("region", region)
+		if b.Endpoint != "" {
+			// 非 AWS 的 S3 兼容存储需要跳过 AWS 特有的校验
+			attrs = append(attrs, backendAttr{"endpoints", fmt.Sprintf("{ s3 = %s }", strconv.Quote(b.Endpoint))})
+			flag("skip_credentials_validation")
+			flag("skip_region_validation")
+			flag("skip_requesting_account_id")
+			flag("skip_metadata_api_check")
+			flag("skip_s3_checksum")
+		}
+		if b.UsePathStyle {
+			flag("use_path_style")
+		}
+		if b.LockTable != "" {
+			str("dynamodb_table", b.LockTable)
+		} else {
+			flag("use_lockfile")
+		}
+	case BackendHTTP:
+		lock := b.LockAddress
+		if lock == "" {
+			lock = b.Address
+		}
+		unlock := b.UnlockAddress
+		if unlock == "" {
+			unlock = lock
+		}
+		str("address", expandAddress(b.Address, project, caseID))
+		str("lock_address", expandAddress(lock, project, caseID))
+		str("unlock_address", expandAddress(unlock, project, caseID))
+		str("lock_method", b.LockMethod)
+		str("unlock_method", b.UnlockMethod)
+	}
+	return attrs
+}
+
+// credentialArgs 敏感字段不写入 .tf 文件，通过 -backend-config 传入。
+// 注意 terraform init 会把 -backend-config 的值保存在场景目录的 .terraform/terraform.tfstate 中
+func (b *BackendConfig) credentialArgs() []string {
+	var args []string
+	add := func(k, v string) {
+		if v != "" {
+			args = append(args, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+	switch b.Type {
+	case BackendS3:
+		add("access_key", b.AccessKey)
+		add("secret_key", b.SecretKey)
+	case BackendHTTP:
+		add("username", b.Username)
+		add("password", b.Password)
+	}
+	return args
+}
+
+// renderBackendFile 生成 redc_backend.tf 内容
+func (b *BackendConfig) renderBackendFile(project, caseID string) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by redc, do not edit\n")
+	sb.WriteString("terraform {\n")
+	sb.WriteString(fmt.Sprintf("  backend %s {\n", strconv.Quote(b.Type)))
+	for _, a := range b.backendAttributes(project, caseID) {
+		sb.WriteString(fmt.Sprintf("    %s = %s\n", a.key, a.value))
+	}
+	sb.WriteString("  }\n}\n")
+	return sb.String()
+}
+
+// GetProjectBackend 返回项目配置的远程 backend，未配置时返回 nil
+func GetProjectBackend(project string) *BackendConfig {
+	if LoadedConfig == nil || LoadedConfig.Backends == nil {
+		return nil
+	}
+	b := LoadedConfig.Backends[project]
+	if b == nil || b.Type == "" || b.Type == "local" {
+		return nil
+	}
+	return b
+}
+
+var backendBlockRe = regexp.MustCompile(`(?m)^\s*backend\s+"`)
+
+// hasBackendBlock 模板自身是否已声明 backend
+func hasBackendBlock(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+	for _, f := range files {
+		if filepath.Base(f) == RedcBackendFile {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err == nil && backendBlockRe.Match(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteBackendFile 在场景目录写入 backend 声明，返回 init 需要的参数
+// 返回 nil 表示使用本地 state
+func WriteBackendFile(dir string, b *BackendConfig, project, caseID string) ([]tfexec.InitOption, error) {
+	if b == nil {
+		return nil, nil
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	if hasBackendBlock(dir) {
+		gologger.Warning().Msgf("%s", i18n.Tf("backend_template_declared", dir))
+		return nil, nil
+	}
+	content := b.renderBackendFile(project, caseID)
+	if err := os.WriteFile(filepath.Join(dir, RedcBackendFile), []byte(content), 0644); err != nil {
+		return nil, err
+	}
+	var opts []tfexec.InitOption
+	for _, arg := range b.credentialArgs() {
+		opts = append(opts, tfexec.BackendConfig(arg))
+	}
+	return opts, nil
+}
+
+// BackendInitOptions 返回项目 backend 的 init 参数，并确保场景目录中存在 backend 声明
+func (p *RedcProject) BackendInitOptions(casePath, caseID string) ([]tfexec.InitOption, error) {
+	return WriteBackendFile(casePath, GetProjectBackend(p.ProjectName), p.ProjectName, caseID)
+}
+
+// backendInitOptions 已使用远程 backend 的场景重新 init 时需要再次传入凭据
+func (c *Case) backendInitOptions() []tfexec.InitOption {
+	if _, err := os.Stat(filepath.Join(c.Path, RedcBackendFile)); err != nil {
+		return nil
+	}
+	b := GetProjectBackend(c.ProjectID)
+	if b == nil {
+		return nil
+	}
+	var opts []tfexec.InitOption
+	for _, arg := range b.credentialArgs() {
+		opts = append(opts, tfexec.BackendConfig(arg))
+	}
+	return opts
+}
+
+// MigrateResult 单个场景的迁移结果
+type MigrateResult struct {
+	CaseID   string `json:"caseId"`
+	CaseName string `json:"caseName"`
+	Status   string `json:"status"` // migrated, skipped, failed
+	Error    string `json:"error,omitempty"`
+}
+
+// MigrateStateToBackend 将项目内本地 state 迁移到已配置的远程 backend
+func (p *RedcProject) MigrateStateToBackend() ([]MigrateResult, error) {
+	b := GetProjectBackend(p.ProjectName)
+	if b == nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("backend_not_configured", p.ProjectName))
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	cases, err := LoadProjectCases(p.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []MigrateResult
+	for _, c := range cases {
+		results = append(results, migrateCase(c, b, p.ProjectName))
+	}
+	return results, nil
+}
+
+// migrateCase 持有场景锁迁移单个场景的 state，场景正在被其他操作使用时跳过并在结果中报告
+func migrateCase(c *Case, b *BackendConfig, project string) MigrateResult {
+	r := MigrateResult{CaseID: c.Id, CaseName: c.Name}
+	release, err := c.acquireLock("migrate-state")
+	if err != nil {
+		r.Status = "failed"
+		if IsCaseLocked(err) {
+			r.Status = "skipped"
+		}
+		r.Error = err.Error()
+		return r
+	}
+	defer release()
+
+	if _, err := os.Stat(filepath.Join(c.Path, RedcBackendFile)); err == nil {
+		r.Status = "skipped"
+		return r
+	}
+	if hasBackendBlock(c.Path) {
+		r.Status = "skipped"
+		r.Error = i18n.Tf("backend_template_declared", c.Path)
+		return r
+	}
+	if err := migrateCaseState(c, b, project); err != nil {
+		r.Status = "failed"
+		r.Error = err.Error()
+		gologger.Error().Msgf("%s", i18n.Tf("backend_migrate_case_failed", c.Name, err))
+	} else {
+		r.Status = "migrated"
+		gologger.Info().Msgf("%s", i18n.Tf("backend_migrate_case_done", c.Name))
+	}
+	return r
+}
+
+// migrateCaseState 写入 backend 声明并执行 init -force-copy 迁移 state
+func migrateCaseState(c *Case, b *BackendConfig, project string) error {
+	opts, err := WriteBackendFile(c.Path, b, project, c.Id)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, tfexec.ForceCopy(true))
+	if err := TfInit(c.Path, opts...); err != nil {
+		// 迁移失败时回退到本地 state
+		os.Remove(filepath.Join(c.Path, RedcBackendFile))
+		return err
+	}
+	// 保留一份本地 state 备份，避免误用
+	local := filepath.Join(c.Path, "terraform.tfstate")
+	if _, err := os.Stat(local); err == nil {
+		os.Rename(local, local+".migrated")
+	}
+	return nil
+}
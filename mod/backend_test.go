@@ -0,0 +1,87 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderBackendFileS3(t *testing.T) {
+	b := &BackendConfig{
+		Type:         BackendS3,
+		Bucket:       "redc-state",
+		Endpoint:     "http://127.0.0.1:9000",
+		AccessKey:    "minio",
+		SecretKey:    "minio123",
+		KeyPrefix:    "/team/",
+		UsePathStyle: true,
+	}
+	content := b.renderBackendFile("default", "abc123")
+	for _, want := range []string{
+		`backend "s3" {`,
+		`bucket = "redc-state"`,
+		`key = "team/default/abc123/terraform.tfstate"`,
+		`region = "us-east-1"`,
+		`endpoints = { s3 = "http://127.0.0.1:9000" }`,
+		`use_path_style = true`,
+		`use_lockfile = true`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("backend file missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "minio123") {
+		t.Errorf("credentials must not be written to backend file:\n%s", content)
+	}
+	if args := b.credentialArgs(); len(args) != 2 || args[1] != "secret_key=minio123" {
+		t.Errorf("unexpected credential args: %v", args)
+	}
+}
+
+func TestRenderBackendFileHTTP(t *testing.T) {
+	b := &BackendConfig{Type: BackendHTTP, Address: "https://state.example.com/{project}/{case}"}
+	content := b.renderBackendFile("team", "c1")
+	if !strings.Contains(content, `address = "https://state.example.com/team/c1"`) ||
+		!strings.Contains(content, `lock_address = "https://state.example.com/team/c1"`) {
+		t.Errorf("unexpected http backend file:\n%s", content)
+	}
+	if err := (&BackendConfig{Type: BackendHTTP}).Validate(); err == nil {
+		t.Error("expected validation error for missing address")
+	}
+}
+
+func TestWriteBackendFileSkipsDeclaredBackend(t *testing.T) {
+	dir := t.TempDir()
+	tf := "terraform {\n  backend \"local\" {}\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(tf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := WriteBackendFile(dir, &BackendConfig{Type: BackendS3, Bucket: "b"}, "p", "c")
+	if err != nil || opts != nil {
+		t.Fatalf("expected skip, got opts=%v err=%v", opts, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, RedcBackendFile)); !os.IsNotExist(err) {
+		t.Error("backend file should not be written when template declares a backend")
+	}
+}
+
+func TestMigrateCaseSkipsLockedCase(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "migrate-locked", Name: "locked", ProjectID: "default", Path: t.TempDir()}
+	holder := &Case{Id: c.Id, Name: "holder"}
+	release, err := holder.acquireLock("apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	b := &BackendConfig{Type: BackendHTTP, Address: "https://state.example.com/{project}/{case}"}
+	r := migrateCase(c, b, "default")
+	if r.Status != "skipped" || r.Error == "" {
+		t.Fatalf("locked case should be skipped with the lock reported, got %+v", r)
+	}
+	if _, err := os.Stat(filepath.Join(c.Path, RedcBackendFile)); !os.IsNotExist(err) {
+		t.Error("backend file should not be written for a locked case")
+	}
+}
@@ -7,7 +7,8 @@ import (
 )
 
 // QueryAliyunBalance queries Aliyun account balance via BSS OpenAPI.
-func QueryAliyunBalance(accessKey string, secretKey string, region string) (string, string, error) {
+// securityToken is set when the keys are STS credentials from a RAM role
+func QueryAliyunBalance(accessKey string, secretKey string, securityToken string, region string) (string, string, error) {
 	if accessKey == "" || secretKey == "" {
 		return "", "", fmt.Errorf("missing aliyun access key or secret")
 	}
@@ -15,7 +16,13 @@ func QueryAliyunBalance(accessKey string, secretKey string, region string) (stri
 		region = "cn-hangzhou"
 	}
 
-	client, err := bssopenapi.NewClientWithAccessKey(region, accessKey, secretKey)
+	var client *bssopenapi.Client
+	var err error
+	if securityToken != "" {
+		client, err = bssopenapi.NewClientWithStsToken(region, accessKey, secretKey, securityToken)
+	} else {
+		client, err = bssopenapi.NewClientWithAccessKey(region, accessKey, secretKey)
+	}
 	if err != nil {
 		return "", "", err
 	}
@@ -10,8 +10,9 @@ import (
 	"github.com/aws/aws-sdk-go/service/costexplorer"
 )
 
-// QueryAWSBill queries AWS current month bill via Cost Explorer API
-func QueryAWSBill(accessKey string, secretKey string, region string) (string, string, error) {
+// QueryAWSBill queries AWS current month bill via Cost Explorer API.
+// sessionToken is set when the keys are temporary credentials from AssumeRole
+func QueryAWSBill(accessKey string, secretKey string, sessionToken string, region string) (string, string, error) {
 	if accessKey == "" || secretKey == "" {
 		return "", "", fmt.Errorf("missing AWS access key or secret")
 	}
@@ -22,7 +23,7 @@ func QueryAWSBill(accessKey string, secretKey string, region string) (string, st
 
 	sess, err := session.NewSession(&aws.Config{
 		Region:      aws.String(region),
-		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, ""),
+		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, sessionToken),
 	})
 	if err != nil {
 		return "", "", err
@@ -84,8 +85,8 @@ func QueryAWSBill(accessKey string, secretKey string, region string) (string, st
 
 // QueryAWSBalance queries AWS account balance (credits and remaining)
 // This is an estimate based on recent usage
-func QueryAWSBalance(accessKey string, secretKey string, region string) (string, string, error) {
-	amount, currency, err := QueryAWSBill(accessKey, secretKey, region)
+func QueryAWSBalance(accessKey string, secretKey string, sessionToken string, region string) (string, string, error) {
+	amount, currency, err := QueryAWSBill(accessKey, secretKey, sessionToken, region)
 	if err != nil {
 		return "", "", err
 	}
@@ -109,7 +110,7 @@ type AWSServiceDetail struct {
 }
 
 // QueryAWSBillingDetail queries detailed AWS billing by service
-func QueryAWSBillingDetail(accessKey string, secretKey string, region string) (AWSBillingInfo, error) {
+func QueryAWSBillingDetail(accessKey string, secretKey string, sessionToken string, region string) (AWSBillingInfo, error) {
 	var result AWSBillingInfo
 
 	if accessKey == "" || secretKey == "" {
@@ -122,7 +123,7 @@ func QueryAWSBillingDetail(accessKey string, secretKey string, region string) (A
 
 	sess, err := session.NewSession(&aws.Config{
 		Region:      aws.String(region),
-		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, ""),
+		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, sessionToken),
 	})
 	if err != nil {
 		return result, err
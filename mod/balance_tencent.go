@@ -9,7 +9,8 @@ import (
 )
 
 // QueryTencentBalance queries Tencent Cloud account balance via Billing API.
-func QueryTencentBalance(secretId string, secretKey string, region string) (string, string, error) {
+// token is set when the keys are temporary credentials from a CAM role
+func QueryTencentBalance(secretId string, secretKey string, token string, region string) (string, string, error) {
 	if secretId == "" || secretKey == "" {
 		return "", "", fmt.Errorf("missing tencentcloud secret id or key")
 	}
@@ -17,7 +18,7 @@ func QueryTencentBalance(secretId string, secretKey string, region string) (stri
 		region = "ap-guangzhou"
 	}
 
-	cred := common.NewCredential(secretId, secretKey)
+	cred := common.NewTokenCredential(secretId, secretKey, token)
 	cpf := profile.NewClientProfile()
 	cpf.HttpProfile.ReqMethod = "POST"
 	cpf.HttpProfile.ReqTimeout = 10
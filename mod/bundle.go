@@ -0,0 +1,603 @@
+package mod
+
+import (
+	"archive/zip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"red-cloud/pb"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"golang.org/x/crypto/scrypt"
+	"google.golang.org/protobuf/proto"
+)
+
+// 场景导出包 (zip) 内的固定条目
+const (
+	BundleVersion       = 1
+	BundleExt           = ".redcbundle"
+	bundleManifestFile  = "manifest.json"
+	bundleCaseFile      = "case.pb"
+	bundleTagsFile      = "tags.json"
+	bundleStateFile     = "terraform.tfstate"
+	bundlePluginOutputs = "plugin_outputs.json"
+	bundleFilesDir      = "files/"
+)
+
+// BundleFile 导出包中单个条目的校验信息，SHA256 针对包内实际存储的内容计算
+type BundleFile struct {
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+}
+
+// BundleKDF 口令派生参数 (scrypt)
+type BundleKDF struct {
+	Salt string `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// BundleManifest 导出包清单
+type BundleManifest struct {
+	Version     int          `json:"version"`
+	RedcVersion string       `json:"redcVersion"`
+	CaseID      string       `json:"caseId"`
+	CaseName    string       `json:"caseName"`
+	Template    string       `json:"template"`
+	Project     string       `json:"project"`
+	State       string       `json:"state"`
+	ExportedAt  time.Time    `json:"exportedAt"`
+	ExportedBy  string       `json:"exportedBy"`
+	Encrypted   bool         `json:"encrypted"`
+	KDF         *BundleKDF   `json:"kdf,omitempty"`
+	Files       []BundleFile `json:"files"`
+}
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	Passphrase string // 非空时加密包内的敏感条目
+}
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	Passphrase string
+	SkipInit   bool // 不执行 terraform init (离线导入)
+}
+
+// bundleSkipDirs 导出时跳过的目录，provider 缓存在导入端重新 init
+var bundleSkipDirs = map[string]bool{".terraform": true}
+
+// isBundleSkipped 场景目录中不需要打包的文件
+func isBundleSkipped(rel string) bool {
+	base := path.Base(rel)
+	switch {
+	case base == RedcBackendFile, base == bundleStateFile, base == bundlePluginOutputs:
+		// backend 与项目相关，state 和插件输出单独打包
+		return true
+	case strings.HasSuffix(base, ".tfplan"), strings.HasPrefix(base, "terraform.tfstate."):
+		return true
+	}
+	return false
+}
+
+// isBundleSecret 判断条目是否包含敏感信息 (凭据、密码、私钥等)
+func isBundleSecret(name string) bool {
+	switch name {
+	case bundleCaseFile, bundleStateFile, bundlePluginOutputs:
+		return true
+	}
+	base := strings.ToLower(path.Base(name))
+	for _, suffix := range []string{".tfvars", ".tfvars.json", ".pem", ".key", ".tfstate"} {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(base, "id_rsa") || strings.HasPrefix(base, "id_ed25519")
+}
+
+// newBundleKDF 生成新的随机盐
+func newBundleKDF() (*BundleKDF, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return &BundleKDF{Salt: hex.EncodeToString(salt), N: 1 << 15, R: 8, P: 1}, nil
+}
+
+// deriveKey 从口令派生 AES-256 密钥
+func (k *BundleKDF) deriveKey(passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(k.Salt)
+	if err != nil {
+		return nil, err
+	}
+	return scrypt.Key([]byte(passphrase), salt, k.N, k.R, k.P, 32)
+}
+
+// sealData AES-GCM 加密，输出 nonce||ciphertext，条目名作为附加数据防止互换
+func sealData(key, plain []byte, name string) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plain, []byte(name)), nil
+}
+
+// openData 解密 sealData 的输出
+func openData(key, data []byte, name string) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(name))
+}
+
+// bundleWriter 写入 zip 条目并记录清单
+type bundleWriter struct {
+	zw       *zip.Writer
+	key      []byte
+	manifest *BundleManifest
+}
+
+func (w *bundleWriter) add(name string, data []byte) error {
+	encrypted := false
+	if w.key != nil && isBundleSecret(name) {
+		sealed, err := sealData(w.key, data, name)
+		if err != nil {
+			return err
+		}
+		data, encrypted = sealed, true
+	}
+	f, err := w.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	w.manifest.Files = append(w.manifest.Files, BundleFile{
+		Path:      name,
+		Size:      int64(len(data)),
+		SHA256:    hex.EncodeToString(sum[:]),
+		Encrypted: encrypted,
+	})
+	return nil
+}
+
+// pullState 读取场景 state，远程 backend 时通过 terraform state pull 获取
+func (c *Case) pullState() ([]byte, error) {
+	if _, err := os.Stat(filepath.Join(c.Path, RedcBackendFile)); err != nil {
+		data, err := os.ReadFile(filepath.Join(c.Path, bundleStateFile))
+		if os.IsNotExist(err) {
+			return nil, nil // 尚未 apply
+		}
+		return data, err
+	}
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	te, err := NewTerraformExecutor(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	state, err := te.StatePull(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(state), nil
+}
+
+// Export 将场景目录、state、元数据、插件输出和标签打包到 dest
+func (c *Case) Export(dest string, opts ExportOptions) (*BundleManifest, error) {
+	release, lockErr := c.acquireLock("export")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer release()
+
+	manifest := &BundleManifest{
+		Version:     BundleVersion,
+		RedcVersion: Version,
+		CaseID:      c.Id,
+		CaseName:    c.Name,
+		Template:    c.Type,
+		Project:     c.ProjectID,
+		State:       c.State,
+		ExportedAt:  time.Now(),
+		ExportedBy:  U,
+	}
+	w := &bundleWriter{manifest: manifest}
+	if opts.Passphrase != "" {
+		kdf, err := newBundleKDF()
+		if err != nil {
+			return nil, err
+		}
+		key, err := kdf.deriveKey(opts.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Encrypted, manifest.KDF, w.key = true, kdf, key
+	} else {
+		gologger.Warning().Msgf("%s", i18n.T("bundle_plaintext_warning"))
+	}
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_create_failed", dest, err))
+	}
+	ok := false
+	defer func() {
+		out.Close()
+		if !ok {
+			os.Remove(dest)
+		}
+	}()
+	w.zw = zip.NewWriter(out)
+
+	caseData, err := proto.Marshal(c.toProto())
+	if err != nil {
+		return nil, fmt.Errorf("序列化失败: %v", err)
+	}
+	if err := w.add(bundleCaseFile, caseData); err != nil {
+		return nil, err
+	}
+
+	state, err := c.pullState()
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_state_failed", err))
+	}
+	if state != nil {
+		if err := w.add(bundleStateFile, state); err != nil {
+			return nil, err
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(c.Path, bundlePluginOutputs)); err == nil {
+		if err := w.add(bundlePluginOutputs, data); err != nil {
+			return nil, err
+		}
+	}
+
+	var tags []string
+	if settings, err := LoadGUISettings(); err == nil && settings != nil {
+		tags = settings.CaseTags[c.Id]
+	}
+	tagData, _ := json.Marshal(tags)
+	if err := w.add(bundleTagsFile, tagData); err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(c.Path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if bundleSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(c.Path, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if isBundleSkipped(rel) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return w.add(bundleFilesDir+rel, data)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_create_failed", dest, err))
+	}
+
+	mf, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	f, err := w.zw.Create(bundleManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(mf); err != nil {
+		return nil, err
+	}
+	if err := w.zw.Close(); err != nil {
+		return nil, err
+	}
+	ok = true
+	gologger.Info().Msgf("%s", i18n.Tf("bundle_exported", c.Name, dest, len(manifest.Files)))
+	return manifest, nil
+}
+
+// bundleReader 读取并校验导出包
+type bundleReader struct {
+	zr       *zip.ReadCloser
+	key      []byte
+	manifest *BundleManifest
+	entries  map[string]*zip.File
+}
+
+// loadBundle 打开导出包并解析清单
+func loadBundle(bundlePath string) (*bundleReader, error) {
+	zr, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_open_failed", bundlePath, err))
+	}
+	r := &bundleReader{zr: zr, entries: make(map[string]*zip.File)}
+	for _, f := range zr.File {
+		r.entries[f.Name] = f
+	}
+	mf, err := r.readRaw(bundleManifestFile)
+	if err != nil {
+		zr.Close()
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_invalid", err))
+	}
+	r.manifest = &BundleManifest{}
+	if err := json.Unmarshal(mf, r.manifest); err != nil {
+		zr.Close()
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_invalid", err))
+	}
+	if r.manifest.Version > BundleVersion {
+		zr.Close()
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_version_unsupported", r.manifest.Version))
+	}
+	return r, nil
+}
+
+// openBundle 打开导出包，派生解密密钥并校验清单中所有条目的 SHA256
+func openBundle(bundlePath, passphrase string) (*bundleReader, error) {
+	r, err := loadBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	if r.manifest.Encrypted {
+		if passphrase == "" {
+			r.Close()
+			return nil, fmt.Errorf("%s", i18n.T("bundle_passphrase_required"))
+		}
+		if r.manifest.KDF == nil {
+			r.Close()
+			return nil, fmt.Errorf("%s", i18n.Tf("bundle_invalid", "missing kdf"))
+		}
+		if r.key, err = r.manifest.KDF.deriveKey(passphrase); err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+	if err := r.verify(); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *bundleReader) Close() error {
+	return r.zr.Close()
+}
+
+func (r *bundleReader) readRaw(name string) ([]byte, error) {
+	f, ok := r.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("missing entry %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// verify 校验清单中的每个条目，并拒绝不安全的路径
+func (r *bundleReader) verify() error {
+	for _, bf := range r.manifest.Files {
+		if !filepath.IsLocal(filepath.FromSlash(bf.Path)) {
+			return fmt.Errorf("%s", i18n.Tf("bundle_invalid", "unsafe path "+bf.Path))
+		}
+		data, err := r.readRaw(bf.Path)
+		if err != nil {
+			return fmt.Errorf("%s", i18n.Tf("bundle_invalid", err))
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != bf.SHA256 {
+			return fmt.Errorf("%s", i18n.Tf("bundle_checksum_mismatch", bf.Path))
+		}
+	}
+	return nil
+}
+
+// read 读取条目内容，加密条目自动解密
+func (r *bundleReader) read(name string) ([]byte, error) {
+	data, err := r.readRaw(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, bf := range r.manifest.Files {
+		if bf.Path == name && bf.Encrypted {
+			plain, err := openData(r.key, data, name)
+			if err != nil {
+				return nil, fmt.Errorf("%s", i18n.T("bundle_decrypt_failed"))
+			}
+			return plain, nil
+		}
+	}
+	return data, nil
+}
+
+// has 条目是否在清单中
+func (r *bundleReader) has(name string) bool {
+	for _, bf := range r.manifest.Files {
+		if bf.Path == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadBundleManifest 读取并校验导出包清单，不解密内容
+func ReadBundleManifest(bundlePath string) (*BundleManifest, error) {
+	r, err := loadBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return r.manifest, r.verify()
+}
+
+// ImportCaseBundle 将导出包恢复到项目中，重建数据库记录
+func (p *RedcProject) ImportCaseBundle(bundlePath string, opts ImportOptions) (*Case, error) {
+	r, err := openBundle(bundlePath, opts.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	caseData, err := r.read(bundleCaseFile)
+	if err != nil {
+		return nil, err
+	}
+	var pc pb.Case
+	if err := proto.Unmarshal(caseData, &pc); err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_invalid", err))
+	}
+	c := caseFromProto(&pc)
+
+	// 引用的模版必须存在，否则后续无法管理
+	if _, err := GetTemplatePath(c.Type); err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_template_missing", c.Type, err))
+	}
+	if existing, _ := LoadProjectCases(p.ProjectName); existing != nil {
+		for _, e := range existing {
+			if e.Id == c.Id {
+				return nil, fmt.Errorf("%s", i18n.Tf("bundle_case_exists", c.Id, p.ProjectName))
+			}
+		}
+	}
+
+	casePath := filepath.Join(p.ProjectPath, c.Id)
+	if _, err := os.Stat(casePath); err == nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_case_exists", c.Id, p.ProjectName))
+	}
+	if err := r.extract(casePath); err != nil {
+		os.RemoveAll(casePath)
+		return nil, fmt.Errorf("%s", i18n.Tf("bundle_extract_failed", err))
+	}
+
+	c.Path = casePath
+	c.ProjectID = p.ProjectName
+
+	if !opts.SkipInit {
+		if b := GetProjectBackend(p.ProjectName); b != nil && r.has(bundleStateFile) {
+			// 目标项目使用远程 backend，将 state 迁移上去
+			err = migrateCaseState(c, b, p.ProjectName)
+		} else {
+			var initOpts []tfexec.InitOption
+			initOpts, err = p.BackendInitOptions(casePath, c.Id)
+			if err == nil {
+				err = TfInit(casePath, initOpts...)
+			}
+		}
+		if err != nil {
+			gologger.Warning().Msgf("%s", i18n.Tf("bundle_init_failed", c.Name, err))
+		}
+	}
+
+	if err := p.AddCase(c); err != nil {
+		os.RemoveAll(casePath)
+		return nil, err
+	}
+
+	if tagData, err := r.read(bundleTagsFile); err == nil {
+		var tags []string
+		if json.Unmarshal(tagData, &tags) == nil && len(tags) > 0 {
+			if settings, err := LoadGUISettings(); err == nil && settings != nil {
+				if settings.CaseTags == nil {
+					settings.CaseTags = make(map[string][]string)
+				}
+				settings.CaseTags[c.Id] = tags
+				if err := SaveGUISettings(settings); err != nil {
+					gologger.Warning().Msgf("%s", i18n.Tf("bundle_tags_failed", err))
+				}
+			}
+		}
+	}
+
+	RedcLog("导入成功 " + casePath + " " + c.Type)
+	gologger.Info().Msgf("%s", i18n.Tf("bundle_imported", c.Name, c.Id, p.ProjectName))
+	return c, nil
+}
+
+// extract 解压场景目录、state 和插件输出
+func (r *bundleReader) extract(casePath string) error {
+	names := make([]string, 0, len(r.manifest.Files))
+	for _, bf := range r.manifest.Files {
+		names = append(names, bf.Path)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var rel string
+		switch {
+		case strings.HasPrefix(name, bundleFilesDir):
+			rel = strings.TrimPrefix(name, bundleFilesDir)
+		case name == bundleStateFile, name == bundlePluginOutputs:
+			rel = name
+		default:
+			continue
+		}
+		data, err := r.read(name)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(casePath, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		mode := os.FileMode(0644)
+		if isBundleSecret(name) {
+			mode = 0600
+		}
+		if err := os.WriteFile(target, data, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
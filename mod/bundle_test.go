@@ -0,0 +1,115 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupBundleEnv 准备临时 redc 目录、模版和一个已有 state 的场景
+func setupBundleEnv(t *testing.T) (*Case, *RedcProject) {
+	t.Helper()
+	RedcPath = t.TempDir()
+	TemplateDir = filepath.Join(RedcPath, "redc-templates")
+	LoadedGUISettings = &GUISettings{CaseTags: map[string][]string{"bundle-case-1": {"red", "team-a"}}}
+	t.Cleanup(func() { LoadedGUISettings = nil })
+
+	tmpl := filepath.Join(TemplateDir, "aliyun", "ecs")
+	if err := os.MkdirAll(tmpl, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(tmpl, TmplCaseFile), []byte("{}"), 0644)
+
+	src := &RedcProject{ProjectName: "src", ProjectPath: filepath.Join(RedcPath, "src")}
+	c := &Case{
+		Id:        "bundle-case-1",
+		Name:      "ecs-demo",
+		Type:      "aliyun/ecs",
+		Path:      filepath.Join(src.ProjectPath, "bundle-case-1"),
+		State:     StateRunning,
+		Parameter: []string{"-var=password=S3cret!"},
+		ProjectID: src.ProjectName,
+	}
+	files := map[string]string{
+		"main.tf":                  "resource \"null_resource\" \"a\" {}",
+		"terraform.tfstate":        `{"version":4,"serial":7}`,
+		"plugin_outputs.json":      `{"token":"abc"}`,
+		"keys/id_rsa":              "PRIVATE",
+		".terraform/providers/x":   "cache",
+		"case.tfplan":              "plan",
+		"terraform.tfstate.backup": "old",
+	}
+	for name, content := range files {
+		p := filepath.Join(c.Path, name)
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c, &RedcProject{ProjectName: "dst", ProjectPath: filepath.Join(RedcPath, "dst")}
+}
+
+func TestCaseBundleRoundTripEncrypted(t *testing.T) {
+	c, dst := setupBundleEnv(t)
+	bundle := filepath.Join(RedcPath, "out"+BundleExt)
+	manifest, err := c.Export(bundle, ExportOptions{Passphrase: "pw"})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	for _, f := range manifest.Files {
+		if strings.Contains(f.Path, ".terraform/") || strings.HasSuffix(f.Path, ".tfplan") || strings.HasSuffix(f.Path, ".backup") {
+			t.Errorf("unexpected entry %s", f.Path)
+		}
+		if isBundleSecret(f.Path) != f.Encrypted {
+			t.Errorf("entry %s encrypted=%v", f.Path, f.Encrypted)
+		}
+	}
+
+	if _, err := dst.ImportCaseBundle(bundle, ImportOptions{SkipInit: true}); err == nil {
+		t.Fatal("expected passphrase error")
+	}
+	if _, err := dst.ImportCaseBundle(bundle, ImportOptions{Passphrase: "wrong", SkipInit: true}); err == nil {
+		t.Fatal("expected decrypt error")
+	}
+
+	imported, err := dst.ImportCaseBundle(bundle, ImportOptions{Passphrase: "pw", SkipInit: true})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if imported.ProjectID != "dst" || imported.State != StateRunning || imported.Parameter[0] != c.Parameter[0] {
+		t.Errorf("unexpected imported case %+v", imported)
+	}
+	for name, want := range map[string]string{
+		"terraform.tfstate":   `{"version":4,"serial":7}`,
+		"plugin_outputs.json": `{"token":"abc"}`,
+		"keys/id_rsa":         "PRIVATE",
+	} {
+		data, err := os.ReadFile(filepath.Join(imported.Path, name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v", name, data, err)
+		}
+	}
+	cases, _ := LoadProjectCases("dst")
+	if len(cases) != 1 || cases[0].Id != c.Id {
+		t.Fatalf("case record not rebuilt: %+v", cases)
+	}
+	if _, err := dst.ImportCaseBundle(bundle, ImportOptions{Passphrase: "pw", SkipInit: true}); err == nil {
+		t.Fatal("expected duplicate case error")
+	}
+}
+
+func TestCaseBundleRejectsMissingTemplate(t *testing.T) {
+	c, dst := setupBundleEnv(t)
+	c.Type = "aws/not-installed"
+	bundle := filepath.Join(RedcPath, "out"+BundleExt)
+	if _, err := c.Export(bundle, ExportOptions{}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if m, err := ReadBundleManifest(bundle); err != nil || m.Encrypted {
+		t.Fatalf("manifest: %+v %v", m, err)
+	}
+	if _, err := dst.ImportCaseBundle(bundle, ImportOptions{SkipInit: true}); err == nil || !strings.Contains(err.Error(), "aws/not-installed") {
+		t.Fatalf("expected template error, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"red-cloud/i18n"
 	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
 	"red-cloud/utils"
 	"strings"
 	"text/tabwriter"
@@ -89,16 +90,21 @@ func CaseScene(t string, m map[string]string) ([]string, error) {
 	}
 	// 增加自定义参数
 	for k, v := range m {
-		if scene == "dnslog" || scene == "xraydnslog" || scene == "interactsh" {
-			if strings.EqualFold(k, "domain") {
-				if !strings.HasPrefix(strings.ToLower(v), "a.") {
-					v = "a." + v
-				}
+		par = append(par, fmt.Sprintf("%s=%s", k, normalizeSceneVar(scene, k, v)))
+	}
+	return par, nil
+}
+
+// normalizeSceneVar 处理特定场景对自定义参数的格式要求
+func normalizeSceneVar(scene, k, v string) string {
+	if scene == "dnslog" || scene == "xraydnslog" || scene == "interactsh" {
+		if strings.EqualFold(k, "domain") {
+			if !strings.HasPrefix(strings.ToLower(v), "a.") {
+				v = "a." + v
 			}
 		}
-		par = append(par, fmt.Sprintf("%s=%s", k, v))
 	}
-	return par, nil
+	return v
 }
 
 func providerFromTemplateName(templateName string) string {
@@ -109,7 +115,7 @@ func providerFromTemplateName(templateName string) string {
 	return ""
 }
 
-func ensureProviderVars(templateName string, vars map[string]string) map[string]string {
+func ensureProviderVars(templateName, account string, vars map[string]string) map[string]string {
 	if vars == nil {
 		vars = map[string]string{}
 	}
@@ -119,6 +125,10 @@ func ensureProviderVars(templateName string, vars map[string]string) map[string]
 	if err != nil || conf == nil {
 		return vars
 	}
+	// 指定了账号时使用该账号的凭据
+	if acc, err := conf.ForAccount(provider, account); err == nil {
+		conf = acc
+	}
 	
 	// 处理阿里云
 	// 阿里云使用环境变量 ALICLOUD_ACCESS_KEY 和 ALICLOUD_SECRET_KEY，不需要通过 -var 传递
@@ -132,11 +142,14 @@ func ensureProviderVars(templateName string, vars map[string]string) map[string]
 	
 	// 处理腾讯云
 	if provider == "tencent" || provider == "tencentcloud" {
-		if (vars["tencentcloud_secret_id"] == "" || isPlaceholderSecret(vars["tencentcloud_secret_id"])) && conf.Providers.Tencentcloud.SecretId != "" {
-			vars["tencentcloud_secret_id"] = conf.Providers.Tencentcloud.SecretId
-		}
-		if (vars["tencentcloud_secret_key"] == "" || isPlaceholderSecret(vars["tencentcloud_secret_key"])) && conf.Providers.Tencentcloud.SecretKey != "" {
-			vars["tencentcloud_secret_key"] = conf.Providers.Tencentcloud.SecretKey
+		// 配置了 CAM 角色时由环境变量传递临时凭据，临时密钥不能写入场景参数
+		if !conf.HasRole(provider) {
+			if (vars["tencentcloud_secret_id"] == "" || isPlaceholderSecret(vars["tencentcloud_secret_id"])) && conf.Providers.Tencentcloud.SecretId != "" {
+				vars["tencentcloud_secret_id"] = conf.Providers.Tencentcloud.SecretId
+			}
+			if (vars["tencentcloud_secret_key"] == "" || isPlaceholderSecret(vars["tencentcloud_secret_key"])) && conf.Providers.Tencentcloud.SecretKey != "" {
+				vars["tencentcloud_secret_key"] = conf.Providers.Tencentcloud.SecretKey
+			}
 		}
 		// 如果模板需要 instance_password 且用户未提供，则自动生成
 		if vars["instance_password"] == "" {
@@ -233,7 +246,7 @@ func generateInstancePassword() string {
 	return password
 }
 
-func ensureProviderParams(templateName string, params []string) []string {
+func ensureProviderParams(templateName, account string, params []string) []string {
 	paramMap := map[string]string{}
 	order := make([]string, 0, len(params))
 	for _, param := range params {
@@ -246,7 +259,7 @@ func ensureProviderParams(templateName string, params []string) []string {
 		}
 		paramMap[key] = val
 	}
-	paramMap = ensureProviderVars(templateName, paramMap)
+	paramMap = ensureProviderVars(templateName, account, paramMap)
 	merged := make([]string, 0, len(paramMap))
 	seen := map[string]bool{}
 	for _, key := range order {
@@ -263,11 +276,17 @@ func ensureProviderParams(templateName string, params []string) []string {
 	return merged
 }
 
+// CaseCreate 创建场景。vars[AccountVar] 指定使用的命名账号
 func (p *RedcProject) CaseCreate(CaseName string, User string, Name string, vars map[string]string) (*Case, error) {
+	account := vars[AccountVar]
+	delete(vars, AccountVar)
+	if err := ValidateAccount(account); err != nil {
+		return nil, err
+	}
 	// 创建新的 case 目录,这里不需要检测是否存在,因为名称是采用nanoID
 	gologger.Info().Msgf("%s", i18n.Tf("case_creating", CaseName))
 	uid := GenerateCaseID()
-	vars = ensureProviderVars(CaseName, vars)
+	vars = ensureProviderVars(CaseName, account, vars)
 
 	// 从模版文件夹复制模版
 	tpPath, err := GetTemplatePath(CaseName)
@@ -275,6 +294,7 @@ func (p *RedcProject) CaseCreate(CaseName string, User string, Name string, vars
 		return nil, fmt.Errorf("%s", i18n.Tf("case_get_template_error", err.Error()))
 	}
 	casePath := filepath.Join(p.ProjectPath, uid)
+	SetDirAccount(casePath, account)
 
 	// 复制 tf文件
 	gologger.Debug().Msgf("%s", i18n.Tf("case_copying_template", uid))
@@ -282,8 +302,15 @@ func (p *RedcProject) CaseCreate(CaseName string, User string, Name string, vars
 		return nil, fmt.Errorf("%s", i18n.Tf("case_copy_template_error", err))
 	}
 
+	// 配置项目远程 state backend (未配置时使用本地 state)
+	initOpts, err := p.BackendInitOptions(casePath, uid)
+	if err != nil {
+		os.RemoveAll(casePath)
+		return nil, fmt.Errorf("%s", i18n.Tf("backend_config_failed", err))
+	}
+
 	// 在次 init,防止万一
-	if err := TfInit2(casePath); err != nil {
+	if err := TfInit2(casePath, initOpts...); err != nil {
 		gologger.Error().Msgf("%s", i18n.Tf("case_second_init_failed", err.Error()))
 		return nil, err
 	}
@@ -324,9 +351,12 @@ func (p *RedcProject) CaseCreate(CaseName string, User string, Name string, vars
 		Parameter:  par,
 		ProjectID:  p.ProjectName,
 		State:      StatePending,
+		Account:    account,
 	}
 	// 绑定 project 参数
 	c.bindHandlers()
+	// 创建过程的状态变迁记录到创建者名下
+	c.SetActor(User, "")
 
 	// 构建场景
 	if err := c.TfPlan(); err != nil {
@@ -346,14 +376,27 @@ func (p *RedcProject) CaseCreate(CaseName string, User string, Name string, vars
 }
 
 func (c *Case) TfApply() error {
+	release, lockErr := c.acquireLock("apply")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+	return c.tfApplyLocked()
+}
+
+// tfApplyLocked TfApply 的实现，调用方须已持有场景锁
+func (c *Case) tfApplyLocked() error {
 	var err error
 	gologger.Info().Msgf("%s", i18n.Tf("case_starting", c.Name, c.GetId()))
 	if c.State == StateRunning {
 		return fmt.Errorf("%s", i18n.T("case_scene_running"))
 	}
+	prevState := c.State
 	
 	// 设置为正在启动状态
-	c.StatusChange(StateStarting)
+	if err := c.StatusChange(StateStarting); err != nil {
+		return err
+	}
 	
 	// pre-apply hook
 	c.runPluginHook("pre-apply")
@@ -364,11 +407,20 @@ func (c *Case) TfApply() error {
 		c.StatusChange(StateError)
 		return fmt.Errorf("%s", i18n.Tf("case_plan_refresh_failed", err))
 	}
+
+	// 策略检查，拒绝时尚未创建任何资源，恢复原状态
+	if _, err = c.CheckPolicy(); err != nil {
+		if !CanTransition(StateStarting, prevState) {
+			prevState = StateStopped
+		}
+		c.StatusChange(prevState)
+		return err
+	}
 	
 	if err = TfApply(c.Path, c.Parameter...); err != nil {
 		c.StatusChange(StateError)
 		// 启动失败立即销毁
-		if err := c.TfDestroy(); err != nil {
+		if err := c.TfDestroyLocked(); err != nil {
 			return err
 		}
 		return err
@@ -379,7 +431,7 @@ func (c *Case) TfApply() error {
 		return err
 	}
 	for s, meta := range output {
-		fmt.Println(s, string(meta.Value))
+		fmt.Println(s, secret.Redact(string(meta.Value)))
 	}
 	// Save outputs to DB so SSH and other queries get fresh data
 	if c.saveHandler != nil {
@@ -454,8 +506,24 @@ func (c *Case) runPluginHook(hookPoint string) {
 	if c.pluginHookRunner == nil {
 		return
 	}
-	if err := c.pluginHookRunner(hookPoint, c); err != nil {
+	// 记录到当前操作的日志；单独触发的 hook 作为独立操作记录
+	l := activeOpLog(c.Path)
+	standalone := l == nil
+	if standalone {
+		l = beginCaseOp(c, OpKindPlugin, hookPoint)
+	}
+	if l != nil {
+		l.Printf("plugin hook %s", hookPoint)
+	}
+	err := c.pluginHookRunner(hookPoint, c)
+	if err != nil {
 		gologger.Warning().Msgf("plugin hook %s: %v", hookPoint, err)
+		if l != nil && !standalone {
+			l.Printf("plugin hook %s: %v", hookPoint, err)
+		}
+	}
+	if l != nil && standalone {
+		l.finish(err)
 	}
 }
 
@@ -484,11 +552,19 @@ func (c *Case) bindHandlers() {
 	c.removeHandle = func() error {
 		return c.DBRemove()
 	}
+	SetDirAccount(c.Path, c.Account)
+	c.registerSecrets()
 }
 
 func (c *Case) TfPlan() error {
+	release, lockErr := c.acquireLock("plan")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+
 	gologger.Info().Msgf("%s", i18n.Tf("case_building", c.Name, c.GetId()))
-	c.Parameter = ensureProviderParams(c.Type, c.Parameter)
+	c.Parameter = ensureProviderParams(c.Type, c.Account, c.Parameter)
 	c.runPluginHook("pre-plan")
 	if err := TfPlan(c.Path, c.Parameter...); err != nil {
 		return err
@@ -498,7 +574,17 @@ func (c *Case) TfPlan() error {
 	return nil
 }
 
-func (c *Case) StatusChange(s string) {
+// StatusChange 按状态机变更场景状态并记录变迁历史，非法变迁返回 *InvalidTransitionError 且不修改状态
+func (c *Case) StatusChange(s string) error {
+	from := c.State
+	if !CanTransition(from, s) {
+		err := &InvalidTransitionError{CaseID: c.Id, CaseName: c.Name, From: from, To: s}
+		gologger.Warning().Msgf("%s", err.Error())
+		return err
+	}
+	if s == StateError {
+		recordOpError(c.Path, fmt.Errorf("%s", i18n.Tf("oplog_case_error", c.Name)))
+	}
 	c.State = s
 	// Use RFC3339 format to include timezone information
 	c.StateTime = time.Now().Format(time.RFC3339)
@@ -507,13 +593,30 @@ func (c *Case) StatusChange(s string) {
 			gologger.Error().Msgf("%s", i18n.Tf("case_save_state_failed", err))
 		}
 	}
+	if from != s && c.Id != "" {
+		actor, source := c.transitionActor()
+		recordTransition(&StateTransition{CaseID: c.Id, From: from, To: s, Actor: actor, Source: source, Time: time.Now()})
+	}
+	return nil
 }
 
 func (c *Case) TfDestroy() error {
+	release, lockErr := c.acquireLock("destroy")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+	return c.TfDestroyLocked()
+}
+
+// TfDestroyLocked 与 TfDestroy 相同，调用方须已持有场景锁 (场景内部的组合操作，或 AcquireLock 之后的 Spot 恢复回滚)
+func (c *Case) TfDestroyLocked() error {
 	gologger.Info().Msgf("%s", i18n.Tf("case_destroying", c.Name, c.GetId()))
 	
 	// 设置为正在停止状态
-	c.StatusChange(StateStopping)
+	if err := c.StatusChange(StateStopping); err != nil {
+		return err
+	}
 	
 	// pre-destroy hook
 	c.runPluginHook("pre-destroy")
@@ -525,17 +628,29 @@ func (c *Case) TfDestroy() error {
 		return err
 	}
 	c.StatusChange(StateStopped)
+	// 资源已销毁，TTL 随之失效
+	if err := ClearCaseLease(c.Id); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("lease_clear_failed", c.Name, err))
+	}
 	// post-destroy hook
 	c.runPluginHook("post-destroy")
 	return nil
 }
 func (c *Case) Remove() error {
+	release, lockErr := c.acquireLock("remove")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+
 	if c.State == StateRunning {
 		return fmt.Errorf("%s", i18n.T("case_delete_running"))
 	}
 	
 	// 设置为正在删除状态
-	c.StatusChange(StateRemoving)
+	if err := c.StatusChange(StateRemoving); err != nil {
+		return err
+	}
 	
 	err := os.RemoveAll(c.Path)
 	if err != nil {
@@ -552,10 +667,20 @@ func (c *Case) Remove() error {
 
 // Stop 停止场景
 func (c *Case) Stop() error {
+	release, lockErr := c.acquireLock("stop")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+	return c.stopLocked()
+}
+
+// stopLocked Stop 的实现，调用方须已持有场景锁
+func (c *Case) stopLocked() error {
 	if c.State != StateRunning {
 		gologger.Warning().Msg(i18n.T("case_destroy_warning"))
 	}
-	err := c.TfDestroy()
+	err := c.TfDestroyLocked()
 	if err != nil {
 		return err
 	}
@@ -564,48 +689,95 @@ func (c *Case) Stop() error {
 
 // Kill 强制销毁场景
 func (c *Case) Kill() error {
+	release, lockErr := c.acquireLock("kill")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+
 	// 在次 init,防止万一
 	dirs := utils.ChechDirMain(c.Path)
 	for _, v := range dirs {
 		err := utils.CheckFileName(v, "tf")
 		if err {
-			TfInit2(v)
+			TfInit2(v, c.backendInitOptions()...)
 		}
 	}
 	// 销毁场景
-	if err := c.Stop(); err != nil {
+	if err := c.stopLocked(); err != nil {
 		gologger.Error().Msgf("%s", i18n.Tf("case_destroy_failed", err.Error()))
 		return err
 	}
 	return nil
 }
 
-// Change 重建场景
+// Change 变更场景参数
+// IsRemove 时销毁后按新参数重建；否则对运行中的场景生成 plan，确认后原地应用变更
 func (c *Case) Change(cc ChangeCommand) error {
+	release, lockErr := c.acquireLock("change")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+
+	oldPars := append([]string(nil), c.Parameter...)
+	if !cc.Target.Empty() {
+		if cc.IsRemove {
+			return fmt.Errorf("%s", i18n.T("target_with_rebuild"))
+		}
+		if c.State != StateRunning {
+			return fmt.Errorf("%s", i18n.Tf("target_case_not_running", c.Name))
+		}
+	}
 	if cc.IsRemove {
 		// 销毁场景，不删除项目
 		gologger.Info().Msgf("%s", i18n.Tf("case_change_destroying", c.Name, c.Id))
-		if err := c.TfDestroy(); err != nil {
+		if err := c.TfDestroyLocked(); err != nil {
 			return err
 		}
+		c.Parameter = MergeParameters(c.Type, c.Parameter, cc.Pars)
+		// 重建场景
+		if err := c.tfApplyLocked(); err != nil {
+			return err
+		}
+		c.recordChange(ChangeTypeConfigUpdate, oldPars, c.Parameter, cc.Operator, i18n.T("case_change_rebuilt"))
+		return nil
 	}
-	// TODO 更改弹性公网IP等操作
-	// 重新赋值
-	if par, err := CaseScene(c.Type, cc.Pars); err != nil {
-		c.Parameter = par
+
+	// 未运行的场景没有云上资源，只更新参数，下次启动生效
+	if c.State != StateRunning {
+		c.Parameter = MergeParameters(c.Type, c.Parameter, cc.Pars)
+		if c.saveHandler != nil {
+			if err := c.saveHandler(); err != nil {
+				return err
+			}
+		}
+		c.recordChange(ChangeTypeConfigUpdate, oldPars, c.Parameter, cc.Operator, i18n.T("case_change_params_only"))
+		gologger.Info().Msgf("%s", i18n.Tf("case_change_params_saved", c.Name))
+		return nil
 	}
+
 	// 展示更改信息
-	if err := c.TfPlan(); err != nil {
+	plan, err := c.planChangeLocked(cc.Pars, cc.Target, RedcPlanPath)
+	if err != nil {
 		return err
 	}
-	// 重建场景
-	if err := c.TfApply(); err != nil {
-		return err
+	if !plan.HasChanges() {
+		gologger.Info().Msgf("%s", i18n.Tf("case_change_no_changes", c.Name))
+		c.Parameter = plan.NewParameter
+		if c.saveHandler != nil {
+			c.saveHandler()
+		}
+		return nil
 	}
-	return nil
+	if !cc.AutoApprove && (cc.Confirm == nil || !cc.Confirm(plan)) {
+		return fmt.Errorf("%s", i18n.T("case_change_cancelled"))
+	}
+	return c.applyChangeLocked(plan, cc.Operator)
 }
 
-func (c *Case) Status() error {
+// Status 打印场景状态和 output，reveal 为 false 时敏感 output 显示为 secret.Mask
+func (c *Case) Status(reveal bool) error {
 	gologger.Info().Msgf("%s", i18n.Tf("case_status_format", c.Name, c.State))
 	state, err := TfStatus(c.Path)
 	if err != nil {
@@ -617,7 +789,15 @@ func (c *Case) Status() error {
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 		for key, output := range state.Values.Outputs {
 			// output.Value 是 interface{}，可能是 string, map, list 等
-			fmt.Fprintf(w, "%s:\t%v\n", key, output.Value)
+			value := fmt.Sprintf("%v", output.Value)
+			if !reveal {
+				if IsSensitiveOutput(key, output.Sensitive) {
+					value = secret.Mask
+				} else {
+					value = secret.Redact(value)
+				}
+			}
+			fmt.Fprintf(w, "%s:\t%s\n", key, value)
 		}
 		w.Flush()
 	} else {
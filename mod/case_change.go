@@ -0,0 +1,381 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"red-cloud/pb"
+	"sort"
+	"strings"
+	"time"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+// RedcPreviewPlanPath 预览变更 (如 compose plan) 使用的临时 plan 文件，避免覆盖 case.tfplan
+const RedcPreviewPlanPath = "preview.tfplan"
+
+// 资源变更动作
+const (
+	ChangeActionCreate  = "create"
+	ChangeActionUpdate  = "update"
+	ChangeActionReplace = "replace"
+	ChangeActionDestroy = "destroy"
+)
+
+// ResourceChangeSummary 单个资源的变更摘要
+type ResourceChangeSummary struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Action  string `json:"action"`
+}
+
+// ChangePlan 场景参数变更计划
+type ChangePlan struct {
+	CaseID       string                  `json:"caseId"`
+	OldParameter []string                `json:"oldParameter"`
+	NewParameter []string                `json:"newParameter"`
+	Changes      []ResourceChangeSummary `json:"changes"`
+	ToCreate     int                     `json:"toCreate"`
+	ToUpdate     int                     `json:"toUpdate"`
+	ToReplace    int                     `json:"toReplace"`
+	ToDestroy    int                     `json:"toDestroy"`
+	Scope        *TargetSpec             `json:"scope,omitempty"`      // 仅针对部分资源的变更
+	PlanFile     string                  `json:"planFile,omitempty"`   // 审阅过的 plan 文件 (相对工作目录)，预览变更为空
+	PlanSHA256   string                  `json:"planSha256,omitempty"` // plan 文件的哈希，apply 前校验
+}
+
+// HasChanges 是否有资源需要变更
+func (p *ChangePlan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// Summary 返回 create/update/replace/destroy 计数摘要
+func (p *ChangePlan) Summary() string {
+	summary := fmt.Sprintf("create %d, update %d, replace %d, destroy %d", p.ToCreate, p.ToUpdate, p.ToReplace, p.ToDestroy)
+	if p.Scope != nil {
+		summary += " (" + p.Scope.String() + ")"
+	}
+	return summary
+}
+
+// MergeParameters 将新变量合并进已有参数，同名覆盖，保持原有顺序
+func MergeParameters(scene string, old []string, pars map[string]string) []string {
+	scene = filepath.Base(scene)
+	merged := make([]string, 0, len(old)+len(pars))
+	used := make(map[string]bool, len(pars))
+	for _, param := range old {
+		key, _, ok := strings.Cut(param, "=")
+		if ok {
+			if v, exists := pars[key]; exists {
+				merged = append(merged, fmt.Sprintf("%s=%s", key, normalizeSceneVar(scene, key, v)))
+				used[key] = true
+				continue
+			}
+		}
+		merged = append(merged, param)
+	}
+	keys := make([]string, 0, len(pars))
+	for k := range pars {
+		if !used[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		merged = append(merged, fmt.Sprintf("%s=%s", k, normalizeSceneVar(scene, k, pars[k])))
+	}
+	return merged
+}
+
+// summarizeResourceChanges 将 plan 中的资源变更归类为 create/update/replace/destroy
+func summarizeResourceChanges(plan *ChangePlan, changes []*tfjson.ResourceChange) {
+	plan.Changes = []ResourceChangeSummary{}
+	for _, rc := range changes {
+		if rc == nil || rc.Change == nil {
+			continue
+		}
+		var action string
+		switch {
+		case rc.Change.Actions.Replace():
+			action = ChangeActionReplace
+			plan.ToReplace++
+		case rc.Change.Actions.Create():
+			action = ChangeActionCreate
+			plan.ToCreate++
+		case rc.Change.Actions.Update():
+			action = ChangeActionUpdate
+			plan.ToUpdate++
+		case rc.Change.Actions.Delete():
+			action = ChangeActionDestroy
+			plan.ToDestroy++
+		default:
+			continue
+		}
+		plan.Changes = append(plan.Changes, ResourceChangeSummary{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Action:  action,
+		})
+	}
+}
+
+// PlanChange 合并新参数并生成 plan，返回资源级变更，不会修改任何资源
+func (c *Case) PlanChange(pars map[string]string) (*ChangePlan, error) {
+	return c.PlanTargetedChange(pars, TargetSpec{})
+}
+
+// PreviewChange 与 PlanChange 相同，但 plan 写入临时文件并在返回前删除，case.tfplan 保持不变。
+// 结果只用于展示，不能传给 ApplyChange
+func (c *Case) PreviewChange(pars map[string]string) (*ChangePlan, error) {
+	return c.planChange(pars, TargetSpec{}, RedcPreviewPlanPath)
+}
+
+// PlanTargetedChange 与 PlanChange 相同，但只针对 target 指定的资源 (-target / -replace / -destroy)，
+// 目标地址会先对照当前 state 校验
+func (c *Case) PlanTargetedChange(pars map[string]string, target TargetSpec) (*ChangePlan, error) {
+	return c.planChange(pars, target, RedcPlanPath)
+}
+
+func (c *Case) planChange(pars map[string]string, target TargetSpec, out string) (*ChangePlan, error) {
+	release, lockErr := c.acquireLock("plan-change")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer release()
+	return c.planChangeLocked(pars, target, out)
+}
+
+// planChangeLocked planChange 的实现，调用方须已持有场景锁
+func (c *Case) planChangeLocked(pars map[string]string, target TargetSpec, out string) (*ChangePlan, error) {
+	plan := &ChangePlan{
+		CaseID:       c.Id,
+		OldParameter: append([]string(nil), c.Parameter...),
+		NewParameter: ensureProviderParams(c.Type, c.Account, MergeParameters(c.Type, c.Parameter, pars)),
+	}
+	if !target.Empty() {
+		if err := c.ValidateTargets(target); err != nil {
+			return nil, err
+		}
+		plan.Scope = &target
+	}
+	if out != RedcPlanPath {
+		defer os.Remove(filepath.Join(c.Path, out))
+	}
+	if err := tfPlanTo(c.Path, out, plan.NewParameter, target.planOptions()...); err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("case_change_plan_failed", err))
+	}
+
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	te, err := NewTerraformExecutor(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	tfPlan, err := te.ShowPlanFile(ctx, out)
+	if err != nil {
+		return nil, err
+	}
+	summarizeResourceChanges(plan, tfPlan.ResourceChanges)
+	if out == RedcPlanPath {
+		if err := c.recordPlanFile(plan); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// recordPlanFile 记录刚生成的 case.tfplan 及其哈希，apply 时据此确认仍是审阅过的 plan
+func (c *Case) recordPlanFile(plan *ChangePlan) error {
+	sum, err := fileSHA256(filepath.Join(c.Path, RedcPlanPath))
+	if err != nil {
+		return err
+	}
+	plan.PlanFile, plan.PlanSHA256 = RedcPlanPath, sum
+	return nil
+}
+
+// verifyPlanFile 只允许应用审阅过的 plan 文件：文件缺失或已被其他命令覆盖时拒绝，而不是退回完整 apply
+func (c *Case) verifyPlanFile(plan *ChangePlan) error {
+	if plan.PlanFile == "" {
+		return fmt.Errorf("%s", i18n.Tf("case_change_plan_changed", c.Name))
+	}
+	if sum, err := fileSHA256(filepath.Join(c.Path, plan.PlanFile)); err != nil || sum != plan.PlanSHA256 {
+		return fmt.Errorf("%s", i18n.Tf("case_change_plan_changed", c.Name))
+	}
+	return nil
+}
+
+// ApplyChange 应用 PlanChange 生成的变更，并持久化参数、输出和变更历史
+func (c *Case) ApplyChange(plan *ChangePlan, operator string) error {
+	release, lockErr := c.acquireLock("apply-change")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+	return c.applyChangeLocked(plan, operator)
+}
+
+// applyChangeLocked ApplyChange 的实现，调用方须已持有场景锁
+func (c *Case) applyChangeLocked(plan *ChangePlan, operator string) error {
+	if err := c.verifyPlanFile(plan); err != nil {
+		return err
+	}
+	if _, err := CheckPlanPolicy(c.Path, plan.PlanFile, c.Name); err != nil {
+		return err
+	}
+	gologger.Info().Msgf("%s", i18n.Tf("case_change_applying", c.Name, plan.Summary()))
+	c.runPluginHook("pre-apply")
+	// state 已变化时 terraform 会拒绝过期的 plan
+	if err := TfApplyPlanFile(c.Path, plan.PlanFile); err != nil {
+		return err
+	}
+
+	c.Parameter = plan.NewParameter
+	if _, err := c.TfOutput(); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("case_get_output_failed", err))
+	}
+	c.StateTime = time.Now().Format(time.RFC3339)
+	if c.saveHandler != nil {
+		if err := c.saveHandler(); err != nil {
+			gologger.Error().Msgf("%s", i18n.Tf("case_save_state_failed", err))
+		}
+	}
+	c.runPluginHook("post-apply")
+
+	c.recordChange(ChangeTypeConfigUpdate, plan.OldParameter, plan.NewParameter, operator, plan.Summary())
+	return nil
+}
+
+// recordChange 记录参数变更历史，失败只打印日志
+func (c *Case) recordChange(changeType string, oldPars, newPars []string, operator, description string) {
+	if operator == "" {
+		operator = c.Operator
+	}
+	h := &CaseChangeHistory{
+		ID:          GenerateCaseID(),
+		CaseID:      c.Id,
+		ChangeType:  changeType,
+		OldValue:    parametersToMap(oldPars),
+		NewValue:    parametersToMap(newPars),
+		Operator:    operator,
+		Timestamp:   time.Now(),
+		Description: description,
+		ProjectID:   c.ProjectID,
+	}
+	if err := h.DBSave(); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("case_change_history_failed", err))
+	}
+}
+
+// parametersToMap 将 key=value 参数列表转换为 map
+func parametersToMap(pars []string) map[string]interface{} {
+	m := make(map[string]interface{}, len(pars))
+	for _, p := range pars {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// CaseChangeHistory 场景变更历史记录
+type CaseChangeHistory struct {
+	ID          string                 `json:"id"`
+	CaseID      string                 `json:"case_id"`
+	ChangeType  string                 `json:"change_type"`
+	OldValue    map[string]interface{} `json:"old_value,omitempty"`
+	NewValue    map[string]interface{} `json:"new_value,omitempty"`
+	Operator    string                 `json:"operator,omitempty"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Description string                 `json:"description,omitempty"`
+	ProjectID   string                 `json:"-"`
+}
+
+// toProto 复用 DeploymentChangeHistory 消息结构，DeploymentId 存放 CaseID
+func (h *CaseChangeHistory) toProto() (*pb.DeploymentChangeHistory, error) {
+	oldValueBytes, err := json.Marshal(h.OldValue)
+	if err != nil {
+		return nil, fmt.Errorf("序列化旧值失败: %w", err)
+	}
+	newValueBytes, err := json.Marshal(h.NewValue)
+	if err != nil {
+		return nil, fmt.Errorf("序列化新值失败: %w", err)
+	}
+	return &pb.DeploymentChangeHistory{
+		Id:           h.ID,
+		DeploymentId: h.CaseID,
+		ChangeType:   h.ChangeType,
+		OldValueJson: string(oldValueBytes),
+		NewValueJson: string(newValueBytes),
+		Operator:     h.Operator,
+		Timestamp:    h.Timestamp.Format(time.RFC3339),
+		Description:  h.Description,
+		ProjectId:    h.ProjectID,
+	}, nil
+}
+
+// DBSave 将场景变更历史保存到数据库
+func (h *CaseChangeHistory) DBSave() error {
+	if h.ProjectID == "" {
+		return fmt.Errorf("严重错误: CaseChangeHistory %s 丢失了 ProjectID，无法保存", h.ID)
+	}
+	return dbExec(func(tx *bolt.Tx) error {
+		// 每个项目有独立的 Bucket，例如 "CaseHistory_default"
+		b, err := tx.CreateBucketIfNotExists([]byte(fmt.Sprintf("CaseHistory_%s", h.ProjectID)))
+		if err != nil {
+			return fmt.Errorf("创建 bucket 失败: %w", err)
+		}
+		pbData, err := h.toProto()
+		if err != nil {
+			return err
+		}
+		data, err := proto.Marshal(pbData)
+		if err != nil {
+			return fmt.Errorf("序列化失败: %w", err)
+		}
+		return b.Put([]byte(h.ID), data)
+	})
+}
+
+// LoadCaseHistory 加载指定场景的变更历史，最新的在前
+func LoadCaseHistory(projectName, caseID string) ([]*CaseChangeHistory, error) {
+	var history []*CaseChangeHistory
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(fmt.Sprintf("CaseHistory_%s", projectName)))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var p pb.DeploymentChangeHistory
+			if err := proto.Unmarshal(v, &p); err != nil || p.DeploymentId != caseID {
+				return nil
+			}
+			dh, err := deploymentChangeHistoryFromProto(&p)
+			if err != nil {
+				return nil
+			}
+			history = append(history, &CaseChangeHistory{
+				ID:          dh.ID,
+				CaseID:      dh.DeploymentID,
+				ChangeType:  dh.ChangeType,
+				OldValue:    dh.OldValue,
+				NewValue:    dh.NewValue,
+				Operator:    dh.Operator,
+				Timestamp:   dh.Timestamp,
+				Description: dh.Description,
+				ProjectID:   dh.ProjectID,
+			})
+			return nil
+		})
+	})
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Timestamp.After(history[j].Timestamp)
+	})
+	return history, err
+}
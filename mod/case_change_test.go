@@ -0,0 +1,121 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestMergeParameters(t *testing.T) {
+	old := []string{"node=1", "region=cn-hangzhou", "instance_password=abc"}
+
+	got := MergeParameters("aliyun/ecs", old, map[string]string{"node": "3", "zone": "b", "bandwidth": "10"})
+	want := []string{"node=3", "region=cn-hangzhou", "instance_password=abc", "bandwidth=10", "zone=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeParameters() = %v, want %v", got, want)
+	}
+
+	// dnslog 场景的 domain 需要补全 a. 前缀
+	got = MergeParameters("dnslog", []string{"domain=a.old.com"}, map[string]string{"domain": "new.com"})
+	if !reflect.DeepEqual(got, []string{"domain=a.new.com"}) {
+		t.Errorf("dnslog domain not normalized: %v", got)
+	}
+
+	if got := MergeParameters("ecs", old, nil); !reflect.DeepEqual(got, old) {
+		t.Errorf("nil vars should keep parameters, got %v", got)
+	}
+}
+
+func TestSummarizeResourceChanges(t *testing.T) {
+	plan := &ChangePlan{}
+	summarizeResourceChanges(plan, []*tfjson.ResourceChange{
+		{Address: "a.new", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate}}},
+		{Address: "a.upd", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}}},
+		{Address: "a.rep", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}}},
+		{Address: "a.del", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete}}},
+		{Address: "a.noop", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}}},
+	})
+	if plan.ToCreate != 1 || plan.ToUpdate != 1 || plan.ToReplace != 1 || plan.ToDestroy != 1 {
+		t.Errorf("unexpected counts: %s", plan.Summary())
+	}
+	if len(plan.Changes) != 4 || plan.Changes[2].Action != ChangeActionReplace {
+		t.Errorf("unexpected changes: %+v", plan.Changes)
+	}
+}
+
+// 预览变更写入临时 plan 文件并在结束后删除，case.tfplan 保持不变
+func TestPreviewChangeKeepsCasePlan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform is a shell script")
+	}
+	RedcPath = t.TempDir()
+	// 假的 terraform: plan 写入 -out 指定的文件后失败
+	bin := t.TempDir()
+	script := "#!/bin/sh\nfor a in \"$@\"; do case \"$a\" in -out=*) echo fresh > \"${a#-out=}\";; esac; done\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, RedcPlanPath), []byte("reviewed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := &Case{Id: "preview", Name: "preview", ProjectID: "default", State: StateRunning, Path: dir}
+	if _, err := c.PreviewChange(map[string]string{"node": "2"}); err == nil {
+		t.Fatal("failing plan should return an error")
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, RedcPlanPath)); string(b) != "reviewed" {
+		t.Errorf("case.tfplan should be untouched, got %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, RedcPreviewPlanPath)); !os.IsNotExist(err) {
+		t.Errorf("preview plan should be removed, stat err = %v", err)
+	}
+}
+
+func TestApplyChangeRejectsReplacedPlan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform is a shell script")
+	}
+	RedcPath = t.TempDir()
+	// 假的 terraform: 被调用就留下标记
+	bin := t.TempDir()
+	marker := filepath.Join(bin, "called")
+	script := "#!/bin/sh\ntouch " + marker + "\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, RedcPlanPath)
+	if err := os.WriteFile(planPath, []byte("reviewed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := fileSHA256(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Case{Id: "replaced", Name: "replaced", ProjectID: "default", State: StateRunning, Path: dir}
+	plan := &ChangePlan{CaseID: c.Id, PlanFile: RedcPlanPath, PlanSHA256: sum}
+
+	// 其他命令覆盖了 case.tfplan
+	if err := os.WriteFile(planPath, []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ApplyChange(plan, "tester"); err == nil {
+		t.Error("replaced plan file should be rejected")
+	}
+	os.Remove(planPath)
+	if err := c.ApplyChange(plan, "tester"); err == nil {
+		t.Error("missing plan file should be rejected")
+	}
+	if err := c.ApplyChange(&ChangePlan{CaseID: c.Id}, "tester"); err == nil {
+		t.Error("plan without a plan file should be rejected")
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("terraform should not run for a rejected plan")
+	}
+}
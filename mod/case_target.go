@@ -0,0 +1,139 @@
+package mod
+
+import (
+	"fmt"
+	"red-cloud/i18n"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// TargetSpec 针对单个资源的变更范围 (terraform -target / -replace)
+type TargetSpec struct {
+	Targets []string `json:"targets,omitempty"` // 只变更这些资源 (可为不带索引的资源或 module 地址)
+	Replace []string `json:"replace,omitempty"` // 强制重建这些资源实例
+	Destroy bool     `json:"destroy,omitempty"` // 销毁 Targets 指定的资源，而不是应用
+}
+
+// Empty 未指定任何目标资源，即整体变更
+func (t TargetSpec) Empty() bool {
+	return len(t.Targets) == 0 && len(t.Replace) == 0 && !t.Destroy
+}
+
+// Validate 检查参数组合是否合法
+func (t TargetSpec) Validate() error {
+	if t.Destroy && len(t.Targets) == 0 {
+		return fmt.Errorf("%s", i18n.T("target_destroy_requires_target"))
+	}
+	if t.Destroy && len(t.Replace) > 0 {
+		return fmt.Errorf("%s", i18n.T("target_destroy_with_replace"))
+	}
+	return nil
+}
+
+// String 用于日志和变更历史
+func (t TargetSpec) String() string {
+	var parts []string
+	if len(t.Targets) > 0 {
+		verb := "target"
+		if t.Destroy {
+			verb = "destroy"
+		}
+		parts = append(parts, verb+" "+strings.Join(t.Targets, ","))
+	}
+	if len(t.Replace) > 0 {
+		parts = append(parts, "replace "+strings.Join(t.Replace, ","))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// planOptions 转换为 terraform plan 参数
+func (t TargetSpec) planOptions() []tfexec.PlanOption {
+	var opts []tfexec.PlanOption
+	for _, addr := range t.Targets {
+		opts = append(opts, tfexec.Target(addr))
+	}
+	for _, addr := range t.Replace {
+		opts = append(opts, tfexec.Replace(addr))
+	}
+	if t.Destroy {
+		opts = append(opts, tfexec.Destroy(true))
+	}
+	return opts
+}
+
+// collectStateAddresses 递归收集 state 中所有资源实例地址
+func collectStateAddresses(m *tfjson.StateModule, out []string) []string {
+	if m == nil {
+		return out
+	}
+	for _, r := range m.Resources {
+		out = append(out, r.Address)
+	}
+	for _, child := range m.ChildModules {
+		out = collectStateAddresses(child, out)
+	}
+	return out
+}
+
+// StateAddresses 返回场景当前 state 中的资源实例地址，已排序
+func (c *Case) StateAddresses() ([]string, error) {
+	state, err := TfStatus(c.Path)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	if state.Values != nil {
+		addrs = collectStateAddresses(state.Values.RootModule, nil)
+	}
+	sort.Strings(addrs)
+	return addrs, nil
+}
+
+// matchTargetAddress target 可以是实例地址、不带索引的资源地址或 module 地址；replace 必须精确到实例
+func matchTargetAddress(addr string, stateAddrs []string, exact bool) bool {
+	for _, a := range stateAddrs {
+		if a == addr {
+			return true
+		}
+		if !exact && (strings.HasPrefix(a, addr+"[") || strings.HasPrefix(a, addr+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTargets 对照当前 state 校验目标地址
+func validateTargets(t TargetSpec, stateAddrs []string) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	check := func(addr string, exact bool) error {
+		if !matchTargetAddress(addr, stateAddrs, exact) {
+			return fmt.Errorf("%s", i18n.Tf("target_not_in_state", addr, strings.Join(stateAddrs, ", ")))
+		}
+		return nil
+	}
+	for _, addr := range t.Targets {
+		if err := check(addr, false); err != nil {
+			return err
+		}
+	}
+	for _, addr := range t.Replace {
+		if err := check(addr, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTargets 对照场景当前 state 校验 -target / -replace 地址
+func (c *Case) ValidateTargets(t TargetSpec) error {
+	stateAddrs, err := c.StateAddresses()
+	if err != nil {
+		return err
+	}
+	return validateTargets(t, stateAddrs)
+}
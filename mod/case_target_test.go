@@ -0,0 +1,49 @@
+package mod
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTargets(t *testing.T) {
+	state := []string{
+		"alicloud_instance.node[0]",
+		"alicloud_instance.node[1]",
+		"alicloud_instance.redirector",
+		"module.dns.alicloud_alidns_record.a",
+	}
+	ok := []TargetSpec{
+		{Targets: []string{"alicloud_instance.node[1]"}, Destroy: true},
+		{Targets: []string{"alicloud_instance.node"}},
+		{Targets: []string{"module.dns"}},
+		{Replace: []string{"alicloud_instance.redirector"}},
+	}
+	for _, spec := range ok {
+		if err := validateTargets(spec, state); err != nil {
+			t.Errorf("%s: unexpected error %v", spec.String(), err)
+		}
+	}
+
+	bad := []TargetSpec{
+		{Targets: []string{"alicloud_instance.node[2]"}},
+		{Targets: []string{"alicloud_instance.nod"}},
+		{Replace: []string{"alicloud_instance.node"}}, // replace 必须精确到实例
+		{Destroy: true},
+		{Targets: []string{"alicloud_instance.node[0]"}, Replace: []string{"alicloud_instance.redirector"}, Destroy: true},
+	}
+	for _, spec := range bad {
+		if err := validateTargets(spec, state); err == nil {
+			t.Errorf("%+v: expected error", spec)
+		}
+	}
+}
+
+func TestChangePlanSummaryScope(t *testing.T) {
+	p := &ChangePlan{ToDestroy: 1, Scope: &TargetSpec{Targets: []string{"aws_instance.node[2]"}, Destroy: true}}
+	if got := p.Summary(); !strings.HasSuffix(got, "(destroy aws_instance.node[2])") {
+		t.Errorf("unexpected summary %q", got)
+	}
+	if (TargetSpec{Destroy: true}).Empty() {
+		t.Error("destroy-only spec must not be treated as a whole-case change")
+	}
+}
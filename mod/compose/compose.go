@@ -4,14 +4,18 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"red-cloud/i18n"
+	"red-cloud/mod"
 	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
 	"red-cloud/utils/sshutil"
 
 	"github.com/hashicorp/terraform-exec/tfexec"
@@ -43,59 +47,95 @@ func RunComposeUpWithResult(opts ComposeOptions) (*ComposeUpResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	if rec, err := mod.GetComposeStack(ctx.Project.ProjectName, ctx.StackName); err == nil {
+		ctx.alignWithStack(rec)
+	}
+	var deps []*RuntimeService
+	if len(opts.Services) > 0 {
+		if deps, err = ctx.selectServices(opts.Services, false); err != nil {
+			return nil, err
+		}
+	}
 	if err := VerifyTemplates(ctx); err != nil {
 		return nil, err
 	}
+	if err := ctx.loadSecrets(); err != nil {
+		return nil, err
+	}
+	ctx.adoptRunning(deps)
 
-	ctx.emitLog(i18n.Tf("compose_deploy_total", len(ctx.RuntimeSvcs)))
-
-	// 2. 编排循环
-	pendingCount := len(ctx.RuntimeSvcs)
+	// 2. 按依赖关系并发部署，依赖已部署且健康检查通过的服务同时启动
+	var pending []*RuntimeService
+	for _, name := range ctx.SortedSvcKeys {
+		if svc := ctx.RuntimeSvcs[name]; !svc.IsDeployed {
+			pending = append(pending, svc)
+		}
+	}
+	total := len(pending)
 	deployed := 0
-	for pendingCount > 0 {
-		deployedInThisLoop := 0
-
-		// 使用排序后的 Keys 遍历
-		for _, name := range ctx.SortedSvcKeys {
-			svc := ctx.RuntimeSvcs[name]
-
-			if svc.IsDeployed {
-				continue
-			}
-
-			if canDeploy(svc, ctx.RuntimeSvcs) {
-				msg := i18n.Tf("compose_deploy_service", svc.Name, svc.Spec.Image)
-				gologger.Info().Msgf("%s", msg)
-				ctx.emitLog(msg)
-
-				if err := processServiceUp(svc, ctx); err != nil {
-					return nil, fmt.Errorf("部署服务 [%s] 失败: %v", svc.Name, err)
-				}
-
-				svc.IsDeployed = true
-				deployedInThisLoop++
-				deployed++
-				pendingCount--
-				ctx.emitLog(i18n.Tf("compose_deploy_progress", deployed, deployed+pendingCount))
+	failed := make(map[string]bool)
+	ctx.emitLog(i18n.Tf("compose_deploy_total", total))
+	runErr := ctx.runDAG(pending,
+		func(svc *RuntimeService) bool { return canDeploy(svc, ctx.RuntimeSvcs) },
+		ctx.deployService,
+		func(svc *RuntimeService, err error) {
+			if err != nil {
+				failed[svc.Name] = true
+				gologger.Error().Msgf("%v", err)
+				ctx.emitLog(err.Error())
+				return
 			}
+			deployed++
+			ctx.emitLog(i18n.Tf("compose_deploy_progress", deployed, total))
+		},
+		ctx.logSkipped,
+	)
+	if runErr != nil {
+		if errors.Is(runErr, errDeadlock) {
+			runErr = fmt.Errorf("编排死锁: 存在循环依赖，或依赖的服务被 Profile 过滤未启动")
 		}
-
-		if deployedInThisLoop == 0 && pendingCount > 0 {
-			return nil, fmt.Errorf("编排死锁: 存在循环依赖，或依赖的服务被 Profile 过滤未启动")
-		}
+		runErr = ctx.handleUpFailure(runErr)
+		// 部分服务已部署时同样记录，便于之后按编排记录销毁
+		ctx.recordStack(failed)
+		return composeUpResult(ctx, failed), runErr
 	}
 
-	// 3. 执行 Setup
-	if len(ctx.ConfigRaw.Setup) > 0 {
+	// 3. 执行 Setup，只部署部分服务时仅执行与这些服务相关的任务
+	tasks := ctx.ConfigRaw.Setup
+	if ctx.targets != nil {
+		tasks = tasksReferencing(tasks, ctx.targets)
+	}
+	if len(tasks) > 0 {
 		msg := i18n.T("compose_setup_start")
 		gologger.Info().Msg(msg)
 		ctx.emitLog(msg)
-		if err := runSetupTasks(ctx.ConfigRaw.Setup, ctx.RuntimeSvcs, ctx); err != nil {
-			return nil, err
+		if err := runSetupTasks(tasks, ctx.RuntimeSvcs, ctx); err != nil {
+			err = ctx.handleUpFailure(err)
+			ctx.recordStack(failed)
+			return composeUpResult(ctx, failed), err
 		}
 	}
+	ctx.recordStack(failed)
+
+	return composeUpResult(ctx, failed), nil
+}
 
-	// 4. 收集部署结果
+// deployService 部署单个服务并等待健康检查，通过前依赖该服务的服务不会部署
+func (ctx *ComposeContext) deployService(svc *RuntimeService) error {
+	msg := i18n.Tf("compose_deploy_service", svc.Name, svc.Spec.Image)
+	gologger.Info().Msgf("%s", msg)
+	ctx.emitLog(msg)
+	if err := processServiceUp(svc, ctx); err != nil {
+		return fmt.Errorf("部署服务 [%s] 失败: %v", svc.Name, err)
+	}
+	ctx.mu.Lock()
+	svc.IsDeployed = true
+	ctx.mu.Unlock()
+	return waitHealthy(svc, ctx)
+}
+
+// composeUpResult 收集部署结果，未部署的服务标记为 failed 或 skipped
+func composeUpResult(ctx *ComposeContext, failed map[string]bool) *ComposeUpResult {
 	result := &ComposeUpResult{}
 	for _, name := range ctx.SortedSvcKeys {
 		svc := ctx.RuntimeSvcs[name]
@@ -104,81 +144,193 @@ func RunComposeUpWithResult(opts ComposeOptions) (*ComposeUpResult, error) {
 			Template: svc.Spec.Image,
 			Status:   "deployed",
 		}
+		switch {
+		case svc.RolledBack:
+			s.Status = "rolled_back"
+		case failed[name]:
+			s.Status = "failed"
+		case !svc.IsDeployed:
+			s.Status = "skipped"
+		}
 		if svc.CaseRef != nil {
 			s.CaseID = svc.CaseRef.Id
 		}
 		result.Services = append(result.Services, s)
 	}
-
-	return result, nil
+	return result
 }
 
-// RunComposeDown 销毁入口
+// RunComposeDown 销毁入口：存在编排记录时按记录销毁，编排文件移动或修改后仍可销毁；
+// 没有记录时 (旧版本部署) 按编排文件推导场景名称
 func RunComposeDown(opts ComposeOptions) error {
+	rec, err := mod.GetComposeStack(opts.Project.ProjectName, StackNameForFile(opts))
+	if err == nil {
+		return runStackDown(rec, opts)
+	}
+	if opts.Stack != "" {
+		return err
+	}
+
 	ctx, err := NewComposeContext(opts)
 	if err != nil {
 		return err
 	}
+	if len(opts.Services) > 0 {
+		if _, err := ctx.selectServices(opts.Services, true); err != nil {
+			return err
+		}
+	}
 
 	// 状态回填
-	pendingCount := 0
 	for _, name := range ctx.SortedSvcKeys {
 		svc := ctx.RuntimeSvcs[name]
-		c, err := ctx.Project.GetCase(svc.Name)
+		c, err := ctx.getCase(svc.Name)
 		if err != nil {
 			svc.IsDeployed = false
 			continue
 		}
 		svc.CaseRef = c
 		svc.IsDeployed = true
-		pendingCount++
 
 		if rawOut, err := c.TfOutput(); err == nil {
 			svc.Outputs = parseTfOutput(rawOut)
 		}
 	}
+	return ctx.destroyServices(false)
+}
 
+// destroyServices 销毁上下文中所有已部署的服务，rollback 时每一步写入时间线，销毁后删除场景
+func (ctx *ComposeContext) destroyServices(rollback bool) error {
+	pendingCount := 0
+	for _, svc := range ctx.RuntimeSvcs {
+		if svc.IsDeployed {
+			pendingCount++
+		}
+	}
 	ctx.emitLog(i18n.Tf("compose_destroy_total", pendingCount))
 	destroyed := 0
 
-	// 逆序销毁
-	for pendingCount > 0 {
-		destroyedInThisLoop := 0
-		// 倒序遍历建议
-		for i := len(ctx.SortedSvcKeys) - 1; i >= 0; i-- {
-			svc := ctx.RuntimeSvcs[ctx.SortedSvcKeys[i]]
-
-			if !svc.IsDeployed {
-				continue
+	// 逆序并发销毁：没有已部署服务依赖的服务同时销毁。
+	// 销毁失败的服务保持已部署状态，其依赖的服务不会被销毁
+	var pending []*RuntimeService
+	for i := len(ctx.SortedSvcKeys) - 1; i >= 0; i-- {
+		if svc := ctx.RuntimeSvcs[ctx.SortedSvcKeys[i]]; svc.IsDeployed {
+			pending = append(pending, svc)
 		}
-
-		if canDestroy(svc, ctx.RuntimeSvcs) {
+	}
+	err := ctx.runDAG(pending,
+		func(svc *RuntimeService) bool { return canDestroy(svc, ctx.RuntimeSvcs) },
+		func(svc *RuntimeService) error {
 			msg := i18n.Tf("compose_destroy_service", svc.Name)
+			if rollback {
+				msg = i18n.Tf("compose_rollback_service", svc.Name)
+			}
 			gologger.Info().Msgf("%s", msg)
 			ctx.emitLog(msg)
-			if err := svc.CaseRef.TfDestroy(); err != nil {
-				errMsg := i18n.Tf("compose_destroy_failed", svc.Name, err)
-				gologger.Error().Msgf("%s", errMsg)
-				ctx.emitLog(errMsg)
+			// 从未 apply 的场景 (如 apply 被策略拒绝) 没有云上资源，回滚时直接删除
+			if !rollback || !neverApplied(svc.CaseRef) {
+				if err := svc.CaseRef.TfDestroy(); err != nil {
+					err = fmt.Errorf("%s", i18n.Tf("compose_destroy_failed", svc.Name, err))
+					if rollback {
+						ctx.logTimeline("compose_rollback_service_failed", svc, err.Error(), "error")
+					}
+					return err
+				}
 			}
-
+			if rollback {
+				if err := svc.CaseRef.Remove(); err != nil {
+					gologger.Warning().Msgf("%s", i18n.Tf("compose_rollback_remove_failed", svc.Name, err))
+				}
+				done := i18n.Tf("compose_rollback_service_done", svc.Name)
+				gologger.Info().Msgf("%s", done)
+				ctx.emitLog(done)
+				ctx.logTimeline("compose_rollback_service", svc, done, "success")
+			}
+			ctx.mu.Lock()
 			svc.IsDeployed = false
-			destroyedInThisLoop++
+			svc.RolledBack = rollback
+			if rollback {
+				ctx.removed = append(ctx.removed, svc.Name)
+			}
+			ctx.mu.Unlock()
+			return nil
+		},
+		func(svc *RuntimeService, err error) {
+			if err != nil {
+				gologger.Error().Msgf("%v", err)
+				ctx.emitLog(err.Error())
+				return
+			}
 			destroyed++
-			pendingCount--
-			ctx.emitLog(i18n.Tf("compose_destroy_progress", destroyed, destroyed+pendingCount))
-		}
+			ctx.emitLog(i18n.Tf("compose_destroy_progress", destroyed, pendingCount))
+		},
+		ctx.logSkipped,
+	)
+	if errors.Is(err, errDeadlock) {
+		return fmt.Errorf("销毁死锁: 存在循环依赖")
+	}
+	return err
+}
+
+// processServiceUp 单个服务部署逻辑
+func processServiceUp(svc *RuntimeService, ctx *ComposeContext) error {
+	tfVars, err := serviceTfVars(svc, ctx)
+	if err != nil {
+		return err
 	}
 
-		if destroyedInThisLoop == 0 && pendingCount > 0 {
-			return fmt.Errorf("销毁死锁: 存在循环依赖")
+	// TF Apply
+	ctx.emitLog(fmt.Sprintf("[%s] Terraform Apply...", svc.Name))
+	p := ctx.Project
+	var change *mod.ChangePlan
+	user := p.User
+	if ctx.Actor != "" {
+		user = ctx.Actor
+	}
+	c, err := ctx.getCase(svc.Name)
+	if err != nil {
+		c, err = p.CaseCreate(svc.Spec.Image, user, svc.Name, tfVars)
+		if err != nil {
+			return fmt.Errorf("CaseCreate fail: %v", err)
+		}
+		c.SetActor(ctx.Actor, ctx.Source)
+		svc.Created = true
+	} else if len(paramChanges(c, tfVars)) > 0 {
+		// environment/configs 修改后按变更计划重新 apply，参数变更写入场景的变更历史
+		if change, err = c.PlanChange(caseVars(tfVars)); err != nil {
+			return err
 		}
 	}
-	return nil
+	// apply 失败时也记录场景，编排记录和回滚据此销毁已创建的资源
+	ctx.mu.Lock()
+	svc.CaseRef = c
+	ctx.mu.Unlock()
+	// 策略在 apply 前针对刷新后的 plan 检查，deny 时不启动该服务
+	if change != nil {
+		ctx.emitLog(i18n.Tf("compose_reapply_service", svc.Name, change.Summary()))
+		err = c.ApplyChange(change, user)
+	} else {
+		err = c.TfApply()
+	}
+	if err != nil {
+		return fmt.Errorf("Terraform Apply fail: %v", err)
+	}
+	ctx.emitLog(fmt.Sprintf("[%s] Terraform Apply 完成", svc.Name))
+
+	// Output Cache
+	rawOut, err := c.TfOutput()
+	ctx.mu.Lock()
+	if err == nil {
+		svc.Outputs = parseTfOutput(rawOut)
+	}
+	ctx.mu.Unlock()
+
+	// SSH Actions
+	return runSSHActions(svc, ctx)
 }
 
-// processServiceUp 单个服务部署逻辑
-func processServiceUp(svc *RuntimeService, ctx *ComposeContext) error {
+// serviceTfVars 由 configs、environment、provider 别名和账号生成服务的 Terraform 变量
+func serviceTfVars(svc *RuntimeService, ctx *ComposeContext) (map[string]string, error) {
 	tfVars := make(map[string]string)
 
 	// Configs
@@ -199,43 +351,39 @@ func processServiceUp(svc *RuntimeService, ctx *ComposeContext) error {
 		parts := strings.SplitN(envStr, "=", 2)
 		if len(parts) == 2 {
 			key, rawVal := parts[0], parts[1]
+			rawVal, err := ctx.expandSecrets(rawVal)
+			if err != nil {
+				return nil, err
+			}
+			ctx.mu.Lock()
 			vals, err := expandVariable(rawVal, ctx.RuntimeSvcs, svc)
+			ctx.mu.Unlock()
 			if err != nil {
-				return fmt.Errorf("Environment parse error: %v", err)
+				return nil, fmt.Errorf("Environment parse error: %v", err)
 			}
 			tfVars[key] = strings.Join(vals, ",")
 		}
 	}
 
+	// Secrets
+	for _, ref := range svc.Spec.Secrets {
+		tfName, name := secretMapping(ref)
+		val, ok := ctx.secrets[name]
+		if !ok {
+			return nil, fmt.Errorf("%s", i18n.Tf("compose_secret_not_loaded", name))
+		}
+		tfVars[tfName] = val
+	}
+
 	// Provider Alias
 	if pStr, ok := svc.Spec.Provider.(string); ok && pStr != "" && pStr != "default" {
 		tfVars["provider_alias"] = pStr
 	}
 
-	// TF Apply
-	ctx.emitLog(fmt.Sprintf("[%s] Terraform Apply...", svc.Name))
-	p := ctx.Project
-	c, err := p.GetCase(svc.Name)
-	if err != nil {
-		c, err = p.CaseCreate(svc.Spec.Image, p.User, svc.Name, tfVars)
-		if err != nil {
-			return fmt.Errorf("CaseCreate fail: %v", err)
-		}
-	}
-	if err := c.TfApply(); err != nil {
-		return fmt.Errorf("Terraform Apply fail: %v", err)
+	if svc.Spec.Account != "" {
+		tfVars[mod.AccountVar] = svc.Spec.Account
 	}
-	ctx.emitLog(fmt.Sprintf("[%s] Terraform Apply 完成", svc.Name))
-	svc.CaseRef = c
-
-	// Output Cache
-	rawOut, err := c.TfOutput()
-	if err == nil {
-		svc.Outputs = parseTfOutput(rawOut)
-	}
-
-	// SSH Actions
-	return runSSHActions(svc, ctx)
+	return tfVars, nil
 }
 
 func runSSHActions(svc *RuntimeService, ctx *ComposeContext) error {
@@ -273,6 +421,15 @@ func runSSHActions(svc *RuntimeService, ctx *ComposeContext) error {
 
 	// Volumes
 	for _, vol := range svc.Spec.Volumes {
+		if name, remotePath, ok := secretVolume(vol); ok {
+			msg := fmt.Sprintf("[%s] Writing secret %s -> %s", svc.Name, name, remotePath)
+			gologger.Info().Msg(msg)
+			ctx.emitLog(msg)
+			if err := client.WritePrivateFile(remotePath, []byte(ctx.secrets[name])); err != nil {
+				gologger.Error().Msgf("[%s] Upload failed: %v", svc.Name, err)
+			}
+			continue
+		}
 		parts := strings.Split(vol, ":")
 		if len(parts) == 2 {
 			localPath, remotePath := parts[0], parts[1]
@@ -290,7 +447,11 @@ func runSSHActions(svc *RuntimeService, ctx *ComposeContext) error {
 		msg := fmt.Sprintf("[%s] Running init command...", svc.Name)
 		gologger.Info().Msg(msg)
 		ctx.emitLog(msg)
-		if err := client.RunCommandWithLogger(svc.Spec.Command, writer); err != nil {
+		command, err := ctx.expandSecrets(svc.Spec.Command)
+		if err != nil {
+			return err
+		}
+		if err := client.RunCommandWithLogger(command, writer); err != nil {
 			gologger.Error().Msgf("[%s] Command failed: %v", svc.Name, err)
 		}
 	}
@@ -322,7 +483,7 @@ func (w *callbackWriter) Write(p []byte) (n int, err error) {
 	for scanner.Scan() {
 		text := scanner.Text()
 		if text != "" {
-			w.cb(fmt.Sprintf("[%s] %s", w.prefix, text))
+			w.cb(secret.Redact(fmt.Sprintf("[%s] %s", w.prefix, text)))
 		}
 	}
 	return len(p), nil
@@ -330,6 +491,12 @@ func (w *callbackWriter) Write(p []byte) (n int, err error) {
 
 func runSetupTasks(tasks []SetupTask, svcs map[string]*RuntimeService, ctx *ComposeContext) error {
 	gologger.Debug().Msgf("Running Setup Tasks %d...", len(tasks))
+	// 失败的任务记录后继续执行其余任务，最后汇总返回，由 on_failure 策略处理
+	var errs []error
+	fail := func(task SetupTask, svc *RuntimeService, err error) {
+		ctx.recordSetup(task, svc, err)
+		errs = append(errs, fmt.Errorf("%s", i18n.Tf("compose_setup_failed", task.Name, svc.Name, err)))
+	}
 	for _, task := range tasks {
 		// 1. 查找目标实例 (支持裂变/多实例)
 		var targets []*RuntimeService
@@ -347,15 +514,24 @@ func runSetupTasks(tasks []SetupTask, svcs map[string]*RuntimeService, ctx *Comp
 		ctx.emitLog(msg)
 		// 2. 遍历所有匹配的实例并执行命令
 		for _, targetSvc := range targets {
-			cmds, err := expandVariable(task.Command, svcs, targetSvc)
+			task.Outputs = ""
+			command, err := ctx.expandSecrets(task.Command)
+			if err != nil {
+				gologger.Error().Msgf("Setup task [%s] var error: %v", task.Name, err)
+				fail(task, targetSvc, err)
+				continue
+			}
+			cmds, err := expandVariable(command, svcs, targetSvc)
 			if err != nil {
 				gologger.Error().Msgf("Setup task [%s] var error: %v", task.Name, err)
+				fail(task, targetSvc, err)
 				continue
 			}
 
 			sshConf, err := targetSvc.CaseRef.GetSSHConfig()
 			if err != nil {
 				gologger.Error().Msgf("Setup task [%s] SSH config error: %v", task.Name, err)
+				fail(task, targetSvc, err)
 				continue
 			}
 
@@ -408,12 +584,22 @@ func runSetupTasks(tasks []SetupTask, svcs map[string]*RuntimeService, ctx *Comp
 				return nil
 			}()
 			if err != nil {
-				// 停止执行后续任务
-				//return err
+				fail(task, targetSvc, err)
+				continue
 			}
+			ctx.recordSetup(task, targetSvc, nil)
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// recordSetup 记录 setup 任务在实例上的执行结果，输出经过脱敏后写入编排记录
+func (ctx *ComposeContext) recordSetup(task SetupTask, svc *RuntimeService, err error) {
+	r := mod.ComposeSetupResult{Task: task.Name, Service: svc.Name, Output: secret.Redact(task.Outputs), RanAt: time.Now()}
+	if err != nil {
+		r.Error = secret.Redact(err.Error())
+	}
+	ctx.setupResults = append(ctx.setupResults, r)
 }
 
 func expandVariable(raw string, ctx map[string]*RuntimeService, currentSvc *RuntimeService) ([]string, error) {
@@ -485,7 +671,7 @@ func canDeploy(svc *RuntimeService, all map[string]*RuntimeService) bool {
 		for _, rtSvc := range all {
 			if rtSvc.RawName == depName {
 				foundAny = true
-				if !rtSvc.IsDeployed {
+				if !rtSvc.IsDeployed || !rtSvc.IsHealthy {
 					return false
 				}
 			}
@@ -1,28 +1,36 @@
 package compose
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"red-cloud/mod"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
 	"red-cloud/mod/gologger"
-
-	"gopkg.in/yaml.v3"
+	"red-cloud/mod/secret"
 )
 
 // --- 基础结构体定义 (移动到 Core 以便共享) ---
 
 // ComposeConfig 对应 YAML 文件的根结构
 type ComposeConfig struct {
+	Name      string                 `yaml:"name,omitempty"` // 编排名称，默认为编排文件所在目录名
 	Version   string                 `yaml:"version"`
 	Providers map[string]interface{} `yaml:"providers,omitempty"` // 预留：如果以后需要内联 Provider 定义
 	Configs   map[string]ConfigItem  `yaml:"configs"`
+	Secrets   map[string]SecretItem  `yaml:"secrets,omitempty"` // 密钥，值在日志、预览与 MCP 输出中脱敏
 	Plugins   map[string]ServiceSpec `yaml:"plugins"`
 	Services  map[string]ServiceSpec `yaml:"services"`
 	Setup     []SetupTask            `yaml:"setup"`
+	Parallel  ParallelSpec           `yaml:"parallel,omitempty"`   // 并发调度配置
+	OnFailure string                 `yaml:"on_failure,omitempty"` // up 失败时的处理: rollback | keep | pause
 }
 
 // ConfigItem 全局配置项 (configs 块)
@@ -39,14 +47,17 @@ type ServiceSpec struct {
 	ContainerName string `yaml:"container_name,omitempty"` // 自定义容器/实例名
 
 	// 编排与控制
-	Provider  interface{} `yaml:"provider,omitempty"` // 支持 string (单云) 或 []string (多云矩阵)
-	Profiles  []string    `yaml:"profiles,omitempty"` // 激活环境 (prod, dev, attack)
-	DependsOn []string    `yaml:"depends_on,omitempty"`
-	Deploy    DeploySpec  `yaml:"deploy,omitempty"` // 部署策略 (Replicas)
+	Provider    interface{}      `yaml:"provider,omitempty"` // 支持 string (单云) 或 []string (多云矩阵)
+	Profiles    []string         `yaml:"profiles,omitempty"` // 激活环境 (prod, dev, attack)
+	DependsOn   []string         `yaml:"depends_on,omitempty"`
+	Deploy      DeploySpec       `yaml:"deploy,omitempty"`      // 部署策略 (Replicas)
+	HealthCheck *HealthCheckSpec `yaml:"healthcheck,omitempty"` // 健康检查，通过后依赖方才会部署
+	Account     string           `yaml:"account,omitempty"`     // 使用的命名账号，为空时使用默认凭据
 
 	// 变量与配置注入
 	Configs     []string `yaml:"configs,omitempty"`     // 格式 ["tf_var=config_key"]
 	Environment []string `yaml:"environment,omitempty"` // 格式 ["key=value"]
+	Secrets     []string `yaml:"secrets,omitempty"`     // 格式 ["tf_var=secret_name"] 或 ["secret_name"]
 
 	// SSH 后置操作
 	Volumes   []string `yaml:"volumes,omitempty"`   // 上传: ["local_path:remote_path"]
@@ -76,14 +87,34 @@ type RuntimeService struct {
 	Outputs    map[string]interface{} // TF Output 缓存
 	CaseRef    *mod.Case              // 关联的 Case 实例
 	IsDeployed bool                   // 部署状态标记
+	IsHealthy  bool                   // 健康检查已通过 (未配置健康检查时部署完成即为 true)
+	Created    bool                   // 场景由本次运行创建，回滚时销毁
+	RolledBack bool                   // 场景已被回滚
 }
 
 // ComposeOptions 编排选项
 type ComposeOptions struct {
 	File        string
+	Stack       string // 编排名称，覆盖编排文件中的 name
 	Profiles    []string
 	Project     *mod.RedcProject
 	LogCallback func(message string) // optional callback for GUI log streaming
+
+	// 并发调度，覆盖编排文件中的 parallel 配置
+	Parallelism     int            // 最大并发数，0 使用编排文件或默认值
+	ProviderLimits  map[string]int // 按云厂商/provider 别名的并发上限
+	ContinueOnError bool           // 出错后继续执行不受影响的服务
+
+	// Services 只操作指定的服务 (原始服务名或实例名)：up 时包含其传递依赖，down 时包含依赖它们的服务
+	Services []string
+
+	OnFailure string                 // up 失败时的处理，覆盖编排文件中的 on_failure
+	OnPause   func(cause error) bool // pause 策略下询问操作者，返回 true 时回滚；为空时保留
+	Timeline  *mod.TimelineStore     // 可选，回滚等事件写入时间线
+
+	// 操作者与来源 (如 HTTP 请求的认证用户)，记录到场景状态变迁与场景锁；为空时使用当前用户和 LockOwner
+	Actor  string
+	Source string
 }
 
 // ComposeContext 核心上下文，贯穿整个生命周期
@@ -91,16 +122,45 @@ type ComposeContext struct {
 	RuntimeSvcs   map[string]*RuntimeService // 服务实例 Map
 	SortedSvcKeys []string                   // 排序后的 Key (保证遍历顺序一致)
 	GlobalConfigs map[string]string          // 解析后的 Configs
-	ConfigRaw     ComposeConfig              // 原始 YAML
+	ConfigRaw     ComposeConfig              // 合并 include/extends 并完成变量插值后的 YAML
 	LogMgr        *gologger.LogManager       // 日志管理器
 	Project       *mod.RedcProject           // 项目引用
 	LogCallback   func(message string)       // optional GUI log callback
+	Parallel      ParallelSpec               // 生效的并发调度配置
+	StackName     string                     // 编排名称，用于持久化编排记录
+	File          string                     // 编排文件绝对路径
+	FileHash      string                     // 编排文件 SHA256
+	Profiles      []string                   // 激活的 Profiles
+	OnFailure     string                     // 生效的失败处理策略
+	OnPause       func(cause error) bool     // pause 策略的决策回调
+	Timeline      *mod.TimelineStore         // 时间线，可为空
+	Actor         string                     // 操作者，见 ComposeOptions.Actor
+	Source        string                     // 操作来源
+
+	setupResults []mod.ComposeSetupResult // 本次执行的 setup 结果
+	paused       bool                     // 失败后按 pause 策略保留
+	targets      map[string]bool          // 只操作指定服务时的原始服务名，为空时操作整个编排
+	removed      []string                 // 本次删除了场景的实例，从编排记录中移除
+	secrets      map[string]string        // loadSecrets 解析的 secret 值
+	dotEnv       map[string]string        // .env 中的变量
+
+	// mu 保护并发调度期间各服务的 IsDeployed/IsHealthy/Outputs/CaseRef
+	mu sync.Mutex
+}
+
+// getCase 查找场景，之后的状态变迁与场景锁记录本次编排的操作者
+func (ctx *ComposeContext) getCase(id string) (*mod.Case, error) {
+	c, err := ctx.Project.GetCase(id)
+	if err == nil {
+		c.SetActor(ctx.Actor, ctx.Source)
+	}
+	return c, err
 }
 
 // emitLog sends a log message to the callback if set
 func (ctx *ComposeContext) emitLog(msg string) {
 	if ctx.LogCallback != nil {
-		ctx.LogCallback(msg)
+		ctx.LogCallback(secret.Redact(msg))
 	}
 }
 
@@ -108,15 +168,24 @@ func (ctx *ComposeContext) emitLog(msg string) {
 
 // NewComposeContext 初始化上下文：读取 -> 解析 -> 过滤 -> 裂变
 func NewComposeContext(opts ComposeOptions) (*ComposeContext, error) {
-	// 1. 读取 YAML
+	// 1. 读取 YAML (合并 include/extends 并完成变量插值)
+	loaded, err := loadCompose(opts.File)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range loaded.Warnings {
+		gologger.Warning().Msgf("%s", w)
+	}
+	cfg := loaded.Config
+	if err := validateSecrets(cfg); err != nil {
+		return nil, err
+	}
 	data, err := os.ReadFile(opts.File)
 	if err != nil {
 		return nil, fmt.Errorf("读取配置文件失败: %v", err)
 	}
-	var cfg ComposeConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("解析 YAML 结构失败: %v", err)
-	}
+	absFile, _ := filepath.Abs(opts.File)
+	sum := sha256.Sum256(data)
 
 	// 2. 初始化组件
 	logMgr := gologger.NewLogManager(opts.Project.ProjectPath)
@@ -124,6 +193,14 @@ func NewComposeContext(opts ComposeOptions) (*ComposeContext, error) {
 	if err != nil {
 		return nil, err
 	}
+	parallel, err := resolveParallel(cfg.Parallel, opts)
+	if err != nil {
+		return nil, err
+	}
+	onFailure, err := resolveOnFailure(cfg.OnFailure, opts.OnFailure)
+	if err != nil {
+		return nil, err
+	}
 
 	// 3. 合并 Services 和 Plugins
 	allSpecs := cfg.Services
@@ -142,6 +219,12 @@ func NewComposeContext(opts ComposeOptions) (*ComposeContext, error) {
 			gologger.Debug().Msgf("Skipping service %s (profile not active)", name)
 			continue
 		}
+		if spec.HealthCheck != nil {
+			if err := spec.HealthCheck.validate(name); err != nil {
+				return nil, err
+			}
+		}
+		spec.DependsOn = withOutputDeps(name, spec)
 
 		// 裂变逻辑
 		expandedList := expandService(name, spec)
@@ -168,9 +251,41 @@ func NewComposeContext(opts ComposeOptions) (*ComposeContext, error) {
 		LogMgr:        logMgr,
 		Project:       opts.Project,
 		LogCallback:   opts.LogCallback,
+		Parallel:      parallel,
+		StackName:     stackName(opts.Stack, cfg.Name, absFile),
+		File:          absFile,
+		FileHash:      hex.EncodeToString(sum[:]),
+		Profiles:      opts.Profiles,
+		OnFailure:     onFailure,
+		OnPause:       opts.OnPause,
+		Timeline:      opts.Timeline,
+		Actor:         opts.Actor,
+		Source:        opts.Source,
+		dotEnv:        loaded.DotEnv,
 	}, nil
 }
 
+var outputRefRe = regexp.MustCompile(`\$\{([^.}]+)\.outputs\.`)
+
+// withOutputDeps 将 environment 中 ${svc.outputs.key} 引用的服务补充为隐式依赖，
+// 保证并发部署时被引用的服务先完成、销毁时后销毁
+func withOutputDeps(name string, spec ServiceSpec) []string {
+	deps := append([]string(nil), spec.DependsOn...)
+	seen := map[string]bool{name: true}
+	for _, d := range deps {
+		seen[d] = true
+	}
+	for _, env := range spec.Environment {
+		for _, m := range outputRefRe.FindAllStringSubmatch(env, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				deps = append(deps, m[1])
+			}
+		}
+	}
+	return deps
+}
+
 // resolveConfigs 解析配置
 func resolveConfigs(raw map[string]ConfigItem) (map[string]string, error) {
 	res := make(map[string]string)
@@ -0,0 +1,271 @@
+package compose
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"red-cloud/utils/sshutil"
+)
+
+// 健康检查类型
+const (
+	HealthTCP          = "tcp"
+	HealthHTTP         = "http"
+	HealthSSHCommand   = "ssh-command"
+	HealthOutputExists = "terraform-output-present"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 5 * time.Second
+	defaultHealthRetries  = 30
+)
+
+// HealthCheckSpec 服务健康检查 (healthcheck 块)，检查通过后依赖该服务的服务才会部署
+type HealthCheckSpec struct {
+	Type         string `yaml:"type"`                    // tcp | http | ssh-command | terraform-output-present
+	Target       string `yaml:"target,omitempty"`        // tcp: host:port，http: URL，支持 ${svc.outputs.key}
+	Port         int    `yaml:"port,omitempty"`          // tcp 未指定 target 时连接实例 SSH 地址的该端口
+	Command      string `yaml:"command,omitempty"`       // ssh-command: 退出码为 0 视为健康
+	Output       string `yaml:"output,omitempty"`        // terraform-output-present: 需要存在且非空的 output
+	ExpectStatus int    `yaml:"expect_status,omitempty"` // http: 期望的状态码，默认小于 400 即可
+	Interval     string `yaml:"interval,omitempty"`      // 检查间隔，默认 10s
+	Timeout      string `yaml:"timeout,omitempty"`       // 单次检查超时，默认 5s
+	Retries      int    `yaml:"retries,omitempty"`       // 最多检查次数，默认 30
+}
+
+// healthChecker 执行一次检查，返回 nil 表示健康
+type healthChecker func(svc *RuntimeService, ctx *ComposeContext, hc *HealthCheckSpec, timeout time.Duration) error
+
+var healthCheckers = map[string]healthChecker{
+	HealthTCP:          checkTCP,
+	HealthHTTP:         checkHTTP,
+	HealthSSHCommand:   checkSSHCommand,
+	HealthOutputExists: checkOutputPresent,
+}
+
+func parseHealthDuration(raw string, def time.Duration) (time.Duration, error) {
+	if raw == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+	return d, nil
+}
+
+// settings 返回检查间隔、单次超时和最多检查次数
+func (hc *HealthCheckSpec) settings() (interval, timeout time.Duration, retries int, err error) {
+	if interval, err = parseHealthDuration(hc.Interval, defaultHealthInterval); err != nil {
+		return
+	}
+	if timeout, err = parseHealthDuration(hc.Timeout, defaultHealthTimeout); err != nil {
+		return
+	}
+	retries = hc.Retries
+	if retries <= 0 {
+		retries = defaultHealthRetries
+	}
+	return
+}
+
+// validate 解析编排文件时检查健康检查配置
+func (hc *HealthCheckSpec) validate(service string) error {
+	fail := func(reason string) error {
+		return fmt.Errorf("%s", i18n.Tf("compose_health_invalid", service, reason))
+	}
+	if _, ok := healthCheckers[hc.Type]; !ok {
+		return fail(fmt.Sprintf("unknown type %q", hc.Type))
+	}
+	if _, _, _, err := hc.settings(); err != nil {
+		return fail(err.Error())
+	}
+	switch hc.Type {
+	case HealthTCP:
+		if hc.Target == "" && hc.Port <= 0 {
+			return fail("tcp requires target or port")
+		}
+	case HealthHTTP:
+		if hc.Target == "" {
+			return fail("http requires target")
+		}
+	case HealthSSHCommand:
+		if hc.Command == "" {
+			return fail("ssh-command requires command")
+		}
+	case HealthOutputExists:
+		if hc.Output == "" {
+			return fail("terraform-output-present requires output")
+		}
+	}
+	return nil
+}
+
+// waitHealthy 按 interval 重复执行健康检查直到通过或次数用尽，进度写入编排日志。
+// 未配置健康检查的服务部署完成即视为健康
+func waitHealthy(svc *RuntimeService, ctx *ComposeContext) error {
+	hc := svc.Spec.HealthCheck
+	if hc == nil {
+		ctx.setHealthy(svc)
+		return nil
+	}
+	interval, timeout, retries, err := hc.settings()
+	if err != nil {
+		return err
+	}
+	check := healthCheckers[hc.Type]
+	msg := i18n.Tf("compose_health_start", svc.Name, hc.Type)
+	gologger.Info().Msgf("%s", msg)
+	ctx.emitLog(msg)
+	var lastErr error
+	for attempt := 1; attempt <= retries; attempt++ {
+		if lastErr = check(svc, ctx, hc, timeout); lastErr == nil {
+			ctx.setHealthy(svc)
+			msg := i18n.Tf("compose_health_passed", svc.Name, hc.Type, attempt)
+			gologger.Info().Msgf("%s", msg)
+			ctx.emitLog(msg)
+			return nil
+		}
+		ctx.emitLog(i18n.Tf("compose_health_waiting", svc.Name, attempt, retries, lastErr))
+		if attempt < retries {
+			time.Sleep(interval)
+		}
+	}
+	return fmt.Errorf("%s", i18n.Tf("compose_health_failed", svc.Name, hc.Type, retries, lastErr))
+}
+
+func (ctx *ComposeContext) setHealthy(svc *RuntimeService) {
+	ctx.mu.Lock()
+	svc.IsHealthy = true
+	ctx.mu.Unlock()
+}
+
+// healthTarget 展开 target 中的 ${svc.outputs.key}，多实例引用时取第一个
+func healthTarget(svc *RuntimeService, ctx *ComposeContext, raw string) (string, error) {
+	ctx.mu.Lock()
+	vals, err := expandVariable(raw, ctx.RuntimeSvcs, svc)
+	ctx.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	return vals[0], nil
+}
+
+func checkTCP(svc *RuntimeService, ctx *ComposeContext, hc *HealthCheckSpec, timeout time.Duration) error {
+	addr := hc.Target
+	if addr == "" {
+		sshConf, err := svc.CaseRef.GetSSHConfig()
+		if err != nil {
+			return err
+		}
+		addr = net.JoinHostPort(sshConf.Host, strconv.Itoa(hc.Port))
+	} else {
+		var err error
+		if addr, err = healthTarget(svc, ctx, addr); err != nil {
+			return err
+		}
+	}
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func checkHTTP(svc *RuntimeService, ctx *ComposeContext, hc *HealthCheckSpec, timeout time.Duration) error {
+	url, err := healthTarget(svc, ctx, hc.Target)
+	if err != nil {
+		return err
+	}
+	// 团队服务器等通常使用自签名证书
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if hc.ExpectStatus != 0 && resp.StatusCode != hc.ExpectStatus {
+		return fmt.Errorf("status %d, want %d", resp.StatusCode, hc.ExpectStatus)
+	}
+	if hc.ExpectStatus == 0 && resp.StatusCode >= 400 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func checkSSHCommand(svc *RuntimeService, ctx *ComposeContext, hc *HealthCheckSpec, timeout time.Duration) error {
+	sshConf, err := svc.CaseRef.GetSSHConfig()
+	if err != nil {
+		return err
+	}
+	// 超时后关闭连接，使仍在执行的命令返回，避免会话和连接泄漏
+	var (
+		mu       sync.Mutex
+		client   *sshutil.Client
+		timedOut bool
+	)
+	done := make(chan error, 1)
+	go func() {
+		cl, err := sshutil.NewClient(sshConf)
+		if err != nil {
+			done <- err
+			return
+		}
+		mu.Lock()
+		if timedOut {
+			mu.Unlock()
+			cl.Close()
+			return
+		}
+		client = cl
+		mu.Unlock()
+		defer cl.Close()
+		var out bytes.Buffer
+		if err := cl.RunCommandWithLogger(hc.Command, &out); err != nil {
+			done <- fmt.Errorf("%v: %s", err, truncateString(strings.TrimSpace(out.String()), 200))
+			return
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		mu.Lock()
+		timedOut = true
+		if client != nil {
+			client.Close()
+		}
+		mu.Unlock()
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+}
+
+// checkOutputPresent 每次重新读取 state 中的 output，通过后更新服务的 output 缓存供依赖方引用
+func checkOutputPresent(svc *RuntimeService, ctx *ComposeContext, hc *HealthCheckSpec, timeout time.Duration) error {
+	rawOut, err := svc.CaseRef.TfOutput()
+	if err != nil {
+		return err
+	}
+	outputs := parseTfOutput(rawOut)
+	if v, ok := outputs[hc.Output]; !ok || v == nil || fmt.Sprint(v) == "" {
+		return fmt.Errorf("output %q not present", hc.Output)
+	}
+	ctx.mu.Lock()
+	svc.Outputs = outputs
+	ctx.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,78 @@
+package compose
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWaitHealthy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ready" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	var logs []string
+	ctx := &ComposeContext{RuntimeSvcs: map[string]*RuntimeService{}, LogCallback: func(m string) { logs = append(logs, m) }}
+	cases := []struct {
+		hc *HealthCheckSpec
+		ok bool
+	}{
+		{&HealthCheckSpec{Type: HealthTCP, Target: ln.Addr().String()}, true},
+		{&HealthCheckSpec{Type: HealthHTTP, Target: srv.URL + "/ready"}, true},
+		{&HealthCheckSpec{Type: HealthHTTP, Target: srv.URL + "/missing", Retries: 2, Interval: "1ms"}, false},
+		{&HealthCheckSpec{Type: HealthHTTP, Target: srv.URL + "/missing", ExpectStatus: 404}, true},
+	}
+	for _, c := range cases {
+		svc := &RuntimeService{Name: "ts", RawName: "ts", IsDeployed: true, Spec: ServiceSpec{HealthCheck: c.hc}}
+		err := waitHealthy(svc, ctx)
+		if (err == nil) != c.ok || svc.IsHealthy != c.ok {
+			t.Errorf("%s %s: err = %v, healthy = %v", c.hc.Type, c.hc.Target, err, svc.IsHealthy)
+		}
+	}
+	if len(logs) == 0 || !strings.Contains(strings.Join(logs, "\n"), "[ts]") {
+		t.Errorf("health status should be streamed to LogCallback, got %v", logs)
+	}
+}
+
+func TestHealthGatesDependents(t *testing.T) {
+	ts := &RuntimeService{Name: "teamserver", RawName: "teamserver", IsDeployed: true}
+	redirector := &RuntimeService{Name: "redirector", RawName: "redirector", Spec: ServiceSpec{DependsOn: []string{"teamserver"}}}
+	all := map[string]*RuntimeService{"teamserver": ts, "redirector": redirector}
+	if canDeploy(redirector, all) {
+		t.Error("dependent should wait until the dependency is healthy")
+	}
+	ts.IsHealthy = true
+	if !canDeploy(redirector, all) {
+		t.Error("dependent should deploy once the dependency is healthy")
+	}
+}
+
+func TestHealthCheckValidate(t *testing.T) {
+	bad := []HealthCheckSpec{
+		{Type: "ping"},
+		{Type: HealthTCP},
+		{Type: HealthHTTP},
+		{Type: HealthSSHCommand},
+		{Type: HealthOutputExists},
+		{Type: HealthTCP, Port: 50050, Interval: "soon"},
+	}
+	for _, hc := range bad {
+		if err := hc.validate("ts"); err == nil {
+			t.Errorf("%+v should be rejected", hc)
+		}
+	}
+	good := HealthCheckSpec{Type: HealthOutputExists, Output: "public_ip", Interval: "5s", Timeout: "2s", Retries: 3}
+	if err := good.validate("ts"); err != nil {
+		t.Error(err)
+	}
+}
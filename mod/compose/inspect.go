@@ -53,6 +53,9 @@ func InspectConfig(opts ComposeOptions) error {
 		if len(svc.Spec.DependsOn) > 0 {
 			fmt.Fprintf(w, "Depends On:\t%v\n", svc.Spec.DependsOn)
 		}
+		if hc := svc.Spec.HealthCheck; hc != nil {
+			fmt.Fprintf(w, "Health Check:\t%s %s\n", hc.Type, truncateString(hc.Target+hc.Command+hc.Output, 50))
+		}
 		fmt.Fprintln(w, strings.Repeat("-", 60))
 	}
 	w.Flush()
@@ -123,11 +126,21 @@ func previewTfVars(svc *RuntimeService, ctx *ComposeContext) map[string]string {
 		parts := strings.SplitN(envStr, "=", 2)
 		if len(parts) == 2 {
 			key, rawVal := parts[0], parts[1]
+			if preview := previewSecrets(rawVal); preview != rawVal {
+				tfVars[key] = preview
+				continue
+			}
 			vals := previewExpandVariable(rawVal, ctx.RuntimeSvcs, svc)
-			tfVars[key] = strings.Join(vals, ",")
+			tfVars[key] = maskParam(key, strings.Join(vals, ","))
 		}
 	}
 
+	// Secrets
+	for _, ref := range svc.Spec.Secrets {
+		tfName, name := secretMapping(ref)
+		tfVars[tfName] = fmt.Sprintf("<Secret: %s>", name)
+	}
+
 	// Provider Alias
 	if pStr, ok := svc.Spec.Provider.(string); ok && pStr != "" && pStr != "default" {
 		tfVars["provider_alias"] = pStr
@@ -0,0 +1,591 @@
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"red-cloud/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 编排文件加载流程：读取 -> 合并 extends/include -> 变量插值 -> 展开 env_file -> 解析为 ComposeConfig。
+// 合并规则 (与顺序无关的部分都按 key 排序，保证结果确定)：
+//   - extends: 先加载被继承的文件，当前文件的 map 递归覆盖其上，标量与列表整体替换
+//   - include: 按列出顺序加载，services/plugins/configs/providers 中同名项视为冲突并报错，
+//     setup 按 被包含文件 -> 当前文件 的顺序拼接，其他字段以当前文件为准
+//   - 变量插值在合并后进行，取值顺序为 进程环境变量 > 主编排文件同目录的 .env
+
+// namedSections include 时不允许同名的顶层集合
+var namedSections = []string{"services", "plugins", "configs", "providers", "secrets"}
+
+// loadedCompose 加载结果
+type loadedCompose struct {
+	Config   ComposeConfig
+	Resolved []byte            // 合并与插值后的完整 YAML
+	Warnings []string          // 未设置的变量等提示
+	DotEnv   map[string]string // .env 中的变量，供 environment 来源的 secret 使用
+}
+
+// loadCompose 加载编排文件，处理 extends/include、.env、变量插值与 env_file
+func loadCompose(file string) (*loadedCompose, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := loadTree(absFile, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{}
+	dotEnv := filepath.Join(filepath.Dir(absFile), ".env")
+	if _, err := os.Stat(dotEnv); err == nil {
+		pairs, err := parseEnvFile(dotEnv)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pairs {
+			vars[p[0]] = p[1]
+		}
+	}
+	in := &interpolator{vars: vars, warned: map[string]bool{}}
+	resolved, err := in.node(tree, "")
+	if err != nil {
+		return nil, err
+	}
+	tree = resolved.(map[string]interface{})
+	if err := foldEnvFiles(tree); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(tree); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	res := &loadedCompose{Resolved: data, Warnings: in.warnings, DotEnv: vars}
+	if err := yaml.Unmarshal(data, &res.Config); err != nil {
+		return nil, fmt.Errorf("解析 YAML 结构失败: %v", err)
+	}
+	return res, nil
+}
+
+// ResolvedConfig 返回合并 include/extends 并完成变量插值后的编排文件 (compose config --resolved)
+func ResolvedConfig(file string) ([]byte, []string, error) {
+	loaded, err := loadCompose(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loaded.Resolved, loaded.Warnings, nil
+}
+
+// loadTree 读取单个文件并递归合并其 extends/include，visiting 用于检测循环引用
+func loadTree(absFile string, visiting map[string]bool) (map[string]interface{}, error) {
+	if visiting[absFile] {
+		return nil, fmt.Errorf("%s", i18n.Tf("compose_include_cycle", absFile))
+	}
+	visiting[absFile] = true
+	defer delete(visiting, absFile)
+
+	data, err := os.ReadFile(absFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	own := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &own); err != nil {
+		return nil, fmt.Errorf("解析 YAML 结构失败: %v", err)
+	}
+	if own == nil {
+		own = map[string]interface{}{}
+	}
+	dir := filepath.Dir(absFile)
+	absEnvFiles(own, dir)
+	absSecretFiles(own, dir)
+
+	extends, hasExtends := own["extends"]
+	includes, hasIncludes := own["include"]
+	delete(own, "extends")
+	delete(own, "include")
+
+	if hasExtends {
+		path, err := refPath(extends, "file", dir)
+		if err != nil {
+			return nil, err
+		}
+		base, err := loadTree(path, visiting)
+		if err != nil {
+			return nil, err
+		}
+		own = mergeOverride(base, own)
+	}
+	if !hasIncludes {
+		return own, nil
+	}
+
+	list, ok := includes.([]interface{})
+	if !ok {
+		list = []interface{}{includes}
+	}
+	acc := map[string]interface{}{}
+	for _, item := range list {
+		path, err := refPath(item, "path", dir)
+		if err != nil {
+			return nil, err
+		}
+		inc, err := loadTree(path, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if acc, err = mergeInclude(acc, inc, path); err != nil {
+			return nil, err
+		}
+	}
+	return mergeInclude(acc, own, absFile)
+}
+
+// refPath 解析 include/extends 引用：字符串或 {key: path}，相对路径基于声明它的文件所在目录
+func refPath(ref interface{}, key, dir string) (string, error) {
+	var path string
+	switch v := ref.(type) {
+	case string:
+		path = v
+	case map[string]interface{}:
+		path, _ = v[key].(string)
+	}
+	if path == "" {
+		return "", fmt.Errorf("%s", i18n.Tf("compose_include_invalid", fmt.Sprint(ref)))
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// absEnvFiles 将服务中 env_file 的相对路径转换为基于声明文件目录的绝对路径
+func absEnvFiles(tree map[string]interface{}, dir string) {
+	for _, section := range []string{"services", "plugins"} {
+		svcs, _ := tree[section].(map[string]interface{})
+		for _, raw := range svcs {
+			svc, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch v := svc["env_file"].(type) {
+			case string:
+				svc["env_file"] = absPath(v, dir)
+			case []interface{}:
+				for i, f := range v {
+					if s, ok := f.(string); ok {
+						v[i] = absPath(s, dir)
+					}
+				}
+			}
+		}
+	}
+}
+
+// absSecretFiles 将 secrets 中 file 的相对路径转换为基于声明文件目录的绝对路径
+func absSecretFiles(tree map[string]interface{}, dir string) {
+	secrets, _ := tree["secrets"].(map[string]interface{})
+	for _, raw := range secrets {
+		if item, ok := raw.(map[string]interface{}); ok {
+			if f, ok := item["file"].(string); ok {
+				item["file"] = absPath(f, dir)
+			}
+		}
+	}
+}
+
+func absPath(path, dir string) string {
+	if path == "" || filepath.IsAbs(path) || strings.Contains(path, "${") {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// mergeOverride 递归合并 map，over 中的值覆盖 base，标量与列表整体替换
+func mergeOverride(base, over map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(base)+len(over))
+	for k, v := range base {
+		res[k] = v
+	}
+	for k, v := range over {
+		bm, ok1 := res[k].(map[string]interface{})
+		om, ok2 := v.(map[string]interface{})
+		if ok1 && ok2 {
+			res[k] = mergeOverride(bm, om)
+			continue
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// mergeInclude 合并被包含的文件：同名服务/插件/配置报错，setup 拼接，其他字段以 b 为准
+func mergeInclude(a, b map[string]interface{}, source string) (map[string]interface{}, error) {
+	res := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		res[k] = v
+	}
+	for k, v := range b {
+		switch {
+		case slices.Contains(namedSections, k):
+			am, _ := res[k].(map[string]interface{})
+			bm, _ := v.(map[string]interface{})
+			merged := make(map[string]interface{}, len(am)+len(bm))
+			for name, spec := range am {
+				merged[name] = spec
+			}
+			names := make([]string, 0, len(bm))
+			for name := range bm {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if _, dup := merged[name]; dup {
+					return nil, fmt.Errorf("%s", i18n.Tf("compose_include_conflict", k, name, source))
+				}
+				merged[name] = bm[name]
+			}
+			res[k] = merged
+		case k == "setup":
+			as, _ := res[k].([]interface{})
+			bs, _ := v.([]interface{})
+			res[k] = append(append([]interface{}{}, as...), bs...)
+		default:
+			res[k] = v
+		}
+	}
+	return res, nil
+}
+
+// foldEnvFiles 读取服务的 env_file 并追加到 environment，environment 中已定义的 key 优先
+func foldEnvFiles(tree map[string]interface{}) error {
+	for _, section := range []string{"services", "plugins"} {
+		svcs, _ := tree[section].(map[string]interface{})
+		for _, raw := range svcs {
+			svc, ok := raw.(map[string]interface{})
+			if !ok || svc["env_file"] == nil {
+				continue
+			}
+			var files []string
+			switch v := svc["env_file"].(type) {
+			case string:
+				files = []string{v}
+			case []interface{}:
+				for _, f := range v {
+					files = append(files, fmt.Sprint(f))
+				}
+			}
+			delete(svc, "env_file")
+
+			env, _ := svc["environment"].([]interface{})
+			defined := map[string]bool{}
+			for _, e := range env {
+				k, _, _ := strings.Cut(fmt.Sprint(e), "=")
+				defined[k] = true
+			}
+			for _, f := range files {
+				pairs, err := parseEnvFile(f)
+				if err != nil {
+					return err
+				}
+				for _, p := range pairs {
+					if !defined[p[0]] {
+						defined[p[0]] = true
+						env = append(env, p[0]+"="+p[1])
+					}
+				}
+			}
+			svc["environment"] = env
+		}
+	}
+	return nil
+}
+
+// parseEnvFile 解析 KEY=VALUE 格式的文件，支持注释、空行、export 前缀与引号
+func parseEnvFile(path string) ([][2]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("compose_env_file_failed", path, err))
+	}
+	defer f.Close()
+	var pairs [][2]string
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+		key, val, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isVarName(key) {
+			return nil, fmt.Errorf("%s", i18n.Tf("compose_env_file_invalid", path, line))
+		}
+		val = strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		}
+		pairs = append(pairs, [2]string{key, val})
+	}
+	return pairs, scanner.Err()
+}
+
+func isVarName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isNameChar(s[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameChar(c byte, first bool) bool {
+	return c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || !first && c >= '0' && c <= '9'
+}
+
+// interpolator docker-compose 风格的变量插值：${VAR}、${VAR:-default}、${VAR-default}、
+// ${VAR:?error}、${VAR?error}、${VAR:+alt}、${VAR+alt}，$$ 转义为 $。
+// 不是合法变量名的 ${...} (如 ${teamserver.outputs.ip}) 原样保留，由部署时解析
+type interpolator struct {
+	vars     map[string]string
+	warned   map[string]bool
+	warnings []string
+}
+
+func (in *interpolator) lookup(name string) (string, bool) {
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true
+	}
+	v, ok := in.vars[name]
+	return v, ok
+}
+
+// node 对 YAML 树中所有字符串值插值，map 的 key 不变
+func (in *interpolator) node(v interface{}, path string) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			r, err := in.node(child, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = r
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(t))
+		for i, child := range t {
+			r, err := in.node(child, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			res[i] = r
+		}
+		return res, nil
+	case string:
+		r, err := in.interpolate(t, strings.TrimPrefix(path, "."))
+		if err != nil || r == t {
+			return r, err
+		}
+		return typedScalar(r, fieldKind(path)), nil
+	}
+	return v, nil
+}
+
+// typedScalar 将插值结果还原为 replicas 等整数 / 布尔字段的类型，字符串字段 (environment、command 等) 保持原样
+func typedScalar(s string, kind reflect.Kind) interface{} {
+	switch kind {
+	case reflect.Int:
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return s
+}
+
+// fieldKind 按 yaml 标签在 ComposeConfig 中查找插值路径 (如 .services.web.deploy.replicas、.setup[0].command) 对应字段的类型，
+// 找不到或为 interface{} 时返回 reflect.Invalid
+func fieldKind(path string) reflect.Kind {
+	t := reflect.TypeOf(ComposeConfig{})
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		key, idx, _ := strings.Cut(seg, "[")
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			f, ok := yamlField(t, key)
+			if !ok {
+				return reflect.Invalid
+			}
+			t = f.Type
+		default:
+			return reflect.Invalid
+		}
+		for ; idx != ""; _, idx, _ = strings.Cut(idx, "[") {
+			if t.Kind() != reflect.Slice {
+				return reflect.Invalid
+			}
+			t = t.Elem()
+		}
+	}
+	return t.Kind()
+}
+
+func yamlField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag, _, _ := strings.Cut(f.Tag.Get("yaml"), ","); tag == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// interpolate 替换字符串中的变量引用
+func (in *interpolator) interpolate(s, path string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if s[i+1] != '{' {
+			b.WriteByte('$')
+			continue
+		}
+		end := matchBrace(s, i+1)
+		if end < 0 {
+			b.WriteString(s[i:])
+			break
+		}
+		expr := s[i+2 : end]
+		val, ok, err := in.expand(expr, path)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			b.WriteString(val)
+		} else {
+			b.WriteString(s[i : end+1])
+		}
+		i = end
+	}
+	return b.String(), nil
+}
+
+// matchBrace 返回与 s[open] 处 '{' 匹配的 '}' 位置，支持嵌套
+func matchBrace(s string, open int) int {
+	depth := 0
+	for j := open; j < len(s); j++ {
+		switch s[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// deployRefRe 部署时才解析的引用，如 ${cs-server.outputs.public_ip}、${secrets.license}。
+// 服务名可以包含 '-'，必须在解析 ${VAR-default} 之前排除
+var deployRefRe = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$`)
+
+// expand 计算 ${expr}，expr 不是变量引用时返回 ok=false
+func (in *interpolator) expand(expr, path string) (string, bool, error) {
+	if deployRefRe.MatchString(expr) {
+		return "", false, nil
+	}
+	n := 0
+	for n < len(expr) && isNameChar(expr[n], n == 0) {
+		n++
+	}
+	name, rest := expr[:n], expr[n:]
+	if name == "" {
+		return "", false, nil
+	}
+	val, set := in.lookup(name)
+	if rest == "" {
+		if !set && !in.warned[name] {
+			in.warned[name] = true
+			in.warnings = append(in.warnings, i18n.Tf("compose_var_unset", name, path))
+		}
+		return val, true, nil
+	}
+
+	op := rest[:1]
+	colon := op == ":"
+	if colon {
+		if len(rest) < 2 {
+			return "", false, nil
+		}
+		op = rest[1:2]
+		rest = rest[2:]
+	} else {
+		rest = rest[1:]
+	}
+	if op != "-" && op != "?" && op != "+" {
+		return "", false, nil
+	}
+	// 带冒号时空值视为未设置
+	present := set && (!colon || val != "")
+	switch op {
+	case "-":
+		if present {
+			return val, true, nil
+		}
+		def, err := in.interpolate(rest, path)
+		return def, true, err
+	case "?":
+		if present {
+			return val, true, nil
+		}
+		msg, err := in.interpolate(rest, path)
+		if err != nil {
+			return "", true, err
+		}
+		return "", true, fmt.Errorf("%s", i18n.Tf("compose_var_required", name, path, msg))
+	default:
+		if !present {
+			return "", true, nil
+		}
+		alt, err := in.interpolate(rest, path)
+		return alt, true, err
+	}
+}
@@ -0,0 +1,150 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("REDC_TEST_REGION", "ap-east-1")
+	in := &interpolator{vars: map[string]string{"EMPTY": "", "SIZE": "2"}, warned: map[string]bool{}}
+	cases := map[string]string{
+		"${REDC_TEST_REGION}":         "ap-east-1",
+		"${MISSING:-cn-hongkong}":     "cn-hongkong",
+		"${EMPTY:-fallback}":          "fallback",
+		"${EMPTY-fallback}":           "",
+		"${SIZE:+x${SIZE}}":           "x2",
+		"${MISSING:-${SIZE}}":         "2",
+		"cost $$5":                    "cost $5",
+		"$HOME stays":                 "$HOME stays",
+		"${teamserver.outputs.ip}":    "${teamserver.outputs.ip}",
+		"ip=${ts.outputs.ip}:${SIZE}": "ip=${ts.outputs.ip}:2",
+		"${provider.alias}-${SIZE}":   "${provider.alias}-2",
+		// 包含 '-' 的服务名不能被当作 ${VAR-default}
+		"${cs-server.outputs.public_ip}": "${cs-server.outputs.public_ip}",
+		"${secrets.ts-pass}":             "${secrets.ts-pass}",
+		"${MISSING:-example.com}":        "example.com",
+	}
+	for raw, want := range cases {
+		if got, err := in.interpolate(raw, "test"); err != nil || got != want {
+			t.Errorf("interpolate(%q) = %q, %v, want %q", raw, got, err, want)
+		}
+	}
+	if got, _ := in.interpolate("${UNSET_VAR}", "services.a.image"); got != "" || len(in.warnings) != 1 {
+		t.Errorf("unset variable should become empty with a warning: %q, %v", got, in.warnings)
+	}
+	if _, err := in.interpolate("${UNSET_VAR:?region is required}", "test"); err == nil || !strings.Contains(err.Error(), "region is required") {
+		t.Errorf("required variable error = %v", err)
+	}
+}
+
+func TestLoadComposeIncludeExtends(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env":        "IMAGE=aliyun/ecs\nexport REPLICAS=3\nPIN=0123\nCONTINUE=true\n",
+		"scanner.env": "# scanner settings\nthreads=50\nregion='cn-beijing'\n",
+		"base.yaml": `
+version: "3.9"
+on_failure: keep
+services:
+  scanner:
+    image: ${IMAGE}
+    env_file: scanner.env
+    environment:
+      - region=cn-hangzhou
+      - pin=${PIN}
+    command: ${PIN}
+    deploy:
+      replicas: 1
+`,
+		"infra/proxy.yaml": `
+services:
+  proxy:
+    image: aws/ec2
+setup:
+  - name: proxy-init
+    service: proxy
+    command: init
+`,
+		"redc-compose.yaml": `
+name: demo
+extends: base.yaml
+include:
+  - infra/proxy.yaml
+on_failure: rollback
+parallel:
+  continue_on_error: ${CONTINUE}
+services:
+  scanner:
+    deploy:
+      replicas: ${REPLICAS}
+setup:
+  - name: scan
+    service: scanner
+    command: scan ${proxy.outputs.ip}
+`,
+	})
+
+	loaded, err := loadCompose(filepath.Join(dir, "redc-compose.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := loaded.Config
+	scanner := cfg.Services["scanner"]
+	if cfg.Name != "demo" || cfg.Version != "3.9" || cfg.OnFailure != OnFailureRollback {
+		t.Errorf("top-level fields = %q %q %q", cfg.Name, cfg.Version, cfg.OnFailure)
+	}
+	if scanner.Image != "aliyun/ecs" || scanner.Deploy.Replicas != 3 {
+		t.Errorf("scanner = %+v", scanner)
+	}
+	if scanner.Command != "0123" || !cfg.Parallel.ContinueOnError {
+		t.Errorf("strings should keep leading zeros and bool fields should be typed: %q %v", scanner.Command, cfg.Parallel.ContinueOnError)
+	}
+	if strings.Join(scanner.Environment, ",") != "region=cn-hangzhou,pin=0123,threads=50" {
+		t.Errorf("environment should keep explicit values before env_file: %v", scanner.Environment)
+	}
+	if _, ok := cfg.Services["proxy"]; !ok {
+		t.Error("included service missing")
+	}
+	if len(cfg.Setup) != 2 || cfg.Setup[0].Name != "proxy-init" || cfg.Setup[1].Command != "scan ${proxy.outputs.ip}" {
+		t.Errorf("setup = %+v", cfg.Setup)
+	}
+	if strings.Contains(string(loaded.Resolved), "env_file") || strings.Contains(string(loaded.Resolved), "include") {
+		t.Errorf("resolved document should not keep env_file/include:\n%s", loaded.Resolved)
+	}
+}
+
+func TestLoadComposeIncludeErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.yaml":   "include: [b.yaml]\nservices:\n  web:\n    image: aws/ec2\n",
+		"b.yaml":   "services:\n  web:\n    image: aliyun/ecs\n",
+		"c.yaml":   "extends: d.yaml\n",
+		"d.yaml":   "include: c.yaml\n",
+		"req.yaml": "services:\n  web:\n    image: ${REDC_TEST_UNSET:?set the image}\n",
+	})
+	if _, err := loadCompose(filepath.Join(dir, "a.yaml")); err == nil || !strings.Contains(err.Error(), "web") {
+		t.Errorf("duplicate service should be rejected, got %v", err)
+	}
+	if _, err := loadCompose(filepath.Join(dir, "c.yaml")); err == nil {
+		t.Error("include cycle should be rejected")
+	}
+	if _, err := loadCompose(filepath.Join(dir, "req.yaml")); err == nil {
+		t.Error("missing required variable should be rejected")
+	}
+}
@@ -0,0 +1,397 @@
+package compose
+
+import (
+	"sort"
+	"strings"
+
+	"red-cloud/i18n"
+	"red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
+)
+
+// compose plan 中服务实例的动作
+const (
+	PlanActionCreate    = "create"    // 新部署的实例
+	PlanActionUpdate    = "update"    // environment/configs 变化或场景未运行，compose up 时重新 apply
+	PlanActionDestroy   = "destroy"   // 已从编排文件移除或缩容掉的实例
+	PlanActionUnchanged = "unchanged" // 参数一致，无需变更
+)
+
+// ComposePlanResult compose plan 的结果：编排文件与已部署场景及其参数的差异
+type ComposePlanResult struct {
+	Stack     string                 `json:"stack"`
+	File      string                 `json:"file"`
+	Deployed  bool                   `json:"deployed"` // 是否存在编排记录
+	Services  []ComposePlanService   `json:"services"`
+	Added     []string               `json:"added"`    // 新增的服务 (原始服务名)
+	Removed   []string               `json:"removed"`  // 从编排文件中移除的服务
+	Replicas  []ComposeReplicaChange `json:"replicas"` // 副本数变化
+	ToCreate  int                    `json:"toCreate"` // 各场景 terraform plan 的资源变更合计
+	ToUpdate  int                    `json:"toUpdate"`
+	ToReplace int                    `json:"toReplace"`
+	ToDestroy int                    `json:"toDestroy"`
+	CostDelta float64                `json:"costDelta"` // 月度成本变化合计
+	Currency  string                 `json:"currency"`
+	Warnings  []string               `json:"warnings,omitempty"`
+}
+
+// ComposePlanService 单个服务实例的计划
+type ComposePlanService struct {
+	Name      string               `json:"name"`
+	RawName   string               `json:"rawName"`
+	Image     string               `json:"image"`
+	CaseID    string               `json:"caseId,omitempty"`
+	Action    string               `json:"action"`
+	Params    []ComposeParamChange `json:"params,omitempty"`    // 与场景当前参数不同的变量
+	Resources *mod.ChangePlan      `json:"resources,omitempty"` // 已有场景的资源级变更
+	CostDelta float64              `json:"costDelta"`
+	Currency  string               `json:"currency,omitempty"`
+	Error     string               `json:"error,omitempty"` // plan 或变量解析失败，该实例的资源变更未知
+}
+
+// ComposeParamChange 场景参数变化，敏感值以占位符展示
+type ComposeParamChange struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ComposeReplicaChange 服务副本数变化
+type ComposeReplicaChange struct {
+	Service string `json:"service"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+}
+
+// HasChanges 是否有实例需要变更
+func (r *ComposePlanResult) HasChanges() bool {
+	for _, s := range r.Services {
+		if s.Action != PlanActionUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary 返回实例与资源变更计数摘要
+func (r *ComposePlanResult) Summary() string {
+	counts := make(map[string]int)
+	for _, s := range r.Services {
+		counts[s.Action]++
+	}
+	return i18n.Tf("compose_plan_summary", counts[PlanActionCreate], counts[PlanActionUpdate], counts[PlanActionDestroy],
+		r.ToCreate, r.ToUpdate, r.ToReplace, r.ToDestroy)
+}
+
+// RunComposePlan 比较编排文件与已部署的场景及其参数，不修改任何资源。
+// 已有场景的参数变化通过 terraform plan 得到资源级变更，新增与销毁的实例按模板估算成本
+func RunComposePlan(opts ComposeOptions) (*ComposePlanResult, error) {
+	rec, recErr := mod.GetComposeStack(opts.Project.ProjectName, StackNameForFile(opts))
+	planOpts := opts
+	if recErr == nil && len(planOpts.Profiles) == 0 {
+		planOpts.Profiles = rec.Profiles
+	}
+	ctx, err := NewComposeContext(planOpts)
+	if err != nil {
+		return nil, err
+	}
+	if recErr != nil {
+		rec = &mod.ComposeStack{Name: ctx.StackName}
+	}
+	ctx.alignWithStack(rec)
+	if err := ctx.loadSecrets(); err != nil {
+		return nil, err
+	}
+
+	result := &ComposePlanResult{
+		Stack:    ctx.StackName,
+		File:     ctx.File,
+		Deployed: recErr == nil,
+		Services: []ComposePlanService{},
+	}
+	result.Added, result.Removed, result.Replicas = diffStack(ctx, rec)
+
+	// 1. 已部署的实例从场景恢复 outputs，用于解析 ${svc.outputs.key}
+	cases := make(map[string]*mod.Case)
+	for _, name := range ctx.SortedSvcKeys {
+		svc := ctx.RuntimeSvcs[name]
+		c := ctx.liveCase(rec, name)
+		if c == nil {
+			continue
+		}
+		cases[name] = c
+		svc.CaseRef = c
+		if c.State == mod.StateRunning {
+			if rawOut, err := c.TfOutput(); err == nil {
+				svc.Outputs = parseTfOutput(rawOut)
+			}
+			svc.IsDeployed = true
+		}
+	}
+
+	// 2. 编排文件中的实例
+	for _, name := range ctx.SortedSvcKeys {
+		msg := i18n.Tf("compose_plan_service", name)
+		gologger.Info().Msgf("%s", msg)
+		ctx.emitLog(msg)
+		result.planService(ctx, ctx.RuntimeSvcs[name], cases[name])
+	}
+
+	// 3. 编排记录中已不在编排文件里的实例
+	for _, s := range rec.Services {
+		if s.CaseID == "" || ctx.RuntimeSvcs[s.Name] != nil {
+			continue
+		}
+		if c, err := ctx.getCase(s.CaseID); err == nil {
+			result.planDestroy(s, c)
+		}
+	}
+	return result, nil
+}
+
+// liveCase 查找实例对应的场景：优先使用编排记录中的场景 ID，其次按实例名 (旧版本部署)
+func (ctx *ComposeContext) liveCase(rec *mod.ComposeStack, name string) *mod.Case {
+	key := name
+	if s := rec.Service(name); s != nil && s.CaseID != "" {
+		key = s.CaseID
+	}
+	c, err := ctx.getCase(key)
+	if err != nil {
+		return nil
+	}
+	return c
+}
+
+// alignWithStack 副本数在 1 与多个之间变化时，已部署的实例沿用编排记录中的名称，与 compose scale 一致
+func (ctx *ComposeContext) alignWithStack(rec *mod.ComposeStack) {
+	recorded := make(map[string]bool)
+	for _, s := range rec.Services {
+		if s.CaseID != "" {
+			recorded[s.Name] = true
+		}
+	}
+	svcs := make([]*RuntimeService, 0, len(ctx.SortedSvcKeys))
+	for _, key := range ctx.SortedSvcKeys {
+		svcs = append(svcs, ctx.RuntimeSvcs[key])
+	}
+	alignReplicaNames(svcs, recorded)
+	ctx.RuntimeSvcs = make(map[string]*RuntimeService, len(svcs))
+	ctx.SortedSvcKeys = ctx.SortedSvcKeys[:0]
+	for _, svc := range svcs {
+		ctx.RuntimeSvcs[svc.Name] = svc
+		ctx.SortedSvcKeys = append(ctx.SortedSvcKeys, svc.Name)
+	}
+	sort.Strings(ctx.SortedSvcKeys)
+}
+
+// diffStack 按原始服务名比较编排记录与编排文件，返回新增、移除的服务和副本数变化
+func diffStack(ctx *ComposeContext, rec *mod.ComposeStack) (added, removed []string, replicas []ComposeReplicaChange) {
+	from := make(map[string]int)
+	for _, s := range rec.Services {
+		if s.CaseID != "" {
+			from[s.RawName]++
+		}
+	}
+	to := make(map[string]int)
+	for _, svc := range ctx.RuntimeSvcs {
+		to[svc.RawName]++
+	}
+	added, removed, replicas = []string{}, []string{}, []ComposeReplicaChange{}
+	for raw, n := range to {
+		switch {
+		case from[raw] == 0:
+			added = append(added, raw)
+		case from[raw] != n:
+			replicas = append(replicas, ComposeReplicaChange{Service: raw, From: from[raw], To: n})
+		}
+	}
+	for raw := range from {
+		if to[raw] == 0 {
+			removed = append(removed, raw)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].Service < replicas[j].Service })
+	return added, removed, replicas
+}
+
+// planService 计划编排文件中的单个实例：没有场景时估算新建成本，
+// 参数变化或场景未运行时执行 terraform plan 并计算成本差
+func (r *ComposePlanResult) planService(ctx *ComposeContext, svc *RuntimeService, c *mod.Case) {
+	ps := ComposePlanService{Name: svc.Name, RawName: svc.RawName, Image: svc.Spec.Image, Action: PlanActionUnchanged}
+	vars, err := serviceTfVars(svc, ctx)
+	pending := err != nil
+	if pending {
+		// 引用的服务尚未部署，outputs 在 apply 时才能确定
+		vars = previewTfVars(svc, ctx)
+		ps.Error = i18n.Tf("compose_plan_vars_pending", err)
+	}
+
+	if c == nil {
+		ps.Action = PlanActionCreate
+		if tpl, err := mod.GetTemplatePath(svc.Spec.Image); err != nil {
+			r.warn(i18n.Tf("compose_plan_cost_failed", svc.Name, err))
+		} else if monthly, currency, err := monthlyCost(tpl, caseVars(vars)); err != nil {
+			r.warn(i18n.Tf("compose_plan_cost_failed", svc.Name, err))
+		} else {
+			r.addCost(&ps, monthly, currency)
+		}
+		r.add(ps)
+		return
+	}
+
+	ps.CaseID = c.Id
+	ps.Params = paramChanges(c, vars)
+	running := c.State == mod.StateRunning
+	if len(ps.Params) == 0 && running {
+		r.add(ps)
+		return
+	}
+	ps.Action = PlanActionUpdate
+	if pending {
+		r.add(ps)
+		return
+	}
+	// 预览使用临时 plan 文件，不覆盖场景的 case.tfplan
+	plan, err := c.PreviewChange(caseVars(vars))
+	if err != nil {
+		ps.Error = err.Error()
+		r.add(ps)
+		return
+	}
+	var before float64
+	var currency string
+	if running {
+		if before, currency, err = monthlyCost(c.Path, parameterMap(plan.OldParameter)); err != nil {
+			r.warn(i18n.Tf("compose_plan_cost_failed", svc.Name, err))
+		}
+	}
+	if err == nil {
+		if after, cur, err := monthlyCost(c.Path, parameterMap(plan.NewParameter)); err != nil {
+			r.warn(i18n.Tf("compose_plan_cost_failed", svc.Name, err))
+		} else {
+			if currency == "" {
+				currency = cur
+			}
+			r.addCost(&ps, after-before, currency)
+		}
+	}
+	// 参数中包含云凭据，结果只保留遮蔽后的 Params
+	plan.OldParameter, plan.NewParameter = nil, nil
+	ps.Resources = plan
+	r.add(ps)
+}
+
+// planDestroy 计划编排记录中多余的实例：state 中的资源全部销毁，成本按当前参数扣除
+func (r *ComposePlanResult) planDestroy(s mod.ComposeStackService, c *mod.Case) {
+	ps := ComposePlanService{Name: s.Name, RawName: s.RawName, Image: s.Image, CaseID: c.Id, Action: PlanActionDestroy}
+	if addrs, err := c.StateAddresses(); err != nil {
+		ps.Error = err.Error()
+	} else {
+		plan := &mod.ChangePlan{CaseID: c.Id, Changes: []mod.ResourceChangeSummary{}}
+		for _, addr := range addrs {
+			if strings.HasPrefix(addr, "data.") || strings.Contains(addr, ".data.") {
+				continue
+			}
+			plan.Changes = append(plan.Changes, mod.ResourceChangeSummary{Address: addr, Action: mod.ChangeActionDestroy})
+			plan.ToDestroy++
+		}
+		ps.Resources = plan
+	}
+	if c.State == mod.StateRunning {
+		if monthly, currency, err := monthlyCost(c.Path, parameterMap(c.Parameter)); err != nil {
+			r.warn(i18n.Tf("compose_plan_cost_failed", s.Name, err))
+		} else {
+			r.addCost(&ps, -monthly, currency)
+		}
+	}
+	r.add(ps)
+}
+
+func (r *ComposePlanResult) add(ps ComposePlanService) {
+	if ps.Resources != nil {
+		r.ToCreate += ps.Resources.ToCreate
+		r.ToUpdate += ps.Resources.ToUpdate
+		r.ToReplace += ps.Resources.ToReplace
+		r.ToDestroy += ps.Resources.ToDestroy
+	}
+	r.Services = append(r.Services, ps)
+}
+
+func (r *ComposePlanResult) warn(msg string) {
+	gologger.Warning().Msgf("%s", msg)
+	r.Warnings = append(r.Warnings, msg)
+}
+
+// addCost 记录实例的成本变化并累加，币种与已有合计不同时只在实例上展示
+func (r *ComposePlanResult) addCost(ps *ComposePlanService, delta float64, currency string) {
+	ps.CostDelta, ps.Currency = delta, currency
+	switch {
+	case r.Currency == "":
+		r.Currency = currency
+	case currency != "" && currency != r.Currency:
+		r.warn(i18n.Tf("compose_plan_currency_mixed", ps.Name, currency, r.Currency))
+		return
+	}
+	r.CostDelta += delta
+}
+
+// monthlyCost 按变量估算模板目录或场景工作目录的月度成本
+func monthlyCost(dir string, vars map[string]string) (float64, string, error) {
+	est, err := mod.EstimateTemplateCost(dir, vars)
+	if err != nil {
+		return 0, "", err
+	}
+	return est.TotalMonthlyCost, est.Currency, nil
+}
+
+// paramChanges 返回 vars 中与场景当前参数不同的变量，值按 secret 规则遮蔽
+func paramChanges(c *mod.Case, vars map[string]string) []ComposeParamChange {
+	vars = caseVars(vars)
+	old := parameterMap(c.Parameter)
+	merged := parameterMap(mod.MergeParameters(c.Type, c.Parameter, vars))
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var res []ComposeParamChange
+	for _, k := range keys {
+		if prev, ok := old[k]; ok && prev == merged[k] {
+			continue
+		}
+		res = append(res, ComposeParamChange{Key: k, Old: maskParam(k, old[k]), New: maskParam(k, merged[k])})
+	}
+	return res
+}
+
+func maskParam(key, val string) string {
+	if val != "" && (secret.IsSensitiveName(key) || secret.Registered(val)) {
+		return secret.Mask
+	}
+	return val
+}
+
+// caseVars 去掉账号变量，账号在创建场景时确定，不作为 terraform 变量比较或变更
+func caseVars(vars map[string]string) map[string]string {
+	res := make(map[string]string, len(vars))
+	for k, v := range vars {
+		if k != mod.AccountVar {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// parameterMap 将场景的 key=value 参数转换为 map
+func parameterMap(params []string) map[string]string {
+	res := make(map[string]string, len(params))
+	for _, p := range params {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			res[k] = v
+		}
+	}
+	return res
+}
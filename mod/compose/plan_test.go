@@ -0,0 +1,82 @@
+package compose
+
+import (
+	"testing"
+
+	"red-cloud/mod"
+	"red-cloud/mod/secret"
+)
+
+func TestParamChanges(t *testing.T) {
+	c := &mod.Case{Type: "aliyun/ecs", Parameter: []string{"region=cn-hangzhou", "node=1", "ali_access_key=LTAI-old"}}
+	vars := map[string]string{
+		"region":       "cn-beijing",
+		"node":         "1",
+		"password":     "P@ssw0rd-new",
+		mod.AccountVar: "team-b",
+	}
+	got := paramChanges(c, vars)
+	if len(got) != 2 || got[0].Key != "password" || got[1].Key != "region" {
+		t.Fatalf("paramChanges = %+v", got)
+	}
+	if got[0].Old != "" || got[0].New != secret.Mask {
+		t.Errorf("sensitive value should be masked: %+v", got[0])
+	}
+	if got[1].Old != "cn-hangzhou" || got[1].New != "cn-beijing" {
+		t.Errorf("region change = %+v", got[1])
+	}
+	if len(paramChanges(c, map[string]string{"region": "cn-hangzhou"})) != 0 {
+		t.Error("unchanged parameters should not be reported")
+	}
+}
+
+func TestDiffStack(t *testing.T) {
+	ctx := testDAG(ParallelSpec{Max: 2})
+	for _, name := range []string{"c_1", "c_2"} {
+		ctx.RuntimeSvcs[name] = &RuntimeService{Name: name, RawName: "c"}
+		ctx.SortedSvcKeys = append(ctx.SortedSvcKeys, name)
+	}
+	delete(ctx.RuntimeSvcs, "c")
+	rec := &mod.ComposeStack{Services: []mod.ComposeStackService{
+		{Name: "a", RawName: "a", CaseID: "1"},
+		{Name: "c", RawName: "c", CaseID: "2"},
+		{Name: "old", RawName: "old", CaseID: "3"},
+		{Name: "b", RawName: "b", Status: "skipped"},
+	}}
+	added, removed, replicas := diffStack(ctx, rec)
+	if len(added) != 2 || added[0] != "b" || added[1] != "d" {
+		t.Errorf("added = %v", added)
+	}
+	if len(removed) != 1 || removed[0] != "old" {
+		t.Errorf("removed = %v", removed)
+	}
+	if len(replicas) != 1 || replicas[0] != (ComposeReplicaChange{Service: "c", From: 1, To: 2}) {
+		t.Errorf("replicas = %+v", replicas)
+	}
+}
+
+func TestAlignWithStack(t *testing.T) {
+	ctx := &ComposeContext{RuntimeSvcs: map[string]*RuntimeService{}}
+	for _, svc := range expandService("scanner", ServiceSpec{Image: "aliyun/ecs", Deploy: DeploySpec{Replicas: 2}}) {
+		ctx.RuntimeSvcs[svc.Name] = svc
+		ctx.SortedSvcKeys = append(ctx.SortedSvcKeys, svc.Name)
+	}
+	ctx.alignWithStack(&mod.ComposeStack{Services: []mod.ComposeStackService{{Name: "scanner", RawName: "scanner", CaseID: "1"}}})
+	if len(ctx.SortedSvcKeys) != 2 || ctx.SortedSvcKeys[0] != "scanner" || ctx.RuntimeSvcs["scanner"] == nil {
+		t.Errorf("the deployed instance should keep its name: %v", ctx.SortedSvcKeys)
+	}
+}
+
+func TestPlanCostTotal(t *testing.T) {
+	r := &ComposePlanResult{}
+	a, b, c := ComposePlanService{Name: "a"}, ComposePlanService{Name: "b"}, ComposePlanService{Name: "c"}
+	r.addCost(&a, 30, "USD")
+	r.addCost(&b, -10, "USD")
+	r.addCost(&c, 100, "CNY")
+	if r.CostDelta != 20 || r.Currency != "USD" || len(r.Warnings) != 1 {
+		t.Errorf("cost total = %v %s, warnings %v", r.CostDelta, r.Currency, r.Warnings)
+	}
+	if c.CostDelta != 100 || c.Currency != "CNY" {
+		t.Errorf("service cost should be kept: %+v", c)
+	}
+}
@@ -0,0 +1,117 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+
+	"red-cloud/i18n"
+	"red-cloud/mod"
+	"red-cloud/mod/gologger"
+)
+
+// compose up 失败时的处理策略
+const (
+	OnFailureKeep     = "keep"     // 保留已部署的服务 (默认)
+	OnFailureRollback = "rollback" // 按依赖逆序销毁本次创建的场景
+	OnFailurePause    = "pause"    // 停下等待操作者决定回滚或保留
+)
+
+// resolveOnFailure 命令行优先，其次为编排文件中的 on_failure，默认 keep
+func resolveOnFailure(file, opt string) (string, error) {
+	policy := opt
+	if policy == "" {
+		policy = file
+	}
+	switch policy {
+	case "":
+		return OnFailureKeep, nil
+	case OnFailureKeep, OnFailureRollback, OnFailurePause:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%s", i18n.Tf("compose_on_failure_invalid", policy))
+}
+
+// logTimeline 将编排事件写入时间线，未提供时间线时忽略
+func (ctx *ComposeContext) logTimeline(eventType string, svc *RuntimeService, msg, level string) {
+	if ctx.Timeline == nil {
+		return
+	}
+	var caseID, caseName string
+	if svc != nil {
+		caseName = svc.Name
+		if svc.CaseRef != nil {
+			caseID = svc.CaseRef.Id
+		}
+	}
+	ctx.Timeline.Log("compose", eventType, caseID, caseName, msg, ctx.StackName, level)
+}
+
+// handleUpFailure 按 on_failure 策略处理部署失败，返回值始终包含原始错误
+func (ctx *ComposeContext) handleUpFailure(cause error) error {
+	policy := ctx.OnFailure
+	if policy == OnFailurePause {
+		msg := i18n.Tf("compose_paused", ctx.StackName, cause)
+		gologger.Warning().Msgf("%s", msg)
+		ctx.emitLog(msg)
+		ctx.logTimeline("compose_paused", nil, msg, "warning")
+		if ctx.OnPause == nil || !ctx.OnPause(cause) {
+			ctx.paused = true
+			msg := i18n.Tf("compose_paused_kept", ctx.StackName, ctx.StackName)
+			gologger.Info().Msgf("%s", msg)
+			ctx.emitLog(msg)
+			return cause
+		}
+		policy = OnFailureRollback
+	}
+	if policy != OnFailureRollback {
+		return cause
+	}
+	if err := ctx.rollback(); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// rollback 销毁本次运行中创建的场景 (包括 apply 失败的场景)，顺序与 compose down 相同 (canDestroy)，
+// 成功销毁的场景随后删除。运行前已存在的场景不受影响
+func (ctx *ComposeContext) rollback() error {
+	kept := make(map[*RuntimeService]bool)
+	count := 0
+	for _, svc := range ctx.RuntimeSvcs {
+		if svc.Created && svc.CaseRef != nil {
+			svc.IsDeployed = true
+			count++
+			continue
+		}
+		if svc.IsDeployed {
+			kept[svc] = true
+		}
+		svc.IsDeployed = false
+	}
+	msg := i18n.Tf("compose_rollback_start", ctx.StackName, count)
+	gologger.Warning().Msgf("%s", msg)
+	ctx.emitLog(msg)
+	ctx.logTimeline("compose_rollback_started", nil, msg, "warning")
+
+	err := ctx.destroyServices(true)
+	for svc := range kept {
+		svc.IsDeployed = true
+	}
+	if err != nil {
+		msg := i18n.Tf("compose_rollback_failed", ctx.StackName, err)
+		gologger.Error().Msgf("%s", msg)
+		ctx.emitLog(msg)
+		ctx.logTimeline("compose_rollback_failed", nil, msg, "error")
+		return err
+	}
+	msg = i18n.Tf("compose_rollback_done", ctx.StackName, count)
+	gologger.Info().Msgf("%s", msg)
+	ctx.emitLog(msg)
+	ctx.logTimeline("compose_rolled_back", nil, msg, "success")
+	return nil
+}
+
+// neverApplied 场景停留在 Pending / Created，说明 apply 从未执行
+func neverApplied(c *mod.Case) bool {
+	return c.State == mod.StatePending || c.State == mod.StateCreated
+}
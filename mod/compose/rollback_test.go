@@ -0,0 +1,89 @@
+package compose
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"red-cloud/mod"
+)
+
+func TestResolveOnFailure(t *testing.T) {
+	cases := []struct {
+		file, opt, want string
+	}{
+		{"", "", OnFailureKeep},
+		{OnFailureRollback, "", OnFailureRollback},
+		{OnFailureRollback, OnFailurePause, OnFailurePause},
+	}
+	for _, c := range cases {
+		if got, err := resolveOnFailure(c.file, c.opt); err != nil || got != c.want {
+			t.Errorf("resolveOnFailure(%q, %q) = %q, %v", c.file, c.opt, got, err)
+		}
+	}
+	if _, err := resolveOnFailure("", "destroy"); err == nil {
+		t.Error("unknown policy should be rejected")
+	}
+}
+
+func TestHandleUpFailure(t *testing.T) {
+	cause := errors.New("apply failed")
+
+	ctx := testDAG(ParallelSpec{Max: 2})
+	ctx.OnFailure = OnFailurePause
+	if err := ctx.handleUpFailure(cause); !errors.Is(err, cause) || !ctx.paused {
+		t.Errorf("pause without a prompt should keep the stack: err = %v, paused = %v", err, ctx.paused)
+	}
+
+	// 回滚只处理本次创建的场景，运行前已部署的服务保持不变
+	ctx = testDAG(ParallelSpec{Max: 2})
+	ctx.OnFailure = OnFailurePause
+	ctx.RuntimeSvcs["a"].IsDeployed = true
+	var asked error
+	ctx.OnPause = func(err error) bool { asked = err; return true }
+	if err := ctx.handleUpFailure(cause); !errors.Is(err, cause) || ctx.paused {
+		t.Errorf("confirmed pause should roll back: err = %v, paused = %v", err, ctx.paused)
+	}
+	if asked != cause {
+		t.Errorf("OnPause should receive the failure, got %v", asked)
+	}
+	if !ctx.RuntimeSvcs["a"].IsDeployed || ctx.RuntimeSvcs["a"].RolledBack {
+		t.Error("pre-existing service must not be rolled back")
+	}
+}
+
+// apply 被拒绝的服务停留在 Created，回滚时不执行 terraform destroy，直接删除场景
+func TestRollbackNeverApplied(t *testing.T) {
+	mod.RedcPath = t.TempDir()
+	p := &mod.RedcProject{ProjectName: "default", ProjectPath: t.TempDir()}
+	c := &mod.Case{Id: "rb-denied", Name: "a", ProjectID: "default", State: mod.StateCreated, Path: t.TempDir()}
+	if err := p.AddCase(c); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := testDAG(ParallelSpec{Max: 2})
+	ctx.OnFailure = OnFailureRollback
+	svc := ctx.RuntimeSvcs["a"]
+	svc.CaseRef, svc.Created = c, true
+	cause := errors.New("policy denied")
+	if err := ctx.handleUpFailure(cause); err != cause {
+		t.Fatalf("rollback of a never-applied service should succeed, got %v", err)
+	}
+	if !svc.RolledBack || svc.IsDeployed {
+		t.Errorf("service should be rolled back: rolledBack = %v, deployed = %v", svc.RolledBack, svc.IsDeployed)
+	}
+	if _, err := os.Stat(c.Path); !os.IsNotExist(err) {
+		t.Errorf("case directory should be removed, stat err = %v", err)
+	}
+}
+
+func TestSetupFailureReturned(t *testing.T) {
+	ctx := testDAG(ParallelSpec{Max: 2})
+	tasks := []SetupTask{{Name: "login", Service: "a", Command: "login ${secrets.missing}"}}
+	if err := runSetupTasks(tasks, ctx.RuntimeSvcs, ctx); err == nil {
+		t.Fatal("failed setup task should be returned so on_failure applies")
+	}
+	if len(ctx.setupResults) != 1 || ctx.setupResults[0].Error == "" {
+		t.Errorf("failure should still be recorded: %+v", ctx.setupResults)
+	}
+}
@@ -0,0 +1,345 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"red-cloud/i18n"
+	"red-cloud/mod"
+	"red-cloud/mod/gologger"
+)
+
+// ComposeScaleResult compose scale 的结果
+type ComposeScaleResult struct {
+	Added   []string `json:"added"`   // 新部署的实例
+	Removed []string `json:"removed"` // 已销毁并删除的实例
+}
+
+// selectServices 只保留 names 指定的服务 (原始服务名或实例名)，up 时补充其传递依赖，
+// down 时补充依赖它们的服务。返回为满足依赖关系而额外包含的实例
+func (ctx *ComposeContext) selectServices(names []string, dependents bool) ([]*RuntimeService, error) {
+	selected := make(map[string]bool)
+	var queue []*RuntimeService
+	ctx.targets = make(map[string]bool)
+	for _, n := range names {
+		found := false
+		for _, key := range ctx.SortedSvcKeys {
+			svc := ctx.RuntimeSvcs[key]
+			if svc.Name != n && svc.RawName != n {
+				continue
+			}
+			found = true
+			ctx.targets[svc.RawName] = true
+			if !selected[svc.Name] {
+				selected[svc.Name] = true
+				queue = append(queue, svc)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s", i18n.Tf("compose_service_not_found", n))
+		}
+	}
+
+	var extra []*RuntimeService
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, key := range ctx.SortedSvcKeys {
+			svc := ctx.RuntimeSvcs[key]
+			if selected[svc.Name] {
+				continue
+			}
+			related := false
+			if dependents {
+				related = dependsOn(svc, cur.RawName)
+			} else {
+				related = dependsOn(cur, svc.RawName)
+			}
+			if related {
+				selected[svc.Name] = true
+				queue = append(queue, svc)
+				extra = append(extra, svc)
+			}
+		}
+	}
+
+	var keys []string
+	for _, key := range ctx.SortedSvcKeys {
+		if selected[key] {
+			keys = append(keys, key)
+		} else {
+			delete(ctx.RuntimeSvcs, key)
+		}
+	}
+	ctx.SortedSvcKeys = keys
+	if len(extra) > 0 {
+		var extraNames []string
+		for _, svc := range extra {
+			extraNames = append(extraNames, svc.Name)
+		}
+		key := "compose_services_with_deps"
+		if dependents {
+			key = "compose_services_with_dependents"
+		}
+		msg := i18n.Tf(key, strings.Join(names, ", "), strings.Join(extraNames, ", "))
+		gologger.Info().Msgf("%s", msg)
+		ctx.emitLog(msg)
+	}
+	return extra, nil
+}
+
+func dependsOn(svc *RuntimeService, raw string) bool {
+	for _, d := range svc.Spec.DependsOn {
+		if d == raw {
+			return true
+		}
+	}
+	return false
+}
+
+// adoptRunning 为满足依赖而包含的服务已有运行中的场景时直接使用，不重新部署
+func (ctx *ComposeContext) adoptRunning(svcs []*RuntimeService) {
+	for _, svc := range svcs {
+		c, err := ctx.getCase(svc.Name)
+		if err != nil || c.State != mod.StateRunning {
+			continue
+		}
+		svc.CaseRef = c
+		if rawOut, err := c.TfOutput(); err == nil {
+			svc.Outputs = parseTfOutput(rawOut)
+		}
+		svc.IsDeployed, svc.IsHealthy = true, true
+		msg := i18n.Tf("compose_dependency_running", svc.Name)
+		gologger.Info().Msgf("%s", msg)
+		ctx.emitLog(msg)
+	}
+}
+
+// tasksReferencing 返回目标为 raws 中的服务或在命令中引用其 outputs 的 setup 任务
+func tasksReferencing(tasks []SetupTask, raws map[string]bool) []SetupTask {
+	var res []SetupTask
+	for _, task := range tasks {
+		if raws[task.Service] {
+			res = append(res, task)
+			continue
+		}
+		for _, m := range outputRefRe.FindAllStringSubmatch(task.Command, -1) {
+			if raws[m[1]] {
+				res = append(res, task)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// alignReplicaNames 副本数在 1 与多个之间变化时实例名会增减 _1 后缀，已存在的实例沿用原名称
+func alignReplicaNames(desired []*RuntimeService, existing map[string]bool) {
+	for _, svc := range desired {
+		if existing[svc.Name] {
+			continue
+		}
+		alt := svc.Name + "_1"
+		if base, ok := strings.CutSuffix(svc.Name, "_1"); ok {
+			alt = base
+		}
+		if existing[alt] {
+			svc.Name = alt
+		}
+	}
+}
+
+// RunComposeScale 调整已部署编排中服务的副本数：只部署新增的实例、销毁并删除多余的实例，
+// 随后重新执行引用这些服务的 setup 任务。编排文件中的 replicas 不会被修改
+func RunComposeScale(opts ComposeOptions, replicas map[string]int) (*ComposeScaleResult, error) {
+	rec, err := mod.GetComposeStack(opts.Project.ProjectName, StackNameForFile(opts))
+	if err != nil {
+		return nil, err
+	}
+	scaleOpts := opts
+	scaleOpts.File, scaleOpts.Stack = rec.File, rec.Name
+	if len(scaleOpts.Profiles) == 0 {
+		scaleOpts.Profiles = rec.Profiles
+	}
+	ctx, err := NewComposeContext(scaleOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.loadSecrets(); err != nil {
+		return nil, err
+	}
+	ctx.targets = make(map[string]bool)
+
+	// 1. 计算每个服务的目标实例，与编排记录中的实例比较得出增减
+	recorded := make(map[string]bool)
+	for _, s := range rec.Services {
+		if s.CaseID != "" {
+			recorded[s.Name] = true
+		}
+	}
+	var removed []mod.ComposeStackService
+	raws := make([]string, 0, len(replicas))
+	for raw := range replicas {
+		raws = append(raws, raw)
+	}
+	sort.Strings(raws)
+	for _, raw := range raws {
+		n := replicas[raw]
+		if n < 1 {
+			return nil, fmt.Errorf("%s", i18n.Tf("compose_scale_invalid", raw, n))
+		}
+		spec, ok := ctx.ConfigRaw.Services[raw]
+		if !ok || !ctx.hasService(raw) {
+			return nil, fmt.Errorf("%s", i18n.Tf("compose_service_not_found", raw))
+		}
+		spec.Deploy.Replicas = n
+		spec.DependsOn = withOutputDeps(raw, spec)
+		desired := expandService(raw, spec)
+		alignReplicaNames(desired, recorded)
+
+		keep := make(map[string]bool)
+		for _, svc := range desired {
+			keep[svc.Name] = true
+		}
+		for _, s := range rec.Services {
+			if s.RawName == raw && !keep[s.Name] {
+				removed = append(removed, s)
+			}
+		}
+		for name, svc := range ctx.RuntimeSvcs {
+			if svc.RawName == raw {
+				delete(ctx.RuntimeSvcs, name)
+			}
+		}
+		for _, svc := range desired {
+			ctx.RuntimeSvcs[svc.Name] = svc
+		}
+		ctx.targets[raw] = true
+	}
+
+	// 2. 只保留已部署的实例与新增实例，已部署的实例从记录中的场景恢复状态
+	var added []*RuntimeService
+	for name, svc := range ctx.RuntimeSvcs {
+		s := rec.Service(name)
+		if s == nil || s.CaseID == "" {
+			if ctx.targets[svc.RawName] {
+				added = append(added, svc)
+			} else {
+				delete(ctx.RuntimeSvcs, name)
+			}
+			continue
+		}
+		if c, err := ctx.getCase(s.CaseID); err == nil {
+			svc.CaseRef = c
+			if rawOut, err := c.TfOutput(); err == nil {
+				svc.Outputs = parseTfOutput(rawOut)
+			}
+			svc.IsDeployed, svc.IsHealthy = true, true
+		} else if ctx.targets[svc.RawName] {
+			added = append(added, svc)
+		} else {
+			delete(ctx.RuntimeSvcs, name)
+		}
+	}
+	ctx.SortedSvcKeys = ctx.SortedSvcKeys[:0]
+	for name := range ctx.RuntimeSvcs {
+		ctx.SortedSvcKeys = append(ctx.SortedSvcKeys, name)
+	}
+	sort.Strings(ctx.SortedSvcKeys)
+	sort.Slice(added, func(i, j int) bool { return added[i].Name < added[j].Name })
+
+	result := &ComposeScaleResult{Added: []string{}, Removed: []string{}}
+	msg := i18n.Tf("compose_scale_start", ctx.StackName, len(added), len(removed))
+	gologger.Info().Msgf("%s", msg)
+	ctx.emitLog(msg)
+
+	// 3. 部署新增实例
+	failed := make(map[string]bool)
+	if len(added) > 0 {
+		runErr := ctx.runDAG(added,
+			func(svc *RuntimeService) bool { return canDeploy(svc, ctx.RuntimeSvcs) },
+			ctx.deployService,
+			func(svc *RuntimeService, err error) {
+				if err != nil {
+					failed[svc.Name] = true
+					gologger.Error().Msgf("%v", err)
+					ctx.emitLog(err.Error())
+					return
+				}
+				result.Added = append(result.Added, svc.Name)
+			},
+			ctx.logSkipped,
+		)
+		if runErr != nil {
+			if errors.Is(runErr, errDeadlock) {
+				runErr = fmt.Errorf("编排死锁: 存在循环依赖，或依赖的服务被 Profile 过滤未启动")
+			}
+			runErr = ctx.handleUpFailure(runErr)
+			ctx.recordStack(failed)
+			return result, runErr
+		}
+	}
+
+	// 4. 销毁多余实例，依赖这些服务的其他实例保持不变
+	if len(removed) > 0 {
+		names, err := ctx.removeInstances(removed)
+		result.Removed = names
+		if err != nil {
+			ctx.recordStack(failed)
+			return result, err
+		}
+	}
+
+	// 5. 重新执行引用被调整服务的 setup 任务
+	if tasks := tasksReferencing(ctx.ConfigRaw.Setup, ctx.targets); len(tasks) > 0 {
+		msg := i18n.T("compose_setup_start")
+		gologger.Info().Msg(msg)
+		ctx.emitLog(msg)
+		if err := runSetupTasks(tasks, ctx.RuntimeSvcs, ctx); err != nil {
+			err = ctx.handleUpFailure(err)
+			ctx.recordStack(failed)
+			return result, err
+		}
+	}
+	ctx.recordStack(failed)
+	msg = i18n.Tf("compose_scale_done", ctx.StackName, len(result.Added), len(result.Removed))
+	gologger.Info().Msgf("%s", msg)
+	ctx.emitLog(msg)
+	return result, nil
+}
+
+func (ctx *ComposeContext) hasService(raw string) bool {
+	for _, svc := range ctx.RuntimeSvcs {
+		if svc.RawName == raw {
+			return true
+		}
+	}
+	return false
+}
+
+// removeInstances 按记录销毁缩容掉的实例并删除其场景，返回已删除的实例名
+func (ctx *ComposeContext) removeInstances(entries []mod.ComposeStackService) ([]string, error) {
+	rm, err := contextFromStack(&mod.ComposeStack{Name: ctx.StackName, Services: entries}, ComposeOptions{Project: ctx.Project, LogCallback: ctx.LogCallback})
+	if err != nil {
+		return nil, err
+	}
+	rm.Parallel = ctx.Parallel
+	destroyErr := rm.destroyServices(false)
+	names := []string{}
+	for _, name := range rm.SortedSvcKeys {
+		svc := rm.RuntimeSvcs[name]
+		if svc.IsDeployed {
+			continue
+		}
+		if svc.CaseRef != nil {
+			if err := svc.CaseRef.Remove(); err != nil {
+				gologger.Warning().Msgf("%s", i18n.Tf("compose_scale_remove_failed", svc.Name, err))
+			}
+		}
+		names = append(names, name)
+	}
+	ctx.removed = append(ctx.removed, names...)
+	return names, destroyErr
+}
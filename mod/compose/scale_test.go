@@ -0,0 +1,78 @@
+package compose
+
+import (
+	"sort"
+	"testing"
+
+	"red-cloud/mod"
+)
+
+func TestSelectServices(t *testing.T) {
+	ctx := testDAG(ParallelSpec{Max: 2})
+	extra, err := ctx.selectServices([]string{"d"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extra) != 1 || extra[0].Name != "a" {
+		t.Errorf("up d should pull in its dependency a, extra = %v", extra)
+	}
+	if len(ctx.SortedSvcKeys) != 2 || len(ctx.RuntimeSvcs) != 2 || !ctx.targets["d"] || ctx.targets["a"] {
+		t.Errorf("selected = %v, targets = %v", ctx.SortedSvcKeys, ctx.targets)
+	}
+
+	ctx = testDAG(ParallelSpec{Max: 2})
+	if _, err := ctx.selectServices([]string{"a"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.SortedSvcKeys) != 2 || ctx.SortedSvcKeys[0] != "a" || ctx.SortedSvcKeys[1] != "d" {
+		t.Errorf("down a should include its dependent d, selected = %v", ctx.SortedSvcKeys)
+	}
+
+	if _, err := testDAG(ParallelSpec{Max: 2}).selectServices([]string{"missing"}, false); err == nil {
+		t.Error("unknown service should be rejected")
+	}
+}
+
+func TestAlignReplicaNames(t *testing.T) {
+	spec := ServiceSpec{Image: "aliyun/ecs", Deploy: DeploySpec{Replicas: 3}}
+	desired := expandService("scanner", spec)
+	alignReplicaNames(desired, map[string]bool{"scanner": true})
+	var names []string
+	for _, svc := range desired {
+		names = append(names, svc.Name)
+	}
+	sort.Strings(names)
+	if names[0] != "scanner" || names[1] != "scanner_2" || names[2] != "scanner_3" {
+		t.Errorf("scaling up from 1 should keep the existing instance name: %v", names)
+	}
+
+	spec.Deploy.Replicas = 1
+	desired = expandService("scanner", spec)
+	alignReplicaNames(desired, map[string]bool{"scanner_1": true, "scanner_2": true})
+	if desired[0].Name != "scanner_1" {
+		t.Errorf("scaling down to 1 should keep scanner_1, got %s", desired[0].Name)
+	}
+}
+
+func TestTasksReferencing(t *testing.T) {
+	tasks := []SetupTask{
+		{Name: "register", Service: "teamserver", Command: "add ${scanner.outputs.ip}"},
+		{Name: "init", Service: "scanner", Command: "init"},
+		{Name: "other", Service: "proxy", Command: "echo ${teamserver.outputs.ip}"},
+	}
+	got := tasksReferencing(tasks, map[string]bool{"scanner": true})
+	if len(got) != 2 || got[0].Name != "register" || got[1].Name != "init" {
+		t.Errorf("tasksReferencing = %+v", got)
+	}
+}
+
+func TestMergeSetupResults(t *testing.T) {
+	old := []mod.ComposeSetupResult{
+		{Task: "init", Service: "scanner_1", Output: "old"},
+		{Task: "init", Service: "proxy", Output: "keep"},
+	}
+	got := mergeSetupResults(old, []mod.ComposeSetupResult{{Task: "init", Service: "scanner_1", Output: "new"}})
+	if len(got) != 2 || got[0].Output != "keep" || got[1].Output != "new" {
+		t.Errorf("mergeSetupResults = %+v", got)
+	}
+}
@@ -0,0 +1,144 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+)
+
+// DefaultParallelism 未配置 parallel.max 时同时部署/销毁的服务数
+const DefaultParallelism = 4
+
+// ParallelSpec 并发调度配置 (parallel 块)
+type ParallelSpec struct {
+	Max             int            `yaml:"max,omitempty"`               // 同时执行的服务数，1 为串行，默认 4
+	Providers       map[string]int `yaml:"providers,omitempty"`         // 按云厂商 (image 前缀，如 aliyun) 或 provider 别名限制并发
+	ContinueOnError bool           `yaml:"continue_on_error,omitempty"` // 出错后继续执行不受影响的服务，默认 fail-fast
+}
+
+// resolveParallel 合并编排文件与命令行的并发配置，命令行优先
+func resolveParallel(file ParallelSpec, opts ComposeOptions) (ParallelSpec, error) {
+	res := ParallelSpec{Max: file.Max, Providers: map[string]int{}, ContinueOnError: file.ContinueOnError || opts.ContinueOnError}
+	if opts.Parallelism > 0 {
+		res.Max = opts.Parallelism
+	}
+	if res.Max < 0 {
+		return res, fmt.Errorf("%s", i18n.Tf("compose_parallel_invalid", "max", res.Max))
+	}
+	if res.Max == 0 {
+		res.Max = DefaultParallelism
+	}
+	for k, v := range file.Providers {
+		res.Providers[k] = v
+	}
+	for k, v := range opts.ProviderLimits {
+		res.Providers[k] = v
+	}
+	for k, v := range res.Providers {
+		if v <= 0 {
+			return res, fmt.Errorf("%s", i18n.Tf("compose_parallel_invalid", k, v))
+		}
+	}
+	return res, nil
+}
+
+// limitKeys 返回服务占用的并发限制 key：image 的云厂商前缀与 provider 别名
+func (ctx *ComposeContext) limitKeys(svc *RuntimeService) []string {
+	var keys []string
+	if cloud, _, ok := strings.Cut(strings.TrimPrefix(svc.Spec.Image, "./"), "/"); ok {
+		if _, limited := ctx.Parallel.Providers[cloud]; limited {
+			keys = append(keys, cloud)
+		}
+	}
+	if alias, ok := svc.Spec.Provider.(string); ok && alias != "" {
+		if _, limited := ctx.Parallel.Providers[alias]; limited && (len(keys) == 0 || keys[0] != alias) {
+			keys = append(keys, alias)
+		}
+	}
+	return keys
+}
+
+// runDAG 并发执行 pending 中的服务：ready 在持有 ctx.mu 时判断依赖是否满足，满足且有空闲并发槽位的服务立即在新 goroutine 中执行 work。
+// fail-fast 模式下首个错误出现后不再启动新服务，只等待已启动的服务结束；continue 模式下继续执行与失败服务无关的服务。
+// 因依赖失败而无法执行的服务通过 skipped 回调报告，返回所有错误的合并结果
+func (ctx *ComposeContext) runDAG(pending []*RuntimeService, ready func(*RuntimeService) bool, work func(*RuntimeService) error, finished func(svc *RuntimeService, err error), skipped func(*RuntimeService)) error {
+	type result struct {
+		svc *RuntimeService
+		err error
+	}
+	done := make(chan result)
+	running := 0
+	inUse := make(map[string]int)
+	held := make(map[*RuntimeService][]string)
+	var errs []error
+
+	for {
+		ctx.mu.Lock()
+		if len(errs) == 0 || ctx.Parallel.ContinueOnError {
+			for i := 0; i < len(pending) && running < ctx.Parallel.Max; {
+				svc := pending[i]
+				keys := ctx.limitKeys(svc)
+				if !ready(svc) || !slotsFree(keys, inUse, ctx.Parallel.Providers) {
+					i++
+					continue
+				}
+				for _, k := range keys {
+					inUse[k]++
+				}
+				held[svc] = keys
+				running++
+				pending = append(pending[:i], pending[i+1:]...)
+				go func(svc *RuntimeService) {
+					done <- result{svc, work(svc)}
+				}(svc)
+			}
+		}
+		ctx.mu.Unlock()
+
+		if running == 0 {
+			break
+		}
+		r := <-done
+		running--
+		for _, k := range held[r.svc] {
+			inUse[k]--
+		}
+		delete(held, r.svc)
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+		finished(r.svc, r.err)
+	}
+
+	if len(pending) > 0 {
+		if len(errs) == 0 {
+			return errDeadlock
+		}
+		for _, svc := range pending {
+			skipped(svc)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// errDeadlock 由调用方替换为具体的部署/销毁死锁错误
+var errDeadlock = errors.New("deadlock")
+
+func slotsFree(keys []string, inUse map[string]int, limits map[string]int) bool {
+	for _, k := range keys {
+		if inUse[k] >= limits[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// logSkipped 报告因依赖失败而未执行的服务
+func (ctx *ComposeContext) logSkipped(svc *RuntimeService) {
+	msg := i18n.Tf("compose_service_skipped", svc.Name)
+	gologger.Warning().Msgf("%s", msg)
+	ctx.emitLog(msg)
+}
@@ -0,0 +1,169 @@
+package compose
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDAG 构造服务 a, b, c (无依赖) 和 d (依赖 a)
+func testDAG(parallel ParallelSpec) *ComposeContext {
+	ctx := &ComposeContext{RuntimeSvcs: map[string]*RuntimeService{}, Parallel: parallel}
+	for _, s := range []*RuntimeService{
+		{Name: "a", RawName: "a", Spec: ServiceSpec{Image: "aliyun/ecs"}},
+		{Name: "b", RawName: "b", Spec: ServiceSpec{Image: "aliyun/ecs"}},
+		{Name: "c", RawName: "c", Spec: ServiceSpec{Image: "aws/ec2"}},
+		{Name: "d", RawName: "d", Spec: ServiceSpec{Image: "aws/ec2", DependsOn: []string{"a"}}},
+	} {
+		ctx.RuntimeSvcs[s.Name] = s
+		ctx.SortedSvcKeys = append(ctx.SortedSvcKeys, s.Name)
+	}
+	return ctx
+}
+
+// runUp 模拟部署，fail 中的服务返回错误，返回执行过的服务、跳过的服务、最大并发数和 aliyun 最大并发数
+func runUp(ctx *ComposeContext, fail string) (ran, skipped []string, peak, aliyunPeak int, err error) {
+	var mu sync.Mutex
+	cur, aliyun := 0, 0
+	var pending []*RuntimeService
+	for _, k := range ctx.SortedSvcKeys {
+		pending = append(pending, ctx.RuntimeSvcs[k])
+	}
+	err = ctx.runDAG(pending,
+		func(svc *RuntimeService) bool { return canDeploy(svc, ctx.RuntimeSvcs) },
+		func(svc *RuntimeService) error {
+			mu.Lock()
+			cur++
+			peak = max(peak, cur)
+			if svc.Spec.Image == "aliyun/ecs" {
+				aliyun++
+				aliyunPeak = max(aliyunPeak, aliyun)
+			}
+			ran = append(ran, svc.Name)
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			cur--
+			if svc.Spec.Image == "aliyun/ecs" {
+				aliyun--
+			}
+			mu.Unlock()
+			if svc.Name == fail {
+				return errors.New("apply failed")
+			}
+			ctx.mu.Lock()
+			svc.IsDeployed, svc.IsHealthy = true, true
+			ctx.mu.Unlock()
+			return nil
+		},
+		func(*RuntimeService, error) {},
+		func(svc *RuntimeService) { skipped = append(skipped, svc.Name) },
+	)
+	sort.Strings(ran)
+	return
+}
+
+func TestRunDAGParallel(t *testing.T) {
+	ran, _, peak, _, err := runUp(testDAG(ParallelSpec{Max: 4}), "")
+	if err != nil || len(ran) != 4 {
+		t.Fatalf("ran = %v, err = %v", ran, err)
+	}
+	if peak != 3 {
+		t.Errorf("a, b and c should run concurrently, peak = %d", peak)
+	}
+	if _, _, peak, _, _ = runUp(testDAG(ParallelSpec{Max: 1}), ""); peak != 1 {
+		t.Errorf("max 1 should run sequentially, peak = %d", peak)
+	}
+	_, _, _, aliyunPeak, _ := runUp(testDAG(ParallelSpec{Max: 4, Providers: map[string]int{"aliyun": 1}}), "")
+	if aliyunPeak != 1 {
+		t.Errorf("aliyun limit 1 violated, peak = %d", aliyunPeak)
+	}
+}
+
+func TestRunDAGFailFast(t *testing.T) {
+	ran, skipped, _, _, err := runUp(testDAG(ParallelSpec{Max: 1}), "a")
+	if err == nil {
+		t.Fatal("failure should be returned")
+	}
+	if len(ran) != 1 || len(skipped) != 3 {
+		t.Errorf("fail-fast should stop after a: ran = %v, skipped = %v", ran, skipped)
+	}
+}
+
+func TestRunDAGContinueOnError(t *testing.T) {
+	ran, skipped, _, _, err := runUp(testDAG(ParallelSpec{Max: 1, ContinueOnError: true}), "a")
+	if err == nil {
+		t.Fatal("failure should be returned")
+	}
+	if len(ran) != 3 || len(skipped) != 1 || skipped[0] != "d" {
+		t.Errorf("only the dependent of a should be skipped: ran = %v, skipped = %v", ran, skipped)
+	}
+}
+
+func TestRunDAGDownKeepsDependencies(t *testing.T) {
+	ctx := testDAG(ParallelSpec{Max: 4, ContinueOnError: true})
+	var pending []*RuntimeService
+	for _, svc := range ctx.RuntimeSvcs {
+		svc.IsDeployed = true
+		pending = append(pending, svc)
+	}
+	var skipped []string
+	err := ctx.runDAG(pending,
+		func(svc *RuntimeService) bool { return canDestroy(svc, ctx.RuntimeSvcs) },
+		func(svc *RuntimeService) error {
+			if svc.Name == "d" {
+				return errors.New("destroy failed")
+			}
+			ctx.mu.Lock()
+			svc.IsDeployed = false
+			ctx.mu.Unlock()
+			return nil
+		},
+		func(*RuntimeService, error) {},
+		func(svc *RuntimeService) { skipped = append(skipped, svc.Name) },
+	)
+	if err == nil || len(skipped) != 1 || skipped[0] != "a" || !ctx.RuntimeSvcs["a"].IsDeployed {
+		t.Errorf("a must not be destroyed while d still exists: skipped = %v, err = %v", skipped, err)
+	}
+	if ctx.RuntimeSvcs["b"].IsDeployed || ctx.RuntimeSvcs["c"].IsDeployed {
+		t.Error("unrelated services should still be destroyed with continue_on_error")
+	}
+}
+
+func TestRunDAGDeadlock(t *testing.T) {
+	ctx := testDAG(ParallelSpec{Max: 2})
+	ctx.RuntimeSvcs["a"].Spec.DependsOn = []string{"d"}
+	if _, _, _, _, err := runUp(ctx, ""); !errors.Is(err, errDeadlock) {
+		t.Errorf("cycle should be reported as deadlock, got %v", err)
+	}
+}
+
+func TestResolveParallel(t *testing.T) {
+	file := ParallelSpec{Max: 2, Providers: map[string]int{"aliyun": 1, "aws": 3}}
+	got, err := resolveParallel(file, ComposeOptions{Parallelism: 8, ProviderLimits: map[string]int{"aws": 2}, ContinueOnError: true})
+	if err != nil || got.Max != 8 || got.Providers["aliyun"] != 1 || got.Providers["aws"] != 2 || !got.ContinueOnError {
+		t.Errorf("resolveParallel = %+v, %v", got, err)
+	}
+	if got, _ := resolveParallel(ParallelSpec{}, ComposeOptions{}); got.Max != DefaultParallelism {
+		t.Errorf("default max = %d", got.Max)
+	}
+	if _, err := resolveParallel(ParallelSpec{Providers: map[string]int{"aws": 0}}, ComposeOptions{}); err == nil {
+		t.Error("non-positive provider limit should be rejected")
+	}
+}
+
+func TestWithOutputDeps(t *testing.T) {
+	spec := ServiceSpec{
+		DependsOn:   []string{"teamserver"},
+		Environment: []string{"ts_ip=${teamserver.outputs.public_ip}", "c2=${c2.outputs.ip}:${redirector.outputs.port}"},
+	}
+	got := withOutputDeps("redirector", spec)
+	if len(got) != 2 || got[0] != "teamserver" || got[1] != "c2" {
+		t.Errorf("withOutputDeps = %v", got)
+	}
+	if len(spec.DependsOn) != 1 {
+		t.Error("original depends_on must not be modified")
+	}
+}
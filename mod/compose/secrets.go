@@ -0,0 +1,248 @@
+package compose
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"red-cloud/i18n"
+	"red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
+)
+
+// SecretItem 编排 secret (secrets 块)，只能指定一种来源
+type SecretItem struct {
+	File        string        `yaml:"file,omitempty"`        // 读取本地文件内容，相对路径基于声明它的编排文件
+	Environment string        `yaml:"environment,omitempty"` // 读取环境变量 (包括 .env)
+	Encrypted   string        `yaml:"encrypted,omitempty"`   // redc cred encrypt 生成的密文，使用凭据存储的主密钥解密
+	Generate    *GenerateSpec `yaml:"generate,omitempty"`    // 首次使用时随机生成，随编排保存并在之后复用
+}
+
+// GenerateSpec 随机生成的 secret
+type GenerateSpec struct {
+	Length  int  `yaml:"length,omitempty"`  // 长度，默认 24
+	Special bool `yaml:"special,omitempty"` // 包含特殊字符
+}
+
+const (
+	defaultSecretLength = 24
+	maxSecretLength     = 128
+	secretLower         = "abcdefghijklmnopqrstuvwxyz"
+	secretUpper         = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	secretDigits        = "0123456789"
+	secretSpecial       = "!@#%^*-_=+"
+)
+
+// secretRefRe 匹配 environment、command 与 setup 命令中的 ${secrets.name}
+var secretRefRe = regexp.MustCompile(`\$\{secrets\.([^}]+)\}`)
+
+// secretMapping 解析服务 secrets 中的 "tf_var=secret" 或 "secret" (变量名与 secret 同名)
+func secretMapping(ref string) (tfVar, name string) {
+	if k, v, ok := strings.Cut(ref, "="); ok {
+		return k, v
+	}
+	return ref, ref
+}
+
+// secretVolume 解析 volumes 中的 "secret:name:/remote/path"，将 secret 写入远程文件
+func secretVolume(vol string) (name, remotePath string, ok bool) {
+	rest, ok := strings.CutPrefix(vol, "secret:")
+	if !ok {
+		return "", "", false
+	}
+	name, remotePath, ok = strings.Cut(rest, ":")
+	return name, remotePath, ok && name != "" && remotePath != ""
+}
+
+// serviceSecretRefs 返回服务引用的 secret 名称
+func serviceSecretRefs(spec ServiceSpec) []string {
+	var refs []string
+	for _, ref := range spec.Secrets {
+		_, name := secretMapping(ref)
+		refs = append(refs, name)
+	}
+	texts := append([]string{spec.Command}, spec.Environment...)
+	for _, t := range texts {
+		for _, m := range secretRefRe.FindAllStringSubmatch(t, -1) {
+			refs = append(refs, m[1])
+		}
+	}
+	for _, vol := range spec.Volumes {
+		if name, _, ok := secretVolume(vol); ok {
+			refs = append(refs, name)
+		}
+	}
+	return refs
+}
+
+// validateSecrets 检查每个 secret 只有一种来源，服务与 setup 任务引用的 secret 均已定义。不会读取任何值
+func validateSecrets(cfg ComposeConfig) error {
+	names := make([]string, 0, len(cfg.Secrets))
+	for name := range cfg.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		item := cfg.Secrets[name]
+		sources := 0
+		for _, set := range []bool{item.File != "", item.Environment != "", item.Encrypted != "", item.Generate != nil} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("%s", i18n.Tf("compose_secret_source", name))
+		}
+		if g := item.Generate; g != nil && g.Length != 0 && (g.Length < secret.MinLength || g.Length > maxSecretLength) {
+			return fmt.Errorf("%s", i18n.Tf("compose_secret_length", name, g.Length, secret.MinLength, maxSecretLength))
+		}
+	}
+
+	check := func(refs []string, where string) error {
+		for _, ref := range refs {
+			if _, ok := cfg.Secrets[ref]; !ok {
+				return fmt.Errorf("%s", i18n.Tf("compose_secret_undefined", ref, where))
+			}
+		}
+		return nil
+	}
+	for section, specs := range map[string]map[string]ServiceSpec{"services": cfg.Services, "plugins": cfg.Plugins} {
+		for name, spec := range specs {
+			if err := check(serviceSecretRefs(spec), section+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, task := range cfg.Setup {
+		var refs []string
+		for _, m := range secretRefRe.FindAllStringSubmatch(task.Command, -1) {
+			refs = append(refs, m[1])
+		}
+		if err := check(refs, "setup."+task.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSecrets 读取所有 secret 的值并登记脱敏，之后日志、预览、编排记录和 MCP 输出中的这些值都会被遮蔽。
+// 只在 up / scale / plan 前调用，预览编排不需要解锁凭据存储
+func (ctx *ComposeContext) loadSecrets() error {
+	if ctx.secrets != nil {
+		return nil
+	}
+	names := make([]string, 0, len(ctx.ConfigRaw.Secrets))
+	for name := range ctx.ConfigRaw.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	vals := make(map[string]string, len(names))
+	for _, name := range names {
+		v, err := ctx.resolveSecret(name, ctx.ConfigRaw.Secrets[name])
+		if err != nil {
+			return fmt.Errorf("%s", i18n.Tf("compose_secret_failed", name, err))
+		}
+		if len(v) < secret.MinLength {
+			gologger.Warning().Msgf("%s", i18n.Tf("compose_secret_too_short", name, secret.MinLength))
+		}
+		secret.Register(v)
+		vals[name] = v
+	}
+	ctx.secrets = vals
+	return nil
+}
+
+func (ctx *ComposeContext) resolveSecret(name string, item SecretItem) (string, error) {
+	switch {
+	case item.File != "":
+		b, err := os.ReadFile(item.File)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	case item.Environment != "":
+		if v, ok := os.LookupEnv(item.Environment); ok {
+			return v, nil
+		}
+		if v, ok := ctx.dotEnv[item.Environment]; ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("%s", i18n.Tf("compose_secret_env_unset", item.Environment))
+	case item.Encrypted != "":
+		return mod.OpenSecret(mod.ActiveConfigPath, item.Encrypted)
+	}
+	pid := ctx.Project.ProjectName
+	v, err := mod.GetComposeSecret(pid, ctx.StackName, name)
+	if err != nil || v != "" {
+		return v, err
+	}
+	if v, err = generateSecret(item.Generate); err != nil {
+		return "", err
+	}
+	gologger.Info().Msgf("%s", i18n.Tf("compose_secret_generated", name, ctx.StackName))
+	return v, mod.SaveComposeSecret(pid, ctx.StackName, name, v)
+}
+
+// generateSecret 生成随机值，至少包含大小写字母和数字 (开启 special 时还包含特殊字符)，满足常见云主机密码规则
+func generateSecret(spec *GenerateSpec) (string, error) {
+	length := spec.Length
+	if length == 0 {
+		length = defaultSecretLength
+	}
+	classes := []string{secretLower, secretUpper, secretDigits}
+	if spec.Special {
+		classes = append(classes, secretSpecial)
+	}
+	charset := strings.Join(classes, "")
+	for {
+		buf := make([]byte, length)
+		for i := range buf {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+			if err != nil {
+				return "", err
+			}
+			buf[i] = charset[n.Int64()]
+		}
+		v := string(buf)
+		complete := true
+		for _, c := range classes {
+			if !strings.ContainsAny(v, c) {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			return v, nil
+		}
+	}
+}
+
+// expandSecrets 将 ${secrets.name} 替换为 secret 的值，需要先调用 loadSecrets
+func (ctx *ComposeContext) expandSecrets(s string) (string, error) {
+	var missing string
+	out := secretRefRe.ReplaceAllStringFunc(s, func(m string) string {
+		name := secretRefRe.FindStringSubmatch(m)[1]
+		v, ok := ctx.secrets[name]
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return m
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("%s", i18n.Tf("compose_secret_not_loaded", missing))
+	}
+	return out, nil
+}
+
+// previewSecrets 预览时以占位符代替 ${secrets.name}
+func previewSecrets(s string) string {
+	return secretRefRe.ReplaceAllString(s, "<Secret: $1>")
+}
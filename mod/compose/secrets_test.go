@@ -0,0 +1,163 @@
+package compose
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"red-cloud/mod/secret"
+)
+
+func TestValidateSecrets(t *testing.T) {
+	base := func() ComposeConfig {
+		return ComposeConfig{
+			Secrets: map[string]SecretItem{
+				"license": {Environment: "CS_LICENSE"},
+				"ts_pass": {Generate: &GenerateSpec{}},
+			},
+			Services: map[string]ServiceSpec{
+				"teamserver": {
+					Image:       "aliyun/ecs",
+					Secrets:     []string{"password=ts_pass"},
+					Environment: []string{"license=${secrets.license}"},
+					Volumes:     []string{"secret:license:/opt/cs/license"},
+				},
+			},
+			Setup: []SetupTask{{Name: "start", Service: "teamserver", Command: "./teamserver ${teamserver.outputs.ip} ${secrets.ts_pass}"}},
+		}
+	}
+	if err := validateSecrets(base()); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	cfg := base()
+	cfg.Secrets["license"] = SecretItem{Environment: "CS_LICENSE", File: "license.txt"}
+	if err := validateSecrets(cfg); err == nil || !strings.Contains(err.Error(), "license") {
+		t.Errorf("multiple sources should be rejected, got %v", err)
+	}
+
+	cfg = base()
+	cfg.Secrets["ts_pass"] = SecretItem{Generate: &GenerateSpec{Length: 4}}
+	if err := validateSecrets(cfg); err == nil {
+		t.Error("too short generated secret should be rejected")
+	}
+
+	for _, mutate := range []func(*ComposeConfig){
+		func(c *ComposeConfig) { c.Setup[0].Command = "echo ${secrets.missing}" },
+		func(c *ComposeConfig) {
+			svc := c.Services["teamserver"]
+			svc.Volumes = []string{"secret:missing:/tmp/x"}
+			c.Services["teamserver"] = svc
+		},
+		func(c *ComposeConfig) {
+			svc := c.Services["teamserver"]
+			svc.Secrets = []string{"missing"}
+			c.Services["teamserver"] = svc
+		},
+	} {
+		cfg := base()
+		mutate(&cfg)
+		if err := validateSecrets(cfg); err == nil || !strings.Contains(err.Error(), "missing") {
+			t.Errorf("undefined secret should be rejected, got %v", err)
+		}
+	}
+}
+
+func TestSecretRefs(t *testing.T) {
+	if k, v := secretMapping("password=ts_pass"); k != "password" || v != "ts_pass" {
+		t.Errorf("secretMapping = %q %q", k, v)
+	}
+	if k, v := secretMapping("ts_pass"); k != "ts_pass" || v != "ts_pass" {
+		t.Errorf("secretMapping = %q %q", k, v)
+	}
+	if name, path, ok := secretVolume("secret:license:/opt/cs/license"); !ok || name != "license" || path != "/opt/cs/license" {
+		t.Errorf("secretVolume = %q %q %v", name, path, ok)
+	}
+	for _, vol := range []string{"./init.sh:/root/init.sh", "secret:license", "secret::/tmp/x"} {
+		if _, _, ok := secretVolume(vol); ok {
+			t.Errorf("secretVolume(%q) should not match", vol)
+		}
+	}
+
+	ctx := &ComposeContext{secrets: map[string]string{"ts_pass": "Zx9-teamserver"}}
+	got, err := ctx.expandSecrets("./teamserver ${teamserver.outputs.ip} ${secrets.ts_pass}")
+	if err != nil || got != "./teamserver ${teamserver.outputs.ip} Zx9-teamserver" {
+		t.Errorf("expandSecrets = %q, %v", got, err)
+	}
+	if _, err := ctx.expandSecrets("${secrets.other}"); err == nil {
+		t.Error("unloaded secret should be an error")
+	}
+	if got := previewSecrets("pass=${secrets.ts_pass}"); got != "pass=<Secret: ts_pass>" {
+		t.Errorf("previewSecrets = %q", got)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	v, err := generateSecret(&GenerateSpec{})
+	if err != nil || len(v) != defaultSecretLength {
+		t.Fatalf("generateSecret = %q, %v", v, err)
+	}
+	for _, class := range []string{secretLower, secretUpper, secretDigits} {
+		if !strings.ContainsAny(v, class) {
+			t.Errorf("%q should contain one of %q", v, class)
+		}
+	}
+	v, _ = generateSecret(&GenerateSpec{Length: 8, Special: true})
+	if len(v) != 8 || !strings.ContainsAny(v, secretSpecial) {
+		t.Errorf("special secret = %q", v)
+	}
+}
+
+func TestLoadSecrets(t *testing.T) {
+	t.Setenv("REDC_TEST_LICENSE", "license-from-env")
+	dir := writeFiles(t, map[string]string{
+		".env":                "TS_PASS=dotenv-password\n",
+		"keys/webhook.txt":    "webhook-token-1234\n",
+		"infra/redc-sub.yaml": "secrets:\n  webhook:\n    file: ../keys/webhook.txt\n",
+		"redc-compose.yaml": `
+include: [infra/redc-sub.yaml]
+secrets:
+  license:
+    environment: REDC_TEST_LICENSE
+  ts_pass:
+    environment: TS_PASS
+services:
+  teamserver:
+    image: aliyun/ecs
+    secrets: [password=ts_pass]
+`,
+	})
+	loaded, err := loadCompose(filepath.Join(dir, "redc-compose.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := loaded.Config.Secrets["webhook"].File; f != filepath.Join(dir, "keys", "webhook.txt") {
+		t.Errorf("secret file should be relative to the declaring file: %q", f)
+	}
+
+	ctx := &ComposeContext{ConfigRaw: loaded.Config, dotEnv: loaded.DotEnv}
+	if err := ctx.loadSecrets(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"license": "license-from-env", "ts_pass": "dotenv-password", "webhook": "webhook-token-1234"}
+	for name, v := range want {
+		if ctx.secrets[name] != v {
+			t.Errorf("secret %s = %q, want %q", name, ctx.secrets[name], v)
+		}
+		if !secret.Registered(v) {
+			t.Errorf("secret %s should be registered for masking", name)
+		}
+	}
+	if got := secret.Redact("login with dotenv-password"); strings.Contains(got, "dotenv-password") {
+		t.Errorf("secret value leaked: %q", got)
+	}
+
+	svc := &RuntimeService{Name: "teamserver", RawName: "teamserver", Spec: loaded.Config.Services["teamserver"]}
+	vars, err := serviceTfVars(svc, ctx)
+	if err != nil || vars["password"] != "dotenv-password" {
+		t.Errorf("serviceTfVars = %v, %v", vars, err)
+	}
+	if p := previewTfVars(svc, ctx); p["password"] != "<Secret: ts_pass>" {
+		t.Errorf("preview should not show the value: %v", p)
+	}
+}
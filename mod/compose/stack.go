@@ -0,0 +1,278 @@
+package compose
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"red-cloud/i18n"
+	"red-cloud/mod"
+	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
+)
+
+// 编排文件相对于记录的状态
+const (
+	StackFileUnchanged = "unchanged"
+	StackFileModified  = "modified"
+	StackFileMissing   = "missing"
+)
+
+// StackInfo compose ps 展示的编排记录及其场景的当前状态
+type StackInfo struct {
+	*mod.ComposeStack
+	FileStatus string            `json:"fileStatus"` // unchanged | modified | missing
+	States     map[string]string `json:"states"`     // 服务实例 -> 场景当前状态，场景已删除时为 missing
+}
+
+// stackName 编排名称：显式指定 > 编排文件中的 name > 编排文件所在目录名
+func stackName(explicit, fromFile, absFile string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if fromFile != "" {
+		return fromFile
+	}
+	return filepath.Base(filepath.Dir(absFile))
+}
+
+// StackNameForFile 返回编排文件对应的编排名称，文件不存在或无法解析时使用目录名
+func StackNameForFile(opts ComposeOptions) string {
+	if opts.Stack != "" {
+		return opts.Stack
+	}
+	absFile, _ := filepath.Abs(opts.File)
+	var name string
+	if loaded, err := loadCompose(opts.File); err == nil {
+		name = loaded.Config.Name
+	}
+	return stackName("", name, absFile)
+}
+
+// maskedOutputs 记录中的敏感 output 以占位符保存
+func maskedOutputs(outputs map[string]interface{}) map[string]interface{} {
+	if len(outputs) == 0 {
+		return nil
+	}
+	res := make(map[string]interface{}, len(outputs))
+	for k, v := range outputs {
+		if secret.IsSensitiveName(k) || secret.Registered(fmt.Sprint(v)) {
+			res[k] = secret.Mask
+		} else {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// recordStack 将本次 up 的结果合并到编排记录：本次跳过的服务保留原有记录，
+// 其他 Profile 部署的服务不受影响。记录失败不影响部署结果
+func (ctx *ComposeContext) recordStack(failed map[string]bool) {
+	pid := ctx.Project.ProjectName
+	rec, err := mod.GetComposeStack(pid, ctx.StackName)
+	if err != nil {
+		rec = &mod.ComposeStack{Name: ctx.StackName, ProjectID: pid}
+	}
+	rec.File, rec.FileHash, rec.Profiles = ctx.File, ctx.FileHash, ctx.Profiles
+	for _, name := range ctx.SortedSvcKeys {
+		svc := ctx.RuntimeSvcs[name]
+		entry := mod.ComposeStackService{
+			Name:      svc.Name,
+			RawName:   svc.RawName,
+			Image:     svc.Spec.Image,
+			Provider:  formatProvider(svc.Spec.Provider),
+			Account:   svc.Spec.Account,
+			Profiles:  svc.Spec.Profiles,
+			DependsOn: svc.Spec.DependsOn,
+			Status:    "deployed",
+			Outputs:   maskedOutputs(svc.Outputs),
+		}
+		switch {
+		case svc.RolledBack:
+			// 回滚后场景已删除，与跳过的服务一样不写入记录
+			entry.Status = "skipped"
+		case failed[name]:
+			entry.Status = "failed"
+		case !svc.IsDeployed:
+			entry.Status = "skipped"
+		}
+		if svc.CaseRef != nil {
+			entry.CaseID = svc.CaseRef.Id
+		}
+		existing := rec.Service(name)
+		switch {
+		case existing == nil:
+			if entry.Status != "skipped" {
+				rec.Services = append(rec.Services, entry)
+			}
+		case entry.Status != "skipped":
+			*existing = entry
+		}
+	}
+	if len(ctx.removed) > 0 {
+		removed := make(map[string]bool, len(ctx.removed))
+		for _, name := range ctx.removed {
+			removed[name] = true
+		}
+		rec.Services = slices.DeleteFunc(rec.Services, func(s mod.ComposeStackService) bool { return removed[s.Name] })
+		rec.Setup = slices.DeleteFunc(rec.Setup, func(r mod.ComposeSetupResult) bool { return removed[r.Service] })
+	}
+	sort.Slice(rec.Services, func(i, j int) bool { return rec.Services[i].Name < rec.Services[j].Name })
+	switch {
+	case ctx.setupResults == nil:
+	case ctx.targets != nil:
+		// 只操作部分服务时，保留未重新执行的 setup 结果
+		rec.Setup = mergeSetupResults(rec.Setup, ctx.setupResults)
+	default:
+		rec.Setup = ctx.setupResults
+	}
+	rec.Paused = ctx.paused
+	if len(rec.Services) == 0 {
+		err = mod.DeleteComposeStack(pid, ctx.StackName)
+	} else {
+		err = mod.SaveComposeStack(rec)
+	}
+	if err != nil {
+		msg := i18n.Tf("compose_stack_save_failed", ctx.StackName, err)
+		gologger.Warning().Msgf("%s", msg)
+		ctx.emitLog(msg)
+	}
+}
+
+// mergeSetupResults 用本次结果替换同一任务在同一实例上的旧结果
+func mergeSetupResults(old, cur []mod.ComposeSetupResult) []mod.ComposeSetupResult {
+	ran := make(map[string]bool, len(cur))
+	for _, r := range cur {
+		ran[r.Task+"/"+r.Service] = true
+	}
+	res := slices.DeleteFunc(old, func(r mod.ComposeSetupResult) bool { return ran[r.Task+"/"+r.Service] })
+	return append(res, cur...)
+}
+
+func formatProvider(p interface{}) string {
+	if s, ok := p.(string); ok && s != "default" {
+		return s
+	}
+	return ""
+}
+
+// contextFromStack 从编排记录重建上下文，场景按记录中的 ID 查找
+func contextFromStack(rec *mod.ComposeStack, opts ComposeOptions) (*ComposeContext, error) {
+	parallel, err := resolveParallel(ParallelSpec{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx := &ComposeContext{
+		RuntimeSvcs: make(map[string]*RuntimeService),
+		LogMgr:      gologger.NewLogManager(opts.Project.ProjectPath),
+		Project:     opts.Project,
+		LogCallback: opts.LogCallback,
+		Parallel:    parallel,
+		StackName:   rec.Name,
+		File:        rec.File,
+		FileHash:    rec.FileHash,
+		Profiles:    rec.Profiles,
+		Actor:       opts.Actor,
+		Source:      opts.Source,
+	}
+	for _, s := range rec.Services {
+		if len(opts.Profiles) > 0 && !checkProfile(s.Profiles, opts.Profiles) {
+			continue
+		}
+		svc := &RuntimeService{
+			Name:    s.Name,
+			RawName: s.RawName,
+			Spec:    ServiceSpec{Image: s.Image, Provider: s.Provider, Account: s.Account, Profiles: s.Profiles, DependsOn: s.DependsOn},
+			Outputs: s.Outputs,
+		}
+		if s.CaseID != "" {
+			if c, err := ctx.getCase(s.CaseID); err == nil {
+				svc.CaseRef = c
+				svc.IsDeployed = true
+			}
+		}
+		ctx.RuntimeSvcs[s.Name] = svc
+		ctx.SortedSvcKeys = append(ctx.SortedSvcKeys, s.Name)
+	}
+	sort.Strings(ctx.SortedSvcKeys)
+	return ctx, nil
+}
+
+// runStackDown 按编排记录销毁 (指定服务时只销毁这些服务及依赖它们的服务)，已销毁的服务从记录中移除，全部销毁后删除记录
+func runStackDown(rec *mod.ComposeStack, opts ComposeOptions) error {
+	ctx, err := contextFromStack(rec, opts)
+	if err != nil {
+		return err
+	}
+	if len(opts.Services) > 0 {
+		if _, err := ctx.selectServices(opts.Services, true); err != nil {
+			return err
+		}
+	}
+	ctx.emitLog(i18n.Tf("compose_stack_down", rec.Name, rec.File))
+	destroyErr := ctx.destroyServices(false)
+
+	var remaining []mod.ComposeStackService
+	for _, s := range rec.Services {
+		if svc, ok := ctx.RuntimeSvcs[s.Name]; ok && !svc.IsDeployed {
+			continue
+		}
+		remaining = append(remaining, s)
+	}
+	if len(remaining) == 0 {
+		err = mod.DeleteComposeStack(rec.ProjectID, rec.Name)
+	} else {
+		rec.Services = remaining
+		err = mod.SaveComposeStack(rec)
+	}
+	if err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("compose_stack_save_failed", rec.Name, err))
+	}
+	return destroyErr
+}
+
+// ListStacks 列出项目中的编排记录，附带编排文件是否变化及各场景的当前状态
+func ListStacks(project *mod.RedcProject) ([]StackInfo, error) {
+	stacks, err := mod.ListComposeStacks(project.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]StackInfo, 0, len(stacks))
+	for _, rec := range stacks {
+		res = append(res, stackInfo(project, rec))
+	}
+	return res, nil
+}
+
+// GetStack 读取单个编排记录及其状态
+func GetStack(project *mod.RedcProject, name string) (*StackInfo, error) {
+	rec, err := mod.GetComposeStack(project.ProjectName, name)
+	if err != nil {
+		return nil, err
+	}
+	info := stackInfo(project, rec)
+	return &info, nil
+}
+
+func stackInfo(project *mod.RedcProject, rec *mod.ComposeStack) StackInfo {
+	info := StackInfo{ComposeStack: rec, FileStatus: StackFileUnchanged, States: map[string]string{}}
+	if data, err := os.ReadFile(rec.File); err != nil {
+		info.FileStatus = StackFileMissing
+	} else if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != rec.FileHash {
+		info.FileStatus = StackFileModified
+	}
+	for _, s := range rec.Services {
+		state := "missing"
+		if s.CaseID != "" {
+			if c, err := project.GetCase(s.CaseID); err == nil {
+				state = c.State
+			}
+		}
+		info.States[s.Name] = state
+	}
+	return info
+}
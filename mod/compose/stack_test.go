@@ -0,0 +1,81 @@
+package compose
+
+import (
+	"path/filepath"
+	"testing"
+
+	"red-cloud/mod"
+	"red-cloud/mod/secret"
+)
+
+func TestStackName(t *testing.T) {
+	file := filepath.Join("/ops", "phish", "redc-compose.yaml")
+	if got := stackName("", "", file); got != "phish" {
+		t.Errorf("default stack name = %q", got)
+	}
+	if got := stackName("", "campaign", file); got != "campaign" {
+		t.Errorf("name from file = %q", got)
+	}
+	if got := stackName("override", "campaign", file); got != "override" {
+		t.Errorf("explicit name = %q", got)
+	}
+}
+
+func TestRecordStack(t *testing.T) {
+	mod.RedcPath = t.TempDir()
+	defer secret.Reset()
+	secret.Register("s3cr3t-value")
+	project := &mod.RedcProject{ProjectName: "default"}
+	ctx := &ComposeContext{
+		RuntimeSvcs: map[string]*RuntimeService{
+			"teamserver": {Name: "teamserver", RawName: "teamserver", IsDeployed: true, CaseRef: &mod.Case{Id: "c1"},
+				Outputs: map[string]interface{}{"public_ip": "1.2.3.4", "admin_password": "hunter22", "token_out": "s3cr3t-value"}},
+			"redirector": {Name: "redirector", RawName: "redirector", CaseRef: &mod.Case{Id: "c2"}, Spec: ServiceSpec{DependsOn: []string{"teamserver"}}},
+		},
+		SortedSvcKeys: []string{"redirector", "teamserver"},
+		Project:       project,
+		StackName:     "op",
+		File:          "/ops/op/redc-compose.yaml",
+	}
+	ctx.recordSetup(SetupTask{Name: "init", Outputs: "key=s3cr3t-value"}, ctx.RuntimeSvcs["teamserver"], nil)
+	ctx.recordStack(map[string]bool{"redirector": true})
+
+	rec, err := mod.GetComposeStack("default", "op")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, rd := rec.Service("teamserver"), rec.Service("redirector")
+	if ts == nil || ts.CaseID != "c1" || ts.Status != "deployed" || rd == nil || rd.Status != "failed" || rd.CaseID != "c2" {
+		t.Fatalf("services = %+v", rec.Services)
+	}
+	if ts.Outputs["public_ip"] != "1.2.3.4" || ts.Outputs["admin_password"] != secret.Mask || ts.Outputs["token_out"] != secret.Mask {
+		t.Errorf("sensitive outputs should be masked: %v", ts.Outputs)
+	}
+	if len(rec.Setup) != 1 || rec.Setup[0].Output != "key="+secret.Mask {
+		t.Errorf("setup = %+v", rec.Setup)
+	}
+
+	// 第二次部署只涉及 redirector，teamserver 被跳过时保留原记录
+	ctx.RuntimeSvcs["teamserver"].IsDeployed = false
+	ctx.RuntimeSvcs["redirector"].IsDeployed = true
+	ctx.setupResults = nil
+	ctx.recordStack(nil)
+	rec, _ = mod.GetComposeStack("default", "op")
+	if rec.Service("teamserver").Status != "deployed" || rec.Service("redirector").Status != "deployed" || len(rec.Setup) != 1 {
+		t.Errorf("merged record = %+v", rec)
+	}
+}
+
+func TestContextFromStack(t *testing.T) {
+	rec := &mod.ComposeStack{Name: "op", Services: []mod.ComposeStackService{
+		{Name: "teamserver", RawName: "teamserver", Image: "aliyun/ecs"},
+		{Name: "scanner", RawName: "scanner", Image: "aws/ec2", Profiles: []string{"recon"}},
+	}}
+	ctx, err := contextFromStack(rec, ComposeOptions{Project: &mod.RedcProject{ProjectName: "default", ProjectPath: t.TempDir()}, Profiles: []string{"prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.SortedSvcKeys) != 1 || ctx.SortedSvcKeys[0] != "teamserver" || ctx.RuntimeSvcs["teamserver"].IsDeployed {
+		t.Errorf("keys = %v", ctx.SortedSvcKeys)
+	}
+}
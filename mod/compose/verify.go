@@ -67,6 +67,12 @@ func VerifyTemplates(ctx *ComposeContext) error {
 			}
 		}
 
+		// D. Secrets
+		for _, ref := range svc.Spec.Secrets {
+			key, name := secretMapping(ref)
+			injectedVars[key] = fmt.Sprintf("YAML secrets: %s", name)
+		}
+
 		// 3. 执行比对
 		var missingVars []string
 		for key, reason := range injectedVars {
@@ -0,0 +1,193 @@
+package mod
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"red-cloud/i18n"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	composeStackBucket  = "Compose_Stacks"
+	composeSecretBucket = "Compose_Secrets" // 编排 secrets 中随机生成的值
+)
+
+// ComposeStack compose up 后持久化的编排记录，compose ps / down 基于该记录工作，不依赖编排文件
+type ComposeStack struct {
+	Name      string                `json:"name"`
+	ProjectID string                `json:"projectId"`
+	File      string                `json:"file"`     // 部署时编排文件的绝对路径
+	FileHash  string                `json:"fileHash"` // 部署时编排文件的 SHA256
+	Profiles  []string              `json:"profiles,omitempty"`
+	Services  []ComposeStackService `json:"services"`
+	Setup     []ComposeSetupResult  `json:"setup,omitempty"`
+	Paused    bool                  `json:"paused,omitempty"` // up 失败后按 pause 策略保留，等待恢复或销毁
+	CreatedAt time.Time             `json:"createdAt"`
+	UpdatedBy string                `json:"updatedBy"`
+	UpdatedAt time.Time             `json:"updatedAt"`
+}
+
+// ComposeStackService 编排中的单个服务实例及其关联场景
+type ComposeStackService struct {
+	Name      string                 `json:"name"`
+	RawName   string                 `json:"rawName"`
+	Image     string                 `json:"image"`
+	Provider  string                 `json:"provider,omitempty"`
+	Account   string                 `json:"account,omitempty"`
+	Profiles  []string               `json:"profiles,omitempty"`
+	DependsOn []string               `json:"dependsOn,omitempty"`
+	CaseID    string                 `json:"caseId,omitempty"`
+	Status    string                 `json:"status"` // deployed | failed | skipped
+	Outputs   map[string]interface{} `json:"outputs,omitempty"`
+}
+
+// ComposeSetupResult setup 任务在单个实例上的执行结果
+type ComposeSetupResult struct {
+	Task    string    `json:"task"`
+	Service string    `json:"service"`
+	Output  string    `json:"output,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	RanAt   time.Time `json:"ranAt"`
+}
+
+// Service 按实例名查找服务
+func (s *ComposeStack) Service(name string) *ComposeStackService {
+	for i := range s.Services {
+		if s.Services[i].Name == name {
+			return &s.Services[i]
+		}
+	}
+	return nil
+}
+
+func composeStackKey(projectID, name string) []byte {
+	return []byte(projectID + "/" + name)
+}
+
+// SaveComposeStack 写入编排记录
+func SaveComposeStack(s *ComposeStack) error {
+	now := time.Now()
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = now
+	}
+	s.UpdatedBy = U
+	s.UpdatedAt = now
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return dbExec(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(composeStackBucket))
+		if err != nil {
+			return err
+		}
+		return b.Put(composeStackKey(s.ProjectID, s.Name), data)
+	})
+}
+
+// GetComposeStack 读取编排记录，不存在时返回错误
+func GetComposeStack(projectID, name string) (*ComposeStack, error) {
+	var s *ComposeStack
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(composeStackBucket))
+		if b == nil {
+			return nil
+		}
+		data := b.Get(composeStackKey(projectID, name))
+		if data == nil {
+			return nil
+		}
+		s = &ComposeStack{}
+		return json.Unmarshal(data, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("compose_stack_not_found", name))
+	}
+	return s, nil
+}
+
+// ListComposeStacks 列出项目中的编排记录，按名称排序
+func ListComposeStacks(projectID string) ([]*ComposeStack, error) {
+	var stacks []*ComposeStack
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(composeStackBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var s ComposeStack
+			if err := json.Unmarshal(v, &s); err == nil && s.ProjectID == projectID {
+				stacks = append(stacks, &s)
+			}
+			return nil
+		})
+	})
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].Name < stacks[j].Name })
+	return stacks, err
+}
+
+// DeleteComposeStack 删除编排记录及其生成的 secrets
+func DeleteComposeStack(projectID, name string) error {
+	return dbExec(func(tx *bolt.Tx) error {
+		if b := tx.Bucket([]byte(composeSecretBucket)); b != nil {
+			prefix := append(composeStackKey(projectID, name), '/')
+			var keys [][]byte
+			c := b.Cursor()
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+		b := tx.Bucket([]byte(composeStackBucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete(composeStackKey(projectID, name))
+	})
+}
+
+func composeSecretKey(projectID, stack, name string) []byte {
+	return []byte(projectID + "/" + stack + "/" + name)
+}
+
+// GetComposeSecret 读取编排生成的 secret，不存在时返回空字符串。凭据存储已加密时需要已解锁
+func GetComposeSecret(projectID, stack, name string) (string, error) {
+	var val string
+	err := dbExec(func(tx *bolt.Tx) error {
+		if b := tx.Bucket([]byte(composeSecretBucket)); b != nil {
+			val = string(b.Get(composeSecretKey(projectID, stack, name)))
+		}
+		return nil
+	})
+	if err != nil || !strings.HasPrefix(val, credEncPrefix) {
+		return val, err
+	}
+	return OpenSecret(ActiveConfigPath, val)
+}
+
+// SaveComposeSecret 保存编排生成的 secret，凭据存储已加密时以密文保存，未解锁时返回错误
+func SaveComposeSecret(projectID, stack, name, value string) error {
+	stored, err := sealIfEncrypted(ActiveConfigPath, value)
+	if err != nil {
+		return err
+	}
+	return dbExec(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(composeSecretBucket))
+		if err != nil {
+			return err
+		}
+		return b.Put(composeSecretKey(projectID, stack, name), []byte(stored))
+	})
+}
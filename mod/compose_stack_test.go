@@ -0,0 +1,90 @@
+package mod
+
+import (
+	"strings"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestComposeStackStore(t *testing.T) {
+	RedcPath = t.TempDir()
+	s := &ComposeStack{
+		Name:      "op",
+		ProjectID: "default",
+		File:      "/tmp/op/redc-compose.yaml",
+		Services:  []ComposeStackService{{Name: "teamserver", RawName: "teamserver", CaseID: "c1", Status: "deployed"}},
+	}
+	if err := SaveComposeStack(s); err != nil {
+		t.Fatal(err)
+	}
+	other := &ComposeStack{Name: "op", ProjectID: "other"}
+	if err := SaveComposeStack(other); err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetComposeStack("default", "op")
+	if err != nil || got.Service("teamserver").CaseID != "c1" || got.CreatedAt.IsZero() {
+		t.Fatalf("GetComposeStack = %+v, %v", got, err)
+	}
+	if list, _ := ListComposeStacks("default"); len(list) != 1 {
+		t.Errorf("stacks of other projects should not be listed, got %d", len(list))
+	}
+	for _, pid := range []string{"default", "other"} {
+		if err := SaveComposeSecret(pid, "op", "ts_pass", "generated-"+pid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, err := GetComposeSecret("default", "op", "ts_pass"); err != nil || v != "generated-default" {
+		t.Errorf("GetComposeSecret = %q, %v", v, err)
+	}
+	if err := DeleteComposeStack("default", "op"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetComposeStack("default", "op"); err == nil {
+		t.Error("deleted stack should not be found")
+	}
+	if v, _ := GetComposeSecret("default", "op", "ts_pass"); v != "" {
+		t.Errorf("secrets should be deleted with the stack, got %q", v)
+	}
+	if v, _ := GetComposeSecret("other", "op", "ts_pass"); v != "generated-other" {
+		t.Errorf("secrets of other projects should be kept, got %q", v)
+	}
+}
+
+// 凭据存储已加密时生成的 secret 以密文保存，未解锁时拒绝保存而不是退回明文
+func TestComposeSecretSealed(t *testing.T) {
+	path := setupCredentialTest(t)
+	oldActive := ActiveConfigPath
+	ActiveConfigPath = path
+	t.Cleanup(func() { ActiveConfigPath = oldActive })
+	writeTestConfig(t, path, &Config{})
+	if _, err := MigrateCredentials(path, []byte("correct horse"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	LockCredentials()
+	if err := SaveComposeSecret("default", "op", "ts_pass", "generated-value"); err == nil {
+		t.Fatal("saving while locked should fail")
+	}
+	if v, _ := GetComposeSecret("default", "op", "ts_pass"); v != "" {
+		t.Fatalf("nothing should be stored while locked, got %q", v)
+	}
+
+	if _, err := UnlockCredentials(path, []byte("correct horse"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveComposeSecret("default", "op", "ts_pass", "generated-value"); err != nil {
+		t.Fatal(err)
+	}
+	var stored string
+	dbExec(func(tx *bolt.Tx) error {
+		stored = string(tx.Bucket([]byte(composeSecretBucket)).Get(composeSecretKey("default", "op", "ts_pass")))
+		return nil
+	})
+	if !strings.HasPrefix(stored, credEncPrefix) {
+		t.Errorf("secret should be stored encrypted, got %q", stored)
+	}
+	if v, err := GetComposeSecret("default", "op", "ts_pass"); err != nil || v != "generated-value" {
+		t.Errorf("GetComposeSecret = %q, %v", v, err)
+	}
+}
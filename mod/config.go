@@ -33,6 +33,9 @@ type GUISettings struct {
 	NotificationEnabled    bool   `json:"notificationEnabled"`
 	SpotMonitorEnabled     bool   `json:"spotMonitorEnabled"`
 	SpotAutoRecoverEnabled bool   `json:"spotAutoRecoverEnabled"`
+	DriftMonitorEnabled    bool   `json:"driftMonitorEnabled"`
+	DriftMonitorInterval   int    `json:"driftMonitorInterval"` // 分钟，0 表示默认 60
+	CaseTTLWarnMinutes     int    `json:"caseTTLWarnMinutes"`   // TTL 到期前提前提醒的分钟数，0 表示默认 30
 	DebugEnabled           bool   `json:"debugEnabled"`
 	HttpProxy           string `json:"httpProxy"`
 	HttpsProxy          string `json:"httpsProxy"`
@@ -72,51 +75,64 @@ type Config struct {
 			AccessKey string `yaml:"AWS_ACCESS_KEY_ID" env:"AWS_ACCESS_KEY_ID"`
 			SecretKey string `yaml:"AWS_SECRET_ACCESS_KEY" env:"AWS_SECRET_ACCESS_KEY" `
 			Region    string `yaml:"region"`
+			Role     RoleConfig       `yaml:",inline"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"aws"`
 		Alicloud struct {
 			AccessKey string `yaml:"ALICLOUD_ACCESS_KEY" env:"ALICLOUD_ACCESS_KEY" `
 			SecretKey string `yaml:"ALICLOUD_SECRET_KEY" env:"ALICLOUD_SECRET_KEY"`
 			Region    string `yaml:"region"`
+			Role     RoleConfig       `yaml:",inline"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"aliyun"`
 		Tencentcloud struct {
 			SecretId  string `yaml:"TENCENTCLOUD_SECRET_ID" env:"TENCENTCLOUD_SECRET_ID"`
 			SecretKey string `yaml:"TENCENTCLOUD_SECRET_KEY" env:"TENCENTCLOUD_SECRET_KEY"`
 			Region    string `yaml:"region"`
+			Role     RoleConfig       `yaml:",inline"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"tencentcloud"`
 		Volcengine struct {
 			AccessKey string `yaml:"VOLCENGINE_ACCESS_KEY" env:"VOLCENGINE_ACCESS_KEY"`
 			SecretKey string `yaml:"VOLCENGINE_SECRET_KEY" env:"VOLCENGINE_SECRET_KEY"`
 			Region    string `yaml:"region"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"volcengine"`
 		UCloud struct {
 			PublicKey  string `yaml:"UCLOUD_PUBLIC_KEY" env:"UCLOUD_PUBLIC_KEY"`
 			PrivateKey string `yaml:"UCLOUD_PRIVATE_KEY" env:"UCLOUD_PRIVATE_KEY"`
 			Region     string `yaml:"region"`
 			ProjectId  string `yaml:"project_id" env:"UCLOUD_PROJECT_ID"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"ucloud"`
 		Huaweicloud struct {
 			AccessKey string `yaml:"HUAWEICLOUD_ACCESS_KEY" env:"HUAWEICLOUD_ACCESS_KEY"`
 			SecretKey string `yaml:"HUAWEICLOUD_SECRET_KEY" env:"HUAWEICLOUD_SECRET_KEY"`
 			Region    string `yaml:"region"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"huaweicloud"`
 		Vultr struct {
 			ApiKey string `yaml:"VULTR_API_KEY" env:"VULTR_API_KEY"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"vultr"`
 		Ctyun struct {
 			AccessKey string `yaml:"CTYUN_AK" env:"CTYUN_AK"`
 			SecretKey string `yaml:"CTYUN_SK" env:"CTYUN_SK"`
 			Region    string `yaml:"region"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"ctyun"`
 		Google struct {
 			Credentials string `yaml:"GOOGLE_APPLICATION_CREDENTIALS" env:"GOOGLE_APPLICATION_CREDENTIALS"`
 			Project     string `yaml:"project"`
 			Region      string `yaml:"region"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"google"`
 		Azure struct {
 			ClientId       string `yaml:"ARM_CLIENT_ID" env:"ARM_CLIENT_ID"`
 			ClientSecret   string `yaml:"ARM_CLIENT_SECRET" env:"ARM_CLIENT_SECRET"`
 			SubscriptionId string `yaml:"ARM_SUBSCRIPTION_ID" env:"ARM_SUBSCRIPTION_ID"`
 			TenantId       string `yaml:"ARM_TENANT_ID" env:"ARM_TENANT_ID"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"azure"`
 		Oracle struct {
 			User        string `yaml:"OCI_CLI_USER" env:"OCI_CLI_USER"`
@@ -124,12 +140,17 @@ type Config struct {
 			Fingerprint string `yaml:"OCI_CLI_FINGERPRINT" env:"OCI_CLI_FINGERPRINT"`
 			KeyFile     string `yaml:"OCI_CLI_KEY_FILE" env:"OCI_CLI_KEY_FILE"`
 			Region      string `yaml:"OCI_CLI_REGION" env:"OCI_CLI_REGION"`
+			Accounts ProviderAccounts `yaml:"accounts,omitempty"`
 		} `yaml:"oracle"`
 	} `yaml:"providers"`
 	Cloudflare struct {
 		Email  string `yaml:"CF_EMAIL" env:"CF_EMAIL"`
 		APIKey string `yaml:"CF_API_KEY" env:"CF_API_KEY"`
 	} `yaml:"cloudflare"`
+	// Backends 按项目名配置远程 state backend，未配置的项目使用本地 state
+	Backends map[string]*BackendConfig `yaml:"backends,omitempty"`
+	// Credentials 凭据加密存储和外部凭据来源设置
+	Credentials CredentialSettings `yaml:"credentials,omitempty"`
 }
 
 func LoadConfig(path string) error {
@@ -193,6 +214,8 @@ func LoadConfig(path string) error {
 	if err := yaml.Unmarshal(data, &conf); err != nil {
 		return err
 	}
+	// 解密凭据并解析 env: / exec: 引用
+	warnCredentialError(resolveCredentials(&conf))
 
 	// 批量配置环境变量
 	bindEnv(conf)
@@ -236,8 +259,8 @@ func bindEnv(v interface{}) {
 			continue
 		}
 
-		// 如果是字符串且有值，读取 yaml 标签并 Setenv
-		if tag := fieldType.Tag.Get("env"); (tag != "" && tag != "-") && fieldVal.String() != "" {
+		// 如果是字符串且有值，读取 yaml 标签并 Setenv；未解析的密文或引用不能作为凭据
+		if tag := fieldType.Tag.Get("env"); (tag != "" && tag != "-") && fieldVal.String() != "" && !isCredentialRef(fieldVal.String()) {
 			os.Setenv(tag, fieldVal.String())
 		}
 	}
@@ -277,6 +300,8 @@ func ReadConfig(customPath string) (*Config, string, error) {
 	if err := yaml.Unmarshal(data, &conf); err != nil {
 		return nil, configPath, fmt.Errorf("%s", i18n.Tf("config_parse_failed", err))
 	}
+	// 未解锁时加密字段为空，保存时会保留原来的密文
+	_ = resolveCredentials(&conf)
 	return &conf, configPath, nil
 }
 
@@ -291,14 +316,21 @@ func SaveConfig(conf *Config, customPath string) error {
 		return fmt.Errorf("%s", i18n.Tf("config_create_dir_failed", err))
 	}
 
-	data, err := yaml.Marshal(conf)
+	// 写入文件的副本：保留未修改字段的密文 / 引用，加密新写入的凭据
+	raw, err := readRawConfig(configPath)
 	if err != nil {
-		return fmt.Errorf("%s", i18n.Tf("config_serialize_failed", err))
+		return err
 	}
-
-	if err := os.WriteFile(configPath, data, 0600); err != nil {
-		return fmt.Errorf("%s", i18n.Tf("config_write_failed", err))
+	stored := *conf
+	stored.cloneAccounts()
+	if err := protectCredentials(&stored, raw); err != nil {
+		return err
+	}
+	if err := writeRawConfig(configPath, &stored); err != nil {
+		return err
 	}
+	conf.Credentials = stored.Credentials
+	_ = resolveCredentials(conf)
 
 	// Rebind environment variables after saving
 	bindEnv(*conf)
@@ -309,27 +341,52 @@ func SaveConfig(conf *Config, customPath string) error {
 	return nil
 }
 
-// GetProviderCredentials 从已加载的配置中获取云厂商凭据
+// GetProviderCredentials 从已加载的配置中获取云厂商凭据。
+// 默认凭据为空时使用第一个配置了凭据的命名账号
 func GetProviderCredentials(provider string) (accessKey, secretKey string) {
 	if LoadedConfig == nil {
 		return "", ""
 	}
+	accessKey, secretKey = providerCredentials(LoadedConfig, provider)
+	if accessKey != "" && secretKey != "" {
+		return accessKey, secretKey
+	}
+	for _, account := range LoadedConfig.AccountNames(provider) {
+		if ak, sk := GetAccountCredentials(provider, account); ak != "" && sk != "" {
+			return ak, sk
+		}
+	}
+	return accessKey, secretKey
+}
+
+// GetAccountCredentials 获取命名账号的云厂商凭据，account 为空时返回默认凭据
+func GetAccountCredentials(provider, account string) (accessKey, secretKey string) {
+	if LoadedConfig == nil {
+		return "", ""
+	}
+	conf, err := LoadedConfig.ForAccount(provider, account)
+	if err != nil {
+		return "", ""
+	}
+	return providerCredentials(conf, provider)
+}
 
+func providerCredentials(conf *Config, provider string) (accessKey, secretKey string) {
 	switch provider {
 	case "volcengine":
-		return LoadedConfig.Providers.Volcengine.AccessKey, LoadedConfig.Providers.Volcengine.SecretKey
-	case "alicloud":
-		return LoadedConfig.Providers.Alicloud.AccessKey, LoadedConfig.Providers.Alicloud.SecretKey
-	case "tencentcloud":
-		return LoadedConfig.Providers.Tencentcloud.SecretId, LoadedConfig.Providers.Tencentcloud.SecretKey
+		return conf.Providers.Volcengine.AccessKey, conf.Providers.Volcengine.SecretKey
+	case "alicloud", "aliyun":
+		return conf.Providers.Alicloud.AccessKey, conf.Providers.Alicloud.SecretKey
+	case "tencentcloud", "tencent":
+		return conf.Providers.Tencentcloud.SecretId, conf.Providers.Tencentcloud.SecretKey
 	case "aws":
-		return LoadedConfig.Providers.Aws.AccessKey, LoadedConfig.Providers.Aws.SecretKey
+		return conf.Providers.Aws.AccessKey, conf.Providers.Aws.SecretKey
 	case "huaweicloud":
-		return LoadedConfig.Providers.Huaweicloud.AccessKey, LoadedConfig.Providers.Huaweicloud.SecretKey
+		return conf.Providers.Huaweicloud.AccessKey, conf.Providers.Huaweicloud.SecretKey
 	case "ucloud":
-		return LoadedConfig.Providers.UCloud.PublicKey, LoadedConfig.Providers.UCloud.PrivateKey
+		return conf.Providers.UCloud.PublicKey, conf.Providers.UCloud.PrivateKey
 	case "ctyun":
-		return LoadedConfig.Providers.Ctyun.AccessKey, LoadedConfig.Providers.Ctyun.SecretKey
+		return conf.Providers.Ctyun.AccessKey, conf.Providers.Ctyun.SecretKey
 	}
 
 	return "", ""
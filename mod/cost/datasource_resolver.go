@@ -145,7 +145,7 @@ func (r *DataSourceResolver) resolveDataSource(ds *DataSourceDefinition) (interf
 // resolveTencentCloudInstanceTypes resolves tencentcloud_instance_types data source
 func (r *DataSourceResolver) resolveTencentCloudInstanceTypes(ds *DataSourceDefinition) (interface{}, error) {
 	// Get Tencent Cloud credentials
-	accessKey, secretKey, region, err := r.credentialProvider("tencentcloud")
+	accessKey, secretKey, sessionToken, region, err := r.credentialProvider("tencentcloud")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Tencent Cloud credentials: %w", err)
 	}
@@ -160,7 +160,7 @@ func (r *DataSourceResolver) resolveTencentCloudInstanceTypes(ds *DataSourceDefi
 	}
 	
 	// Create Tencent Cloud client
-	credential := common.NewCredential(accessKey, secretKey)
+	credential := common.NewTokenCredential(accessKey, secretKey, sessionToken)
 	cpf := profile.NewClientProfile()
 	client, err := cvm.NewClient(credential, region, cpf)
 	if err != nil {
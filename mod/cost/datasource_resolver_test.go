@@ -8,8 +8,8 @@ import (
 func TestDataSourceResolver_ParseDataSources(t *testing.T) {
 	// This test verifies that data source blocks are correctly parsed
 	// We'll use a mock credential provider
-	mockCredProvider := func(provider string) (accessKey, secretKey, region string, err error) {
-		return "test-key", "test-secret", "ap-guangzhou", nil
+	mockCredProvider := func(provider string) (accessKey, secretKey, sessionToken, region string, err error) {
+		return "test-key", "test-secret", "", "ap-guangzhou", nil
 	}
 	
 	resolver := NewDataSourceResolver(mockCredProvider)
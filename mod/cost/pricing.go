@@ -10,8 +10,9 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// CredentialProvider is a function type that provides credentials for a given provider
-type CredentialProvider func(provider string) (accessKey, secretKey, region string, err error)
+// CredentialProvider is a function type that provides credentials for a given provider.
+// sessionToken is non-empty when the keys are temporary credentials obtained via AssumeRole
+type CredentialProvider func(provider string) (accessKey, secretKey, sessionToken, region string, err error)
 
 // inFlightRequest represents an in-flight pricing request
 type inFlightRequest struct {
@@ -234,11 +235,11 @@ func (ps *PricingService) fetchPricingFromProvider(provider, region, resourceTyp
 	}
 	
 	// Get credentials from credential provider if available
-	var accessKey, secretKey, defaultRegion string
+	var accessKey, secretKey, sessionToken, defaultRegion string
 	var err error
 	
 	if ps.credentialProvider != nil {
-		accessKey, secretKey, defaultRegion, err = ps.credentialProvider(provider)
+		accessKey, secretKey, sessionToken, defaultRegion, err = ps.credentialProvider(provider)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get credentials for provider %s: %w", provider, err)
 		}
@@ -252,11 +253,11 @@ func (ps *PricingService) fetchPricingFromProvider(provider, region, resourceTyp
 	// Call provider-specific pricing function
 	switch provider {
 	case "alicloud":
-		return ps.getAlicloudPricing(region, resourceType, accessKey, secretKey)
+		return ps.getAlicloudPricing(region, resourceType, accessKey, secretKey, sessionToken)
 	case "tencentcloud":
-		return ps.getTencentcloudPricing(region, resourceType, accessKey, secretKey)
+		return ps.getTencentcloudPricing(region, resourceType, accessKey, secretKey, sessionToken)
 	case "aws":
-		return ps.getAWSPricing(region, resourceType, accessKey, secretKey)
+		return ps.getAWSPricing(region, resourceType, accessKey, secretKey, sessionToken)
 	case "volcengine":
 		return ps.getVolcenginePricing(region, resourceType, accessKey, secretKey)
 	default:
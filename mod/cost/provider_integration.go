@@ -7,9 +7,9 @@ import (
 )
 
 // getAlicloudPricing fetches Alibaba Cloud pricing using the providers package
-func (ps *PricingService) getAlicloudPricing(region, resourceType, accessKey, secretKey string) (*PricingData, error) {
+func (ps *PricingService) getAlicloudPricing(region, resourceType, accessKey, secretKey, sessionToken string) (*PricingData, error) {
 	// Call the providers package function
-	providerData, err := providers.GetAlicloudPricing(region, resourceType, accessKey, secretKey)
+	providerData, err := providers.GetAlicloudPricingWithToken(region, resourceType, accessKey, secretKey, sessionToken)
 	if err != nil {
 		return nil, err
 	}
@@ -33,9 +33,9 @@ func (ps *PricingService) getAlicloudPricing(region, resourceType, accessKey, se
 }
 
 // getTencentcloudPricing fetches Tencent Cloud pricing using the providers package
-func (ps *PricingService) getTencentcloudPricing(region, resourceType, accessKey, secretKey string) (*PricingData, error) {
+func (ps *PricingService) getTencentcloudPricing(region, resourceType, accessKey, secretKey, sessionToken string) (*PricingData, error) {
 	// Call the providers package function
-	providerData, err := providers.GetTencentcloudPricing(region, resourceType, accessKey, secretKey)
+	providerData, err := providers.GetTencentcloudPricingWithToken(region, resourceType, accessKey, secretKey, sessionToken)
 	if err != nil {
 		return nil, err
 	}
@@ -58,9 +58,9 @@ func (ps *PricingService) getTencentcloudPricing(region, resourceType, accessKey
 }
 
 // getAWSPricing fetches AWS pricing using the providers package
-func (ps *PricingService) getAWSPricing(region, resourceType, accessKey, secretKey string) (*PricingData, error) {
+func (ps *PricingService) getAWSPricing(region, resourceType, accessKey, secretKey, sessionToken string) (*PricingData, error) {
 	// Call the providers package function
-	providerData, err := providers.GetAWSPricing(region, resourceType, accessKey, secretKey)
+	providerData, err := providers.GetAWSPricingWithToken(region, resourceType, accessKey, secretKey, sessionToken)
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,11 @@ import (
 // GetAlicloudPricing retrieves pricing data for Alibaba Cloud resources
 // It uses the DescribePrice API to fetch real-time pricing information
 func GetAlicloudPricing(region, resourceType, accessKey, secretKey string) (*PricingData, error) {
+	return GetAlicloudPricingWithToken(region, resourceType, accessKey, secretKey, "")
+}
+
+// GetAlicloudPricingWithToken is GetAlicloudPricing with STS credentials from a RAM role
+func GetAlicloudPricingWithToken(region, resourceType, accessKey, secretKey, securityToken string) (*PricingData, error) {
 	if accessKey == "" || secretKey == "" {
 		return nil, fmt.Errorf("missing Alibaba Cloud access key or secret key")
 	}
@@ -24,7 +29,13 @@ func GetAlicloudPricing(region, resourceType, accessKey, secretKey string) (*Pri
 	}
 	
 	// Create ECS client
-	client, err := ecs.NewClientWithAccessKey(region, accessKey, secretKey)
+	var client *ecs.Client
+	var err error
+	if securityToken != "" {
+		client, err = ecs.NewClientWithStsToken(region, accessKey, secretKey, securityToken)
+	} else {
+		client, err = ecs.NewClientWithAccessKey(region, accessKey, secretKey)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Alibaba Cloud ECS client: %w", err)
 	}
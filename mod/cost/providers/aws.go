@@ -16,6 +16,11 @@ import (
 // GetAWSPricing retrieves pricing data for AWS resources
 // It uses the AWS Price List API to fetch real-time pricing information
 func GetAWSPricing(region, resourceType, accessKey, secretKey string) (*PricingData, error) {
+	return GetAWSPricingWithToken(region, resourceType, accessKey, secretKey, "")
+}
+
+// GetAWSPricingWithToken is GetAWSPricing with temporary credentials from AssumeRole
+func GetAWSPricingWithToken(region, resourceType, accessKey, secretKey, sessionToken string) (*PricingData, error) {
 	if accessKey == "" || secretKey == "" {
 		return nil, fmt.Errorf("missing AWS access key or secret key")
 	}
@@ -37,7 +42,7 @@ func GetAWSPricing(region, resourceType, accessKey, secretKey string) (*PricingD
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
 			accessKey,
 			secretKey,
-			"",
+			sessionToken,
 		)),
 	)
 	if err != nil {
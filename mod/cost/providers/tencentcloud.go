@@ -14,6 +14,11 @@ import (
 // It uses the InquiryPriceRunInstances API to fetch real-time pricing information
 // The region parameter can be either a region (e.g., "ap-guangzhou") or a zone (e.g., "ap-beijing-7")
 func GetTencentcloudPricing(region, resourceType, secretId, secretKey string) (*PricingData, error) {
+	return GetTencentcloudPricingWithToken(region, resourceType, secretId, secretKey, "")
+}
+
+// GetTencentcloudPricingWithToken is GetTencentcloudPricing with temporary credentials from a CAM role
+func GetTencentcloudPricingWithToken(region, resourceType, secretId, secretKey, token string) (*PricingData, error) {
 	if secretId == "" || secretKey == "" {
 		return nil, fmt.Errorf("missing Tencent Cloud secret ID or secret key")
 	}
@@ -46,7 +51,7 @@ func GetTencentcloudPricing(region, resourceType, secretId, secretKey string) (*
 	}
 	
 	// Create credential
-	credential := common.NewCredential(secretId, secretKey)
+	credential := common.NewTokenCredential(secretId, secretKey, token)
 	
 	// Create client profile
 	cpf := profile.NewClientProfile()
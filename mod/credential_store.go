@@ -0,0 +1,818 @@
+package mod
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// 凭据字段取值前缀：加密值、环境变量引用、外部命令
+const (
+	credEncPrefix  = "enc:v1:"
+	credEnvPrefix  = "env:"
+	credExecPrefix = "exec:"
+)
+
+// 主密钥来源的环境变量
+const (
+	EnvMasterPassphrase  = "REDC_MASTER_PASSPHRASE"
+	EnvCredentialKeyFile = "REDC_CREDENTIAL_KEYFILE"
+	// EnvCredentialSession redc cred unlock 输出的会话令牌，会话文件中的主密钥由它加密
+	EnvCredentialSession = "REDC_CRED_SESSION"
+)
+
+// credCheckPlain 用于校验主密钥的明文
+const credCheckPlain = "redc-credential-store"
+
+// credCommandTTL 外部命令输出的缓存时间
+var credCommandTTL = 5 * time.Minute
+
+// CredentialSettings config.yaml 中的 credentials 段
+type CredentialSettings struct {
+	Salt    string `yaml:"salt,omitempty"`               // scrypt salt，设置后表示已启用加密存储
+	Check   string `yaml:"check,omitempty"`              // 主密钥校验值
+	KeyFile string `yaml:"keyfile,omitempty"`            // 使用密钥文件代替口令
+	Process string `yaml:"credential_process,omitempty"` // 外部命令，输出 JSON 格式的凭据，填充为空的字段
+}
+
+// Encrypted 是否已启用加密存储
+func (s CredentialSettings) Encrypted() bool {
+	return s.Salt != ""
+}
+
+// CredentialsLockedError 凭据已加密但主密钥不可用
+type CredentialsLockedError struct{}
+
+func (e *CredentialsLockedError) Error() string {
+	return i18n.T("cred_locked")
+}
+
+// IsCredentialsLocked 判断错误是否为凭据未解锁
+func IsCredentialsLocked(err error) bool {
+	var le *CredentialsLockedError
+	return errors.As(err, &le)
+}
+
+var (
+	credKeyMu sync.Mutex
+	credKey   []byte // 进程内解锁的主密钥 (GUI)
+
+	credCmdMu    sync.Mutex
+	credCmdCache = map[string]credCmdResult{}
+)
+
+type credCmdResult struct {
+	out string
+	at  time.Time
+}
+
+// isCredentialRef 值是否为加密值或引用，这类值不能直接作为凭据使用
+func isCredentialRef(v string) bool {
+	return strings.HasPrefix(v, credEncPrefix) || strings.HasPrefix(v, credEnvPrefix) || strings.HasPrefix(v, credExecPrefix)
+}
+
+func deriveCredentialKey(material, salt []byte) ([]byte, error) {
+	return scrypt.Key(material, salt, 1<<15, 8, 1, 32)
+}
+
+func expandHomePath(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, p[2:])
+		}
+	}
+	return p
+}
+
+func keyFromFile(path string, salt []byte) ([]byte, error) {
+	data, err := os.ReadFile(expandHomePath(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("cred_keyfile_read_failed", path, err))
+	}
+	return deriveCredentialKey([]byte(strings.TrimSpace(string(data))), salt)
+}
+
+// GenerateKeyFile 生成随机密钥文件，文件已存在时不覆盖
+func GenerateKeyFile(path string) error {
+	path = expandHomePath(path)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(hex.EncodeToString(buf)+"\n"), 0600)
+}
+
+func sealCredential(key []byte, plain string) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return credEncPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func openCredential(key []byte, v string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, credEncPrefix))
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("%s", i18n.T("cred_decrypt_failed"))
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("%s", i18n.T("cred_decrypt_failed"))
+	}
+	return string(plain), nil
+}
+
+// keyForSettings 由口令或密钥文件派生主密钥并校验
+func keyForSettings(s CredentialSettings, passphrase []byte) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(s.Salt)
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	if s.KeyFile != "" {
+		key, err = keyFromFile(s.KeyFile, salt)
+	} else {
+		key, err = deriveCredentialKey(passphrase, salt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if plain, err := openCredential(key, s.Check); err != nil || plain != credCheckPlain {
+		return nil, fmt.Errorf("%s", i18n.T("cred_wrong_passphrase"))
+	}
+	return key, nil
+}
+
+// credentialSessionPath redc cred unlock 写入的会话文件
+func credentialSessionPath() string {
+	return filepath.Join(RedcPath, "credential.session")
+}
+
+// credentialSession 会话文件。Key 是用会话令牌加密的主密钥，只有导出了 REDC_CRED_SESSION 的 shell 能解开
+type credentialSession struct {
+	Salt    string    `json:"salt"`
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+}
+
+// sessionWrapKey 由会话令牌得到加密会话文件的密钥。令牌是 32 字节随机数，不需要慢哈希
+func sessionWrapKey(token string) []byte {
+	sum := sha256.Sum256([]byte("redc-credential-session:" + token))
+	return sum[:]
+}
+
+// writeCredentialSession 写入会话文件，返回会话令牌
+func writeCredentialSession(salt string, key []byte, ttl time.Duration) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	wrapped, err := sealCredential(sessionWrapKey(token), base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(credentialSession{Salt: salt, Key: wrapped, Expires: time.Now().Add(ttl)})
+	if err := os.WriteFile(credentialSessionPath(), data, 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func readCredentialSession(s CredentialSettings) []byte {
+	token := os.Getenv(EnvCredentialSession)
+	if token == "" {
+		return nil
+	}
+	data, err := os.ReadFile(credentialSessionPath())
+	if err != nil {
+		return nil
+	}
+	var sess credentialSession
+	if json.Unmarshal(data, &sess) != nil || sess.Salt != s.Salt || time.Now().After(sess.Expires) {
+		return nil
+	}
+	encoded, err := openCredential(sessionWrapKey(token), sess.Key)
+	if err != nil {
+		return nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+	if plain, err := openCredential(key, s.Check); err != nil || plain != credCheckPlain {
+		return nil
+	}
+	return key
+}
+
+// masterKey 依次尝试：进程内已解锁的密钥、密钥文件、环境变量口令、unlock 会话
+func masterKey(s CredentialSettings) ([]byte, error) {
+	credKeyMu.Lock()
+	key := credKey
+	credKeyMu.Unlock()
+	if key != nil {
+		if plain, err := openCredential(key, s.Check); err == nil && plain == credCheckPlain {
+			return key, nil
+		}
+	}
+	if kf := os.Getenv(EnvCredentialKeyFile); kf != "" && s.KeyFile == "" {
+		s.KeyFile = kf
+	}
+	if s.KeyFile != "" {
+		return keyForSettings(s, nil)
+	}
+	if p := os.Getenv(EnvMasterPassphrase); p != "" {
+		return keyForSettings(s, []byte(p))
+	}
+	if key := readCredentialSession(s); key != nil {
+		return key, nil
+	}
+	return nil, &CredentialsLockedError{}
+}
+
+// credentialField Config 中带 env 标签的字符串字段，以及命名账号中对应的字段
+type credentialField struct {
+	Path    string
+	Env     string
+	Account bool // 命名账号中的字段，不由 credential_process 填充
+	get     func() string
+	set     func(string)
+}
+
+// credentialFields 按声明顺序返回 Config 中所有凭据字段，命名账号按名称排序
+func credentialFields(conf *Config) []credentialField {
+	var out []credentialField
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			f, ft := v.Field(i), t.Field(i)
+			name := strings.Split(ft.Tag.Get("yaml"), ",")[0]
+			if name == "" {
+				name = ft.Name
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			if f.Kind() == reflect.Struct {
+				walk(f, name)
+				continue
+			}
+			if accounts, ok := f.Interface().(ProviderAccounts); ok {
+				out = append(out, accountCredentialFields(v, name, accounts)...)
+				continue
+			}
+			if tag := ft.Tag.Get("env"); tag != "" && tag != "-" && f.Kind() == reflect.String {
+				out = append(out, credentialField{Path: name, Env: tag, get: f.String, set: f.SetString})
+			}
+		}
+	}
+	walk(reflect.ValueOf(&conf.Providers).Elem(), "providers")
+	walk(reflect.ValueOf(&conf.Cloudflare).Elem(), "cloudflare")
+	return out
+}
+
+// accountCredentialFields 命名账号中与厂商凭据字段同名的键
+func accountCredentialFields(section reflect.Value, prefix string, accounts ProviderAccounts) []credentialField {
+	envs := sectionEnvKeys(section)
+	var out []credentialField
+	for _, account := range sortedKeys(accounts) {
+		values := accounts[account]
+		for _, key := range sortedKeys(values) {
+			env, ok := envs[key]
+			if !ok {
+				continue
+			}
+			key := key
+			out = append(out, credentialField{
+				Path:    prefix + "." + account + "." + key,
+				Env:     env,
+				Account: true,
+				get:     func() string { return values[key] },
+				set:     func(v string) { values[key] = v },
+			})
+		}
+	}
+	return out
+}
+
+// runCredentialCommand 通过 shell 执行外部命令并返回 stdout，结果缓存 credCommandTTL
+func runCredentialCommand(command string) (string, error) {
+	credCmdMu.Lock()
+	if r, ok := credCmdCache[command]; ok && time.Since(r.at) < credCommandTTL {
+		credCmdMu.Unlock()
+		return r.out, nil
+	}
+	credCmdMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s", i18n.Tf("cred_command_failed", command, err))
+	}
+	result := strings.TrimSpace(string(out))
+	credCmdMu.Lock()
+	credCmdCache[command] = credCmdResult{out: result, at: time.Now()}
+	credCmdMu.Unlock()
+	return result, nil
+}
+
+// awsProcessKeys AWS credential_process 输出格式到环境变量名的映射
+var awsProcessKeys = map[string]string{
+	"AccessKeyId":     "AWS_ACCESS_KEY_ID",
+	"SecretAccessKey": "AWS_SECRET_ACCESS_KEY",
+	"SessionToken":    "AWS_SESSION_TOKEN",
+}
+
+// credentialProcessValues 执行 credential_process，返回 环境变量名 -> 值。
+// 输出为 JSON 对象，键为环境变量名 (如 ALICLOUD_ACCESS_KEY)，也兼容 AWS 的 AccessKeyId / SecretAccessKey 格式
+func credentialProcessValues(command string) (map[string]string, error) {
+	out, err := runCredentialCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &raw); err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("cred_process_bad_output", err))
+	}
+	values := map[string]string{}
+	for k, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if env, ok := awsProcessKeys[k]; ok {
+			k = env
+		}
+		values[k] = s
+	}
+	return values, nil
+}
+
+// credentialResolver 解析凭据字段，主密钥和 credential_process 只在需要时获取一次
+type credentialResolver struct {
+	settings CredentialSettings
+	key      []byte
+	keyErr   error
+	keyDone  bool
+	process  map[string]string
+	procErr  error
+	procDone bool
+}
+
+func (r *credentialResolver) masterKey() ([]byte, error) {
+	if !r.keyDone {
+		r.key, r.keyErr = masterKey(r.settings)
+		r.keyDone = true
+	}
+	return r.key, r.keyErr
+}
+
+// resolve 返回字段的实际值
+func (r *credentialResolver) resolve(f credentialField, v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, credEncPrefix):
+		key, err := r.masterKey()
+		if err != nil {
+			return "", err
+		}
+		return openCredential(key, v)
+	case strings.HasPrefix(v, credEnvPrefix):
+		return os.Getenv(strings.TrimPrefix(v, credEnvPrefix)), nil
+	case strings.HasPrefix(v, credExecPrefix):
+		return runCredentialCommand(strings.TrimSpace(strings.TrimPrefix(v, credExecPrefix)))
+	case v == "" && r.settings.Process != "" && !f.Account:
+		if !r.procDone {
+			r.process, r.procErr = credentialProcessValues(r.settings.Process)
+			r.procDone = true
+		}
+		return r.process[f.Env], r.procErr
+	}
+	return v, nil
+}
+
+// resolveCredentials 将加密值和引用替换为实际值 (LoadConfig / ReadConfig 时调用)。
+// 无法解析的字段置空，避免密文或引用被当作凭据传给 terraform；未解锁时返回 *CredentialsLockedError
+func resolveCredentials(conf *Config) error {
+	r := &credentialResolver{settings: conf.Credentials}
+	var firstErr error
+	for _, f := range credentialFields(conf) {
+		v, err := r.resolve(f, f.get())
+		if err != nil {
+			f.set("")
+			if firstErr == nil || IsCredentialsLocked(err) {
+				firstErr = err
+			}
+			continue
+		}
+		f.set(v)
+		if secret.IsSensitiveName(f.Env) {
+			secret.Register(v)
+		}
+	}
+	if token := r.process["AWS_SESSION_TOKEN"]; token != "" {
+		os.Setenv("AWS_SESSION_TOKEN", token)
+		secret.Register(token)
+	}
+	return firstErr
+}
+
+// readRawConfig 读取未解析的配置文件，文件不存在时返回空配置
+func readRawConfig(configPath string) (*Config, error) {
+	var conf Config
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &conf, nil
+		}
+		return nil, fmt.Errorf("%s", i18n.Tf("config_load_file_failed", err))
+	}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("config_parse_failed", err))
+	}
+	return &conf, nil
+}
+
+func writeRawConfig(configPath string, conf *Config) error {
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		return fmt.Errorf("%s", i18n.Tf("config_serialize_failed", err))
+	}
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return fmt.Errorf("%s", i18n.Tf("config_write_failed", err))
+	}
+	return nil
+}
+
+// protectCredentials 保存前处理凭据字段：未修改的字段保留原来的密文或引用，
+// 启用加密存储时新写入的值会被加密
+func protectCredentials(conf *Config, raw *Config) error {
+	if !conf.Credentials.Encrypted() && raw.Credentials.Encrypted() {
+		conf.Credentials = raw.Credentials
+	}
+	r := &credentialResolver{settings: raw.Credentials}
+	enc := &credentialResolver{settings: conf.Credentials}
+	rawValues := map[string]string{}
+	for _, f := range credentialFields(raw) {
+		rawValues[f.Path] = f.get()
+	}
+	for _, f := range credentialFields(conf) {
+		nv, rv := f.get(), rawValues[f.Path]
+		if nv == rv {
+			continue
+		}
+		if resolved, err := r.resolve(f, rv); (err == nil && nv == resolved) || (err != nil && nv == "") {
+			f.set(rv)
+			continue
+		}
+		if nv == "" || isCredentialRef(nv) || !conf.Credentials.Encrypted() {
+			continue
+		}
+		key, err := enc.masterKey()
+		if err != nil {
+			return err
+		}
+		sealed, err := sealCredential(key, nv)
+		if err != nil {
+			return err
+		}
+		f.set(sealed)
+	}
+	return nil
+}
+
+// newCredentialSettings 生成新的 salt 和校验值
+func newCredentialSettings(old CredentialSettings, passphrase []byte, keyFile string) (CredentialSettings, []byte, error) {
+	s := CredentialSettings{Process: old.Process, KeyFile: keyFile}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return s, nil, err
+	}
+	s.Salt = base64.StdEncoding.EncodeToString(salt)
+	var key []byte
+	var err error
+	if keyFile != "" {
+		key, err = keyFromFile(keyFile, salt)
+	} else {
+		if len(passphrase) == 0 {
+			return s, nil, fmt.Errorf("%s", i18n.T("cred_passphrase_required"))
+		}
+		key, err = deriveCredentialKey(passphrase, salt)
+	}
+	if err != nil {
+		return s, nil, err
+	}
+	if s.Check, err = sealCredential(key, credCheckPlain); err != nil {
+		return s, nil, err
+	}
+	return s, key, nil
+}
+
+// MigrateCredentials 将配置文件中的明文凭据加密。尚未启用加密存储时使用 passphrase 或 keyFile 初始化，
+// 已启用时使用当前主密钥 (需要已解锁)。返回加密的字段数
+func MigrateCredentials(configPath string, passphrase []byte, keyFile string) (int, error) {
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return 0, err
+	}
+	var key []byte
+	if raw.Credentials.Encrypted() {
+		if key, err = masterKey(raw.Credentials); err != nil {
+			return 0, err
+		}
+	} else {
+		if raw.Credentials, key, err = newCredentialSettings(raw.Credentials, passphrase, keyFile); err != nil {
+			return 0, err
+		}
+	}
+	n := 0
+	for _, f := range credentialFields(raw) {
+		v := f.get()
+		if v == "" || isCredentialRef(v) {
+			continue
+		}
+		sealed, err := sealCredential(key, v)
+		if err != nil {
+			return n, err
+		}
+		f.set(sealed)
+		n++
+	}
+	if err := writeRawConfig(configPath, raw); err != nil {
+		return 0, err
+	}
+	rememberCredentialKey(key)
+	return n, nil
+}
+
+// RotateCredentials 使用新的口令或密钥文件重新加密所有凭据，需要当前主密钥可用
+func RotateCredentials(configPath string, passphrase []byte, keyFile string) (int, error) {
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return 0, err
+	}
+	if !raw.Credentials.Encrypted() {
+		return 0, fmt.Errorf("%s", i18n.T("cred_not_encrypted"))
+	}
+	oldKey, err := masterKey(raw.Credentials)
+	if err != nil {
+		return 0, err
+	}
+	settings, newKey, err := newCredentialSettings(raw.Credentials, passphrase, keyFile)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, f := range credentialFields(raw) {
+		v := f.get()
+		if !strings.HasPrefix(v, credEncPrefix) {
+			continue
+		}
+		plain, err := openCredential(oldKey, v)
+		if err != nil {
+			return 0, err
+		}
+		sealed, err := sealCredential(newKey, plain)
+		if err != nil {
+			return 0, err
+		}
+		f.set(sealed)
+		n++
+	}
+	raw.Credentials = settings
+	if err := writeRawConfig(configPath, raw); err != nil {
+		return 0, err
+	}
+	// 旧会话已失效
+	os.Remove(credentialSessionPath())
+	rememberCredentialKey(newKey)
+	return n, nil
+}
+
+// SealSecret 使用凭据存储的主密钥加密任意值 (如编排 secrets 中的 encrypted)，需要已启用加密存储并已解锁
+func SealSecret(configPath, plain string) (string, error) {
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return "", err
+	}
+	if !raw.Credentials.Encrypted() {
+		return "", fmt.Errorf("%s", i18n.T("cred_not_encrypted"))
+	}
+	key, err := masterKey(raw.Credentials)
+	if err != nil {
+		return "", err
+	}
+	return sealCredential(key, plain)
+}
+
+// OpenSecret 解密 SealSecret 生成的值，值的来源需要调用方登记脱敏
+func OpenSecret(configPath, sealed string) (string, error) {
+	if !strings.HasPrefix(sealed, credEncPrefix) {
+		return "", fmt.Errorf("%s", i18n.T("cred_decrypt_failed"))
+	}
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return "", err
+	}
+	if !raw.Credentials.Encrypted() {
+		return "", fmt.Errorf("%s", i18n.T("cred_not_encrypted"))
+	}
+	key, err := masterKey(raw.Credentials)
+	if err != nil {
+		return "", err
+	}
+	return openCredential(key, sealed)
+}
+
+// sealIfEncrypted 已启用加密存储时加密，未启用时原样返回；已加密但未解锁时返回错误，不会退回明文
+func sealIfEncrypted(configPath, plain string) (string, error) {
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return "", err
+	}
+	if !raw.Credentials.Encrypted() {
+		return plain, nil
+	}
+	key, err := masterKey(raw.Credentials)
+	if err != nil {
+		if IsCredentialsLocked(err) {
+			return "", fmt.Errorf("%s", i18n.T("cred_seal_locked"))
+		}
+		return "", err
+	}
+	return sealCredential(key, plain)
+}
+
+func rememberCredentialKey(key []byte) {
+	credKeyMu.Lock()
+	credKey = key
+	credKeyMu.Unlock()
+}
+
+// UnlockCredentials 校验口令并解锁凭据。ttl > 0 时写入会话文件并返回会话令牌，导出 REDC_CRED_SESSION 后
+// 之后的 CLI 调用在 ttl 内无需再次输入口令；ttl 为 0 时只在当前进程内解锁 (GUI)。解锁后重新加载当前配置
+func UnlockCredentials(configPath string, passphrase []byte, ttl time.Duration) (string, error) {
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return "", err
+	}
+	if !raw.Credentials.Encrypted() {
+		return "", fmt.Errorf("%s", i18n.T("cred_not_encrypted"))
+	}
+	key, err := keyForSettings(raw.Credentials, passphrase)
+	if err != nil {
+		return "", err
+	}
+	rememberCredentialKey(key)
+	var token string
+	if ttl > 0 {
+		if token, err = writeCredentialSession(raw.Credentials.Salt, key, ttl); err != nil {
+			return "", err
+		}
+	}
+	if conf, _, err := ReadConfig(configPath); err == nil {
+		bindEnv(*conf)
+		LoadedConfig = conf
+	}
+	return token, nil
+}
+
+// LockCredentials 清除进程内的主密钥、unlock 会话和缓存的角色临时凭据
+func LockCredentials() error {
+	rememberCredentialKey(nil)
+	if err := os.Remove(credentialSessionPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// 由已加密密钥换取的临时凭据一并清除
+	return ClearRoleCache()
+}
+
+// 凭据字段来源
+const (
+	CredSourceEmpty     = "empty"
+	CredSourcePlain     = "plain"
+	CredSourceEncrypted = "encrypted"
+	CredSourceEnv       = "env"
+	CredSourceExec      = "exec"
+	CredSourceProcess   = "process"
+)
+
+// CredentialFieldStatus 单个凭据字段的存储方式
+type CredentialFieldStatus struct {
+	Path   string `json:"path"`
+	Env    string `json:"env"`
+	Source string `json:"source"`
+}
+
+// CredentialStatus 凭据存储状态
+type CredentialStatus struct {
+	ConfigPath string                  `json:"configPath"`
+	Encrypted  bool                    `json:"encrypted"`
+	Locked     bool                    `json:"locked"`
+	KeyFile    string                  `json:"keyFile,omitempty"`
+	Process    string                  `json:"process,omitempty"`
+	Fields     []CredentialFieldStatus `json:"fields"`
+}
+
+// GetCredentialStatus 返回配置文件的凭据存储状态，不会解密任何值
+func GetCredentialStatus(configPath string) (*CredentialStatus, error) {
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	st := &CredentialStatus{
+		ConfigPath: configPath,
+		Encrypted:  raw.Credentials.Encrypted(),
+		KeyFile:    raw.Credentials.KeyFile,
+		Process:    raw.Credentials.Process,
+	}
+	if st.Encrypted {
+		_, err := masterKey(raw.Credentials)
+		st.Locked = err != nil
+	}
+	for _, f := range credentialFields(raw) {
+		v := f.get()
+		src := CredSourcePlain
+		switch {
+		case strings.HasPrefix(v, credEncPrefix):
+			src = CredSourceEncrypted
+		case strings.HasPrefix(v, credEnvPrefix):
+			src = CredSourceEnv
+		case strings.HasPrefix(v, credExecPrefix):
+			src = CredSourceExec
+		case v == "" && raw.Credentials.Process != "" && !f.Account:
+			src = CredSourceProcess
+		case v == "":
+			src = CredSourceEmpty
+		}
+		st.Fields = append(st.Fields, CredentialFieldStatus{Path: f.Path, Env: f.Env, Source: src})
+	}
+	return st, nil
+}
+
+// warnCredentialError 凭据解析失败时提示，未解锁时给出 unlock 提示
+func warnCredentialError(err error) {
+	if err == nil {
+		return
+	}
+	if IsCredentialsLocked(err) {
+		gologger.Warning().Msgf("%s", i18n.T("cred_locked_hint"))
+		return
+	}
+	gologger.Warning().Msgf("%s", i18n.Tf("cred_resolve_failed", err))
+}
@@ -0,0 +1,222 @@
+package mod
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// setupCredentialTest 使用临时 RedcPath，并在测试结束后恢复全局状态和 bindEnv 设置的环境变量
+func setupCredentialTest(t *testing.T) string {
+	t.Helper()
+	oldPath, oldConf := RedcPath, LoadedConfig
+	RedcPath = t.TempDir()
+	t.Cleanup(func() {
+		RedcPath, LoadedConfig = oldPath, oldConf
+		LockCredentials()
+	})
+	for _, env := range []string{"ALICLOUD_ACCESS_KEY", "ALICLOUD_SECRET_KEY", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", EnvMasterPassphrase, EnvCredentialKeyFile, EnvCredentialSession} {
+		t.Setenv(env, "")
+	}
+	return filepath.Join(RedcPath, "config.yaml")
+}
+
+func writeTestConfig(t *testing.T, path string, conf *Config) {
+	t.Helper()
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSealOpenCredential(t *testing.T) {
+	key, err := deriveCredentialKey([]byte("pass"), []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := sealCredential(key, "LTAI-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, credEncPrefix) || strings.Contains(sealed, "LTAI-secret") {
+		t.Fatalf("unexpected sealed value %q", sealed)
+	}
+	plain, err := openCredential(key, sealed)
+	if err != nil || plain != "LTAI-secret" {
+		t.Fatalf("open = %q, %v", plain, err)
+	}
+	other, _ := deriveCredentialKey([]byte("wrong"), []byte("salt"))
+	if _, err := openCredential(other, sealed); err == nil {
+		t.Error("wrong key should not decrypt")
+	}
+}
+
+func TestResolveCredentialRefs(t *testing.T) {
+	setupCredentialTest(t)
+	t.Setenv("TEST_REDC_AK", "ak-from-env")
+	var conf Config
+	conf.Providers.Alicloud.AccessKey = "env:TEST_REDC_AK"
+	conf.Providers.Alicloud.SecretKey = "exec:echo sk-from-exec"
+	conf.Credentials.Process = `echo '{"AccessKeyId":"AKIAPROCESS","SecretAccessKey":"sk-process","SessionToken":"token-process"}'`
+	if err := resolveCredentials(&conf); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Providers.Alicloud.AccessKey != "ak-from-env" || conf.Providers.Alicloud.SecretKey != "sk-from-exec" {
+		t.Errorf("refs not resolved: %+v", conf.Providers.Alicloud)
+	}
+	if conf.Providers.Aws.AccessKey != "AKIAPROCESS" || conf.Providers.Aws.SecretKey != "sk-process" {
+		t.Errorf("credential_process not applied: %+v", conf.Providers.Aws)
+	}
+	if os.Getenv("AWS_SESSION_TOKEN") != "token-process" {
+		t.Error("session token from credential_process should be exported")
+	}
+}
+
+func TestMigrateUnlockAndSave(t *testing.T) {
+	path := setupCredentialTest(t)
+	var conf Config
+	conf.Providers.Alicloud.AccessKey = "LTAI5tExample"
+	conf.Providers.Alicloud.SecretKey = "aliyun-secret-value"
+	conf.Providers.Aws.AccessKey = "env:TEST_REDC_AWS"
+	writeTestConfig(t, path, &conf)
+
+	n, err := MigrateCredentials(path, []byte("correct horse"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("migrated %d fields, want 2", n)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "aliyun-secret-value") || !strings.Contains(string(data), "env:TEST_REDC_AWS") {
+		t.Fatalf("config after migrate:\n%s", data)
+	}
+
+	LockCredentials()
+	locked, _, err := ReadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locked.Providers.Alicloud.SecretKey != "" {
+		t.Error("encrypted field should be empty while locked")
+	}
+	if _, err := UnlockCredentials(path, []byte("wrong"), 0); err == nil {
+		t.Error("wrong passphrase should fail")
+	}
+	// 未解锁时保存其他字段，密文保持不变
+	locked.Providers.Aws.Region = "us-east-1"
+	if err := SaveConfig(locked, path); err != nil {
+		t.Fatal(err)
+	}
+	locked.Providers.Alicloud.SecretKey = "new-secret"
+	if err := SaveConfig(locked, path); !IsCredentialsLocked(err) {
+		t.Errorf("saving a new credential while locked should fail, got %v", err)
+	}
+
+	if _, err := UnlockCredentials(path, []byte("correct horse"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if LoadedConfig == nil || LoadedConfig.Providers.Alicloud.SecretKey != "aliyun-secret-value" {
+		t.Fatal("config should be reloaded with decrypted values after unlock")
+	}
+	if os.Getenv("ALICLOUD_SECRET_KEY") != "aliyun-secret-value" {
+		t.Error("decrypted value should be bound to the environment")
+	}
+
+	// 新写入的值会被加密
+	conf2, _, _ := ReadConfig(path)
+	conf2.Providers.Alicloud.SecretKey = "rotated-secret"
+	if err := SaveConfig(conf2, path); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := readRawConfig(path)
+	if !strings.HasPrefix(raw.Providers.Alicloud.SecretKey, credEncPrefix) || raw.Providers.Aws.Region != "us-east-1" {
+		t.Errorf("unexpected stored config %+v", raw.Providers)
+	}
+	if raw.Providers.Aws.AccessKey != "env:TEST_REDC_AWS" {
+		t.Error("env reference should be kept on save")
+	}
+
+	st, err := GetCredentialStatus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Encrypted || st.Locked {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestUnlockSession(t *testing.T) {
+	path := setupCredentialTest(t)
+	var conf Config
+	conf.Providers.Alicloud.SecretKey = "aliyun-secret-value"
+	writeTestConfig(t, path, &conf)
+	if _, err := MigrateCredentials(path, []byte("correct horse"), ""); err != nil {
+		t.Fatal(err)
+	}
+	token, err := UnlockCredentials(path, []byte("correct horse"), time.Hour)
+	if err != nil || token == "" {
+		t.Fatalf("UnlockCredentials = %q, %v", token, err)
+	}
+	raw, _ := readRawConfig(path)
+	key, _ := keyForSettings(raw.Credentials, []byte("correct horse"))
+	data, _ := os.ReadFile(credentialSessionPath())
+	if strings.Contains(string(data), base64.StdEncoding.EncodeToString(key)) {
+		t.Error("session file should not contain the master key in clear text")
+	}
+
+	// 模拟新的 CLI 进程：进程内没有主密钥，只有导出了令牌才能使用会话
+	rememberCredentialKey(nil)
+	if _, err := masterKey(raw.Credentials); !IsCredentialsLocked(err) {
+		t.Errorf("session without token should stay locked, got %v", err)
+	}
+	t.Setenv(EnvCredentialSession, "wrong-token")
+	if _, err := masterKey(raw.Credentials); !IsCredentialsLocked(err) {
+		t.Errorf("wrong token should stay locked, got %v", err)
+	}
+	t.Setenv(EnvCredentialSession, token)
+	if got, err := masterKey(raw.Credentials); err != nil || string(got) != string(key) {
+		t.Errorf("session with token should unlock, got %v", err)
+	}
+}
+
+func TestRotateCredentialsWithKeyFile(t *testing.T) {
+	path := setupCredentialTest(t)
+	var conf Config
+	conf.Providers.Alicloud.SecretKey = "aliyun-secret-value"
+	writeTestConfig(t, path, &conf)
+	if _, err := MigrateCredentials(path, []byte("old pass"), ""); err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(RedcPath, "redc.key")
+	if err := GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := RotateCredentials(path, nil, keyFile); err != nil || n != 1 {
+		t.Fatalf("rotate = %d, %v", n, err)
+	}
+	// 密钥文件无需解锁
+	LockCredentials()
+	got, _, err := ReadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Providers.Alicloud.SecretKey != "aliyun-secret-value" {
+		t.Errorf("keyfile should decrypt after rotate, got %q", got.Providers.Alicloud.SecretKey)
+	}
+	if got.Credentials.KeyFile != keyFile {
+		t.Errorf("keyfile not recorded, got %q", got.Credentials.KeyFile)
+	}
+	got.Credentials.KeyFile = ""
+	if _, err := keyForSettings(got.Credentials, []byte("old pass")); err == nil {
+		t.Error("old passphrase should no longer work")
+	}
+}
@@ -156,6 +156,7 @@ type TemplateVariable struct {
 	Description  string              `json:"description"`
 	Required     bool                `json:"required"`
 	DefaultValue string              `json:"default_value,omitempty"`
+	Sensitive    bool                `json:"sensitive,omitempty"`
 	Validation   *VariableValidation `json:"validation,omitempty"`
 }
 
@@ -177,6 +178,7 @@ type DeploymentConfig struct {
 	Userdata       string            `json:"userdata,omitempty"`
 	IsSpotInstance bool              `json:"is_spot_instance"`
 	Variables      map[string]string `json:"variables"`
+	Account        string            `json:"account,omitempty"` // 命名账号，为空时使用默认凭据
 	CreatedAt      time.Time         `json:"created_at"`
 	UpdatedAt      time.Time         `json:"updated_at"`
 }
@@ -251,6 +253,14 @@ type CustomDeployment struct {
 	ProjectID    string                 `json:"-"` // 不序列化到 JSON，仅用于数据库操作
 }
 
+// account 部署使用的命名账号
+func (d *CustomDeployment) account() string {
+	if d.Config == nil {
+		return ""
+	}
+	return d.Config.Account
+}
+
 // GetProviderRegions 获取云厂商支持的地域
 func (s *CustomDeploymentService) GetProviderRegions(provider string) ([]Region, error) {
 	return GetProviderRegions(provider)
@@ -289,6 +299,9 @@ func (s *CustomDeploymentService) CreateCustomDeployment(config *DeploymentConfi
 	if !validationResult.Valid {
 		return nil, fmt.Errorf("配置验证失败: %v", validationResult.Errors)
 	}
+	if err := ValidateAccount(config.Account); err != nil {
+		return nil, err
+	}
 
 	// 2. 为需要密码的云厂商自动生成密码（如果用户未提供）
 	if config.Variables == nil {
@@ -318,6 +331,7 @@ func (s *CustomDeploymentService) CreateCustomDeployment(config *DeploymentConfi
 
 	// 5. 创建部署目录
 	deploymentPath := filepath.Join(projectPath, deploymentID)
+	SetDirAccount(deploymentPath, config.Account)
 	if err := os.MkdirAll(deploymentPath, 0755); err != nil {
 		return nil, fmt.Errorf("创建部署目录失败: %w", err)
 	}
@@ -360,8 +374,13 @@ func (s *CustomDeploymentService) CreateCustomDeployment(config *DeploymentConfi
 		return nil, fmt.Errorf("写入变量文件失败: %w", err)
 	}
 
-	// 10. 调用 Terraform init
-	if err := TfInit2(deploymentPath); err != nil {
+	// 10. 调用 Terraform init (项目配置了远程 backend 时同样使用)
+	initOpts, err := WriteBackendFile(deploymentPath, GetProjectBackend(projectID), projectID, deploymentID)
+	if err != nil {
+		os.RemoveAll(deploymentPath)
+		return nil, fmt.Errorf("配置远程 backend 失败: %w", err)
+	}
+	if err := TfInit2(deploymentPath, initOpts...); err != nil {
 		// 清理失败的部署目录
 		os.RemoveAll(deploymentPath)
 		return nil, fmt.Errorf("Terraform 初始化失败: %w", err)
@@ -433,6 +452,7 @@ func (s *CustomDeploymentService) StartCustomDeployment(projectID, deploymentID,
 	}
 
 	// 更新状态为启动中
+	prevState := deployment.State
 	deployment.State = StateStarting
 	deployment.UpdatedAt = time.Now()
 	if err := deployment.DBSave(); err != nil {
@@ -441,6 +461,7 @@ func (s *CustomDeploymentService) StartCustomDeployment(projectID, deploymentID,
 
 	// 获取部署目录
 	deploymentPath := filepath.Join(projectPath, deploymentID)
+	SetDirAccount(deploymentPath, deployment.account())
 
 	// 删除旧的 plan 文件（如果存在），避免 "Saved plan is stale" 错误
 	planFile := filepath.Join(deploymentPath, RedcPlanPath)
@@ -491,6 +512,22 @@ func (s *CustomDeploymentService) StartCustomDeployment(projectID, deploymentID,
 		}
 	}
 
+	// 配置了部署策略时先生成 plan 检查，拒绝时不创建任何资源
+	if policy, _ := LoadActivePolicy(); !policy.Empty() {
+		if err := TfPlan(deploymentPath); err != nil {
+			deployment.State = prevState
+			deployment.UpdatedAt = time.Now()
+			deployment.DBSave()
+			return err
+		}
+		if _, err := CheckPlanPolicy(deploymentPath, RedcPlanPath, deployment.Name); err != nil {
+			deployment.State = prevState
+			deployment.UpdatedAt = time.Now()
+			deployment.DBSave()
+			return err
+		}
+	}
+
 	// 执行 Terraform apply
 	if err := TfApply(deploymentPath); err != nil {
 		// 将错误信息保存到 outputs 中以便前端显示
@@ -577,6 +614,7 @@ func (s *CustomDeploymentService) StopCustomDeployment(projectID, deploymentID,
 
 	// 获取部署目录
 	deploymentPath := filepath.Join(projectPath, deploymentID)
+	SetDirAccount(deploymentPath, deployment.account())
 
 	// 执行 Terraform destroy
 	if err := TfDestroy(deploymentPath, nil); err != nil {
@@ -625,6 +663,7 @@ func (s *CustomDeploymentService) DeleteCustomDeployment(projectID, deploymentID
 
 	// 获取部署目录
 	deploymentPath := filepath.Join(projectPath, deploymentID)
+	SetDirAccount(deploymentPath, deployment.account())
 
 	// 如果部署正在运行，先执行 destroy
 	if deployment.State == StateRunning || deployment.State == StateStarting {
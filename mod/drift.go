@@ -0,0 +1,172 @@
+package mod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// RedcDriftPlanPath 漂移检测使用独立的 plan 文件，避免覆盖 case.tfplan
+const RedcDriftPlanPath = "drift.tfplan"
+
+// ResourceDrift 单个资源的漂移信息
+type ResourceDrift struct {
+	Address string   `json:"address"`
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Actions []string `json:"actions"`
+	Changed []string `json:"changed,omitempty"` // 发生变化的顶层属性
+}
+
+// DriftReport 场景漂移检测结果
+type DriftReport struct {
+	CaseID    string          `json:"caseId"`
+	CaseName  string          `json:"caseName"`
+	Template  string          `json:"template"`
+	State     string          `json:"state"`
+	Drifted   bool            `json:"drifted"`
+	Resources []ResourceDrift `json:"resources"`
+	CheckedAt string          `json:"checkedAt"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// TfDriftPlan 以 refresh-only 模式执行 plan，返回云上实际状态与 state 的差异
+func TfDriftPlan(Path string, opts ...string) (*tfjson.Plan, error) {
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	gologger.Debug().Msgf("Detecting drift in %s\n", Path)
+	te, err := NewTerraformExecutor(Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	o := ToPlan(opts)
+	o = append(o, tfexec.RefreshOnly(true), tfexec.Out(RedcDriftPlanPath))
+	if err := te.Plan(ctx, o...); err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("drift_plan_failed", err))
+	}
+	defer os.Remove(filepath.Join(Path, RedcDriftPlanPath))
+	return te.ShowPlanFile(ctx, RedcDriftPlanPath)
+}
+
+// DetectDrift 检测单个场景的资源漂移。检测期间持有场景锁，场景正在变更时返回 CaseLockedError
+func (c *Case) DetectDrift() (*DriftReport, error) {
+	report := &DriftReport{
+		CaseID:    c.Id,
+		CaseName:  c.Name,
+		Template:  c.Type,
+		State:     c.State,
+		Resources: []ResourceDrift{},
+		CheckedAt: time.Now().Format(time.RFC3339),
+	}
+	if c.State != StateRunning {
+		return report, fmt.Errorf("%s", i18n.Tf("drift_case_not_running", c.Name, c.State))
+	}
+	// refresh-only plan 会读写 state，不能与 apply/destroy 等操作并发
+	release, err := c.acquireLock("drift")
+	if err != nil {
+		report.Error = err.Error()
+		return report, err
+	}
+	defer release()
+	// 取得锁之前场景可能已被停止或删除，按最新记录判断
+	if cur, err := FindCaseBySearch(c.ProjectID, c.Id); err == nil && cur.State != StateRunning {
+		report.State = cur.State
+		err = fmt.Errorf("%s", i18n.Tf("drift_case_not_running", c.Name, cur.State))
+		report.Error = err.Error()
+		return report, err
+	}
+	plan, err := TfDriftPlan(c.Path, c.Parameter...)
+	if err != nil {
+		report.Error = err.Error()
+		return report, err
+	}
+	report.Resources = collectDrift(plan)
+	report.Drifted = len(report.Resources) > 0
+	return report, nil
+}
+
+// collectDrift 汇总 plan 中的 resource_drift 与 refresh-only 的 resource_changes
+func collectDrift(plan *tfjson.Plan) []ResourceDrift {
+	result := []ResourceDrift{}
+	if plan == nil {
+		return result
+	}
+	seen := make(map[string]bool)
+	add := func(rc *tfjson.ResourceChange) {
+		if rc == nil || rc.Change == nil || seen[rc.Address] {
+			return
+		}
+		if rc.Change.Actions.NoOp() || rc.Change.Actions.Read() {
+			return
+		}
+		seen[rc.Address] = true
+		actions := make([]string, 0, len(rc.Change.Actions))
+		for _, a := range rc.Change.Actions {
+			actions = append(actions, string(a))
+		}
+		result = append(result, ResourceDrift{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Name:    rc.Name,
+			Actions: actions,
+			Changed: changedAttributes(rc.Change.Before, rc.Change.After),
+		})
+	}
+	for _, rc := range plan.ResourceDrift {
+		add(rc)
+	}
+	for _, rc := range plan.ResourceChanges {
+		add(rc)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Address < result[j].Address })
+	return result
+}
+
+// changedAttributes 比较 before/after 的顶层属性，返回发生变化的属性名
+func changedAttributes(before, after interface{}) []string {
+	b, _ := before.(map[string]interface{})
+	a, _ := after.(map[string]interface{})
+	keys := make(map[string]bool)
+	for k := range b {
+		keys[k] = true
+	}
+	for k := range a {
+		keys[k] = true
+	}
+	var changed []string
+	for k := range keys {
+		if !reflect.DeepEqual(b[k], a[k]) {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// DetectProjectDrift 检测项目内所有运行中场景的漂移
+func DetectProjectDrift(projectName string) ([]*DriftReport, error) {
+	cases, err := LoadProjectCases(projectName)
+	if err != nil {
+		return nil, err
+	}
+	var reports []*DriftReport
+	for _, c := range cases {
+		if c.State != StateRunning {
+			continue
+		}
+		report, err := c.DetectDrift()
+		if err != nil {
+			gologger.Warning().Msgf("%s", i18n.Tf("drift_case_failed", c.Name, err))
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,82 @@
+package mod
+
+import (
+	"reflect"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestCollectDrift(t *testing.T) {
+	plan := &tfjson.Plan{
+		ResourceDrift: []*tfjson.ResourceChange{
+			{
+				Address: "aws_instance.web",
+				Type:    "aws_instance",
+				Name:    "web",
+				Change: &tfjson.Change{
+					Actions: tfjson.Actions{tfjson.ActionUpdate},
+					Before:  map[string]interface{}{"instance_type": "t3.micro", "tags": map[string]interface{}{"a": "1"}},
+					After:   map[string]interface{}{"instance_type": "t3.large", "tags": map[string]interface{}{"a": "1"}},
+				},
+			},
+			{
+				Address: "aws_eip.ip",
+				Type:    "aws_eip",
+				Name:    "ip",
+				Change: &tfjson.Change{
+					Actions: tfjson.Actions{tfjson.ActionDelete},
+					Before:  map[string]interface{}{"public_ip": "1.2.3.4"},
+				},
+			},
+		},
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "aws_instance.web",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}},
+			},
+			{
+				Address: "aws_security_group.sg",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}},
+			},
+		},
+	}
+
+	got := collectDrift(plan)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 drifted resources, got %d: %+v", len(got), got)
+	}
+	if got[0].Address != "aws_eip.ip" || got[1].Address != "aws_instance.web" {
+		t.Errorf("unexpected order: %s, %s", got[0].Address, got[1].Address)
+	}
+	if !reflect.DeepEqual(got[1].Changed, []string{"instance_type"}) {
+		t.Errorf("expected only instance_type changed, got %v", got[1].Changed)
+	}
+	if !reflect.DeepEqual(got[0].Changed, []string{"public_ip"}) {
+		t.Errorf("expected public_ip changed on delete, got %v", got[0].Changed)
+	}
+
+	if res := collectDrift(nil); len(res) != 0 {
+		t.Errorf("expected empty result for nil plan, got %v", res)
+	}
+}
+
+// 其他操作持有场景锁时漂移检测不执行 plan
+func TestDetectDriftRespectsCaseLock(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "drift-locked", Name: "locked", ProjectID: "default", State: StateRunning, Path: RedcPath}
+	holder := &Case{Id: c.Id, Name: "holder"}
+	release, err := holder.acquireLock("change")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	report, err := c.DetectDrift()
+	if !IsCaseLocked(err) {
+		t.Fatalf("expected case locked error, got %v", err)
+	}
+	if report.Error == "" || report.Drifted {
+		t.Fatalf("locked case should be reported as not checked: %+v", report)
+	}
+}
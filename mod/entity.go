@@ -40,8 +40,15 @@ type Case struct {
 	State        string   `json:"state"`
 	ProjectID    string   `json:"-"`
 	Output       string
+	// LeaseExpiresAt TTL 到期时间 (RFC3339)，未设置 TTL 时为空，单独存放在 Case_Leases 中
+	LeaseExpiresAt string `json:"lease_expires_at,omitempty"`
+	// Account 使用的命名账号，为空时使用默认凭据，单独存放在 Case_Accounts 中
+	Account      string `json:"account,omitempty"`
 	output       map[string]tfexec.OutputMeta
 	saveHandler  func() error
 	removeHandle func() error
 	pluginHookRunner func(hookPoint string, c *Case) error
+	lockHandle       *caseLockHandle // 当前进程持有的场景锁
+	actor            string          // 状态变迁记录的操作者，见 SetActor
+	source           string          // 状态变迁记录的来源
 }
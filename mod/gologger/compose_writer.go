@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"red-cloud/mod/secret"
 	"sync"
 	"time"
 )
@@ -45,11 +46,12 @@ func (m *LogManager) NewServiceLogger(serviceName string) (*ComposeWriter, error
 }
 
 func (w *ComposeWriter) Write(p []byte) (n int, err error) {
+	data := []byte(secret.Redact(string(p)))
 	if w.File != nil {
-		w.File.Write(p)
+		w.File.Write(data)
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(p))
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	consoleMu.Lock()
 	defer consoleMu.Unlock()
 
@@ -93,7 +95,7 @@ func (w *EventWriter) Write(p []byte) (n int, err error) {
 	if w.emitter == nil {
 		return len(p), nil
 	}
-	scanner := bufio.NewScanner(bytes.NewReader(p))
+	scanner := bufio.NewScanner(bytes.NewReader([]byte(secret.Redact(string(p)))))
 	for scanner.Scan() {
 		text := scanner.Text()
 		if text != "" {
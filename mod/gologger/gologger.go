@@ -3,6 +3,7 @@ package gologger
 import (
 	"fmt"
 	"os"
+	"red-cloud/mod/secret"
 	"strings"
 	"time"
 
@@ -45,7 +46,8 @@ func (l *Logger) Log(event *Event) {
 	if !isCurrentLevelEnabled(event) {
 		return
 	}
-	event.message = strings.TrimSuffix(event.message, "\n")
+	// 写出前对已登记的敏感值脱敏
+	event.message = secret.Redact(strings.TrimSuffix(event.message, "\n"))
 	data, err := l.formatter.Format(&formatter.LogEvent{
 		Message:  event.message,
 		Level:    event.level,
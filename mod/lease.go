@@ -0,0 +1,233 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"red-cloud/i18n"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const caseLeaseBucket = "Case_Leases"
+
+// DefaultLeaseWarnBefore TTL 到期前默认提前提醒时间
+const DefaultLeaseWarnBefore = 30 * time.Minute
+
+// CaseLease 场景租约 (TTL)，到期后由 TaskScheduler 自动销毁
+type CaseLease struct {
+	CaseID    string    `json:"caseId"`
+	CaseName  string    `json:"caseName"`
+	ProjectID string    `json:"projectId"`
+	TTL       string    `json:"ttl"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Warned    bool      `json:"warned"` // 已发送到期提醒
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Remaining 剩余租约时长，已到期时返回 0
+func (l *CaseLease) Remaining() time.Duration {
+	if d := time.Until(l.ExpiresAt); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Expired 租约是否已到期
+func (l *CaseLease) Expired() bool {
+	return !time.Now().Before(l.ExpiresAt)
+}
+
+// ParseTTL 解析 TTL，在 time.ParseDuration 基础上支持 d (天)，如 8h、30m、2d、1d12h
+func ParseTTL(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	orig := s
+	var days time.Duration
+	if i := strings.Index(s, "d"); i > 0 {
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, fmt.Errorf("%s", i18n.Tf("lease_invalid_ttl", orig))
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		s = s[i+1:]
+		if s == "" {
+			return days, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d+days < 0 {
+		return 0, fmt.Errorf("%s", i18n.Tf("lease_invalid_ttl", orig))
+	}
+	return d + days, nil
+}
+
+// FormatLeaseRemaining 以 2d3h、5h20m、15m 的形式显示剩余时长
+func FormatLeaseRemaining(d time.Duration) string {
+	if d <= 0 {
+		return "expired"
+	}
+	d = d.Round(time.Minute)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// putLease 写入租约
+func putLease(tx *bolt.Tx, l *CaseLease) error {
+	b, err := tx.CreateBucketIfNotExists([]byte(caseLeaseBucket))
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(l.CaseID), data)
+}
+
+// getLease 读取租约，不存在时返回 nil
+func getLease(tx *bolt.Tx, caseID string) *CaseLease {
+	b := tx.Bucket([]byte(caseLeaseBucket))
+	if b == nil {
+		return nil
+	}
+	data := b.Get([]byte(caseID))
+	if data == nil {
+		return nil
+	}
+	var l CaseLease
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil
+	}
+	return &l
+}
+
+// deleteLease 删除租约
+func deleteLease(tx *bolt.Tx, caseID string) error {
+	b := tx.Bucket([]byte(caseLeaseBucket))
+	if b == nil {
+		return nil
+	}
+	return b.Delete([]byte(caseID))
+}
+
+// SetLease 设置场景 TTL，从现在开始计时；ttl <= 0 时取消 TTL
+func (c *Case) SetLease(ttl time.Duration) (*CaseLease, error) {
+	if ttl <= 0 {
+		return nil, ClearCaseLease(c.Id)
+	}
+	now := time.Now()
+	l := &CaseLease{
+		CaseID:    c.Id,
+		CaseName:  c.Name,
+		ProjectID: c.ProjectID,
+		TTL:       ttl.String(),
+		ExpiresAt: now.Add(ttl),
+		UpdatedBy: U,
+		UpdatedAt: now,
+	}
+	if err := dbExec(func(tx *bolt.Tx) error { return putLease(tx, l) }); err != nil {
+		return nil, err
+	}
+	c.LeaseExpiresAt = l.ExpiresAt.Format(time.RFC3339)
+	return l, nil
+}
+
+// ExtendCaseLease 延长场景 TTL，已到期的租约从现在开始延长
+func ExtendCaseLease(caseID string, d time.Duration) (*CaseLease, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("%s", i18n.Tf("lease_invalid_ttl", d.String()))
+	}
+	var l *CaseLease
+	err := dbExec(func(tx *bolt.Tx) error {
+		l = getLease(tx, caseID)
+		if l == nil {
+			return fmt.Errorf("%s", i18n.Tf("lease_not_set", caseID))
+		}
+		base := l.ExpiresAt
+		if now := time.Now(); base.Before(now) {
+			base = now
+		}
+		l.ExpiresAt = base.Add(d)
+		l.Warned = false
+		l.UpdatedBy = U
+		l.UpdatedAt = time.Now()
+		return putLease(tx, l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// MarkLeaseWarned 记录已发送到期提醒，避免重复发送
+func MarkLeaseWarned(caseID string) error {
+	return dbExec(func(tx *bolt.Tx) error {
+		l := getLease(tx, caseID)
+		if l == nil {
+			return nil
+		}
+		l.Warned = true
+		return putLease(tx, l)
+	})
+}
+
+// ClearCaseLease 取消场景 TTL
+func ClearCaseLease(caseID string) error {
+	return dbExec(func(tx *bolt.Tx) error { return deleteLease(tx, caseID) })
+}
+
+// GetCaseLease 查询场景 TTL，未设置时返回 nil
+func GetCaseLease(caseID string) (*CaseLease, error) {
+	var l *CaseLease
+	err := dbExec(func(tx *bolt.Tx) error {
+		l = getLease(tx, caseID)
+		return nil
+	})
+	return l, err
+}
+
+// ListCaseLeases 列出所有场景 TTL
+func ListCaseLeases() ([]*CaseLease, error) {
+	var leases []*CaseLease
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(caseLeaseBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var l CaseLease
+			if err := json.Unmarshal(v, &l); err == nil {
+				leases = append(leases, &l)
+			}
+			return nil
+		})
+	})
+	return leases, err
+}
+
+// attachLeases 为加载的场景填充 TTL 到期时间
+func attachLeases(tx *bolt.Tx, cases []*Case) {
+	if tx.Bucket([]byte(caseLeaseBucket)) == nil {
+		return
+	}
+	for _, c := range cases {
+		if l := getLease(tx, c.Id); l != nil {
+			c.LeaseExpiresAt = l.ExpiresAt.Format(time.RFC3339)
+		}
+	}
+}
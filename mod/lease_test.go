@@ -0,0 +1,105 @@
+package mod
+
+import (
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestParseTTL(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":      0,
+		"30m":   30 * time.Minute,
+		"8h":    8 * time.Hour,
+		"2d":    48 * time.Hour,
+		"1d12h": 36 * time.Hour,
+	}
+	for in, want := range cases {
+		got, err := ParseTTL(in)
+		if err != nil || got != want {
+			t.Errorf("ParseTTL(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"abc", "xd", "-5h"} {
+		if _, err := ParseTTL(in); err == nil {
+			t.Errorf("ParseTTL(%q) should fail", in)
+		}
+	}
+}
+
+func TestCaseLeaseLifecycle(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "lease-case-1", Name: "web", ProjectID: "default", CreateTime: "2024-01-01 00:00:00"}
+	if err := c.DBSave(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := c.SetLease(time.Hour)
+	if err != nil || l == nil {
+		t.Fatalf("set lease: %v", err)
+	}
+	if _, err := ExtendCaseLease(c.Id, 30*time.Minute); err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+	got, _ := GetCaseLease(c.Id)
+	if got == nil || got.Remaining() < 89*time.Minute || got.Remaining() > 90*time.Minute {
+		t.Fatalf("unexpected remaining after extend: %+v", got)
+	}
+
+	cases, err := LoadProjectCases("default")
+	if err != nil || len(cases) != 1 || cases[0].LeaseExpiresAt == "" {
+		t.Fatalf("lease not attached to loaded case: %+v %v", cases, err)
+	}
+
+	if err := c.DBRemove(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := GetCaseLease(c.Id); got != nil {
+		t.Fatalf("lease should be removed with the case, got %+v", got)
+	}
+	if _, err := ExtendCaseLease(c.Id, time.Hour); err == nil {
+		t.Fatal("extending a missing lease should fail")
+	}
+}
+
+func TestSchedulerCaseLeases(t *testing.T) {
+	RedcPath = t.TempDir()
+	soon := &Case{Id: "lease-soon", Name: "soon", ProjectID: "default"}
+	expired := &Case{Id: "lease-expired", Name: "expired", ProjectID: "default"}
+	if _, err := soon.SetLease(10 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expired.SetLease(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// 直接改写为已过期
+	l, _ := GetCaseLease(expired.Id)
+	l.ExpiresAt = time.Now().Add(-time.Minute)
+	if err := dbExec(func(tx *bolt.Tx) error { return putLease(tx, l) }); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewTaskScheduler(nil, "")
+	var notified []string
+	s.SetNotifyCallback(func(title, msg string) { notified = append(notified, msg) })
+
+	s.checkCaseLeases()
+	s.checkCaseLeases()
+
+	if len(notified) != 1 {
+		t.Fatalf("expected exactly one warning, got %v", notified)
+	}
+	var destroyTasks int
+	for _, task := range s.ListTasks() {
+		if task.Action == "ttl_destroy" {
+			destroyTasks++
+			if task.CaseID != expired.Id {
+				t.Errorf("unexpected destroy task for %s", task.CaseID)
+			}
+		}
+	}
+	if destroyTasks != 1 {
+		t.Fatalf("expected one ttl_destroy task, got %d", destroyTasks)
+	}
+}
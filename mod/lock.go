@@ -0,0 +1,282 @@
+package mod
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const caseLockBucket = "Case_Locks"
+
+var (
+	// CaseLockTTL 租约时长，持有期间会定期续约；进程崩溃后最多 TTL 时间即可被接管
+	CaseLockTTL = 2 * time.Minute
+	// caseLockRenewInterval 续约间隔
+	caseLockRenewInterval = 30 * time.Second
+	// LockOwner 进程默认的操作来源 (cli / gui / http / mcp)，由入口设置；单个场景可由 SetActor 覆盖
+	LockOwner = "cli"
+)
+
+// CaseLock 场景锁记录，保存在 bbolt 中，跨进程可见
+type CaseLock struct {
+	CaseID     string    `json:"caseId"`
+	Owner      string    `json:"owner"`
+	Operation  string    `json:"operation"`
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// Stale 租约过期，或持有进程在本机已不存在
+func (l *CaseLock) Stale() bool {
+	if time.Now().After(l.ExpiresAt) {
+		return true
+	}
+	if host, _ := os.Hostname(); host == l.Host && !processAlive(l.PID) {
+		return true
+	}
+	return false
+}
+
+// CaseLockedError 场景已被其他操作锁定
+type CaseLockedError struct {
+	Lock *CaseLock
+}
+
+func (e *CaseLockedError) Error() string {
+	return i18n.Tf("lock_case_locked", e.Lock.CaseID, e.Lock.Operation, e.Lock.Owner, e.Lock.PID, e.Lock.Host, e.Lock.AcquiredAt.Format("2006-01-02 15:04:05"))
+}
+
+// IsCaseLocked 判断错误是否为场景锁冲突
+func IsCaseLocked(err error) bool {
+	var le *CaseLockedError
+	return errors.As(err, &le)
+}
+
+// processAlive 检查本机进程是否存活
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Windows 下 FindProcess 成功即表示进程存在
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// caseLockHandle 当前进程持有的锁。锁不可重入，已持有锁的组合操作调用 ...Locked 方法 (如 TfDestroyLocked)
+type caseLockHandle struct {
+	mu     sync.Mutex
+	lock   CaseLock
+	stopCh chan struct{}
+	op     *opLog // 本次操作的日志
+}
+
+// caseLockMu 保护所有 Case 的 lockHandle 字段
+var caseLockMu sync.Mutex
+
+// AcquireLock 获取场景锁，返回释放函数。供需要在同一把锁内组合多个 terraform 操作的调用方使用 (如 Spot 自动恢复)，
+// 持有期间只能调用 ...Locked 方法，再调用 TfDestroy 等加锁方法会得到 CaseLockedError
+func (c *Case) AcquireLock(operation string) (func(), error) {
+	return c.acquireLock(operation)
+}
+
+// lockOwner 锁与操作日志中的持有方，格式为 来源:操作者
+func (c *Case) lockOwner() string {
+	actor, source := c.transitionActor()
+	return fmt.Sprintf("%s:%s", source, actor)
+}
+
+// acquireLock 获取场景锁，返回释放函数。锁不可重入：本进程已持有时与其他进程一样得到 CaseLockedError
+func (c *Case) acquireLock(operation string) (func(), error) {
+	caseLockMu.Lock()
+	if h := c.lockHandle; h != nil {
+		h.mu.Lock()
+		held := h.lock
+		h.mu.Unlock()
+		caseLockMu.Unlock()
+		return nil, &CaseLockedError{Lock: &held}
+	}
+	caseLockMu.Unlock()
+
+	host, _ := os.Hostname()
+	now := time.Now()
+	l := CaseLock{
+		CaseID:     c.Id,
+		Owner:      c.lockOwner(),
+		Operation:  operation,
+		PID:        os.Getpid(),
+		Host:       host,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(CaseLockTTL),
+	}
+	if err := putCaseLock(&l, false); err != nil {
+		return nil, err
+	}
+
+	h := &caseLockHandle{lock: l, stopCh: make(chan struct{})}
+	if h.op = beginCaseOp(c, OpKindTerraform, operation); h.op != nil {
+		h.op.attach(c.Path)
+	}
+	caseLockMu.Lock()
+	c.lockHandle = h
+	caseLockMu.Unlock()
+	go h.renew()
+	return func() { c.releaseLock(h) }, nil
+}
+
+// releaseLock 释放场景锁
+func (c *Case) releaseLock(h *caseLockHandle) {
+	close(h.stopCh)
+	caseLockMu.Lock()
+	if c.lockHandle == h {
+		c.lockHandle = nil
+	}
+	caseLockMu.Unlock()
+	if h.op != nil {
+		h.op.finish(nil)
+	}
+	if err := deleteCaseLock(h.lock.CaseID, h.lock.PID, h.lock.AcquiredAt); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("lock_release_failed", c.Name, err))
+	}
+}
+
+// renew 定期续约，直到释放
+func (h *caseLockHandle) renew() {
+	ticker := time.NewTicker(caseLockRenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.mu.Lock()
+			h.lock.ExpiresAt = time.Now().Add(CaseLockTTL)
+			l := h.lock
+			h.mu.Unlock()
+			if err := putCaseLock(&l, true); err != nil {
+				gologger.Warning().Msgf("%s", i18n.Tf("lock_renew_failed", l.CaseID, err))
+			}
+		case <-h.stopCh:
+			return
+		}
+	}
+}
+
+// putCaseLock 写入锁记录。renew 为 true 时只允许同一持有者续约
+func putCaseLock(l *CaseLock, renew bool) error {
+	return dbExec(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(caseLockBucket))
+		if err != nil {
+			return err
+		}
+		if data := b.Get([]byte(l.CaseID)); data != nil {
+			var cur CaseLock
+			if err := json.Unmarshal(data, &cur); err == nil {
+				sameHolder := cur.PID == l.PID && cur.Host == l.Host && cur.AcquiredAt.Equal(l.AcquiredAt)
+				if !sameHolder {
+					if renew || !cur.Stale() {
+						return &CaseLockedError{Lock: &cur}
+					}
+					gologger.Warning().Msgf("%s", i18n.Tf("lock_stale_taken_over", cur.CaseID, cur.Operation, cur.Owner, cur.PID))
+				}
+			}
+		}
+		data, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(l.CaseID), data)
+	})
+}
+
+// deleteCaseLock 删除锁记录，只删除自己持有的锁
+func deleteCaseLock(caseID string, pid int, acquiredAt time.Time) error {
+	return dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(caseLockBucket))
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(caseID))
+		if data == nil {
+			return nil
+		}
+		var cur CaseLock
+		if err := json.Unmarshal(data, &cur); err == nil {
+			if cur.PID != pid || !cur.AcquiredAt.Equal(acquiredAt) {
+				return nil // 已被接管或强制解锁
+			}
+		}
+		return b.Delete([]byte(caseID))
+	})
+}
+
+// GetCaseLock 查询场景当前的锁，未锁定时返回 nil
+func GetCaseLock(caseID string) (*CaseLock, error) {
+	var l *CaseLock
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(caseLockBucket))
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(caseID))
+		if data == nil {
+			return nil
+		}
+		l = &CaseLock{}
+		return json.Unmarshal(data, l)
+	})
+	return l, err
+}
+
+// ListCaseLocks 列出所有场景锁
+func ListCaseLocks() ([]*CaseLock, error) {
+	var locks []*CaseLock
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(caseLockBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var l CaseLock
+			if err := json.Unmarshal(v, &l); err == nil {
+				locks = append(locks, &l)
+			}
+			return nil
+		})
+	})
+	return locks, err
+}
+
+// ForceUnlockCase 强制删除场景锁 (redc unlock)，返回被删除的锁
+func ForceUnlockCase(caseID string) (*CaseLock, error) {
+	var removed *CaseLock
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(caseLockBucket))
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(caseID))
+		if data == nil {
+			return nil
+		}
+		removed = &CaseLock{}
+		json.Unmarshal(data, removed)
+		return b.Delete([]byte(caseID))
+	})
+	return removed, err
+}
@@ -0,0 +1,111 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCaseLockAcquireConflictAndRelease(t *testing.T) {
+	RedcPath = t.TempDir()
+	a := &Case{Id: "case-lock-1", Name: "a"}
+	release, err := a.acquireLock("apply")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	// 锁不可重入，同一 Case 对象再次获取也会冲突，且不影响已持有的锁
+	if _, err := a.acquireLock("destroy"); !IsCaseLocked(err) {
+		t.Fatalf("reacquire on the same case should conflict, got %v", err)
+	}
+	if l, _ := GetCaseLock(a.Id); l == nil || l.Operation != "apply" {
+		t.Fatalf("lock should still be held after failed reacquire, got %+v", l)
+	}
+
+	// 另一个 Case 对象 (模拟其他进程) 获取失败
+	b := &Case{Id: a.Id, Name: "b"}
+	if _, err := b.acquireLock("stop"); !IsCaseLocked(err) {
+		t.Fatalf("expected CaseLockedError, got %v", err)
+	}
+
+	release()
+	if l, _ := GetCaseLock(a.Id); l != nil {
+		t.Fatalf("lock should be removed, got %+v", l)
+	}
+	releaseB, err := b.acquireLock("stop")
+	if err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+	releaseB()
+}
+
+func TestCaseLockComposedOperations(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform is a shell script")
+	}
+	RedcPath = t.TempDir()
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	// Stop 持锁后通过 TfDestroyLocked 销毁，不会与自己的锁冲突
+	c := &Case{Id: "case-lock-3", Name: "c", ProjectID: "default", State: StateRunning, Path: t.TempDir()}
+	if err := c.Stop(); IsCaseLocked(err) {
+		t.Fatalf("stop should not conflict with its own lock: %v", err)
+	}
+	if l, _ := GetCaseLock(c.Id); l != nil {
+		t.Fatalf("lock should be released after stop, got %+v", l)
+	}
+
+	// 外部持锁后只能调用 Locked 变体
+	release, err := c.AcquireLock("spot-recover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if err := c.TfDestroy(); !IsCaseLocked(err) {
+		t.Fatalf("TfDestroy under a held lock should conflict, got %v", err)
+	}
+	if err := c.TfDestroyLocked(); IsCaseLocked(err) {
+		t.Fatalf("TfDestroyLocked should run under the held lock: %v", err)
+	}
+}
+
+func TestCaseLockStaleTakeoverAndForceUnlock(t *testing.T) {
+	RedcPath = t.TempDir()
+	host, _ := os.Hostname()
+	stale := &CaseLock{
+		CaseID:     "case-lock-2",
+		Owner:      "cli:system",
+		Operation:  "apply",
+		PID:        os.Getpid(),
+		Host:       host,
+		AcquiredAt: time.Now().Add(-time.Hour),
+		ExpiresAt:  time.Now().Add(-time.Minute),
+	}
+	if !stale.Stale() {
+		t.Fatal("expired lock should be stale")
+	}
+	if err := putCaseLock(stale, false); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Case{Id: stale.CaseID, Name: "c"}
+	release, err := c.acquireLock("destroy")
+	if err != nil {
+		t.Fatalf("stale lock should be taken over: %v", err)
+	}
+	removed, err := ForceUnlockCase(c.Id)
+	if err != nil || removed == nil || removed.Operation != "destroy" {
+		t.Fatalf("force unlock: removed=%+v err=%v", removed, err)
+	}
+	// 锁已被强制删除，释放时不应报错也不应删除别人的锁
+	release()
+	if l, _ := GetCaseLock(c.Id); l != nil {
+		t.Fatalf("expected no lock, got %+v", l)
+	}
+}
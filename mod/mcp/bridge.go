@@ -12,6 +12,10 @@ type AppBridge interface {
 	MCPComposeUpSync(filePath string, profiles []string) (interface{}, error)
 	MCPComposeDown(filePath string, profiles []string) error
 	MCPComposeDownSync(filePath string, profiles []string) error
+	MCPListComposeStacks() (interface{}, error)
+	MCPComposeDownStackSync(stack string) error
+	MCPComposeScaleSync(stack string, replicas map[string]int) (interface{}, error)
+	MCPComposePlan(filePath string, profiles []string) (interface{}, error)
 
 	// Cost & Resources
 	MCPGetCostEstimate(templateName string, variables map[string]string) (interface{}, error)
@@ -18,6 +18,7 @@ import (
 	"red-cloud/mod/ai"
 	"red-cloud/mod/gologger"
 	"red-cloud/mod/plugin"
+	"red-cloud/mod/secret"
 	"red-cloud/utils/sshutil"
 )
 
@@ -243,7 +244,7 @@ func (s *MCPServer) HandleRequest(req *MCPRequest) *MCPResponse {
 				Data:    err.Error(),
 			}
 		} else {
-			result, err := s.executeTool(params.Name, params.Arguments)
+			result, err := s.runTool(params.Name, params.Arguments)
 			if err != nil {
 				resp.Result = ToolResult{
 					Content: []ContentItem{{
@@ -374,6 +375,14 @@ func (s *MCPServer) getTools() []Tool {
 						Type:        "object",
 						Description: "Environment variables / terraform variables for the template (optional). Note: for proxy templates (aliyun/proxy, aws/proxy), the 'node' variable controls how many VMs to create in one case (default: 10). Set node=N for N machines rather than creating N separate cases.",
 					},
+					"ttl": {
+						Type:        "string",
+						Description: "Optional time-to-live (e.g. '8h', '30m', '2d'). The case is destroyed automatically when it expires.",
+					},
+					"account": {
+						Type:        "string",
+						Description: "Optional named provider account from the redc config (default credentials when omitted)",
+					},
 				},
 				Required: []string{"template"},
 			},
@@ -633,6 +642,12 @@ func (s *MCPServer) getTools() []Tool {
 		},
 	}
 
+	tools = append(tools, driftToolSchemas()...)
+	tools = append(tools, leaseToolSchemas()...)
+	tools = append(tools, targetToolSchemas()...)
+	tools = append(tools, opLogToolSchemas()...)
+	tools = append(tools, stateHistoryToolSchemas()...)
+
 	// Append extended tools (require AppBridge)
 	if s.app != nil {
 		tools = append(tools, composeToolSchemas()...)
@@ -871,7 +886,9 @@ func (s *MCPServer) executeTool(name string, args map[string]interface{}) (ToolR
 		}
 		caseName, _ := args["name"].(string)
 		env, _ := args["env"].(map[string]interface{})
-		return s.toolPlanCase(template, caseName, env)
+		ttl, _ := args["ttl"].(string)
+		account, _ := args["account"].(string)
+		return s.toolPlanCase(template, caseName, env, ttl, account)
 
 	case "start_case":
 		caseID, ok := args["case_id"].(string)
@@ -901,6 +918,54 @@ func (s *MCPServer) executeTool(name string, args map[string]interface{}) (ToolR
 		}
 		return s.toolGetCaseStatus(caseID)
 
+	case "detect_drift":
+		caseID, _ := args["case_id"].(string)
+		return s.toolDetectDrift(caseID)
+
+	case "set_case_ttl":
+		caseID, ok := args["case_id"].(string)
+		if !ok {
+			return ToolResult{}, fmt.Errorf("missing or invalid 'case_id' parameter")
+		}
+		ttl, _ := args["ttl"].(string)
+		extend, _ := args["extend"].(string)
+		return s.toolSetCaseTTL(caseID, ttl, extend)
+
+	case "replace_resource":
+		caseID, ok := args["case_id"].(string)
+		if !ok {
+			return ToolResult{}, fmt.Errorf("missing or invalid 'case_id' parameter")
+		}
+		rawAddrs, _ := args["addresses"].([]interface{})
+		var addresses []string
+		for _, v := range rawAddrs {
+			if a, ok := v.(string); ok && a != "" {
+				addresses = append(addresses, a)
+			}
+		}
+		if len(addresses) == 0 {
+			return ToolResult{}, fmt.Errorf("missing or invalid 'addresses' parameter")
+		}
+		return s.toolReplaceResource(caseID, addresses)
+
+	case "get_case_logs":
+		caseID, ok := args["case_id"].(string)
+		if !ok {
+			return ToolResult{}, fmt.Errorf("missing or invalid 'case_id' parameter")
+		}
+		opID := 0
+		if v, ok := args["op"].(float64); ok {
+			opID = int(v)
+		}
+		return s.toolGetCaseLogs(caseID, opID)
+
+	case "get_case_state_history":
+		caseID, ok := args["case_id"].(string)
+		if !ok {
+			return ToolResult{}, fmt.Errorf("missing or invalid 'case_id' parameter")
+		}
+		return s.toolGetCaseStateHistory(caseID)
+
 	case "exec_command":
 		caseID, ok := args["case_id"].(string)
 		if !ok {
@@ -1045,9 +1110,26 @@ func (s *MCPServer) executeTool(name string, args map[string]interface{}) (ToolR
 		return s.toolComposeUp(file, profiles)
 
 	case "compose_down":
+		stack, _ := args["stack"].(string)
 		file, _ := args["file"].(string)
 		profiles, _ := args["profiles"].(string)
-		return s.toolComposeDown(file, profiles)
+		return s.toolComposeDown(stack, file, profiles)
+
+	case "compose_ps":
+		return s.toolComposePs()
+
+	case "compose_plan":
+		file, _ := args["file"].(string)
+		profiles, _ := args["profiles"].(string)
+		return s.toolComposePlan(file, profiles)
+
+	case "compose_scale":
+		stack, ok := args["stack"].(string)
+		if !ok || stack == "" {
+			return ToolResult{}, fmt.Errorf("missing or invalid 'stack' parameter")
+		}
+		replicas, _ := args["replicas"].(string)
+		return s.toolComposeScale(stack, replicas)
 
 	// --- Cost & Resource tools ---
 	case "get_cost_estimate":
@@ -1185,7 +1267,20 @@ func (s *MCPServer) executeTool(name string, args map[string]interface{}) (ToolR
 
 // ExecuteTool is the public API for calling MCP tools directly (used by AI Agent)
 func (s *MCPServer) ExecuteTool(name string, args map[string]interface{}) (ToolResult, error) {
-	return s.executeTool(name, args)
+	return s.runTool(name, args)
+}
+
+// runTool executes a tool and masks registered secrets in its result and error,
+// so tool output never carries passwords to MCP clients or AI transcripts.
+func (s *MCPServer) runTool(name string, args map[string]interface{}) (ToolResult, error) {
+	result, err := s.executeTool(name, args)
+	if err != nil {
+		return result, fmt.Errorf("%s", secret.Redact(err.Error()))
+	}
+	for i := range result.Content {
+		result.Content[i].Text = secret.Redact(result.Content[i].Text)
+	}
+	return result, nil
 }
 
 // GetTools returns the list of available MCP tools (used by AI Agent to build tool schema)
@@ -1349,13 +1444,20 @@ func (s *MCPServer) toolListCases() (ToolResult, error) {
 	}, nil
 }
 
-func (s *MCPServer) toolPlanCase(template string, name string, env map[string]interface{}) (ToolResult, error) {
+func (s *MCPServer) toolPlanCase(template string, name string, env map[string]interface{}, ttl string, account string) (ToolResult, error) {
+	lease, err := redc.ParseTTL(ttl)
+	if err != nil {
+		return ToolResult{}, err
+	}
 	vars := make(map[string]string)
 	if env != nil {
 		for k, v := range env {
 			vars[k] = fmt.Sprintf("%v", v)
 		}
 	}
+	if account != "" {
+		vars[redc.AccountVar] = account
+	}
 
 	c, err := s.project.CaseCreate(template, redc.U, name, vars)
 	if err != nil {
@@ -1366,6 +1468,13 @@ func (s *MCPServer) toolPlanCase(template string, name string, env map[string]in
 	output += fmt.Sprintf("- ID: %s\n", c.GetId())
 	output += fmt.Sprintf("- Name: %s\n", c.Name)
 	output += fmt.Sprintf("- Template: %s\n", template)
+	if lease > 0 {
+		if l, err := c.SetLease(lease); err == nil {
+			output += fmt.Sprintf("- TTL: expires at %s\n", l.ExpiresAt.Format("2006-01-02 15:04"))
+		} else {
+			output += fmt.Sprintf("- TTL: failed to set (%v)\n", err)
+		}
+	}
 	output += fmt.Sprintf("\nThe case has been validated but not started yet.\n")
 	output += fmt.Sprintf("Use 'start_case' with ID '%s' to actually create and start the infrastructure.\n", c.GetId())
 
@@ -1382,6 +1491,7 @@ func (s *MCPServer) toolStartCase(caseID string) (ToolResult, error) {
 	if err != nil {
 		return ToolResult{}, fmt.Errorf("case not found: %v", err)
 	}
+	c.SetActor("", redc.SourceMCP)
 
 	if err := c.TfApply(); err != nil {
 		return ToolResult{}, fmt.Errorf("failed to start case: %v", err)
@@ -1401,6 +1511,7 @@ func (s *MCPServer) toolStopCase(caseID string) (ToolResult, error) {
 	if err != nil {
 		return ToolResult{}, fmt.Errorf("case not found: %v", err)
 	}
+	c.SetActor("", redc.SourceMCP)
 
 	if c.State != redc.StateRunning {
 		return ToolResult{}, fmt.Errorf("case '%s' (%s) is not running (current state: %s), cannot stop", c.Name, c.GetId(), c.State)
@@ -1424,6 +1535,7 @@ func (s *MCPServer) toolKillCase(caseID string) (ToolResult, error) {
 	if err != nil {
 		return ToolResult{}, fmt.Errorf("case not found: %v", err)
 	}
+	c.SetActor("", redc.SourceMCP)
 
 	if c.State == redc.StateStopped {
 		return ToolResult{}, fmt.Errorf("case '%s' (%s) is already stopped, no need to kill", c.Name, c.GetId())
@@ -1462,12 +1574,17 @@ func (s *MCPServer) toolGetCaseStatus(caseID string) (ToolResult, error) {
 	}, nil
 }
 
-func (s *MCPServer) toolExecCommand(caseID string, command string, timeoutSec int, conversationId string) (ToolResult, error) {
+func (s *MCPServer) toolExecCommand(caseID string, command string, timeoutSec int, conversationId string) (result ToolResult, err error) {
 	c, err := s.project.GetCase(caseID)
 	if err != nil {
 		return ToolResult{}, fmt.Errorf("case not found: %v", err)
 	}
 
+	var outputBuf strings.Builder
+	defer func() {
+		redc.RecordCaseOp(c, redc.OpKindSSH, command, outputBuf.String(), err)
+	}()
+
 	sshConfig, err := c.GetSSHConfig()
 	if err != nil {
 		return ToolResult{}, fmt.Errorf("failed to get SSH config: %v", err)
@@ -1479,7 +1596,6 @@ func (s *MCPServer) toolExecCommand(caseID string, command string, timeoutSec in
 	}
 	defer client.Close()
 
-	var outputBuf strings.Builder
 	session, err := client.Client.NewSession()
 	if err != nil {
 		return ToolResult{}, fmt.Errorf("failed to create SSH session: %v", err)
@@ -3,6 +3,8 @@ package mcp
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 func composeToolSchemas() []Tool {
@@ -59,12 +61,51 @@ func composeToolSchemas() []Tool {
 				},
 			},
 		},
+		{
+			Name:        "compose_plan",
+			Description: "Show what compose_up would change in an already deployed stack without changing anything: services added or removed, replica changes, environment/configs changes that force a re-apply, the Terraform resource changes of every affected case and the estimated monthly cost change. Sensitive parameter values are masked.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"file": {
+						Type:        "string",
+						Description: "Compose file path (default: redc-compose.yaml)",
+					},
+					"profiles": {
+						Type:        "string",
+						Description: "Comma-separated profiles to activate (default: the profiles the stack was deployed with)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "compose_scale",
+			Description: "Change the replica count of services in a deployed compose stack (see compose_ps). Only the missing instances are deployed and the extra ones destroyed; setup tasks that reference the scaled services run again. This call BLOCKS until done.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"stack": {
+						Type:        "string",
+						Description: "Compose stack name as listed by compose_ps",
+					},
+					"replicas": {
+						Type:        "string",
+						Description: "Comma-separated service=replicas pairs (e.g., 'scanner=5,proxy=2')",
+					},
+				},
+				Required: []string{"stack", "replicas"},
+			},
+		},
 		{
 			Name:        "compose_down",
-			Description: "Destroy a redc-compose deployment (destroys all services in reverse dependency order). This call BLOCKS until all services are fully destroyed.",
+			Description: "Destroy a redc-compose deployment (destroys all services in reverse dependency order). Pass 'stack' to destroy a deployed stack from its saved record, even if the compose file was moved or edited. This call BLOCKS until all services are fully destroyed.",
 			InputSchema: ToolSchema{
 				Type: "object",
 				Properties: map[string]Property{
+					"stack": {
+						Type:        "string",
+						Description: "Compose stack name as listed by compose_ps (takes precedence over file)",
+					},
 					"file": {
 						Type:        "string",
 						Description: "Compose file path (default: redc-compose.yaml)",
@@ -76,6 +117,14 @@ func composeToolSchemas() []Tool {
 				},
 			},
 		},
+		{
+			Name:        "compose_ps",
+			Description: "List deployed compose stacks in the current project: services, their case IDs and live states, recorded outputs and setup task results, and whether the compose file changed since deployment",
+			InputSchema: ToolSchema{
+				Type:       "object",
+				Properties: map[string]Property{},
+			},
+		},
 	}
 }
 
@@ -150,10 +199,18 @@ func (s *MCPServer) toolComposeUp(file string, profiles string) (ToolResult, err
 	}, nil
 }
 
-func (s *MCPServer) toolComposeDown(file string, profiles string) (ToolResult, error) {
+func (s *MCPServer) toolComposeDown(stack string, file string, profiles string) (ToolResult, error) {
 	if s.app == nil {
 		return ToolResult{}, fmt.Errorf("compose tools require GUI mode (AppBridge not available)")
 	}
+	if stack != "" {
+		if err := s.app.MCPComposeDownStackSync(stack); err != nil {
+			return ToolResult{}, err
+		}
+		return ToolResult{
+			Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("Compose stack %s destroyed successfully. All its cases have been removed.", stack)}},
+		}, nil
+	}
 	if err := s.app.MCPComposeDownSync(file, parseProfiles(profiles)); err != nil {
 		return ToolResult{}, err
 	}
@@ -161,3 +218,54 @@ func (s *MCPServer) toolComposeDown(file string, profiles string) (ToolResult, e
 		Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("Compose deployment destroyed successfully (file: %s). All cases have been removed.", file)}},
 	}, nil
 }
+
+func (s *MCPServer) toolComposePlan(file string, profiles string) (ToolResult, error) {
+	if s.app == nil {
+		return ToolResult{}, fmt.Errorf("compose tools require GUI mode (AppBridge not available)")
+	}
+	result, err := s.app.MCPComposePlan(file, parseProfiles(profiles))
+	if err != nil {
+		return ToolResult{}, err
+	}
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
+func (s *MCPServer) toolComposeScale(stack string, replicas string) (ToolResult, error) {
+	if s.app == nil {
+		return ToolResult{}, fmt.Errorf("compose tools require GUI mode (AppBridge not available)")
+	}
+	counts := make(map[string]int)
+	for _, pair := range strings.Split(replicas, ",") {
+		name, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if !ok || name == "" || err != nil {
+			return ToolResult{}, fmt.Errorf("invalid replicas %q, expected service=count", pair)
+		}
+		counts[strings.TrimSpace(name)] = n
+	}
+	result, err := s.app.MCPComposeScaleSync(stack, counts)
+	if err != nil {
+		return ToolResult{}, err
+	}
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("Compose stack %s scaled.\n\n%s", stack, string(data))}},
+	}, nil
+}
+
+func (s *MCPServer) toolComposePs() (ToolResult, error) {
+	if s.app == nil {
+		return ToolResult{}, fmt.Errorf("compose tools require GUI mode (AppBridge not available)")
+	}
+	result, err := s.app.MCPListComposeStacks()
+	if err != nil {
+		return ToolResult{}, err
+	}
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: string(data)}},
+	}, nil
+}
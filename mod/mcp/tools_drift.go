@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	redc "red-cloud/mod"
+)
+
+func driftToolSchemas() []Tool {
+	return []Tool{
+		{
+			Name:        "detect_drift",
+			Description: "Detect configuration drift between terraform state and the real cloud resources (refresh-only plan). Checks a single case when case_id is given, otherwise all running cases in the current project. Read-only: does not modify any resources.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"case_id": {
+						Type:        "string",
+						Description: "Case ID to check (optional, defaults to all running cases)",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *MCPServer) toolDetectDrift(caseID string) (ToolResult, error) {
+	var reports []*redc.DriftReport
+	if caseID != "" {
+		c, err := s.project.GetCase(caseID)
+		if err != nil {
+			return ToolResult{}, fmt.Errorf("case not found: %v", err)
+		}
+		report, err := c.DetectDrift()
+		if err != nil {
+			return ToolResult{}, fmt.Errorf("drift detection failed: %v", err)
+		}
+		reports = append(reports, report)
+	} else {
+		var err error
+		reports, err = redc.DetectProjectDrift(s.project.ProjectName)
+		if err != nil {
+			return ToolResult{}, fmt.Errorf("drift detection failed: %v", err)
+		}
+	}
+
+	if len(reports) == 0 {
+		return ToolResult{
+			Content: []ContentItem{{Type: "text", Text: "No running cases to check."}},
+		}, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Drift Report:\n")
+	for _, r := range reports {
+		sb.WriteString(fmt.Sprintf("\n[%s] %s (%s)\n", r.CaseID, r.CaseName, r.Template))
+		switch {
+		case r.Error != "":
+			sb.WriteString(fmt.Sprintf("  Error: %s\n", r.Error))
+		case !r.Drifted:
+			sb.WriteString("  In sync, no drift detected\n")
+		default:
+			for _, res := range r.Resources {
+				sb.WriteString(fmt.Sprintf("  - %s [%s]", res.Address, strings.Join(res.Actions, ",")))
+				if len(res.Changed) > 0 {
+					sb.WriteString(fmt.Sprintf(" changed: %s", strings.Join(res.Changed, ", ")))
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: sb.String()}},
+	}, nil
+}
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"fmt"
+
+	redc "red-cloud/mod"
+)
+
+func leaseToolSchemas() []Tool {
+	return []Tool{
+		{
+			Name:        "set_case_ttl",
+			Description: "Set, extend or clear the time-to-live (TTL) of a case. When the TTL expires the case is destroyed automatically by the task scheduler. Pass 'ttl' to set a new TTL counted from now ('0' clears it), or 'extend' to add time to the current TTL. With neither, returns the current TTL.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"case_id": {
+						Type:        "string",
+						Description: "Case ID",
+					},
+					"ttl": {
+						Type:        "string",
+						Description: "New TTL from now, e.g. '8h', '30m', '2d'; '0' clears the TTL (optional)",
+					},
+					"extend": {
+						Type:        "string",
+						Description: "Duration to add to the current TTL, e.g. '2h' (optional)",
+					},
+				},
+				Required: []string{"case_id"},
+			},
+		},
+	}
+}
+
+func (s *MCPServer) toolSetCaseTTL(caseID, ttl, extend string) (ToolResult, error) {
+	c, err := s.project.GetCase(caseID)
+	if err != nil {
+		return ToolResult{}, fmt.Errorf("case not found: %v", err)
+	}
+
+	var lease *redc.CaseLease
+	switch {
+	case ttl != "":
+		d, err := redc.ParseTTL(ttl)
+		if err != nil {
+			return ToolResult{}, err
+		}
+		lease, err = c.SetLease(d)
+		if err != nil {
+			return ToolResult{}, fmt.Errorf("failed to set TTL: %v", err)
+		}
+	case extend != "":
+		d, err := redc.ParseTTL(extend)
+		if err != nil {
+			return ToolResult{}, err
+		}
+		lease, err = redc.ExtendCaseLease(c.Id, d)
+		if err != nil {
+			return ToolResult{}, fmt.Errorf("failed to extend TTL: %v", err)
+		}
+	default:
+		lease, err = redc.GetCaseLease(c.Id)
+		if err != nil {
+			return ToolResult{}, err
+		}
+	}
+
+	text := fmt.Sprintf("Case '%s' (%s) has no TTL.", c.Name, c.Id)
+	if lease != nil {
+		text = fmt.Sprintf("Case '%s' (%s) expires at %s (remaining %s). It will be destroyed automatically.",
+			c.Name, c.Id, lease.ExpiresAt.Format("2006-01-02 15:04:05"), redc.FormatLeaseRemaining(lease.Remaining()))
+	}
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: text}},
+	}, nil
+}
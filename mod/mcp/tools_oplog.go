@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	redc "red-cloud/mod"
+)
+
+// maxOpLogToolBytes 返回给 AI 的日志上限，只保留末尾
+const maxOpLogToolBytes = 32 * 1024
+
+func opLogToolSchemas() []Tool {
+	return []Tool{
+		{
+			Name:        "get_case_logs",
+			Description: "Get the persisted operation logs of a case: terraform plan/apply/destroy output, SSH commands and plugin hooks. Without 'op' lists recent operations and returns the log of the latest one; with 'op' returns that operation's log (last 32KB).",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"case_id": {
+						Type:        "string",
+						Description: "Case ID",
+					},
+					"op": {
+						Type:        "integer",
+						Description: "Operation number from the list (optional, defaults to the latest)",
+					},
+				},
+				Required: []string{"case_id"},
+			},
+		},
+	}
+}
+
+func (s *MCPServer) toolGetCaseLogs(caseID string, opID int) (ToolResult, error) {
+	c, err := s.project.GetCase(caseID)
+	if err != nil {
+		return ToolResult{}, fmt.Errorf("case not found: %v", err)
+	}
+
+	var sb strings.Builder
+	if opID <= 0 {
+		ops, err := redc.ListCaseOps(c)
+		if err != nil {
+			return ToolResult{}, err
+		}
+		if len(ops) == 0 {
+			return ToolResult{Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("Case '%s' (%s) has no recorded operations.", c.Name, c.GetId())}}}, nil
+		}
+		sb.WriteString("| Op | Kind | Operation | Started | Status |\n|----|------|-----------|---------|--------|\n")
+		for i, op := range ops {
+			if i >= 20 {
+				break
+			}
+			fmt.Fprintf(&sb, "| %d | %s | %s | %s | %s |\n", op.ID, op.Kind, op.Operation, op.StartedAt.Format("2006-01-02 15:04:05"), op.Status)
+		}
+		sb.WriteString("\n")
+	}
+
+	chunk, err := redc.ReadCaseOpLog(c, opID, 0, 0)
+	if err != nil {
+		return ToolResult{}, err
+	}
+	data := chunk.Data
+	if len(data) > maxOpLogToolBytes {
+		data = "... (truncated)\n" + data[len(data)-maxOpLogToolBytes:]
+	}
+	op := chunk.Op
+	fmt.Fprintf(&sb, "# Operation %d: %s/%s (%s)\n", op.ID, op.Kind, op.Operation, op.Status)
+	if op.Error != "" {
+		fmt.Fprintf(&sb, "Error: %s\n", op.Error)
+	}
+	sb.WriteString("\n```\n" + data + "\n```\n")
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: sb.String()}},
+	}, nil
+}
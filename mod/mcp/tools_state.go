@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	redc "red-cloud/mod"
+)
+
+func stateHistoryToolSchemas() []Tool {
+	return []Tool{
+		{
+			Name:        "get_case_state_history",
+			Description: "Get the state transition history of a case (e.g. stopped -> starting -> running) with actor, source (cli/gui/http/mcp/scheduler) and time, newest first. Use it to find out how a case got into its current state.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"case_id": {
+						Type:        "string",
+						Description: "Case ID",
+					},
+				},
+				Required: []string{"case_id"},
+			},
+		},
+	}
+}
+
+func (s *MCPServer) toolGetCaseStateHistory(caseID string) (ToolResult, error) {
+	c, err := s.project.GetCase(caseID)
+	if err != nil {
+		return ToolResult{}, fmt.Errorf("case not found: %v", err)
+	}
+	list, err := redc.ListStateTransitions(c.Id)
+	if err != nil {
+		return ToolResult{}, err
+	}
+	if len(list) == 0 {
+		return ToolResult{Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("Case '%s' (%s) has no recorded state transitions. Current state: %s", c.Name, c.GetId(), c.State)}}}, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Case '%s' (%s), current state: %s\n\n", c.Name, c.GetId(), c.State)
+	sb.WriteString("| Time | From | To | Actor | Source |\n|------|------|----|-------|--------|\n")
+	for i, t := range list {
+		if i >= 50 {
+			break
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", t.Time.Format("2006-01-02 15:04:05"), t.From, t.To, t.Actor, t.Source)
+	}
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: sb.String()}},
+	}, nil
+}
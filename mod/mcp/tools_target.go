@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	redc "red-cloud/mod"
+)
+
+func targetToolSchemas() []Tool {
+	return []Tool{
+		{
+			Name:        "replace_resource",
+			Description: "Recreate individual resources of a running case (terraform apply -replace), e.g. a tainted redirector, without touching the rest of the case. Addresses must exist in the case state; use get_case_status to list them.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"case_id": {
+						Type:        "string",
+						Description: "Case ID",
+					},
+					"addresses": {
+						Type:        "array",
+						Description: "Resource instance addresses to recreate, e.g. [\"alicloud_instance.redirector\", \"aws_instance.node[1]\"]",
+						Items:       &Property{Type: "string"},
+					},
+				},
+				Required: []string{"case_id", "addresses"},
+			},
+		},
+	}
+}
+
+func (s *MCPServer) toolReplaceResource(caseID string, addresses []string) (ToolResult, error) {
+	c, err := s.project.GetCase(caseID)
+	if err != nil {
+		return ToolResult{}, fmt.Errorf("case not found: %v", err)
+	}
+	if c.State != redc.StateRunning {
+		return ToolResult{}, fmt.Errorf("case '%s' (%s) is not running (current state: %s), cannot replace resources", c.Name, c.GetId(), c.State)
+	}
+
+	target := redc.TargetSpec{Replace: addresses}
+	plan, err := c.PlanTargetedChange(nil, target)
+	if err != nil {
+		return ToolResult{}, fmt.Errorf("failed to plan replacement: %v", err)
+	}
+	if err := c.ApplyChange(plan, redc.U); err != nil {
+		return ToolResult{}, fmt.Errorf("failed to replace resources: %v", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Replaced resources in case '%s' (%s): %s\n", c.Name, c.GetId(), plan.Summary())
+	for _, rc := range plan.Changes {
+		fmt.Fprintf(&sb, "- %s %s\n", rc.Action, rc.Address)
+	}
+	return ToolResult{
+		Content: []ContentItem{{Type: "text", Text: sb.String()}},
+	}, nil
+}
@@ -0,0 +1,367 @@
+package mod
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"red-cloud/mod/secret"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 操作类型
+const (
+	OpKindTerraform = "terraform"
+	OpKindSSH       = "ssh"
+	OpKindPlugin    = "plugin"
+)
+
+// 操作状态
+const (
+	OpStatusRunning = "running"
+	OpStatusSuccess = "success"
+	OpStatusFailed  = "failed"
+)
+
+var (
+	// MaxCaseOpLogs 每个场景保留的操作日志数量，超出后删除最旧的
+	MaxCaseOpLogs = 50
+	// MaxOpLogSize 单个操作日志的大小上限，超出部分丢弃
+	MaxOpLogSize int64 = 10 << 20
+)
+
+// CaseOp 场景的一次操作 (terraform / ssh / plugin hook) 及其日志元数据
+type CaseOp struct {
+	ID        int       `json:"id"`
+	CaseID    string    `json:"caseId"`
+	Kind      string    `json:"kind"`
+	Operation string    `json:"operation"`
+	Owner     string    `json:"owner"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt,omitempty"`
+	Size      int64     `json:"size"`
+}
+
+// Done 操作已结束
+func (o *CaseOp) Done() bool {
+	return o.Status != OpStatusRunning
+}
+
+// CaseOpLogDir 场景操作日志目录，与 compose 日志同在项目的 logs 目录下
+func CaseOpLogDir(c *Case) string {
+	return filepath.Join(filepath.Dir(c.Path), "logs", "cases", c.Id)
+}
+
+func opLogPath(dir string, id int) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.log", id))
+}
+
+func opMetaPath(dir string, id int) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.json", id))
+}
+
+// opLog 正在进行的操作日志，实现 io.Writer
+type opLog struct {
+	mu      sync.Mutex
+	dir     string
+	op      CaseOp
+	f       *os.File
+	workDir string
+}
+
+var (
+	activeOpLogsMu sync.Mutex
+	activeOpLogs   = map[string]*opLog{} // 场景目录 -> 当前操作
+)
+
+// beginCaseOp 为场景创建新的操作日志。目录无法写入时返回 nil，不影响操作本身
+func beginCaseOp(c *Case, kind, operation string) *opLog {
+	if c.Path == "" || c.Id == "" {
+		return nil
+	}
+	dir := CaseOpLogDir(c)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		gologger.Debug().Msgf("oplog: %v", err)
+		return nil
+	}
+	ids, _ := listOpIDs(dir)
+	next := 1
+	if len(ids) > 0 {
+		next = ids[len(ids)-1] + 1
+	}
+	// SSH 等操作不持有场景锁，用 O_EXCL 避免多进程分配到同一个 ID
+	var f *os.File
+	for i := 0; i < 10; i++ {
+		var err error
+		f, err = os.OpenFile(opLogPath(dir, next), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			gologger.Debug().Msgf("oplog: %v", err)
+			return nil
+		}
+		next++
+	}
+	if f == nil {
+		return nil
+	}
+	l := &opLog{
+		dir: dir,
+		f:   f,
+		op: CaseOp{
+			ID:        next,
+			CaseID:    c.Id,
+			Kind:      kind,
+			Operation: operation,
+			Owner:     c.lockOwner(),
+			Status:    OpStatusRunning,
+			StartedAt: time.Now(),
+		},
+	}
+	l.writeMeta()
+	pruneCaseOps(dir, ids, MaxCaseOpLogs-1)
+	return l
+}
+
+// attach 将操作登记到场景目录，terraform 输出会同时写入该日志
+func (l *opLog) attach(workDir string) {
+	l.workDir = filepath.Clean(workDir)
+	activeOpLogsMu.Lock()
+	activeOpLogs[l.workDir] = l
+	activeOpLogsMu.Unlock()
+}
+
+func (l *opLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return len(p), nil
+	}
+	remain := MaxOpLogSize - l.op.Size
+	if remain <= 0 {
+		return len(p), nil
+	}
+	full := []byte(secret.Redact(string(p)))
+	data := full
+	if int64(len(data)) > remain {
+		data = data[:remain]
+	}
+	n, err := l.f.Write(data)
+	l.op.Size += int64(n)
+	if err == nil && int64(len(full)) > remain {
+		l.f.WriteString("\n[truncated]\n")
+	}
+	// 日志写入失败不影响操作本身
+	return len(p), nil
+}
+
+// Printf 写入一行带时间戳的说明信息
+func (l *opLog) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(l, "[%s] %s\n", time.Now().Format("15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// fail 标记操作失败，只记录第一个错误
+func (l *opLog) fail(err error) {
+	l.mu.Lock()
+	l.op.Status = OpStatusFailed
+	if err != nil && l.op.Error == "" {
+		l.op.Error = err.Error()
+	}
+	l.mu.Unlock()
+	if err != nil {
+		l.Printf("error: %v", err)
+	}
+}
+
+// finish 结束操作，写入最终状态
+func (l *opLog) finish(err error) {
+	if err != nil {
+		l.fail(err)
+	}
+	if l.workDir != "" {
+		activeOpLogsMu.Lock()
+		if activeOpLogs[l.workDir] == l {
+			delete(activeOpLogs, l.workDir)
+		}
+		activeOpLogsMu.Unlock()
+	}
+	l.mu.Lock()
+	if l.op.Status == OpStatusRunning {
+		l.op.Status = OpStatusSuccess
+	}
+	l.op.EndedAt = time.Now()
+	if l.f != nil {
+		l.f.Close()
+		l.f = nil
+	}
+	l.mu.Unlock()
+	l.writeMeta()
+}
+
+func (l *opLog) writeMeta() {
+	l.mu.Lock()
+	data, err := json.MarshalIndent(l.op, "", "  ")
+	l.mu.Unlock()
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(opMetaPath(l.dir, l.op.ID), data, 0600); err != nil {
+		gologger.Debug().Msgf("oplog: %v", err)
+	}
+}
+
+// activeOpLog 返回场景目录上正在进行的操作
+func activeOpLog(workDir string) *opLog {
+	activeOpLogsMu.Lock()
+	defer activeOpLogsMu.Unlock()
+	return activeOpLogs[filepath.Clean(workDir)]
+}
+
+// opLogWriter 返回需要同时写入的操作日志，没有进行中的操作时返回 nil
+func opLogWriter(workDir string) io.Writer {
+	if l := activeOpLog(workDir); l != nil {
+		return l
+	}
+	return nil
+}
+
+// recordOpError 记录 terraform 命令的失败
+func recordOpError(workDir string, err error) {
+	if l := activeOpLog(workDir); l != nil && err != nil {
+		l.fail(err)
+	}
+}
+
+// RecordCaseOp 记录一次不持有场景锁的操作 (如 SSH 命令)，output 为完整输出
+func RecordCaseOp(c *Case, kind, operation, output string, opErr error) {
+	l := beginCaseOp(c, kind, operation)
+	if l == nil {
+		return
+	}
+	io.WriteString(l, output)
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		io.WriteString(l, "\n")
+	}
+	l.finish(opErr)
+}
+
+// listOpIDs 按升序返回目录中的操作 ID
+func listOpIDs(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".log")
+		if !ok {
+			continue
+		}
+		if id, err := strconv.Atoi(name); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// pruneCaseOps 删除最旧的操作日志，只保留 keep 个
+func pruneCaseOps(dir string, ids []int, keep int) {
+	if keep < 0 {
+		keep = 0
+	}
+	for len(ids) > keep {
+		os.Remove(opLogPath(dir, ids[0]))
+		os.Remove(opMetaPath(dir, ids[0]))
+		ids = ids[1:]
+	}
+}
+
+// ListCaseOps 列出场景的操作，最新的在前
+func ListCaseOps(c *Case) ([]*CaseOp, error) {
+	dir := CaseOpLogDir(c)
+	ids, err := listOpIDs(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ops := make([]*CaseOp, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		if op, err := GetCaseOp(c, ids[i]); err == nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+// GetCaseOp 读取操作元数据。id <= 0 表示最新的操作
+func GetCaseOp(c *Case, id int) (*CaseOp, error) {
+	dir := CaseOpLogDir(c)
+	if id <= 0 {
+		ids, err := listOpIDs(dir)
+		if err != nil || len(ids) == 0 {
+			return nil, fmt.Errorf("%s", i18n.Tf("oplog_none", c.Name))
+		}
+		id = ids[len(ids)-1]
+	}
+	data, err := os.ReadFile(opMetaPath(dir, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s", i18n.Tf("oplog_not_found", id, c.Name))
+		}
+		return nil, err
+	}
+	var op CaseOp
+	if err := json.Unmarshal(data, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// CaseOpLogChunk 从 offset 开始读取的一段操作日志，用于轮询式跟随
+type CaseOpLogChunk struct {
+	Op     *CaseOp `json:"op"`
+	Data   string  `json:"data"`
+	Offset int64   `json:"offset"` // 下次读取的起始位置
+}
+
+// ReadCaseOpLog 从 offset 开始读取操作日志，最多 limit 字节 (<=0 表示不限制)
+func ReadCaseOpLog(c *Case, id int, offset, limit int64) (*CaseOpLogChunk, error) {
+	op, err := GetCaseOp(c, id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(opLogPath(CaseOpLogDir(c), op.ID))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var r io.Reader = f
+	if limit > 0 {
+		r = io.LimitReader(f, limit)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return &CaseOpLogChunk{Op: op, Data: string(data), Offset: offset + int64(len(data))}, nil
+}
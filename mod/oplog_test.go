@@ -0,0 +1,70 @@
+package mod
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCaseOpLogLifecycle(t *testing.T) {
+	c := &Case{Id: "c1", Name: "demo", Path: t.TempDir() + "/c1"}
+
+	l := beginCaseOp(c, OpKindTerraform, "apply")
+	if l == nil {
+		t.Fatal("expected op log")
+	}
+	l.attach(c.Path)
+	io.WriteString(opLogWriter(c.Path), "Apply complete!\n")
+	recordOpError(c.Path, errors.New("boom"))
+	l.finish(nil)
+	if opLogWriter(c.Path) != nil {
+		t.Fatal("op must be detached after finish")
+	}
+
+	RecordCaseOp(c, OpKindSSH, "whoami", "root", nil)
+
+	ops, err := ListCaseOps(c)
+	if err != nil || len(ops) != 2 {
+		t.Fatalf("ops=%v err=%v", ops, err)
+	}
+	if ops[0].ID != 2 || ops[0].Kind != OpKindSSH || ops[0].Status != OpStatusSuccess {
+		t.Errorf("unexpected latest op %+v", ops[0])
+	}
+	if ops[1].Status != OpStatusFailed || ops[1].Error != "boom" {
+		t.Errorf("terraform op should be failed with first error, got %+v", ops[1])
+	}
+
+	chunk, err := ReadCaseOpLog(c, 1, 0, 0)
+	if err != nil || !strings.HasPrefix(chunk.Data, "Apply complete!\n") {
+		t.Fatalf("chunk=%+v err=%v", chunk, err)
+	}
+	next, err := ReadCaseOpLog(c, 1, chunk.Offset, 0)
+	if err != nil || next.Data != "" {
+		t.Fatalf("expected nothing after offset, got %q err=%v", next.Data, err)
+	}
+	latest, err := ReadCaseOpLog(c, 0, 0, 0)
+	if err != nil || latest.Data != "root\n" {
+		t.Fatalf("latest=%+v err=%v", latest, err)
+	}
+}
+
+func TestCaseOpLogRotationAndSizeLimit(t *testing.T) {
+	oldMax, oldSize := MaxCaseOpLogs, MaxOpLogSize
+	MaxCaseOpLogs, MaxOpLogSize = 3, 8
+	defer func() { MaxCaseOpLogs, MaxOpLogSize = oldMax, oldSize }()
+
+	c := &Case{Id: "c2", Path: t.TempDir() + "/c2"}
+	for i := 0; i < 5; i++ {
+		RecordCaseOp(c, OpKindSSH, fmt.Sprintf("cmd %d", i), "0123456789", nil)
+	}
+	ops, _ := ListCaseOps(c)
+	if len(ops) != 3 || ops[0].ID != 5 || ops[2].ID != 3 {
+		t.Fatalf("expected ops 5..3, got %d ops", len(ops))
+	}
+	chunk, _ := ReadCaseOpLog(c, 5, 0, 0)
+	if !strings.HasPrefix(chunk.Data, "01234567\n[truncated]") {
+		t.Errorf("expected truncated log, got %q", chunk.Data)
+	}
+}
@@ -0,0 +1,717 @@
+package mod
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/cost"
+	"red-cloud/mod/gologger"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// 策略效果
+const (
+	PolicyDeny = "deny"
+	PolicyWarn = "warn"
+)
+
+// 策略规则类型
+const (
+	PolicyRuleRegion       = "region"        // 禁止 / 只允许部分地域
+	PolicyRuleInstanceType = "instance_type" // 禁止的规格或超过 maxSize 的规格
+	PolicyRuleOpenPort     = "open_port"     // 管理端口对 0.0.0.0/0 开放
+	PolicyRuleSpot         = "spot"          // 实例必须使用抢占式
+	PolicyRuleCost         = "cost"          // 预估成本超过阈值
+	PolicyRuleResourceType = "resource_type" // 禁止的资源类型
+)
+
+// PolicyConfig 保存在 profile 中的部署策略，apply 前对 plan 和成本估算进行检查
+type PolicyConfig struct {
+	Rules []PolicyRule `json:"rules" yaml:"rules"`
+}
+
+// PolicyRule 单条策略规则，按 Type 使用不同字段
+type PolicyRule struct {
+	Name          string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Type          string   `json:"type" yaml:"type"`
+	Effect        string   `json:"effect,omitempty" yaml:"effect,omitempty"`               // deny (默认) / warn
+	Values        []string `json:"values,omitempty" yaml:"values,omitempty"`               // region / instance_type / resource_type: 禁止的值，支持 * 通配
+	Allowed       []string `json:"allowed,omitempty" yaml:"allowed,omitempty"`             // region / instance_type: 只允许这些值
+	MaxSize       string   `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`             // instance_type: 允许的最大规格，如 2xlarge
+	Ports         []int    `json:"ports,omitempty" yaml:"ports,omitempty"`                 // open_port: 管理端口，默认 22 / 3389
+	ResourceTypes []string `json:"resourceTypes,omitempty" yaml:"resourceTypes,omitempty"` // instance_type / spot: 检查的资源类型
+	MaxMonthly    float64  `json:"maxMonthly,omitempty" yaml:"maxMonthly,omitempty"`       // cost: 月成本上限
+	MaxHourly     float64  `json:"maxHourly,omitempty" yaml:"maxHourly,omitempty"`         // cost: 小时成本上限
+	FailOpen      bool     `json:"failOpen,omitempty" yaml:"failOpen,omitempty"`           // cost: 无法估算成本时只提示；默认按 effect 处理，deny 时拒绝
+	Message       string   `json:"message,omitempty" yaml:"message,omitempty"`             // 自定义提示
+}
+
+func (r PolicyRule) effect() string {
+	if r.Effect == "" {
+		return PolicyDeny
+	}
+	return r.Effect
+}
+
+func (r PolicyRule) label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Type
+}
+
+// Validate 检查策略配置
+func (p *PolicyConfig) Validate() error {
+	for i, r := range p.Rules {
+		switch r.Type {
+		case PolicyRuleRegion, PolicyRuleResourceType:
+			if len(r.Values) == 0 && len(r.Allowed) == 0 {
+				return fmt.Errorf("%s", i18n.Tf("policy_rule_missing_field", i+1, r.Type, "values/allowed"))
+			}
+		case PolicyRuleInstanceType:
+			if len(r.Values) == 0 && len(r.Allowed) == 0 && r.MaxSize == "" {
+				return fmt.Errorf("%s", i18n.Tf("policy_rule_missing_field", i+1, r.Type, "values/allowed/maxSize"))
+			}
+			if _, ok := instanceSizeUnits(r.MaxSize); r.MaxSize != "" && !ok {
+				return fmt.Errorf("%s", i18n.Tf("policy_rule_bad_size", i+1, r.MaxSize))
+			}
+		case PolicyRuleCost:
+			if r.MaxMonthly <= 0 && r.MaxHourly <= 0 {
+				return fmt.Errorf("%s", i18n.Tf("policy_rule_missing_field", i+1, r.Type, "maxMonthly/maxHourly"))
+			}
+		case PolicyRuleOpenPort, PolicyRuleSpot:
+		default:
+			return fmt.Errorf("%s", i18n.Tf("policy_rule_bad_type", i+1, r.Type))
+		}
+		if r.Effect != "" && r.Effect != PolicyDeny && r.Effect != PolicyWarn {
+			return fmt.Errorf("%s", i18n.Tf("policy_rule_bad_effect", i+1, r.Effect))
+		}
+	}
+	return nil
+}
+
+// Empty 没有任何规则
+func (p *PolicyConfig) Empty() bool {
+	return p == nil || len(p.Rules) == 0
+}
+
+// needsCost 是否有规则需要成本估算
+func (p *PolicyConfig) needsCost() bool {
+	for _, r := range p.Rules {
+		if r.Type == PolicyRuleCost {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyViolation 一条规则命中结果
+type PolicyViolation struct {
+	Rule     string `json:"rule"`
+	Type     string `json:"type"`
+	Effect   string `json:"effect"`
+	Resource string `json:"resource,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (v PolicyViolation) String() string {
+	if v.Resource != "" {
+		return fmt.Sprintf("[%s] %s: %s", v.Rule, v.Resource, v.Message)
+	}
+	return fmt.Sprintf("[%s] %s", v.Rule, v.Message)
+}
+
+// PolicyReport 策略检查结果
+type PolicyReport struct {
+	Violations []PolicyViolation `json:"violations"`
+}
+
+func (r *PolicyReport) add(rule PolicyRule, resource, msg string) {
+	if rule.Message != "" {
+		msg = rule.Message
+	}
+	r.Violations = append(r.Violations, PolicyViolation{Rule: rule.label(), Type: rule.Type, Effect: rule.effect(), Resource: resource, Message: msg})
+}
+
+func (r *PolicyReport) filter(effect string) []PolicyViolation {
+	var out []PolicyViolation
+	for _, v := range r.Violations {
+		if v.Effect == effect {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Denied 返回 deny 级别的结果
+func (r *PolicyReport) Denied() []PolicyViolation {
+	return r.filter(PolicyDeny)
+}
+
+// Warnings 返回 warn 级别的结果
+func (r *PolicyReport) Warnings() []PolicyViolation {
+	return r.filter(PolicyWarn)
+}
+
+// Err 存在 deny 结果时返回 *PolicyDeniedError
+func (r *PolicyReport) Err(target string) error {
+	if denied := r.Denied(); len(denied) > 0 {
+		return &PolicyDeniedError{Target: target, Violations: denied}
+	}
+	return nil
+}
+
+// PolicyDeniedError 部署被策略拒绝
+type PolicyDeniedError struct {
+	Target     string
+	Violations []PolicyViolation
+}
+
+func (e *PolicyDeniedError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		lines = append(lines, "  - "+v.String())
+	}
+	return i18n.Tf("policy_denied", e.Target, strings.Join(lines, "\n"))
+}
+
+// IsPolicyDenied 判断错误是否为策略拒绝
+func IsPolicyDenied(err error) bool {
+	var pe *PolicyDeniedError
+	return errors.As(err, &pe)
+}
+
+// PolicyInput 策略检查的输入：plan 中的资源变更、变量和成本估算
+type PolicyInput struct {
+	Changes   []*tfjson.ResourceChange
+	Variables map[string]interface{}
+	Estimate  *cost.CostEstimate
+}
+
+// EvaluatePolicy 对输入执行所有规则
+func EvaluatePolicy(p *PolicyConfig, in *PolicyInput) *PolicyReport {
+	report := &PolicyReport{}
+	if p.Empty() || in == nil {
+		return report
+	}
+	changes := appliedChanges(in.Changes)
+	for _, rule := range p.Rules {
+		switch rule.Type {
+		case PolicyRuleRegion:
+			evalRegion(rule, in, changes, report)
+		case PolicyRuleInstanceType:
+			evalInstanceType(rule, changes, report)
+		case PolicyRuleOpenPort:
+			evalOpenPort(rule, changes, report)
+		case PolicyRuleSpot:
+			evalSpot(rule, changes, report)
+		case PolicyRuleResourceType:
+			for _, rc := range changes {
+				if deniedValue(rule, rc.Type) {
+					report.add(rule, rc.Address, i18n.Tf("policy_msg_resource_type", rc.Type))
+				}
+			}
+		case PolicyRuleCost:
+			evalCost(rule, in.Estimate, report)
+		}
+	}
+	return report
+}
+
+// appliedChanges 只检查会创建或修改的资源
+func appliedChanges(changes []*tfjson.ResourceChange) []*tfjson.ResourceChange {
+	var out []*tfjson.ResourceChange
+	for _, rc := range changes {
+		if rc == nil || rc.Change == nil || rc.Mode == tfjson.DataResourceMode {
+			continue
+		}
+		for _, a := range rc.Change.Actions {
+			if a == tfjson.ActionCreate || a == tfjson.ActionUpdate {
+				out = append(out, rc)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func afterAttrs(rc *tfjson.ResourceChange) map[string]interface{} {
+	m, _ := rc.Change.After.(map[string]interface{})
+	return m
+}
+
+// matchPattern 大小写不敏感，支持 * ? 通配
+func matchPattern(pattern, value string) bool {
+	pattern, value = strings.ToLower(pattern), strings.ToLower(value)
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, _ := path.Match(pattern, value)
+		return ok
+	}
+	return value == pattern
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if matchPattern(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// deniedValue 命中禁止列表，或设置了允许列表但不在其中
+func deniedValue(rule PolicyRule, value string) bool {
+	if value == "" {
+		return false
+	}
+	if matchAny(rule.Values, value) {
+		return true
+	}
+	return len(rule.Allowed) > 0 && !matchAny(rule.Allowed, value)
+}
+
+func stringAttr(m map[string]interface{}, keys ...string) (string, string) {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return k, s
+		}
+	}
+	return "", ""
+}
+
+var zoneSuffix = regexp.MustCompile(`-?[a-z]$`)
+
+func evalRegion(rule PolicyRule, in *PolicyInput, changes []*tfjson.ResourceChange, report *PolicyReport) {
+	for _, k := range []string{"region", "regions"} {
+		if v, ok := in.Variables[k].(string); ok && deniedValue(rule, v) {
+			report.add(rule, "var."+k, i18n.Tf("policy_msg_region", v))
+		}
+	}
+	for _, rc := range changes {
+		m := afterAttrs(rc)
+		if m == nil {
+			continue
+		}
+		key, v := stringAttr(m, "region", "availability_zone", "zone")
+		if v == "" {
+			continue
+		}
+		denied := deniedValue(rule, v)
+		if key != "region" {
+			// 可用区 cn-hangzhou-h / us-east-1a 同时按所属地域匹配
+			region := zoneSuffix.ReplaceAllString(v, "")
+			denied = matchAny(rule.Values, v) || matchAny(rule.Values, region) ||
+				(len(rule.Allowed) > 0 && !matchAny(rule.Allowed, v) && !matchAny(rule.Allowed, region))
+		}
+		if denied {
+			report.add(rule, rc.Address, i18n.Tf("policy_msg_region", v))
+		}
+	}
+}
+
+var instanceTypeAttrs = []string{"instance_type", "flavor_id", "flavor_name", "machine_type", "plan", "size"}
+
+var sizePattern = regexp.MustCompile(`(?i)(?:^|[.\-_])(\d*)(xlarge|large|medium|small|micro|nano)`)
+var vcpuPattern = regexp.MustCompile(`(?i)(?:^|[.\-_])(\d+)c(?:[.\-_]|$)`)
+
+// instanceSizeUnits 将规格名称换算为近似 vCPU 数，用于比较大小
+func instanceSizeUnits(s string) (float64, bool) {
+	if m := sizePattern.FindStringSubmatch(s); m != nil {
+		switch strings.ToLower(m[2]) {
+		case "nano", "micro", "small", "medium":
+			return 1, true
+		case "large":
+			return 2, true
+		case "xlarge":
+			n := 1
+			if m[1] != "" {
+				n, _ = strconv.Atoi(m[1])
+			}
+			return float64(4 * n), true
+		}
+	}
+	if m := vcpuPattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func checksType(rule PolicyRule, resourceType string, defaults []string) bool {
+	if len(rule.ResourceTypes) > 0 {
+		return matchAny(rule.ResourceTypes, resourceType)
+	}
+	return defaults == nil || matchAny(defaults, resourceType)
+}
+
+func evalInstanceType(rule PolicyRule, changes []*tfjson.ResourceChange, report *PolicyReport) {
+	maxUnits, hasMax := instanceSizeUnits(rule.MaxSize)
+	for _, rc := range changes {
+		m := afterAttrs(rc)
+		if m == nil || !checksType(rule, rc.Type, nil) {
+			continue
+		}
+		_, t := stringAttr(m, instanceTypeAttrs...)
+		if t == "" {
+			continue
+		}
+		if deniedValue(rule, t) {
+			report.add(rule, rc.Address, i18n.Tf("policy_msg_instance_type", t))
+			continue
+		}
+		if units, ok := instanceSizeUnits(t); hasMax && ok && units > maxUnits {
+			report.add(rule, rc.Address, i18n.Tf("policy_msg_instance_size", t, rule.MaxSize))
+		}
+	}
+}
+
+// defaultManagementPorts open_port 规则默认检查的端口 (SSH / RDP)
+var defaultManagementPorts = []int{22, 3389}
+
+type portRange struct{ from, to int }
+
+func isWorldCIDR(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "0.0.0.0/0" || s == "::/0"
+}
+
+// parsePortRanges 解析 "22"、"22/22"、"1-65535"、"80,443"、"ALL"、"-1/-1" 等端口写法
+func parsePortRanges(s string) []portRange {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "all") || s == "-1/-1" || s == "-1" {
+		return []portRange{{0, 65535}}
+	}
+	var out []portRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, found := strings.Cut(part, "-")
+		if !found {
+			lo, hi, found = strings.Cut(part, "/")
+		}
+		from, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			continue
+		}
+		to := from
+		if found {
+			if n, err := strconv.Atoi(strings.TrimSpace(hi)); err == nil {
+				to = n
+			}
+		}
+		if from < 0 {
+			from, to = 0, 65535
+		}
+		out = append(out, portRange{from, to})
+	}
+	return out
+}
+
+func numberAttr(m map[string]interface{}, key string) (int, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// rulePorts 从安全组规则对象中读取端口范围，没有端口信息时视为全部端口
+func rulePorts(m map[string]interface{}) []portRange {
+	if from, ok := numberAttr(m, "from_port"); ok {
+		to, _ := numberAttr(m, "to_port")
+		if from <= 0 && to <= 0 {
+			return []portRange{{0, 65535}}
+		}
+		return []portRange{{from, to}}
+	}
+	if from, ok := numberAttr(m, "port_range_min"); ok {
+		to, _ := numberAttr(m, "port_range_max")
+		if from <= 0 && to <= 0 {
+			return []portRange{{0, 65535}}
+		}
+		return []portRange{{from, to}}
+	}
+	for _, k := range []string{"port_range", "port", "ports"} {
+		switch v := m[k].(type) {
+		case string:
+			return parsePortRanges(v)
+		case float64:
+			return []portRange{{int(v), int(v)}}
+		case []interface{}:
+			var out []portRange
+			for _, p := range v {
+				out = append(out, parsePortRanges(fmt.Sprint(p))...)
+			}
+			return out
+		}
+	}
+	return []portRange{{0, 65535}}
+}
+
+func isEgress(m map[string]interface{}) bool {
+	for _, k := range []string{"type", "direction", "traffic_direction"} {
+		if s, _ := m[k].(string); strings.EqualFold(s, "egress") || strings.EqualFold(s, "outbound") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWorldCIDR 规则对象的来源地址是否包含 0.0.0.0/0 或 ::/0
+func hasWorldCIDR(m map[string]interface{}) bool {
+	for k, v := range m {
+		lk := strings.ToLower(k)
+		if !strings.Contains(lk, "cidr") && lk != "remote_ip_prefix" && lk != "source_ranges" && lk != "source_address_prefix" {
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			if isWorldCIDR(val) {
+				return true
+			}
+		case []interface{}:
+			for _, item := range val {
+				if s, ok := item.(string); ok && isWorldCIDR(s) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// worldOpenPorts 递归查找资源属性中对公网开放的入方向端口
+func worldOpenPorts(v interface{}, egress bool, out []portRange) []portRange {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if !egress && !isEgress(val) && hasWorldCIDR(val) {
+			out = append(out, rulePorts(val)...)
+		}
+		for k, child := range val {
+			out = worldOpenPorts(child, egress || strings.Contains(strings.ToLower(k), "egress"), out)
+		}
+	case []interface{}:
+		for _, item := range val {
+			out = worldOpenPorts(item, egress, out)
+		}
+	case string:
+		// 腾讯云 lite 规则: ACCEPT#0.0.0.0/0#22#TCP
+		if parts := strings.Split(val, "#"); !egress && len(parts) >= 3 && strings.EqualFold(parts[0], "ACCEPT") && isWorldCIDR(parts[1]) {
+			out = append(out, parsePortRanges(parts[2])...)
+		}
+	}
+	return out
+}
+
+func evalOpenPort(rule PolicyRule, changes []*tfjson.ResourceChange, report *PolicyReport) {
+	ports := rule.Ports
+	if len(ports) == 0 {
+		ports = defaultManagementPorts
+	}
+	for _, rc := range changes {
+		if !checksType(rule, rc.Type, nil) {
+			continue
+		}
+		open := worldOpenPorts(rc.Change.After, false, nil)
+		var hit []string
+		for _, p := range ports {
+			for _, r := range open {
+				if p >= r.from && p <= r.to {
+					hit = append(hit, strconv.Itoa(p))
+					break
+				}
+			}
+		}
+		if len(hit) > 0 {
+			report.add(rule, rc.Address, i18n.Tf("policy_msg_open_port", strings.Join(hit, ",")))
+		}
+	}
+}
+
+// spotInstanceTypes spot 规则默认检查的实例资源
+var spotInstanceTypes = []string{"aws_instance", "alicloud_instance", "tencentcloud_instance", "huaweicloud_compute_instance", "volcengine_ecs_instance"}
+
+func isSpotInstance(resourceType string, m map[string]interface{}) bool {
+	if strings.Contains(resourceType, "spot") {
+		return true
+	}
+	if s, _ := m["spot_strategy"].(string); s != "" && !strings.EqualFold(s, "NoSpot") {
+		return true
+	}
+	for _, k := range []string{"instance_charge_type", "charging_mode"} {
+		if s, _ := m[k].(string); strings.EqualFold(s, "SPOTPAID") || strings.EqualFold(s, "spot") {
+			return true
+		}
+	}
+	if opts, ok := m["instance_market_options"].([]interface{}); ok && len(opts) > 0 {
+		return true
+	}
+	return false
+}
+
+func evalSpot(rule PolicyRule, changes []*tfjson.ResourceChange, report *PolicyReport) {
+	for _, rc := range changes {
+		if !checksType(rule, rc.Type, spotInstanceTypes) {
+			continue
+		}
+		m := afterAttrs(rc)
+		if m == nil {
+			m = map[string]interface{}{}
+		}
+		if !isSpotInstance(rc.Type, m) {
+			report.add(rule, rc.Address, i18n.T("policy_msg_spot"))
+		}
+	}
+}
+
+func evalCost(rule PolicyRule, est *cost.CostEstimate, report *PolicyReport) {
+	if est == nil {
+		if rule.FailOpen {
+			report.Violations = append(report.Violations, PolicyViolation{Rule: rule.label(), Type: rule.Type, Effect: PolicyWarn, Message: i18n.T("policy_msg_cost_unavailable")})
+			return
+		}
+		// 无法确认是否超出上限，按规则的 effect 处理 (deny 时拒绝)
+		report.add(rule, "", i18n.T("policy_msg_cost_unknown"))
+		return
+	}
+	if rule.MaxMonthly > 0 && est.TotalMonthlyCost > rule.MaxMonthly {
+		report.add(rule, "", i18n.Tf("policy_msg_cost_monthly", est.TotalMonthlyCost, est.Currency, rule.MaxMonthly))
+	}
+	if rule.MaxHourly > 0 && est.TotalHourlyCost > rule.MaxHourly {
+		report.add(rule, "", i18n.Tf("policy_msg_cost_hourly", est.TotalHourlyCost, est.Currency, rule.MaxHourly))
+	}
+}
+
+// LoadActivePolicy 读取当前 profile 中的策略，未配置时返回 nil
+func LoadActivePolicy() (*PolicyConfig, error) {
+	profile, err := GetActiveProfile()
+	if err != nil {
+		return nil, err
+	}
+	return profile.Policy, nil
+}
+
+// SaveActivePolicy 将策略保存到当前 profile，nil 表示清除
+func SaveActivePolicy(p *PolicyConfig) error {
+	if p != nil {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	profile, err := GetActiveProfile()
+	if err != nil {
+		return err
+	}
+	return UpdateProfilePolicy(profile.ID, p)
+}
+
+var (
+	// PolicyPricingService 成本规则使用的定价服务，GUI 启动时设置；未设置时按需创建
+	PolicyPricingService *cost.PricingService
+	policyPricingOnce    sync.Once
+)
+
+func policyPricing() *cost.PricingService {
+	policyPricingOnce.Do(func() {
+		if PolicyPricingService == nil {
+			PolicyPricingService = cost.NewPricingService(filepath.Join(RedcPath, "pricing_cache.db"))
+		}
+	})
+	return PolicyPricingService
+}
+
+// estimatePolicyCost 按 plan 中的变量估算工作目录中模板的成本
+func estimatePolicyCost(workDir string, vars map[string]interface{}) (*cost.CostEstimate, error) {
+	strVars := make(map[string]string, len(vars))
+	for k, v := range vars {
+		if s, ok := v.(string); ok {
+			strVars[k] = s
+		} else if v != nil {
+			strVars[k] = fmt.Sprint(v)
+		}
+	}
+	return EstimateTemplateCost(workDir, strVars)
+}
+
+// EstimateTemplateCost 按变量估算模板目录或场景工作目录的成本
+func EstimateTemplateCost(dir string, vars map[string]string) (*cost.CostEstimate, error) {
+	resources, err := cost.ParseTemplate(dir, vars)
+	if err != nil {
+		return nil, err
+	}
+	return cost.NewCostCalculator().CalculateCost(resources, policyPricing())
+}
+
+// PlanPolicyInput 从工作目录中的 plan 文件构建策略输入
+func PlanPolicyInput(workDir, planFile string, withCost bool) (*PolicyInput, error) {
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	te, err := NewTerraformExecutor(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	plan, err := te.ShowPlanFile(ctx, planFile)
+	if err != nil {
+		return nil, err
+	}
+	in := &PolicyInput{Changes: plan.ResourceChanges, Variables: map[string]interface{}{}}
+	for k, v := range plan.Variables {
+		if v != nil {
+			in.Variables[k] = v.Value
+		}
+	}
+	if withCost {
+		if est, err := estimatePolicyCost(workDir, in.Variables); err == nil {
+			in.Estimate = est
+		} else {
+			gologger.Debug().Msgf("policy cost estimate: %v", err)
+		}
+	}
+	return in, nil
+}
+
+// CheckPlanPolicy 用当前 profile 的策略检查 plan 文件。warn 结果写入日志，deny 时返回 *PolicyDeniedError；
+// 未配置策略时直接返回空结果，策略无法加载时返回错误而不是放行
+func CheckPlanPolicy(workDir, planFile, target string) (*PolicyReport, error) {
+	policy, err := LoadActivePolicy()
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("policy_load_failed", target, err))
+	}
+	if policy.Empty() {
+		return &PolicyReport{}, nil
+	}
+	in, err := PlanPolicyInput(workDir, planFile, policy.needsCost())
+	if err != nil {
+		return nil, err
+	}
+	report := EvaluatePolicy(policy, in)
+	l := activeOpLog(workDir)
+	for _, v := range report.Warnings() {
+		msg := i18n.Tf("policy_warning", target, v.String())
+		gologger.Warning().Msgf("%s", msg)
+		if l != nil {
+			l.Printf("%s", msg)
+		}
+	}
+	if err := report.Err(target); err != nil {
+		if l != nil {
+			l.Printf("%s", err.Error())
+		}
+		return report, err
+	}
+	return report, nil
+}
+
+// CheckPolicy 用当前 profile 的策略检查场景的 case.tfplan
+func (c *Case) CheckPolicy() (*PolicyReport, error) {
+	return CheckPlanPolicy(c.Path, RedcPlanPath, c.Name)
+}
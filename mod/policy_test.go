@@ -0,0 +1,139 @@
+package mod
+
+import (
+	"os"
+	"red-cloud/mod/cost"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func createChange(address, typ string, after map[string]interface{}) *tfjson.ResourceChange {
+	return &tfjson.ResourceChange{
+		Address: address,
+		Type:    typ,
+		Mode:    tfjson.ManagedResourceMode,
+		Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate}, After: after},
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	bad := []PolicyConfig{
+		{Rules: []PolicyRule{{Type: "unknown"}}},
+		{Rules: []PolicyRule{{Type: PolicyRuleRegion}}},
+		{Rules: []PolicyRule{{Type: PolicyRuleCost}}},
+		{Rules: []PolicyRule{{Type: PolicyRuleInstanceType, MaxSize: "huge"}}},
+		{Rules: []PolicyRule{{Type: PolicyRuleSpot, Effect: "block"}}},
+	}
+	for i, p := range bad {
+		if err := p.Validate(); err == nil {
+			t.Errorf("policy %d should be invalid", i)
+		}
+	}
+	ok := PolicyConfig{Rules: []PolicyRule{
+		{Type: PolicyRuleRegion, Values: []string{"cn-*"}},
+		{Type: PolicyRuleInstanceType, MaxSize: "2xlarge", Effect: PolicyWarn},
+		{Type: PolicyRuleOpenPort},
+		{Type: PolicyRuleCost, MaxMonthly: 100},
+	}}
+	if err := ok.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	p := &PolicyConfig{Rules: []PolicyRule{
+		{Name: "no-us", Type: PolicyRuleRegion, Values: []string{"us-*"}},
+		{Type: PolicyRuleInstanceType, MaxSize: "2xlarge", Effect: PolicyWarn},
+		{Type: PolicyRuleOpenPort},
+		{Type: PolicyRuleSpot},
+		{Type: PolicyRuleCost, MaxMonthly: 50},
+	}}
+	in := &PolicyInput{
+		Variables: map[string]interface{}{"region": "us-east-1"},
+		Changes: []*tfjson.ResourceChange{
+			createChange("aws_instance.big", "aws_instance", map[string]interface{}{"instance_type": "c5.4xlarge"}),
+			createChange("aws_instance.spot", "aws_instance", map[string]interface{}{
+				"instance_type":           "t3.micro",
+				"instance_market_options": []interface{}{map[string]interface{}{"market_type": "spot"}},
+			}),
+			createChange("aws_security_group_rule.ssh", "aws_security_group_rule", map[string]interface{}{
+				"type": "ingress", "from_port": 22.0, "to_port": 22.0, "cidr_blocks": []interface{}{"0.0.0.0/0"},
+			}),
+			createChange("aws_security_group_rule.web", "aws_security_group_rule", map[string]interface{}{
+				"type": "ingress", "from_port": 443.0, "to_port": 443.0, "cidr_blocks": []interface{}{"0.0.0.0/0"},
+			}),
+		},
+		Estimate: &cost.CostEstimate{TotalMonthlyCost: 120, Currency: "USD"},
+	}
+	report := EvaluatePolicy(p, in)
+	count := map[string]int{}
+	for _, v := range report.Violations {
+		count[v.Type+"/"+v.Effect]++
+	}
+	want := map[string]int{
+		PolicyRuleRegion + "/deny":       1,
+		PolicyRuleInstanceType + "/warn": 1,
+		PolicyRuleOpenPort + "/deny":     1,
+		PolicyRuleSpot + "/deny":         1,
+		PolicyRuleCost + "/deny":         1,
+	}
+	for k, n := range want {
+		if count[k] != n {
+			t.Errorf("%s: got %d violations, want %d (%+v)", k, count[k], n, report.Violations)
+		}
+	}
+	err := report.Err("demo")
+	if !IsPolicyDenied(err) {
+		t.Fatalf("expected policy denied error, got %v", err)
+	}
+	if len(report.Warnings()) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(report.Warnings()))
+	}
+}
+
+func TestEvaluatePolicyIgnoresDeletes(t *testing.T) {
+	p := &PolicyConfig{Rules: []PolicyRule{{Type: PolicyRuleResourceType, Values: []string{"aws_iam_*"}}}}
+	del := createChange("aws_iam_user.u", "aws_iam_user", nil)
+	del.Change.Actions = tfjson.Actions{tfjson.ActionDelete}
+	report := EvaluatePolicy(p, &PolicyInput{Changes: []*tfjson.ResourceChange{del}})
+	if len(report.Violations) != 0 {
+		t.Fatalf("deletes should not be checked: %+v", report.Violations)
+	}
+	report = EvaluatePolicy(p, &PolicyInput{Changes: []*tfjson.ResourceChange{createChange("aws_iam_user.u", "aws_iam_user", map[string]interface{}{})}})
+	if report.Err("demo") == nil {
+		t.Fatal("resource type rule should deny aws_iam_user")
+	}
+}
+
+// 无法估算成本时 deny 规则拒绝，failOpen 时只提示
+func TestEvaluatePolicyCostUnavailable(t *testing.T) {
+	p := &PolicyConfig{Rules: []PolicyRule{{Type: PolicyRuleCost, MaxHourly: 1}}}
+	report := EvaluatePolicy(p, &PolicyInput{})
+	if !IsPolicyDenied(report.Err("demo")) {
+		t.Fatalf("missing estimate should fail closed: %+v", report.Violations)
+	}
+
+	p.Rules[0].FailOpen = true
+	report = EvaluatePolicy(p, &PolicyInput{})
+	if report.Err("demo") != nil || len(report.Warnings()) != 1 {
+		t.Fatalf("failOpen should only warn: %+v", report.Violations)
+	}
+}
+
+// 策略无法加载时不能放行部署
+func TestCheckPlanPolicyLoadError(t *testing.T) {
+	RedcPath = t.TempDir()
+	profile, err := GetActiveProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _ := profileFilePath(profile.ID)
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	report, err := CheckPlanPolicy(t.TempDir(), RedcPlanPath, "demo")
+	if err == nil || report != nil {
+		t.Fatalf("unreadable policy should block the deployment, got %v, %v", report, err)
+	}
+}
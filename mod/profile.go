@@ -20,6 +20,8 @@ type ProfileInfo struct {
 	ConfigPath  string    `json:"configPath"`
 	TemplateDir string    `json:"templateDir"`
 	AIConfig    *AIConfig `json:"aiConfig,omitempty"`
+	// Policy apply 前检查的部署策略
+	Policy *PolicyConfig `json:"policy,omitempty"`
 }
 
 // AIConfig represents AI provider configuration
@@ -45,10 +47,11 @@ type FallbackProvider struct {
 }
 
 type profilePayload struct {
-	Name        string    `json:"name"`
-	ConfigPath  string    `json:"configPath"`
-	TemplateDir string    `json:"templateDir"`
-	AIConfig    *AIConfig `json:"aiConfig,omitempty"`
+	Name        string        `json:"name"`
+	ConfigPath  string        `json:"configPath"`
+	TemplateDir string        `json:"templateDir"`
+	AIConfig    *AIConfig     `json:"aiConfig,omitempty"`
+	Policy      *PolicyConfig `json:"policy,omitempty"`
 }
 
 func ensureRedcPath() error {
@@ -131,6 +134,7 @@ func readProfileFile(id string) (ProfileInfo, error) {
 		ConfigPath:  payload.ConfigPath,
 		TemplateDir: payload.TemplateDir,
 		AIConfig:    payload.AIConfig,
+		Policy:      payload.Policy,
 	}, nil
 }
 
@@ -152,6 +156,7 @@ func writeProfileFile(id string, payload profilePayload) (ProfileInfo, error) {
 		ConfigPath:  payload.ConfigPath,
 		TemplateDir: payload.TemplateDir,
 		AIConfig:    payload.AIConfig,
+		Policy:      payload.Policy,
 	}, nil
 }
 
@@ -285,10 +290,15 @@ func UpdateProfile(id string, name string, configPath string, templateDir string
 		}
 		templateDir = path
 	}
+	var policy *PolicyConfig
+	if existing, err := readProfileFile(id); err == nil {
+		policy = existing.Policy
+	}
 	return writeProfileFile(id, profilePayload{
 		Name:        name,
 		ConfigPath:  configPath,
 		TemplateDir: templateDir,
+		Policy:      policy,
 	})
 }
 
@@ -311,6 +321,7 @@ func writeProfileFileWithAIConfig(id string, profile ProfileInfo, aiConfig *AICo
 		ConfigPath:  profile.ConfigPath,
 		TemplateDir: profile.TemplateDir,
 		AIConfig:    aiConfig,
+		Policy:      profile.Policy,
 	}
 	data, err := json.MarshalIndent(payload, "", "  ")
 	if err != nil {
@@ -319,6 +330,22 @@ func writeProfileFileWithAIConfig(id string, profile ProfileInfo, aiConfig *AICo
 	return os.WriteFile(path, data, 0600)
 }
 
+// UpdateProfilePolicy updates only the deployment policy for a profile
+func UpdateProfilePolicy(id string, policy *PolicyConfig) error {
+	profile, err := readProfileFile(id)
+	if err != nil {
+		return err
+	}
+	_, err = writeProfileFile(id, profilePayload{
+		Name:        profile.Name,
+		ConfigPath:  profile.ConfigPath,
+		TemplateDir: profile.TemplateDir,
+		AIConfig:    profile.AIConfig,
+		Policy:      policy,
+	})
+	return err
+}
+
 func DeleteProfile(id string) error {
 	profiles, err := ListProfiles()
 	if err != nil {
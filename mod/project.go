@@ -16,8 +16,12 @@ import (
 )
 
 type ChangeCommand struct {
-	IsRemove bool
-	Pars     map[string]string
+	IsRemove    bool
+	Pars        map[string]string
+	AutoApprove bool                   // 跳过确认直接应用
+	Confirm     func(*ChangePlan) bool // 展示变更后询问是否继续，返回 false 取消
+	Operator    string
+	Target      TargetSpec // 只变更部分资源 (-target / -replace / -destroy)
 }
 
 func GetProjectCase(projectId string, caseID string, userName string) (*Case, error) {
@@ -84,6 +88,7 @@ func (p *RedcProject) GetCase(identifier string) (*Case, error) {
 		return nil, err
 	}
 
+	c.Account = GetCaseAccount(c.Id)
 	// 2. 绑定运行时逻辑 (复活对象)
 	c.bindHandlers()
 	return c, nil
@@ -206,7 +211,7 @@ func (p *RedcProject) CaseList() {
 
 	// 优化2: 表头全大写，符合 CLI 惯例
 	// 并在每一列后明确加上 \t 进行分割
-	fmt.Fprintln(w, "Case ID\tTYPE\tNAME\tOPERATOR\tCREATED\tSTATUS")
+	fmt.Fprintln(w, "Case ID\tTYPE\tNAME\tOPERATOR\tCREATED\tSTATUS\tTTL")
 
 	for _, c := range cases {
 
@@ -239,14 +244,23 @@ func (p *RedcProject) CaseList() {
 			displayStatus = string(c.State)
 		}
 
+		// 剩余租约
+		displayTTL := "-"
+		if c.LeaseExpiresAt != "" {
+			if t, err := time.Parse(time.RFC3339, c.LeaseExpiresAt); err == nil {
+				displayTTL = FormatLeaseRemaining(time.Until(t))
+			}
+		}
+
 		// 使用 Fprintf 配合 \t 格式化输出
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			displayID,
 			c.Type,
 			c.Name,
 			c.Operator,
 			c.CreateTime,
 			displayStatus,
+			displayTTL,
 		)
 
 	}
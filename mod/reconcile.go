@@ -0,0 +1,316 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const interruptedOpBucket = "Case_Interrupted"
+
+// 被中断的操作
+const (
+	InterruptedStart  = "start"
+	InterruptedStop   = "stop"
+	InterruptedRemove = "remove"
+)
+
+// InterruptedOp 进程崩溃等原因被中断的场景操作，由 ReconcileCases 发现并持久化，
+// 等待操作者选择继续 (resume) 或回滚 (rollback)
+type InterruptedOp struct {
+	CaseID     string    `json:"caseId"`
+	CaseName   string    `json:"caseName"`
+	ProjectID  string    `json:"projectId"`
+	Operation  string    `json:"operation"`  // start / stop / remove
+	FromState  string    `json:"fromState"`  // 卡住的过渡状态
+	ToState    string    `json:"toState"`    // 修正后的状态
+	Resources  int       `json:"resources"`  // state 中的资源数
+	HasOutputs bool      `json:"hasOutputs"` // state 中是否有 output
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// ReconcileResult 单个场景的修正结果
+type ReconcileResult struct {
+	CaseID    string         `json:"caseId"`
+	CaseName  string         `json:"caseName"`
+	OldState  string         `json:"oldState"`
+	NewState  string         `json:"newState,omitempty"` // 记录被删除时为空
+	Removed   bool           `json:"removed,omitempty"`  // 场景目录已不存在，删除了残留记录
+	Skipped   bool           `json:"skipped,omitempty"`  // 操作仍在进行 (锁有效) 或已经完成，未修正
+	Error     string         `json:"error,omitempty"`
+	Interrupt *InterruptedOp `json:"interrupt,omitempty"`
+}
+
+// isTransitionalState 是否为操作进行中的过渡状态
+func isTransitionalState(s string) bool {
+	return s == StateStarting || s == StateStopping || s == StateRemoving
+}
+
+// caseStateFacts 通过 TfStatus 统计 state 中的资源数和 output
+func caseStateFacts(c *Case) (int, bool, error) {
+	state, err := TfStatus(c.Path)
+	if err != nil {
+		// 没有 state 时 TfStatus 也会报错，用 state pull 区分“无资源”与真正的失败
+		ctx, cancel := createContextWithTimeout()
+		defer cancel()
+		te, teErr := NewTerraformExecutor(c.Path)
+		if teErr != nil {
+			return 0, false, err
+		}
+		raw, pullErr := te.StatePull(ctx)
+		if pullErr != nil {
+			return 0, false, err
+		}
+		if raw == "" {
+			return 0, false, nil
+		}
+		var s struct {
+			Resources []json.RawMessage `json:"resources"`
+		}
+		if json.Unmarshal([]byte(raw), &s) != nil || len(s.Resources) > 0 {
+			return 0, false, err
+		}
+		return 0, false, nil
+	}
+	if state.Values == nil {
+		return 0, false, nil
+	}
+	return len(collectStateAddresses(state.Values.RootModule, nil)), len(state.Values.Outputs) > 0, nil
+}
+
+// decideReconciledState 根据卡住的状态和实际资源情况决定修正后的状态及被中断的操作
+func decideReconciledState(stuck string, resources int, hasOutputs bool) (string, string) {
+	switch stuck {
+	case StateStarting:
+		switch {
+		case resources == 0:
+			return StateStopped, InterruptedStart
+		case hasOutputs:
+			return StateRunning, InterruptedStart
+		default:
+			return StateError, InterruptedStart
+		}
+	case StateStopping:
+		if resources == 0 {
+			return StateStopped, InterruptedStop
+		}
+		return StateError, InterruptedStop
+	case StateRemoving:
+		if resources == 0 {
+			return StateStopped, InterruptedRemove
+		}
+		return StateError, InterruptedRemove
+	}
+	return stuck, ""
+}
+
+// ReconcileCases 检查项目中卡在 starting / stopping / removing 的场景，按实际 state 修正状态，
+// 并记录被中断的操作供后续 resume / rollback
+func (p *RedcProject) ReconcileCases() ([]*ReconcileResult, error) {
+	cases, err := LoadProjectCases(p.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	var results []*ReconcileResult
+	for _, c := range cases {
+		if !isTransitionalState(c.State) {
+			continue
+		}
+		c.bindHandlers()
+		results = append(results, c.reconcile())
+	}
+	return results, nil
+}
+
+// reconcile 修正单个场景
+func (c *Case) reconcile() *ReconcileResult {
+	r := &ReconcileResult{CaseID: c.Id, CaseName: c.Name, OldState: c.State}
+
+	// 整个检查和修正都持有场景锁：锁仍然有效说明操作确实在进行中，过期的锁由 acquireLock 接管
+	release, err := c.acquireLock("reconcile")
+	if err != nil {
+		if IsCaseLocked(err) {
+			r.Skipped = true
+		} else {
+			r.Error = err.Error()
+		}
+		return r
+	}
+	defer release()
+	// 加载记录后、取得锁之前操作可能刚好完成，按最新记录判断
+	if cur, err := FindCaseBySearch(c.ProjectID, c.Id); err == nil && !isTransitionalState(cur.State) {
+		r.Skipped = true
+		return r
+	}
+	// 删除过程中目录已被删掉，只剩数据库记录
+	if c.State == StateRemoving {
+		if _, err := os.Stat(c.Path); os.IsNotExist(err) {
+			if err := c.DBRemove(); err != nil {
+				r.Error = err.Error()
+				return r
+			}
+			r.Removed = true
+			return r
+		}
+	}
+
+	resources, hasOutputs, err := caseStateFacts(c)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	newState, op := decideReconciledState(c.State, resources, hasOutputs)
+	r.Interrupt = &InterruptedOp{
+		CaseID:     c.Id,
+		CaseName:   c.Name,
+		ProjectID:  c.ProjectID,
+		Operation:  op,
+		FromState:  c.State,
+		ToState:    newState,
+		Resources:  resources,
+		HasOutputs: hasOutputs,
+		DetectedAt: time.Now(),
+	}
+	if err := saveInterruptedOp(r.Interrupt); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("reconcile_record_failed", c.Name, err))
+	}
+	c.StatusChange(newState)
+	r.NewState = newState
+	gologger.Info().Msgf("%s", i18n.Tf("reconcile_corrected", c.Name, r.OldState, newState, resources))
+	return r
+}
+
+// ResumeInterrupted 继续执行被中断的操作
+func (c *Case) ResumeInterrupted() error {
+	op, err := GetInterruptedOp(c.Id)
+	if err != nil {
+		return err
+	}
+	switch op.Operation {
+	case InterruptedStart:
+		err = c.reapply()
+	case InterruptedStop:
+		err = c.TfDestroy()
+	case InterruptedRemove:
+		if err = c.TfDestroy(); err == nil {
+			err = c.Remove()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return ClearInterruptedOp(c.Id)
+}
+
+// RollbackInterrupted 撤销被中断的操作，回到操作之前的状态
+func (c *Case) RollbackInterrupted() error {
+	op, err := GetInterruptedOp(c.Id)
+	if err != nil {
+		return err
+	}
+	switch op.Operation {
+	case InterruptedStart:
+		err = c.TfDestroy()
+	case InterruptedStop:
+		// 重新 apply 以恢复被部分销毁的资源
+		err = c.reapply()
+	case InterruptedRemove:
+		// 目录和记录仍在，保留场景即可
+	}
+	if err != nil {
+		return err
+	}
+	return ClearInterruptedOp(c.Id)
+}
+
+// reapply 没有资源时正常启动；已有部分资源时原地收敛，失败不会销毁已有资源
+func (c *Case) reapply() error {
+	if c.State == StateStopped {
+		return c.TfApply()
+	}
+	plan, err := c.PlanChange(nil)
+	if err != nil {
+		return err
+	}
+	if plan.HasChanges() {
+		if err := c.ApplyChange(plan, c.Operator); err != nil {
+			return err
+		}
+	}
+	c.StatusChange(StateRunning)
+	return nil
+}
+
+func saveInterruptedOp(op *InterruptedOp) error {
+	return dbExec(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(interruptedOpBucket))
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(op)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(op.CaseID), data)
+	})
+}
+
+// GetInterruptedOp 查询场景被中断的操作
+func GetInterruptedOp(caseID string) (*InterruptedOp, error) {
+	var op *InterruptedOp
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(interruptedOpBucket))
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(caseID))
+		if data == nil {
+			return nil
+		}
+		op = &InterruptedOp{}
+		return json.Unmarshal(data, op)
+	})
+	if err == nil && op == nil {
+		err = fmt.Errorf("%s", i18n.Tf("reconcile_no_interrupted", caseID))
+	}
+	return op, err
+}
+
+// ListInterruptedOps 列出项目中等待处理的中断操作，最新的在前
+func ListInterruptedOps(projectID string) ([]*InterruptedOp, error) {
+	var ops []*InterruptedOp
+	err := dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(interruptedOpBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var op InterruptedOp
+			if err := json.Unmarshal(v, &op); err == nil && (projectID == "" || op.ProjectID == projectID) {
+				ops = append(ops, &op)
+			}
+			return nil
+		})
+	})
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].DetectedAt.After(ops[j].DetectedAt)
+	})
+	return ops, err
+}
+
+// ClearInterruptedOp 删除中断记录 (已处理或忽略)
+func ClearInterruptedOp(caseID string) error {
+	return dbExec(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(interruptedOpBucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(caseID))
+	})
+}
@@ -0,0 +1,117 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDecideReconciledState(t *testing.T) {
+	cases := []struct {
+		stuck      string
+		resources  int
+		hasOutputs bool
+		want, op   string
+	}{
+		{StateStarting, 0, false, StateStopped, InterruptedStart},
+		{StateStarting, 3, true, StateRunning, InterruptedStart},
+		{StateStarting, 3, false, StateError, InterruptedStart},
+		{StateStopping, 0, false, StateStopped, InterruptedStop},
+		{StateStopping, 2, true, StateError, InterruptedStop},
+		{StateRemoving, 0, false, StateStopped, InterruptedRemove},
+		{StateRemoving, 1, false, StateError, InterruptedRemove},
+		{StateRunning, 1, true, StateRunning, ""},
+	}
+	for _, tc := range cases {
+		got, op := decideReconciledState(tc.stuck, tc.resources, tc.hasOutputs)
+		if got != tc.want || op != tc.op {
+			t.Errorf("%s/%d/%v: got %s,%s want %s,%s", tc.stuck, tc.resources, tc.hasOutputs, got, op, tc.want, tc.op)
+		}
+	}
+}
+
+func TestInterruptedOpStorage(t *testing.T) {
+	RedcPath = t.TempDir()
+	if _, err := GetInterruptedOp("missing"); err == nil {
+		t.Fatal("expected error for missing interrupted op")
+	}
+	older := &InterruptedOp{CaseID: "a", ProjectID: "default", Operation: InterruptedStart, DetectedAt: time.Now().Add(-time.Hour)}
+	newer := &InterruptedOp{CaseID: "b", ProjectID: "default", Operation: InterruptedStop, DetectedAt: time.Now()}
+	other := &InterruptedOp{CaseID: "c", ProjectID: "other", Operation: InterruptedRemove, DetectedAt: time.Now()}
+	for _, op := range []*InterruptedOp{older, newer, other} {
+		if err := saveInterruptedOp(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ops, err := ListInterruptedOps("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || ops[0].CaseID != "b" || ops[1].CaseID != "a" {
+		t.Fatalf("unexpected ops %+v", ops)
+	}
+	if err := ClearInterruptedOp("b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetInterruptedOp("b"); err == nil {
+		t.Fatal("interrupted op should be cleared")
+	}
+}
+
+func TestReconcileRemovingCaseWithoutDir(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "reconcile-gone", Name: "gone", ProjectID: "default", State: StateRemoving,
+		Path: filepath.Join(RedcPath, "missing")}
+	if err := c.DBSave(); err != nil {
+		t.Fatal(err)
+	}
+	r := c.reconcile()
+	if !r.Removed || r.Error != "" {
+		t.Fatalf("expected removed result, got %+v", r)
+	}
+	cases, err := LoadProjectCases("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 0 {
+		t.Fatalf("case record should be removed, got %d", len(cases))
+	}
+}
+
+func TestReconcileRespectsCaseLock(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "reconcile-locked", Name: "locked", ProjectID: "default", State: StateRemoving,
+		Path: filepath.Join(RedcPath, "missing")}
+	if err := c.DBSave(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他进程持有有效的锁，跳过且不修改记录
+	holder := &Case{Id: c.Id, Name: "holder"}
+	release, err := holder.acquireLock("remove")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := c.reconcile(); !r.Skipped || r.Removed {
+		t.Fatalf("locked case should be skipped, got %+v", r)
+	}
+	if cases, _ := LoadProjectCases("default"); len(cases) != 1 {
+		t.Fatalf("locked case record should be kept, got %d", len(cases))
+	}
+	release()
+
+	// 过期的锁被接管后照常修正
+	host, _ := os.Hostname()
+	stale := &CaseLock{CaseID: c.Id, Owner: "cli:system", Operation: "remove", PID: os.Getpid(), Host: host,
+		AcquiredAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := putCaseLock(stale, false); err != nil {
+		t.Fatal(err)
+	}
+	if r := c.reconcile(); !r.Removed || r.Error != "" {
+		t.Fatalf("stale lock should be taken over, got %+v", r)
+	}
+	if l, _ := GetCaseLock(c.Id); l != nil {
+		t.Fatalf("reconcile should release its lock, got %+v", l)
+	}
+}
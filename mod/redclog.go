@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"red-cloud/mod/secret"
 	"red-cloud/mod2"
 	"time"
 )
@@ -27,7 +28,7 @@ func RedcLog(message string) {
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 
 	// 构造日志消息并写入文件
-	_, err = file.WriteString(fmt.Sprintf("[%s] %s\n", timestamp, message))
+	_, err = file.WriteString(fmt.Sprintf("[%s] %s\n", timestamp, secret.Redact(message)))
 	mod2.PrintOnError(err, "failed to write to log file")
 
 }
@@ -0,0 +1,288 @@
+package mod
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SavedPlanDir 场景目录下保存已审阅 plan 的子目录
+const SavedPlanDir = "plans"
+
+// SavedPlan 已保存的 plan 文件，apply 时必须与审阅时完全一致
+type SavedPlan struct {
+	ID          string    `json:"id"`
+	CaseID      string    `json:"caseId"`
+	File        string    `json:"file"` // 相对场景目录的 plan 文件路径
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	StateSerial uint64    `json:"stateSerial"` // 生成 plan 时的 state serial
+	Lineage     string    `json:"lineage,omitempty"`
+	Summary     string    `json:"summary"`
+	AppliedAt   time.Time `json:"appliedAt,omitempty"`
+}
+
+// Applied 是否已经被应用过
+func (p *SavedPlan) Applied() bool {
+	return !p.AppliedAt.IsZero()
+}
+
+// StalePlanError 生成 plan 后 state 已变化，plan 不再可信
+type StalePlanError struct {
+	PlanID       string
+	PlanSerial   uint64
+	StateSerial  uint64
+	LineageMatch bool
+}
+
+func (e *StalePlanError) Error() string {
+	if !e.LineageMatch {
+		return i18n.Tf("plan_stale_lineage", e.PlanID)
+	}
+	return i18n.Tf("plan_stale_serial", e.PlanID, e.PlanSerial, e.StateSerial)
+}
+
+// IsStalePlan 判断错误是否为 plan 过期
+func IsStalePlan(err error) bool {
+	var se *StalePlanError
+	return errors.As(err, &se)
+}
+
+// stateSerial 读取当前 state 的 serial 和 lineage，尚无 state 时返回 0
+func stateSerial(path string) (uint64, string, error) {
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	te, err := NewTerraformExecutor(path)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	raw, err := te.StatePull(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	return parseStateSerial(raw)
+}
+
+// parseStateSerial 解析 terraform state pull 输出中的 serial / lineage
+func parseStateSerial(raw string) (uint64, string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return 0, "", nil
+	}
+	var s struct {
+		Serial  uint64 `json:"serial"`
+		Lineage string `json:"lineage"`
+	}
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		return 0, "", fmt.Errorf("%s", i18n.Tf("plan_state_parse_failed", err))
+	}
+	return s.Serial, s.Lineage, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *Case) savedPlanMetaPath(id string) string {
+	return filepath.Join(c.Path, SavedPlanDir, id+".json")
+}
+
+func (c *Case) writeSavedPlan(p *SavedPlan) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.savedPlanMetaPath(p.ID), data, 0600)
+}
+
+// SavePlan 重新生成 plan 并保存为可审阅的快照
+func (c *Case) SavePlan(operator string) (*SavedPlan, error) {
+	release, lockErr := c.acquireLock("plan")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer release()
+
+	c.Parameter = ensureProviderParams(c.Type, c.Account, c.Parameter)
+	if err := TfPlan(c.Path, c.Parameter...); err != nil {
+		return nil, err
+	}
+	return c.SnapshotPlan(operator)
+}
+
+// SnapshotPlan 将当前的 case.tfplan 复制为带 hash、时间戳和 state serial 的已保存 plan
+func (c *Case) SnapshotPlan(operator string) (*SavedPlan, error) {
+	src := filepath.Join(c.Path, RedcPlanPath)
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s", i18n.Tf("plan_file_missing", err))
+	}
+	serial, lineage, err := stateSerial(c.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	id := GenerateCaseID()[:12]
+	p := &SavedPlan{
+		ID:          id,
+		CaseID:      c.Id,
+		File:        filepath.Join(SavedPlanDir, id+".tfplan"),
+		CreatedAt:   time.Now(),
+		CreatedBy:   operator,
+		StateSerial: serial,
+		Lineage:     lineage,
+	}
+	sum := sha256.Sum256(data)
+	p.SHA256 = hex.EncodeToString(sum[:])
+
+	if err := os.MkdirAll(filepath.Join(c.Path, SavedPlanDir), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(c.Path, p.File), data, 0600); err != nil {
+		return nil, err
+	}
+
+	// 摘要仅用于展示，解析失败不影响保存
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	if te, err := NewTerraformExecutor(c.Path); err == nil {
+		if tp, err := te.ShowPlanFile(ctx, p.File); err == nil {
+			cp := &ChangePlan{}
+			summarizeResourceChanges(cp, tp.ResourceChanges)
+			p.Summary = cp.Summary()
+		}
+	}
+
+	if err := c.writeSavedPlan(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetSavedPlan 读取已保存的 plan
+func (c *Case) GetSavedPlan(id string) (*SavedPlan, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return nil, fmt.Errorf("%s", i18n.Tf("plan_not_found", id))
+	}
+	data, err := os.ReadFile(c.savedPlanMetaPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s", i18n.Tf("plan_not_found", id))
+		}
+		return nil, err
+	}
+	var p SavedPlan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListSavedPlans 列出场景的已保存 plan，最新的在前
+func (c *Case) ListSavedPlans() ([]*SavedPlan, error) {
+	entries, err := os.ReadDir(filepath.Join(c.Path, SavedPlanDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var plans []*SavedPlan
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if p, err := c.GetSavedPlan(id); err == nil {
+			plans = append(plans, p)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].CreatedAt.After(plans[j].CreatedAt)
+	})
+	return plans, nil
+}
+
+// verifySavedPlan 校验 plan 文件 hash 与 state serial，确保应用的就是审阅过的内容
+func (c *Case) verifySavedPlan(p *SavedPlan) error {
+	if p.Applied() {
+		return fmt.Errorf("%s", i18n.Tf("plan_already_applied", p.ID, p.AppliedAt.Format("2006-01-02 15:04:05")))
+	}
+	sum, err := fileSHA256(filepath.Join(c.Path, p.File))
+	if err != nil {
+		return fmt.Errorf("%s", i18n.Tf("plan_file_missing", err))
+	}
+	if sum != p.SHA256 {
+		return fmt.Errorf("%s", i18n.Tf("plan_hash_mismatch", p.ID))
+	}
+	serial, lineage, err := stateSerial(c.Path)
+	if err != nil {
+		return err
+	}
+	if serial != p.StateSerial || (p.Lineage != "" && lineage != p.Lineage) {
+		return &StalePlanError{PlanID: p.ID, PlanSerial: p.StateSerial, StateSerial: serial, LineageMatch: p.Lineage == "" || lineage == p.Lineage}
+	}
+	return nil
+}
+
+// ApplySavedPlan 应用已保存的 plan 文件 (terraform apply <planfile>)，不会重新生成 plan。
+// 生成 plan 后 state serial 有变化时拒绝执行；失败时不会自动销毁资源
+func (c *Case) ApplySavedPlan(id, operator string) error {
+	release, lockErr := c.acquireLock("apply-plan")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer release()
+
+	p, err := c.GetSavedPlan(id)
+	if err != nil {
+		return err
+	}
+	if err := c.verifySavedPlan(p); err != nil {
+		return err
+	}
+	if _, err := CheckPlanPolicy(c.Path, p.File, c.Name); err != nil {
+		return err
+	}
+
+	gologger.Info().Msgf("%s", i18n.Tf("plan_applying", p.ID, c.Name, p.Summary))
+	starting := c.State != StateRunning
+	if starting {
+		if err := c.StatusChange(StateStarting); err != nil {
+			return err
+		}
+	}
+	c.runPluginHook("pre-apply")
+	if err := TfApplyPlanFile(c.Path, p.File); err != nil {
+		if starting {
+			c.StatusChange(StateError)
+		}
+		return err
+	}
+
+	p.AppliedAt = time.Now()
+	if err := c.writeSavedPlan(p); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("plan_mark_applied_failed", p.ID, err))
+	}
+	if _, err := c.TfOutput(); err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("case_get_output_failed", err))
+	}
+	c.StatusChange(StateRunning)
+	c.runPluginHook("post-apply")
+	c.recordChange(ChangeTypeStateChange, c.Parameter, c.Parameter, operator, "apply plan "+p.ID+": "+p.Summary)
+	return nil
+}
@@ -0,0 +1,67 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseStateSerial(t *testing.T) {
+	serial, lineage, err := parseStateSerial(`{"version":4,"serial":7,"lineage":"abc-123","resources":[]}`)
+	if err != nil || serial != 7 || lineage != "abc-123" {
+		t.Fatalf("got serial=%d lineage=%q err=%v", serial, lineage, err)
+	}
+	if serial, _, err := parseStateSerial("  \n"); err != nil || serial != 0 {
+		t.Fatalf("empty state: serial=%d err=%v", serial, err)
+	}
+	if _, _, err := parseStateSerial("not json"); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestSavedPlanStorageAndHash(t *testing.T) {
+	c := &Case{Id: "case1", Path: t.TempDir()}
+	if err := os.MkdirAll(filepath.Join(c.Path, SavedPlanDir), 0700); err != nil {
+		t.Fatal(err)
+	}
+	planFile := filepath.Join(SavedPlanDir, "p1.tfplan")
+	if err := os.WriteFile(filepath.Join(c.Path, planFile), []byte("plan-bytes"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum, _ := fileSHA256(filepath.Join(c.Path, planFile))
+	older := &SavedPlan{ID: "p0", CaseID: c.Id, File: planFile, SHA256: sum, CreatedAt: time.Now().Add(-time.Hour)}
+	newer := &SavedPlan{ID: "p1", CaseID: c.Id, File: planFile, SHA256: sum, CreatedAt: time.Now()}
+	for _, p := range []*SavedPlan{older, newer} {
+		if err := c.writeSavedPlan(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plans, err := c.ListSavedPlans()
+	if err != nil || len(plans) != 2 || plans[0].ID != "p1" {
+		t.Fatalf("unexpected plans %v err=%v", plans, err)
+	}
+	if _, err := c.GetSavedPlan("../p1"); err == nil {
+		t.Fatal("path traversal in plan id must be rejected")
+	}
+
+	// plan 文件被改动后必须拒绝
+	if err := os.WriteFile(filepath.Join(c.Path, planFile), []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.verifySavedPlan(newer); err == nil {
+		t.Fatal("expected hash mismatch")
+	}
+	newer.AppliedAt = time.Now()
+	if err := c.verifySavedPlan(newer); err == nil {
+		t.Fatal("expected already-applied error")
+	}
+}
+
+func TestStalePlanError(t *testing.T) {
+	var err error = &StalePlanError{PlanID: "p1", PlanSerial: 3, StateSerial: 5, LineageMatch: true}
+	if !IsStalePlan(err) {
+		t.Fatal("IsStalePlan should match StalePlanError")
+	}
+}
@@ -3,6 +3,7 @@ package mod
 import (
 	"database/sql"
 	"fmt"
+	"red-cloud/i18n"
 	"sync"
 	"time"
 
@@ -14,7 +15,7 @@ type ScheduledTask struct {
 	ID             string    `json:"id"`
 	CaseID         string    `json:"caseId"`
 	CaseName       string    `json:"caseName"`
-	Action         string    `json:"action"` // "start", "stop", "ssh_command", "auto_stop"
+	Action         string    `json:"action"` // "start", "stop", "ssh_command", "auto_stop", "ttl_destroy"
 	ScheduledAt    time.Time `json:"scheduledAt"`
 	CreatedAt      time.Time `json:"createdAt"`
 	Status         string    `json:"status"` // "pending", "completed", "failed", "cancelled"
@@ -38,6 +39,7 @@ type TaskScheduler struct {
 	onNotify      func(title string, message string)
 	db            *sql.DB
 	dbPath        string
+	leaseWarn     time.Duration // TTL 到期前提醒时间
 }
 
 // NewTaskScheduler 创建新的任务调度器
@@ -47,6 +49,7 @@ func NewTaskScheduler(project *RedcProject, dbPath string) *TaskScheduler {
 		stopChan: make(chan struct{}),
 		project:  project,
 		dbPath:   dbPath,
+		leaseWarn: DefaultLeaseWarnBefore,
 	}
 }
 
@@ -65,6 +68,16 @@ func (s *TaskScheduler) SetNotifyCallback(callback func(string, string)) {
 	s.onNotify = callback
 }
 
+// SetLeaseWarnBefore 设置 TTL 到期前的提醒时间
+func (s *TaskScheduler) SetLeaseWarnBefore(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d <= 0 {
+		d = DefaultLeaseWarnBefore
+	}
+	s.leaseWarn = d
+}
+
 // InitDB 初始化数据库
 func (s *TaskScheduler) InitDB() error {
 	db, err := sql.Open("sqlite3", s.dbPath)
@@ -280,6 +293,8 @@ func (s *TaskScheduler) periodicCleanup() {
 func (s *TaskScheduler) run() {
 	ticker := time.NewTicker(10 * time.Second) // 每10秒检查一次
 	defer ticker.Stop()
+	leaseTicker := time.NewTicker(time.Minute) // 每分钟检查一次场景 TTL
+	defer leaseTicker.Stop()
 
 	for {
 		select {
@@ -287,7 +302,69 @@ func (s *TaskScheduler) run() {
 			return
 		case <-ticker.C:
 			s.checkAndExecuteTasks()
+		case <-leaseTicker.C:
+			s.checkCaseLeases()
+		}
+	}
+}
+
+// checkCaseLeases 检查场景 TTL：到期前发送提醒，到期后创建 ttl_destroy 任务
+func (s *TaskScheduler) checkCaseLeases() {
+	leases, err := ListCaseLeases()
+	if err != nil {
+		return
+	}
+	s.mu.RLock()
+	warnBefore := s.leaseWarn
+	s.mu.RUnlock()
+
+	for _, l := range leases {
+		if !l.Expired() {
+			if !l.Warned && l.Remaining() <= warnBefore {
+				if s.onNotify != nil {
+					s.onNotify(i18n.T("lease_warn_title"), i18n.Tf("lease_warn_message", l.CaseName, FormatLeaseRemaining(l.Remaining()), l.ExpiresAt.Format("2006-01-02 15:04")))
+				}
+				MarkLeaseWarned(l.CaseID)
+			}
+			continue
 		}
+		// 任务由 checkAndExecuteTasks 执行，同时出现在任务中心
+		s.scheduleLeaseDestroy(l)
+	}
+}
+
+// scheduleLeaseDestroy 为到期场景创建销毁任务。已有进行中的任务或最近失败过时跳过，避免重复销毁
+func (s *TaskScheduler) scheduleLeaseDestroy(l *CaseLease) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.tasks {
+		if t.CaseID != l.CaseID || t.Action != "ttl_destroy" {
+			continue
+		}
+		if t.Status == "pending" || t.Status == "executing" {
+			return
+		}
+		if t.Status == "failed" && time.Since(t.CompletedAt) < 15*time.Minute {
+			return
+		}
+	}
+
+	now := time.Now()
+	task := &ScheduledTask{
+		ID:            fmt.Sprintf("%s-ttl_destroy-%d", l.CaseID, now.UnixNano()),
+		CaseID:        l.CaseID,
+		CaseName:      l.CaseName,
+		Action:        "ttl_destroy",
+		ScheduledAt:   l.ExpiresAt,
+		CreatedAt:     now,
+		Status:        "pending",
+		RepeatType:    "once",
+		NotifyEnabled: true,
+	}
+	s.tasks[task.ID] = task
+	if s.db != nil {
+		s.saveTaskToDB(task)
 	}
 }
 
@@ -325,6 +402,9 @@ func (s *TaskScheduler) executeTask(id string, task *ScheduledTask) {
 	case "auto_stop":
 		// auto_stop is just a stop action
 		err = s.onExecute(task.CaseID, "stop")
+	case "ttl_destroy":
+		// TTL 到期，销毁场景资源
+		err = s.onExecute(task.CaseID, "kill")
 	default:
 		// "start" / "stop"
 		err = s.onExecute(task.CaseID, task.Action)
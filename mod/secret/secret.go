@@ -0,0 +1,162 @@
+// Package secret 保存运行期间发现的敏感值 (实例密码、密钥等)。
+// 日志、状态输出、MCP 结果、审计记录和 AI 记录在写出前调用 Redact 进行脱敏，
+// 需要原文时由调用方通过受角色限制的 reveal 接口单独获取。
+package secret
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask 脱敏后显示的占位符
+const Mask = "******"
+
+// MinLength 短于该长度的值不登记，避免误伤 true / 22 等普通文本
+const MinLength = 6
+
+var (
+	mu       sync.RWMutex
+	values   = map[string]struct{}{}
+	replacer *strings.Replacer
+)
+
+// Register 登记敏感值，同时登记其 JSON 转义形式
+func Register(vals ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	changed := false
+	for _, v := range vals {
+		if len(v) < MinLength || v == Mask {
+			continue
+		}
+		forms := []string{v}
+		if b, err := json.Marshal(v); err == nil {
+			if esc := string(b[1 : len(b)-1]); esc != v {
+				forms = append(forms, esc)
+			}
+		}
+		for _, f := range forms {
+			if _, ok := values[f]; !ok {
+				values[f] = struct{}{}
+				changed = true
+			}
+		}
+	}
+	if changed {
+		rebuild()
+	}
+}
+
+// RegisterValue 登记任意 JSON 值中的所有字符串 (如 list / map 类型的 output)
+func RegisterValue(v interface{}) {
+	switch t := v.(type) {
+	case string:
+		Register(t)
+	case []interface{}:
+		for _, item := range t {
+			RegisterValue(item)
+		}
+	case map[string]interface{}:
+		for _, item := range t {
+			RegisterValue(item)
+		}
+	}
+}
+
+// RegisterJSON 登记 JSON 编码值中的所有字符串
+func RegisterJSON(raw []byte) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err == nil {
+		RegisterValue(v)
+	}
+}
+
+// rebuild 按长度降序重建替换器，保证较长的值优先匹配
+func rebuild() {
+	list := make([]string, 0, len(values))
+	for v := range values {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if len(list[i]) != len(list[j]) {
+			return len(list[i]) > len(list[j])
+		}
+		return list[i] < list[j]
+	})
+	pairs := make([]string, 0, len(list)*2)
+	for _, v := range list {
+		pairs = append(pairs, v, Mask)
+	}
+	replacer = strings.NewReplacer(pairs...)
+}
+
+// Redact 将文本中已登记的敏感值替换为 Mask
+func Redact(s string) string {
+	mu.RLock()
+	r := replacer
+	mu.RUnlock()
+	if r == nil || s == "" {
+		return s
+	}
+	return r.Replace(s)
+}
+
+// Registered 判断值是否已登记
+func Registered(v string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := values[v]
+	return ok
+}
+
+// Reset 清空登记的值，仅用于测试
+func Reset() {
+	mu.Lock()
+	values = map[string]struct{}{}
+	replacer = nil
+	mu.Unlock()
+}
+
+var nameSplit = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sensitiveWords 名称中出现这些片段时视为敏感
+var sensitiveWords = []string{"password", "passwd", "passphrase", "secret", "token", "apikey", "privatekey", "accesskey", "credential"}
+
+// sensitiveSegments 名称按分隔符拆分后出现这些片段时视为敏感
+var sensitiveSegments = map[string]bool{"pwd": true, "pass": true, "key": true, "secret": true}
+
+// exemptSegments 以这些片段结尾的名称描述的是敏感值的元数据 (名称、路径等) 而不是值本身
+var exemptSegments = map[string]bool{"name": true, "names": true, "path": true, "file": true, "pair": true,
+	"type": true, "length": true, "len": true, "id": true, "ids": true, "fingerprint": true, "format": true}
+
+// IsSensitiveName 按名称判断变量或 output 是否敏感 (password / secret / key 等)
+func IsSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	segs := nameSplit.Split(strings.Trim(lower, "_-. "), -1)
+	if len(segs) == 0 {
+		return false
+	}
+	for _, s := range segs {
+		if s == "public" {
+			return false
+		}
+	}
+	if exemptSegments[segs[len(segs)-1]] {
+		return false
+	}
+	joined := strings.Join(segs, "")
+	for _, w := range sensitiveWords {
+		if strings.Contains(joined, w) {
+			return true
+		}
+	}
+	for _, s := range segs {
+		if sensitiveSegments[s] {
+			return true
+		}
+	}
+	return false
+}
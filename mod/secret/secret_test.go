@@ -0,0 +1,46 @@
+package secret
+
+import "testing"
+
+func TestIsSensitiveName(t *testing.T) {
+	cases := map[string]bool{
+		"ecs_password":            true,
+		"instance_password":       true,
+		"adminPassword":           true,
+		"ssh_key":                 true,
+		"api_token":               true,
+		"tencentcloud_secret_key": true,
+		"private_key_pem":         true,
+		"public_key":              false,
+		"key_name":                false,
+		"ssh_key_path":            false,
+		"public_ip":               false,
+		"monkey":                  false,
+		"region":                  false,
+	}
+	for name, want := range cases {
+		if got := IsSensitiveName(name); got != want {
+			t.Errorf("IsSensitiveName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	Reset()
+	defer Reset()
+	Register("R1a2b3c4d5e_+0f1e2d3c4b5a", "short", `pa"ss\word`)
+	got := Redact("ssh root@1.2.3.4 password R1a2b3c4d5e_+0f1e2d3c4b5a")
+	if got != "ssh root@1.2.3.4 password "+Mask {
+		t.Fatalf("unexpected redaction: %s", got)
+	}
+	if Redact("short") != "short" {
+		t.Fatal("values shorter than MinLength should not be registered")
+	}
+	if got := Redact(`{"password":"pa\"ss\\word"}`); got != `{"password":"`+Mask+`"}` {
+		t.Fatalf("JSON-escaped form should be redacted: %s", got)
+	}
+	RegisterJSON([]byte(`{"a":["longsecret1","longsecret2"]}`))
+	if !Registered("longsecret1") || !Registered("longsecret2") {
+		t.Fatal("strings nested in JSON should be registered")
+	}
+}
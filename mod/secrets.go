@@ -0,0 +1,101 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"red-cloud/i18n"
+	"red-cloud/mod/secret"
+	"strings"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// sensitiveVarNames 返回工作目录中声明了 sensitive = true 的变量
+func sensitiveVarNames(workDir string) map[string]bool {
+	names := map[string]bool{}
+	files, _ := filepath.Glob(filepath.Join(workDir, "*.tf"))
+	for _, f := range files {
+		vars, err := parseVariablesFile(f)
+		if err != nil {
+			continue
+		}
+		for _, v := range vars {
+			if v.Sensitive {
+				names[v.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// registerVarSecrets 登记 key=value 形式的 terraform 变量中的敏感值
+func registerVarSecrets(workDir string, vars []string) {
+	var declared map[string]bool
+	for _, kv := range vars {
+		key, val, ok := strings.Cut(kv, "=")
+		if !ok || val == "" {
+			continue
+		}
+		if secret.IsSensitiveName(key) {
+			secret.Register(val)
+			continue
+		}
+		if declared == nil {
+			declared = sensitiveVarNames(workDir)
+		}
+		if declared[key] {
+			secret.Register(val)
+		}
+	}
+}
+
+// IsSensitiveOutput output 标记了 sensitive 或名称看起来是密码 / 密钥
+func IsSensitiveOutput(name string, sensitive bool) bool {
+	return sensitive || secret.IsSensitiveName(name)
+}
+
+// registerOutputSecrets 登记敏感 output 的值
+func registerOutputSecrets(outputs map[string]tfexec.OutputMeta) {
+	for name, meta := range outputs {
+		if IsSensitiveOutput(name, meta.Sensitive) {
+			secret.RegisterJSON(meta.Value)
+		}
+	}
+}
+
+// registerStateSecrets 登记 terraform state 中的敏感 output
+func registerStateSecrets(state *tfjson.State) {
+	if state == nil || state.Values == nil {
+		return
+	}
+	for name, o := range state.Values.Outputs {
+		if o != nil && IsSensitiveOutput(name, o.Sensitive) {
+			secret.RegisterValue(o.Value)
+		}
+	}
+}
+
+// registerSecrets 登记场景参数中的敏感值，场景从数据库加载后即可对其脱敏
+func (c *Case) registerSecrets() {
+	registerVarSecrets(c.Path, c.Parameter)
+}
+
+// RevealOutput 返回 output 的原始值，字符串去掉 JSON 引号，其他类型返回 JSON。
+// 调用方负责权限检查，返回值不能写入日志
+func (c *Case) RevealOutput(name string) (string, error) {
+	outputs, err := c.TfOutput()
+	if err != nil {
+		return "", err
+	}
+	meta, ok := outputs[name]
+	if !ok {
+		return "", fmt.Errorf("%s", i18n.Tf("case_output_not_found", name))
+	}
+	var s string
+	if err := json.Unmarshal(meta.Value, &s); err == nil {
+		return s, nil
+	}
+	return string(meta.Value), nil
+}
@@ -0,0 +1,35 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"red-cloud/mod/secret"
+	"testing"
+)
+
+func TestRegisterVarSecrets(t *testing.T) {
+	secret.Reset()
+	defer secret.Reset()
+	dir := t.TempDir()
+	tf := `variable "db_conn" {
+  type      = string
+  sensitive = true
+}
+variable "region" {
+  type = string
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "variables.tf"), []byte(tf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	registerVarSecrets(dir, []string{"instance_password=Rabcdef_+123456", "db_conn=mysql://u:p@h/db", "region=cn-hongkong"})
+	if !secret.Registered("Rabcdef_+123456") {
+		t.Error("password variable should be registered by name")
+	}
+	if !secret.Registered("mysql://u:p@h/db") {
+		t.Error("variable declared sensitive should be registered")
+	}
+	if secret.Registered("cn-hongkong") {
+		t.Error("non-sensitive variable should not be registered")
+	}
+}
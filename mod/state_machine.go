@@ -0,0 +1,176 @@
+package mod
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"red-cloud/i18n"
+	"red-cloud/mod/gologger"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const stateTransitionBucket = "Case_Transitions"
+
+// MaxStateTransitions 每个场景保留的状态变迁记录数量
+var MaxStateTransitions = 500
+
+// 状态变迁来源
+const (
+	SourceCLI       = "cli"
+	SourceGUI       = "gui"
+	SourceHTTP      = "http"
+	SourceMCP       = "mcp"
+	SourceScheduler = "scheduler"
+)
+
+// caseTransitions 场景状态机：当前状态 -> 允许进入的状态。
+// 任何状态都可以进入 error，保持原状态 (如刷新 StateTime) 也总是允许
+var caseTransitions = map[string][]string{
+	StatePending:    {StateCreated, StateStarting, StateRunning, StateStopping, StateRemoving},
+	StateCreated:    {StateStarting, StateRunning, StateStopping, StateRemoving},
+	StateStarting:   {StateRunning, StateStopped, StateCreated},
+	StateRunning:    {StateStopping, StateTerminated},
+	StateStopping:   {StateStopped},
+	StateStopped:    {StateStarting, StateStopping, StateRemoving},
+	StateError:      {StateStarting, StateStopping, StateRemoving, StateRunning, StateStopped},
+	StateRemoving:   {StateStopped},
+	StateTerminated: {StateStarting, StateStopping, StateRemoving, StateRunning, StateStopped},
+}
+
+// CanTransition 判断状态变迁是否合法。未知或为空的原状态 (旧数据) 不做限制
+func CanTransition(from, to string) bool {
+	if from == to || to == StateError {
+		return true
+	}
+	allowed, ok := caseTransitions[from]
+	if !ok {
+		return true
+	}
+	for _, s := range allowed {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// InvalidTransitionError 非法的状态变迁
+type InvalidTransitionError struct {
+	CaseID   string
+	CaseName string
+	From     string
+	To       string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return i18n.Tf("state_transition_invalid", e.CaseName, e.From, e.To)
+}
+
+// IsInvalidTransition 判断错误是否为非法状态变迁
+func IsInvalidTransition(err error) bool {
+	var te *InvalidTransitionError
+	return errors.As(err, &te)
+}
+
+// StateTransition 一次状态变迁记录
+type StateTransition struct {
+	CaseID string    `json:"caseId"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Actor  string    `json:"actor"`
+	Source string    `json:"source"` // cli / gui / http / mcp / scheduler
+	Time   time.Time `json:"time"`
+}
+
+// SetActor 设置之后状态变迁和场景锁记录的操作者和来源 (如 HTTP 请求的认证用户、定时任务)，为空的字段保持不变，
+// 最终仍为空时分别使用当前用户和 LockOwner
+func (c *Case) SetActor(actor, source string) {
+	if actor != "" {
+		c.actor = actor
+	}
+	if source != "" {
+		c.source = source
+	}
+}
+
+func (c *Case) transitionActor() (string, string) {
+	actor, source := c.actor, c.source
+	if actor == "" {
+		actor = U
+	}
+	if source == "" {
+		source = LockOwner
+	}
+	return actor, source
+}
+
+func transitionKey(seq uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, seq)
+	return k
+}
+
+// recordTransition 持久化状态变迁，失败只记录警告
+func recordTransition(t *StateTransition) {
+	err := dbExec(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(stateTransitionBucket))
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists([]byte(t.CaseID))
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(transitionKey(seq), data); err != nil {
+			return err
+		}
+		// 超出上限时删除最旧的记录
+		if MaxStateTransitions > 0 && seq > uint64(MaxStateTransitions) {
+			oldest := seq - uint64(MaxStateTransitions)
+			cur := b.Cursor()
+			for k, _ := cur.First(); k != nil && binary.BigEndian.Uint64(k) <= oldest; k, _ = cur.First() {
+				if err := cur.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		gologger.Warning().Msgf("%s", i18n.Tf("state_transition_record_failed", t.CaseID, err))
+	}
+}
+
+// ListStateTransitions 返回场景的状态变迁记录，最新的在前
+func ListStateTransitions(caseID string) ([]*StateTransition, error) {
+	var list []*StateTransition
+	err := dbExec(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(stateTransitionBucket))
+		if root == nil {
+			return nil
+		}
+		b := root.Bucket([]byte(caseID))
+		if b == nil {
+			return nil
+		}
+		cur := b.Cursor()
+		for k, v := cur.Last(); k != nil; k, v = cur.Prev() {
+			var t StateTransition
+			if err := json.Unmarshal(v, &t); err == nil {
+				list = append(list, &t)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
@@ -0,0 +1,137 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	allowed := [][2]string{
+		{StatePending, StateCreated},
+		{StateCreated, StateStarting},
+		{StateCreated, StateStopping},
+		{StatePending, StateStopping},
+		{StateStarting, StateRunning},
+		{StateRunning, StateStopping},
+		{StateStopping, StateStopped},
+		{StateStopped, StateRemoving},
+		{StateRunning, StateError},
+		{StateError, StateStarting},
+		{StateRunning, StateRunning},
+		{"", StateRunning},
+	}
+	for _, tr := range allowed {
+		if !CanTransition(tr[0], tr[1]) {
+			t.Errorf("%s -> %s should be allowed", tr[0], tr[1])
+		}
+	}
+	denied := [][2]string{
+		{StateRemoving, StateRunning},
+		{StateRunning, StateStarting},
+		{StateRunning, StateRemoving},
+		{StateStopping, StateRunning},
+	}
+	for _, tr := range denied {
+		if CanTransition(tr[0], tr[1]) {
+			t.Errorf("%s -> %s should be rejected", tr[0], tr[1])
+		}
+	}
+}
+
+func TestStatusChangeRecordsHistory(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "sm-1", Name: "sm", ProjectID: "default", State: StateStopped}
+	c.SetActor("alice", SourceScheduler)
+	if err := c.StatusChange(StateStarting); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StatusChange(StateRunning); err != nil {
+		t.Fatal(err)
+	}
+	err := c.StatusChange(StateRemoving)
+	if !IsInvalidTransition(err) {
+		t.Fatalf("expected InvalidTransitionError, got %v", err)
+	}
+	if c.State != StateRunning {
+		t.Fatalf("state should be unchanged after rejected transition, got %s", c.State)
+	}
+
+	list, err := ListStateTransitions(c.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].From != StateStarting || list[0].To != StateRunning {
+		t.Fatalf("unexpected history %+v", list)
+	}
+	if list[1].Actor != "alice" || list[1].Source != SourceScheduler {
+		t.Fatalf("unexpected actor/source %+v", list[1])
+	}
+}
+
+// SetActor 设置的调用方 (如 HTTP 认证用户) 同时记录到状态变迁和场景锁，空字段不覆盖已设置的身份
+func TestCallerIdentity(t *testing.T) {
+	RedcPath = t.TempDir()
+	c := &Case{Id: "sm-caller", Name: "caller", ProjectID: "default", State: StateStopped, Path: t.TempDir()}
+	c.SetActor("alice", SourceHTTP)
+	c.SetActor("", "")
+
+	if err := c.StatusChange(StateStarting); err != nil {
+		t.Fatal(err)
+	}
+	list, _ := ListStateTransitions(c.Id)
+	if len(list) != 1 || list[0].Actor != "alice" || list[0].Source != SourceHTTP {
+		t.Fatalf("unexpected history %+v", list)
+	}
+	release, err := c.acquireLock("apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if l, _ := GetCaseLock(c.Id); l == nil || l.Owner != "http:alice" {
+		t.Errorf("lock owner = %+v", l)
+	}
+}
+
+func TestStateTransitionPruning(t *testing.T) {
+	RedcPath = t.TempDir()
+	old := MaxStateTransitions
+	MaxStateTransitions = 3
+	defer func() { MaxStateTransitions = old }()
+
+	c := &Case{Id: "sm-prune", Name: "p", ProjectID: "default", State: StateStopped}
+	for i := 0; i < 3; i++ {
+		c.StatusChange(StateStarting)
+		c.StatusChange(StateStopped)
+	}
+	list, _ := ListStateTransitions(c.Id)
+	if len(list) != 3 {
+		t.Fatalf("expected 3 transitions kept, got %d", len(list))
+	}
+	if list[0].To != StateStopped {
+		t.Fatalf("newest transition should be first, got %+v", list[0])
+	}
+}
+
+func TestDestroyCreatedCase(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform is a shell script")
+	}
+	RedcPath = t.TempDir()
+	// 假的 terraform，避免测试下载或真正执行
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	c := &Case{Id: "sm-created", Name: "created", ProjectID: "default", State: StateCreated, Path: t.TempDir()}
+	err := c.TfDestroy()
+	if IsInvalidTransition(err) {
+		t.Fatalf("destroying a created case should be allowed: %v", err)
+	}
+	list, _ := ListStateTransitions(c.Id)
+	if len(list) == 0 || list[len(list)-1].From != StateCreated || list[len(list)-1].To != StateStopping {
+		t.Fatalf("unexpected history %+v", list)
+	}
+}
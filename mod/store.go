@@ -128,6 +128,9 @@ func (c *Case) DBSave() error {
 			return fmt.Errorf("序列化失败: %v", err)
 		}
 
+		if err := putCaseAccount(tx, c.Id, c.Account); err != nil {
+			return err
+		}
 		// 写入 (Key=CaseID)
 		return b.Put([]byte(c.Id), data)
 	})
@@ -144,6 +147,13 @@ func (c *Case) DBRemove() error {
 		if b == nil {
 			return nil // 桶不存在，也就是数据本来就没有，视为成功
 		}
+		// 同时清理 TTL 记录
+		if err := deleteLease(tx, c.Id); err != nil {
+			return err
+		}
+		if err := putCaseAccount(tx, c.Id, ""); err != nil {
+			return err
+		}
 		return b.Delete([]byte(c.Id))
 	})
 }
@@ -160,7 +170,7 @@ func LoadProjectCases(projectName string) ([]*Case, error) {
 		}
 
 		// 遍历桶内所有数据
-		return b.ForEach(func(k, v []byte) error {
+		err := b.ForEach(func(k, v []byte) error {
 			var p pb.Case
 			// 反序列化 Proto
 			if err := proto.Unmarshal(v, &p); err == nil {
@@ -169,6 +179,12 @@ func LoadProjectCases(projectName string) ([]*Case, error) {
 			}
 			return nil
 		})
+		if err != nil {
+			return err
+		}
+		attachLeases(tx, cases)
+		attachAccounts(tx, cases)
+		return nil
 	})
 
 	// 按创建时间降序排序（最新的在前面）
@@ -228,8 +228,12 @@ func parseVariablesFile(filePath string) ([]TemplateVariable, error) {
 			}
 		}
 
-		// 解析 sensitive
-		// sensitive 属性不影响我们的数据结构，但可以记录
+		// 解析 sensitive，敏感变量的值会登记到 secret 脱敏列表
+		if sensAttr, exists := attrs["sensitive"]; exists {
+			if v, diags := sensAttr.Expr.Value(nil); !diags.HasErrors() && v.Type().Equals(cty.Bool) && v.IsKnown() && !v.IsNull() && v.True() {
+				variable.Sensitive = true
+			}
+		}
 
 		// 解析 validation 块
 		for _, validationBlock := range block.Body.Blocks {
@@ -11,6 +11,7 @@ import (
 	"red-cloud/mod/gologger"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/hc-install/product"
@@ -110,8 +111,14 @@ func NewTerraformExecutor(workingDir string, opts ...TerraformOption) (*Terrafor
 
 	// Always set stdout and stderr for better visibility and debugging
 	// Use captured writers to ensure output is visible in GUI
-	tf.SetStdout(stdoutCapture)
-	tf.SetStderr(stderrCapture)
+	// 场景有进行中的操作时，输出同时写入该操作的日志
+	if w := opLogWriter(workingDir); w != nil {
+		tf.SetStdout(io.MultiWriter(stdoutCapture, w))
+		tf.SetStderr(io.MultiWriter(stderrCapture, w))
+	} else {
+		tf.SetStdout(stdoutCapture)
+		tf.SetStderr(stderrCapture)
+	}
 
 	// 保存 writer 的引用以便后续获取
 	te.stderr = stderrCapture
@@ -127,6 +134,14 @@ func NewTerraformExecutor(workingDir string, opts ...TerraformOption) (*Terrafor
 			envVars[env[:idx]] = env[idx+1:]
 		}
 	}
+	// 场景指定了命名账号时覆盖默认凭据，配置了角色时在每次执行前获取 (或刷新) 临时凭据
+	credEnv, err := credentialEnvForDir(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range credEnv {
+		envVars[k] = v
+	}
 
 
 
@@ -171,12 +186,18 @@ func (te *TerraformExecutor) logCapturedOutput() {
 	}
 }
 
+// initMu 串行化进程内的 terraform init：TF_PLUGIN_CACHE_DIR 不支持并发写入，并行编排时多个场景会同时初始化
+var initMu sync.Mutex
+
 // Init runs terraform init with upgrade option
-func (te *TerraformExecutor) Init(ctx context.Context) error {
-	err := te.tf.Init(ctx, tfexec.Upgrade(false))
+func (te *TerraformExecutor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
+	initMu.Lock()
+	err := te.tf.Init(ctx, append([]tfexec.InitOption{tfexec.Upgrade(false)}, opts...)...)
+	initMu.Unlock()
 	if err == nil {
 		te.logCapturedOutput()
 	}
+	recordOpError(te.workingDir, err)
 	return err
 }
 
@@ -184,6 +205,7 @@ func (te *TerraformExecutor) Init(ctx context.Context) error {
 func (te *TerraformExecutor) Apply(ctx context.Context, opts ...tfexec.ApplyOption) error {
 	err := te.tf.Apply(ctx, opts...)
 	if err != nil {
+		recordOpError(te.workingDir, err)
 		// 获取捕获的 stderr 输出
 		var stderrMsg string
 		if sw, ok := te.stderr.(*stderrWriter); ok {
@@ -199,6 +221,7 @@ func (te *TerraformExecutor) Apply(ctx context.Context, opts ...tfexec.ApplyOpti
 }
 func (te *TerraformExecutor) Plan(ctx context.Context, opts ...tfexec.PlanOption) error {
 	_, err := te.tf.Plan(ctx, opts...)
+	recordOpError(te.workingDir, err)
 	return err
 }
 
@@ -209,7 +232,9 @@ func (te *TerraformExecutor) Validate(ctx context.Context) (*tfjson.ValidateOutp
 
 // Destroy runs terraform destroy (auto-approve is the default behavior in terraform-exec)
 func (te *TerraformExecutor) Destroy(ctx context.Context, opts ...tfexec.DestroyOption) error {
-	return te.tf.Destroy(ctx, opts...)
+	err := te.tf.Destroy(ctx, opts...)
+	recordOpError(te.workingDir, err)
+	return err
 }
 
 // Output retrieves a terraform output value as a string
@@ -221,6 +246,30 @@ func (te *TerraformExecutor) Output(ctx context.Context) (map[string]tfexec.Outp
 	return outputs, err
 }
 
+// Import runs terraform import for a single resource address/ID pair
+func (te *TerraformExecutor) Import(ctx context.Context, address, id string, opts ...tfexec.ImportOption) error {
+	if err := te.tf.Import(ctx, address, id, opts...); err != nil {
+		recordOpError(te.workingDir, err)
+		if sw, ok := te.stderr.(*stderrWriter); ok {
+			if msg := strings.TrimSpace(sw.buf.String()); msg != "" {
+				return fmt.Errorf("%w\n\n%v", err, msg)
+			}
+		}
+		return err
+	}
+	te.logCapturedOutput()
+	return nil
+}
+
+// StatePull returns the raw state, works for both local and remote backends
+func (te *TerraformExecutor) StatePull(ctx context.Context) (string, error) {
+	state, err := te.tf.StatePull(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to pull terraform state: %w", err)
+	}
+	return state, nil
+}
+
 // Show runs terraform show to display current state
 func (te *TerraformExecutor) Show(ctx context.Context) (*tfjson.State, error) {
 	state, err := te.tf.Show(ctx)
@@ -281,13 +330,22 @@ func (te *TerraformExecutor) ShowPlan(ctx context.Context) error {
 
 // GetPlanResourceChanges parses the plan file and returns structured resource changes
 func (te *TerraformExecutor) GetPlanResourceChanges(ctx context.Context) ([]*tfjson.ResourceChange, error) {
-	plan, err := te.tf.ShowPlanFile(ctx, RedcPlanPath)
+	plan, err := te.ShowPlanFile(ctx, RedcPlanPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read plan file: %w", err)
+		return nil, err
 	}
 	return plan.ResourceChanges, nil
 }
 
+// ShowPlanFile parses the given plan file (relative to the working dir) into JSON form
+func (te *TerraformExecutor) ShowPlanFile(ctx context.Context, planFile string) (*tfjson.Plan, error) {
+	plan, err := te.tf.ShowPlanFile(ctx, planFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+	return plan, nil
+}
+
 // GetGraph runs terraform graph and returns DOT format string
 func (te *TerraformExecutor) GetGraph(ctx context.Context) (string, error) {
 	dot, err := te.tf.Graph(ctx, tfexec.GraphPlan(RedcPlanPath))
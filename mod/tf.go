@@ -1,6 +1,7 @@
 package mod
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,8 +22,8 @@ func readFileContent(path string) (string, error) {
 	return strings.TrimSpace(string(data)), nil
 }
 
-// TfInit 初始化场景
-func TfInit(Path string) error {
+// TfInit 初始化场景，opts 用于传入远程 backend 配置等额外参数
+func TfInit(Path string, opts ...tfexec.InitOption) error {
 	ctx, cancel := createContextWithTimeout()
 	defer cancel()
 	gologger.Info().Msgf("%s", i18n.Tf("tf_init_terraform", Path))
@@ -34,7 +35,9 @@ func TfInit(Path string) error {
 	}
 
 	// Use retry logic with InitRetries constant
-	err = retryOperation(ctx, te.Init, InitRetries)
+	err = retryOperation(ctx, func(ctx context.Context) error {
+		return te.Init(ctx, opts...)
+	}, InitRetries)
 	if err != nil {
 		return fmt.Errorf("%s", i18n.Tf("tf_network_error", err))
 	}
@@ -42,8 +45,8 @@ func TfInit(Path string) error {
 }
 
 // TfInit2 复制模版后再尝试初始化
-func TfInit2(Path string) error {
-	if err := TfInit(Path); err != nil {
+func TfInit2(Path string, opts ...tfexec.InitOption) error {
+	if err := TfInit(Path, opts...); err != nil {
 		gologger.Error().Msgf("%s", i18n.Tf("tf_init_failed", err))
 		// 无法初始化,删除 case 文件夹
 		if removeErr := os.RemoveAll(Path); removeErr != nil {
@@ -59,21 +62,36 @@ func RVar(s ...string) []string {
 	return s
 }
 func TfPlan(Path string, opts ...string) error {
+	return TfPlanWithOptions(Path, opts)
+}
+
+// TfPlanWithOptions 在变量之外追加额外的 plan 参数 (如 -target / -replace)
+func TfPlanWithOptions(Path string, opts []string, extra ...tfexec.PlanOption) error {
+	return tfPlanTo(Path, RedcPlanPath, opts, extra...)
+}
+
+// tfPlanTo 与 TfPlanWithOptions 相同，plan 写入工作目录下的 out 文件
+func tfPlanTo(Path, out string, opts []string, extra ...tfexec.PlanOption) error {
 	ctx, cancel := createContextWithTimeout()
 	defer cancel()
 	gologger.Debug().Msgf("Planing terraform in %s\n", Path)
+	registerVarSecrets(Path, opts)
 	te, err := NewTerraformExecutor(Path)
 	if err != nil {
 		return fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
 	}
-	o := ToPlan(opts)
+	o := append(ToPlan(opts), extra...)
 	// 增加 plan 输出文件
-	o = append(o, tfexec.Out(RedcPlanPath))
+	o = append(o, tfexec.Out(out))
 	err = te.Plan(ctx, o...)
 	if err != nil {
 		gologger.Error().Msgf("%s", i18n.Tf("tf_create_failed", err))
 		return err
 	}
+	// 只输出 case.tfplan 的变更，预览用的临时 plan 由调用方展示
+	if out != RedcPlanPath {
+		return nil
+	}
 	err = te.ShowPlan(ctx)
 	if err != nil {
 		gologger.Error().Msg(i18n.T("tf_plan_show_failed"))
@@ -84,6 +102,7 @@ func TfApply(Path string, opts ...string) error {
 	ctx, cancel := createContextWithTimeout()
 	defer cancel()
 	gologger.Debug().Msgf("Applying terraform in %s\n", Path)
+	registerVarSecrets(Path, opts)
 	te, err := NewTerraformExecutor(Path)
 	if err != nil {
 		return fmt.Errorf("%s", i18n.Tf("tf_start_failed_no_tf", err))
@@ -105,6 +124,22 @@ func TfApply(Path string, opts ...string) error {
 	return nil
 }
 
+// TfApplyPlanFile 应用指定的 plan 文件，planFile 为相对工作目录的路径
+func TfApplyPlanFile(Path, planFile string) error {
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	gologger.Debug().Msgf("Applying plan %s in %s\n", planFile, Path)
+	te, err := NewTerraformExecutor(Path)
+	if err != nil {
+		return fmt.Errorf("%s", i18n.Tf("tf_start_failed_no_tf", err))
+	}
+	if err := te.Apply(ctx, tfexec.DirOrPlan(planFile)); err != nil {
+		gologger.Error().Msgf("%s", i18n.Tf("tf_start_failed", err.Error()))
+		return fmt.Errorf("%s", i18n.Tf("tf_apply_failed", err))
+	}
+	return nil
+}
+
 func TfStatus(Path string) (*tfjson.State, error) {
 	ctx, cancel := createContextWithTimeout()
 	defer cancel()
@@ -117,6 +152,7 @@ func TfStatus(Path string) (*tfjson.State, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%s", i18n.Tf("tf_query_failed", Path, err))
 	}
+	registerStateSecrets(s)
 	return s, nil
 }
 
@@ -134,6 +170,7 @@ func TfOutput(Path string) (map[string]tfexec.OutputMeta, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%s", i18n.Tf("tf_output_failed", err))
 	}
+	registerOutputSecrets(outputs)
 	return outputs, nil
 }
 
@@ -157,6 +194,7 @@ func TfDestroy(Path string, opts []string) error {
 	ctx, cancel := createContextWithTimeout()
 	defer cancel()
 	gologger.Debug().Msgf("Destroying terraform in %s\n", Path)
+	registerVarSecrets(Path, opts)
 	te, err := NewTerraformExecutor(Path)
 	if err != nil {
 		gologger.Error().Msgf("%s", i18n.Tf("tf_destroy_failed_no_tf", err))
@@ -172,6 +210,22 @@ func TfDestroy(Path string, opts []string) error {
 	return nil
 }
 
+// TfImport 将已有云资源导入到 state，address 为 terraform 资源地址，id 为云上资源 ID
+func TfImport(Path, address, id string, opts ...string) error {
+	ctx, cancel := createContextWithTimeout()
+	defer cancel()
+	gologger.Debug().Msgf("Importing %s (%s) in %s\n", address, id, Path)
+	registerVarSecrets(Path, opts)
+	te, err := NewTerraformExecutor(Path)
+	if err != nil {
+		return fmt.Errorf("%s", i18n.Tf("tf_exec_failed", err.Error()))
+	}
+	if err := te.Import(ctx, address, id, ToImport(opts)...); err != nil {
+		return fmt.Errorf("%s", i18n.Tf("tf_import_failed", address, id, err))
+	}
+	return nil
+}
+
 func ToApply(v []string) []tfexec.ApplyOption {
 	var opts []tfexec.ApplyOption
 	for _, s := range v {
@@ -188,6 +242,14 @@ func ToPlan(v []string) []tfexec.PlanOption {
 	return opts
 }
 
+func ToImport(v []string) []tfexec.ImportOption {
+	var opts []tfexec.ImportOption
+	for _, s := range v {
+		opts = append(opts, tfexec.Var(s))
+	}
+	return opts
+}
+
 func ToDestroy(v []string) []tfexec.DestroyOption {
 	var opts []tfexec.DestroyOption
 	for _, s := range v {
@@ -148,3 +148,12 @@ func (nm *NotificationManager) SendSpotRecoverFailed(sceneName string) {
 		nm.webhookMgr.Send(title, message, "#ff0000")
 	}
 }
+
+func (nm *NotificationManager) SendDriftDetected(sceneName string, count int) {
+	title := i18n.T("notify_drift_detected")
+	message := i18n.Tf("notify_drift_detected_msg", sceneName, count)
+	nm.Send(title, message)
+	if nm.webhookMgr != nil {
+		nm.webhookMgr.Send(title, message, "#ffa500")
+	}
+}
@@ -166,6 +166,30 @@ func (c *Client) WriteFileContent(remotePath string, content []byte) error {
 	return err
 }
 
+// WritePrivateFile 写入仅所有者可读写 (0600) 的文件，写入内容前先收紧权限
+func (c *Client) WritePrivateFile(remotePath string, content []byte) error {
+	sftpClient, err := sftp.NewClient(c.Client)
+	if err != nil {
+		return fmt.Errorf("SFTP 建立失败: %v", err)
+	}
+	defer sftpClient.Close()
+
+	if err := sftpClient.MkdirAll(path.Dir(remotePath)); err != nil {
+		return fmt.Errorf("创建父目录失败: %v", err)
+	}
+	file, err := sftpClient.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer file.Close()
+
+	if err := file.Chmod(0600); err != nil {
+		return fmt.Errorf("设置文件权限失败: %v", err)
+	}
+	_, err = file.Write(content)
+	return err
+}
+
 // GetFileStats 获取文件统计信息
 func (c *Client) GetFileStats(remotePath string) (*FileInfo, error) {
 	sftpClient, err := sftp.NewClient(c.Client)
@@ -49,7 +49,7 @@ func NewClient(conf *SSHConfig) (*Client, error) {
 	if conf.Password != "" {
 		authMethods = append(authMethods, ssh.Password(conf.Password))
 	}
-	gologger.Debug().Msgf("正在尝试SSH连接: ssh %s@%s %s\n", conf.User, conf.Host, conf.Password)
+	gologger.Debug().Msgf("正在尝试SSH连接: ssh %s@%s -p %d (password: %v, key: %v)\n", conf.User, conf.Host, conf.Port, conf.Password != "", conf.KeyPath != "")
 	// 如果没有设置重试，默认3次
 	if conf.MaxRetries > 0 {
 		maxRetries = 3